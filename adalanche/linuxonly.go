@@ -0,0 +1,8 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	_ "github.com/lkarlslund/adalanche/modules/integrations/linuxmachine/collect"
+)
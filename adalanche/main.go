@@ -3,11 +3,19 @@ package main
 import (
 	"os"
 
+	_ "github.com/lkarlslund/adalanche/modules/anonymize"
 	"github.com/lkarlslund/adalanche/modules/cli"
+	_ "github.com/lkarlslund/adalanche/modules/generate"
 	_ "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	_ "github.com/lkarlslund/adalanche/modules/integrations/advisories/analyze"
 	_ "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/collect"
+	_ "github.com/lkarlslund/adalanche/modules/integrations/evtx/analyze"
+	_ "github.com/lkarlslund/adalanche/modules/integrations/linuxmachine/analyze"
 	_ "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	_ "github.com/lkarlslund/adalanche/modules/integrations/portscan/analyze"
+	_ "github.com/lkarlslund/adalanche/modules/ldapserver"
 	_ "github.com/lkarlslund/adalanche/modules/quickmode"
+	_ "github.com/lkarlslund/adalanche/modules/scripting"
 	"github.com/lkarlslund/adalanche/modules/ui"
 )
 
@@ -3,10 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
 	"github.com/lkarlslund/adalanche/modules/integrations/localmachine/collect"
 	"github.com/lkarlslund/adalanche/modules/ui"
@@ -24,6 +24,7 @@ var (
 	loglevel      = wrapcollector.Flags().String("loglevel", "info", "Console log level")
 	logfile       = wrapcollector.Flags().String("logfile", "", "Log file")
 	logfilelevel  = wrapcollector.Flags().String("logfilelevel", "info", "Log file log level")
+	encryptionkey = wrapcollector.Flags().String("encryptionkey", "", "Encrypt output JSON file to this age public key (age1...), defaults to $"+encryption.KeyEnvironment)
 )
 
 func init() {
@@ -47,6 +48,10 @@ func Execute(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err = encryption.Configure(*encryptionkey, ""); err != nil {
+		return err
+	}
+
 	outputpath := *datapath
 
 	if outputpath == "" {
@@ -76,7 +81,7 @@ func Execute(cmd *cobra.Command, args []string) error {
 	}
 
 	outputfile := filepath.Join(outputpath, targetname)
-	err = ioutil.WriteFile(outputfile, output, 0600)
+	err = encryption.WriteFile(outputfile, output, 0600)
 	if err != nil {
 		return fmt.Errorf("Problem writing to file %v: %v", outputfile, err)
 	}
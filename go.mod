@@ -32,6 +32,7 @@ require (
 	github.com/shirou/gopsutil/v3 v3.24.1
 	github.com/spf13/cobra v1.8.0
 	github.com/tinylib/msgp v1.1.9
+	github.com/yusufpapurcu/wmi v1.2.3
 	golang.org/x/sys v0.17.0
 	golang.org/x/term v0.17.0
 	golang.org/x/text v0.14.0
@@ -89,9 +90,8 @@ require (
 	github.com/tklauser/numcpus v0.6.1 // indirect
 	github.com/ugorji/go/codec v1.2.11 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
-	github.com/yusufpapurcu/wmi v1.2.3 // indirect
 	go4.org/unsafe/assume-no-moving-gc v0.0.0-20230525183740-e7c30c78aeb2 // indirect
-	golang.org/x/crypto v0.19.0 // indirect
+	golang.org/x/crypto v0.19.0
 	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/time v0.3.0 // indirect
 	google.golang.org/protobuf v1.30.0 // indirect
@@ -101,6 +101,7 @@ require (
 )
 
 require (
+	filippo.io/age v1.1.1
 	github.com/Velocidex/ordereddict v0.0.0-20220107075049-3dbe58412844
 	github.com/akyoto/cache v1.0.6
 	github.com/elastic/go-windows v1.0.1
@@ -112,6 +113,9 @@ require (
 	github.com/gorilla/websocket v1.5.1
 	github.com/jcmturner/gokrb5/v8 v8.4.4
 	github.com/lkarlslund/gonk v0.0.0-20240227175124-4dc0aa78e98a
+	go.etcd.io/bbolt v1.3.8
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+	gopkg.in/yaml.v3 v3.0.1
 	www.velocidex.com/golang/go-ese v0.2.1-0.20240207005444-85d57b555f8b
 )
 
@@ -149,5 +153,4 @@ require (
 	golang.org/x/mod v0.15.0 // indirect
 	golang.org/x/tools v0.18.0 // indirect
 	gopkg.in/gcfg.v1 v1.2.3 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -49,6 +49,7 @@ type AnalyzeObjectsOptions struct {
 	PruneIslands              bool
 	NodeLimit                 int
 	DontExpandAUEO            bool
+	ReachableFrom             string // Only follow edges needing network access if port scans from this segment say it's possible
 }
 
 type GraphNode struct {
@@ -161,6 +162,21 @@ func AnalyzeObjects(opts AnalyzeObjectsOptions) AnalysisResults {
 					return true // continue
 				}
 
+				if opts.ReachableFrom != "" {
+					target := nextobject
+					if opts.Direction == engine.In {
+						target = currentobject
+					}
+					for _, edge := range detectededges.Edges() {
+						if !engine.Reachable(target, opts.ReachableFrom, edge.RequiredPorts()) {
+							detectededges = detectededges.Clear(edge)
+						}
+					}
+					if detectededges.IsBlank() {
+						return true // continue
+					}
+				}
+
 				if detectobjecttypes != nil {
 					if _, found := detectobjecttypes[nextobject.Type()]; !found {
 						// We're filtering on types, and it's not wanted
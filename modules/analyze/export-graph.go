@@ -59,7 +59,7 @@ type CytoFlatElement struct {
 	Group string             `json:"group"` // nodes or edges
 }
 
-func GenerateCytoscapeJS(pg graph.Graph[*engine.Object, engine.EdgeBitmap], alldetails bool) (CytoGraph, error) {
+func GenerateCytoscapeJS(ao *engine.Objects, pg graph.Graph[*engine.Object, engine.EdgeBitmap], alldetails bool) (CytoGraph, error) {
 	g := CytoGraph{
 		FormatVersion:            "1.0",
 		GeneratedBy:              version.ProgramVersionShort(),
@@ -140,6 +140,14 @@ func GenerateCytoscapeJS(pg graph.Graph[*engine.Object, engine.EdgeBitmap], alld
 
 		cytoedge.Data["_maxprob"] = edge.MaxProbability(source, target)
 		cytoedge.Data["methods"] = edge.StringSlice()
+		if times, found := ao.EdgeTimes(source, target, edge); found {
+			if !times.FirstSeen.IsZero() {
+				cytoedge.Data["_firstseen"] = times.FirstSeen
+			}
+			if !times.PresentSince.IsZero() {
+				cytoedge.Data["_presentsince"] = times.PresentSince
+			}
+		}
 
 		g.Elements[i] = cytoedge
 
@@ -150,8 +158,8 @@ func GenerateCytoscapeJS(pg graph.Graph[*engine.Object, engine.EdgeBitmap], alld
 	return g, nil
 }
 
-func ExportCytoscapeJS(pg graph.Graph[*engine.Object, engine.EdgeBitmap], filename string) error {
-	g, err := GenerateCytoscapeJS(pg, false)
+func ExportCytoscapeJS(ao *engine.Objects, pg graph.Graph[*engine.Object, engine.EdgeBitmap], filename string) error {
+	g, err := GenerateCytoscapeJS(ao, pg, false)
 	if err != nil {
 		return err
 	}
@@ -638,9 +638,28 @@ function rendermethods(ele) {
     }
     s += '">' + prob + '%</span>'
     s += ele.data("methods").sort().map(function (el) { return '<span class="badge badge-secondary">' + el + '</span>' }).join('');
+    var since = edgesince(ele)
+    if (since != null) {
+        s += '<span class="badge badge-info">' + (since.bound ? 'at least since ' : 'since ') + since.date.toLocaleDateString() + '</span>'
+    }
     return s
 }
 
+// When the edge appeared (exact) or a time where it was already there (bound), null if unknown
+function edgesince(ele) {
+    var since = null
+    if (ele.data("_firstseen")) {
+        since = { date: new Date(ele.data("_firstseen")), bound: false }
+    }
+    if (ele.data("_presentsince")) {
+        var presentsince = new Date(ele.data("_presentsince"))
+        if (since == null || presentsince < since.date) {
+            since = { date: presentsince, bound: true }
+        }
+    }
+    return since
+}
+
 icons = new Map(
     [
         ["User", "<img src='icons/person-fill.svg' class='rounded-circle' width='24' height='24'>"],
@@ -1033,9 +1052,14 @@ function findroute(source) {
         dfs.path.select();
         console.log(dfs.distance);
         pathprobability = 1.0
+        possiblesince = null // The path exists once the newest edge on it appeared
         dfs.path.forEach(function (ele) {
             if (ele.isEdge()) {
                 pathprobability = pathprobability * (edgeprobability(ele) / 100);
+                since = edgesince(ele)
+                if (since != null && (possiblesince == null || since.date > possiblesince.date)) {
+                    possiblesince = since
+                }
             }
         })
         pathprobability = pathprobability * 100 // Back to percentages
@@ -1050,7 +1074,8 @@ function findroute(source) {
             }
         })
         newwindow("route_" + source.id() + "_" + target.id(),
-            `Route from ` + nodelabel(source) + ` to ` + nodelabel(target) + ` - ` + pathprobability.toFixed(2) + `% probability`,
+            `Route from ` + nodelabel(source) + ` to ` + nodelabel(target) + ` - ` + pathprobability.toFixed(2) + `% probability` +
+            (possiblesince != null ? (possiblesince.bound ? ` - possible at least since ` : ` - possible since `) + possiblesince.date.toLocaleDateString() : ``),
             routecontents)
     } else {
         toast("No route found", "If your analysis was for multiple target nodes, there is no guarantee that all results can reach all targets.");
@@ -116,6 +116,46 @@ func analysisfuncs(ws *webservice) {
 			return
 		}
 
+		if c.Query("format") == "timeline" {
+			// Replication metadata changes, newest first
+			type TimelineEntry struct {
+				Attribute     string    `json:"attribute"`
+				Value         string    `json:"value,omitempty"`
+				Changed       time.Time `json:"changed"`
+				Version       int       `json:"version"`
+				OriginatingDC string    `json:"originatingdc"`
+			}
+			var timeline []TimelineEntry
+			o.Attr(activedirectory.MSDSReplAttributeMetaData).Iterate(func(value engine.AttributeValue) bool {
+				if md, err := activedirectory.ParseReplAttributeMetaData(value.String()); err == nil {
+					timeline = append(timeline, TimelineEntry{
+						Attribute:     md.AttributeName,
+						Changed:       md.LastOriginatingTime,
+						Version:       md.Version,
+						OriginatingDC: activedirectory.OriginatingDC(md.OriginatingDSA),
+					})
+				}
+				return true
+			})
+			o.Attr(activedirectory.MSDSReplValueMetaData).Iterate(func(value engine.AttributeValue) bool {
+				if md, err := activedirectory.ParseReplValueMetaData(value.String()); err == nil {
+					timeline = append(timeline, TimelineEntry{
+						Attribute:     md.AttributeName,
+						Value:         md.ObjectDN,
+						Changed:       md.LastOriginatingTime,
+						Version:       md.Version,
+						OriginatingDC: activedirectory.OriginatingDC(md.OriginatingDSA),
+					})
+				}
+				return true
+			})
+			sort.Slice(timeline, func(i, j int) bool {
+				return timeline[i].Changed.After(timeline[j].Changed)
+			})
+			c.JSON(200, timeline)
+			return
+		}
+
 		// default format
 
 		type ObjectDetails struct {
@@ -218,6 +258,8 @@ func analysisfuncs(ws *webservice) {
 
 		dontexpandaueo, _ := util.ParseBool(params["dont-expand-au-eo"])
 
+		reachablefrom := strings.TrimSpace(params["reachablefrom"])
+
 		opts := NewAnalyzeObjectsOptions()
 
 		// tricky tricky - if we get a call with the expanddn set, then we handle things .... differently :-)
@@ -323,6 +365,7 @@ func analysisfuncs(ws *webservice) {
 		opts.Backlinks = backlinks
 		opts.NodeLimit = nodelimit
 		opts.DontExpandAUEO = dontexpandaueo
+		opts.ReachableFrom = reachablefrom
 		results := AnalyzeObjects(opts)
 
 		for _, postprocessor := range PostProcessors {
@@ -347,7 +390,7 @@ func analysisfuncs(ws *webservice) {
 			}
 		}
 
-		cytograph, err := GenerateCytoscapeJS(results.Graph, alldetails)
+		cytograph, err := GenerateCytoscapeJS(ws.Objs, results.Graph, alldetails)
 		if err != nil {
 			c.String(500, "Error generating cytoscape graph: %v", err)
 			return
@@ -771,6 +814,41 @@ func analysisfuncs(ws *webservice) {
 		c.JSON(200, result)
 	})
 
+	ws.Router.GET("/findings/hygiene", func(c *gin.Context) {
+		type findingObject struct {
+			ID    engine.ObjectID `json:"id"`
+			DN    string          `json:"dn"`
+			Label string          `json:"label"`
+			Type  string          `json:"type"`
+		}
+		type finding struct {
+			Count   int             `json:"count"`
+			Objects []findingObject `json:"objects"`
+		}
+
+		result := make(map[string]*finding)
+		ws.Objs.Iterate(func(o *engine.Object) bool {
+			o.Attr(activedirectory.HygieneFinding).Iterate(func(value engine.AttributeValue) bool {
+				f, found := result[value.String()]
+				if !found {
+					f = &finding{}
+					result[value.String()] = f
+				}
+				f.Count++
+				f.Objects = append(f.Objects, findingObject{
+					ID:    o.ID(),
+					DN:    o.DN(),
+					Label: o.Label(),
+					Type:  o.Type().String(),
+				})
+				return true
+			})
+			return true
+		})
+
+		c.JSON(200, result)
+	})
+
 	type ProgressReport struct {
 		ID             uuid.UUID
 		Title          string
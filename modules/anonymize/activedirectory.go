@@ -0,0 +1,240 @@
+package anonymize
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+type attributeKind byte
+
+const (
+	kindDefault attributeKind = iota
+	kindName
+	kindDNS
+	kindEmail
+	kindSPN
+	kindText
+	kindReplace
+	kindIP
+	kindSID
+	kindSecurityDescriptor
+	kindObjectGUID
+	kindGPLink
+	kindDrop
+)
+
+// Attributes are looked up lowercased, everything not listed is handled by kindDefault which
+// pseudonymizes DNs and replaces known names, SIDs and IPs in strings
+var attributeKinds = map[string]attributeKind{
+	"cn": kindName, "name": kindName, "samaccountname": kindName, "displayname": kindName,
+	"displaynameprintable": kindName, "givenname": kindName, "sn": kindName, "initials": kindName,
+	"middlename": kindName, "ou": kindName, "dc": kindName, "o": kindName, "l": kindName, "st": kindName,
+	"company": kindName, "department": kindName, "division": kindName, "physicaldeliveryofficename": kindName,
+	"netbiosname": kindName, "flatname": kindName, "msds-principalname": kindName, "uid": kindName,
+
+	"dnshostname": kindDNS, "dnsroot": kindDNS, "trustpartner": kindDNS, "msds-additionaldnshostname": kindDNS,
+	"dnsforestname": kindDNS, "ldapservicename": kindReplace,
+
+	"userprincipalname": kindEmail, "mail": kindEmail, "proxyaddresses": kindEmail, "targetaddress": kindEmail,
+	"msrtcsip-primaryuseraddress": kindEmail,
+
+	"serviceprincipalname": kindSPN, "msds-allowedtodelegateto": kindSPN,
+
+	"description": kindText, "info": kindText, "comment": kindText, "notes": kindText, "title": kindText,
+	"telephonenumber": kindText, "mobile": kindText, "homephone": kindText, "ipphone": kindText, "pager": kindText,
+	"facsimiletelephonenumber": kindText, "othertelephone": kindText, "othermobile": kindText,
+	"streetaddress": kindText, "street": kindText, "postalcode": kindText, "postofficebox": kindText,
+	"postaladdress": kindText, "wwwhomepage": kindText, "url": kindText, "employeeid": kindText,
+	"employeenumber": kindText, "employeetype": kindText, "personaltitle": kindText, "carlicense": kindText,
+	"admincomment": kindText, "location": kindText, "gecos": kindText, "unixhomedirectory": kindText,
+	"ms-mcs-admpwd": kindText, "mslaps-password": kindText, "unixuserpassword": kindText,
+	"userpassword": kindText, "unicodepwd": kindText, "mssfu30password": kindText, "os400password": kindText,
+
+	"homedirectory": kindReplace, "profilepath": kindReplace, "scriptpath": kindReplace,
+	"gpcfilesyspath": kindReplace, "mstsprofilepath": kindReplace, "mstshomedirectory": kindReplace,
+
+	"networkaddress": kindIP, "iphostnumber": kindIP,
+
+	"objectsid": kindSID, "sidhistory": kindSID, "securityidentifier": kindSID, "ms-ds-creatorsid": kindSID,
+	"tokengroups": kindSID,
+
+	"ntsecuritydescriptor": kindSecurityDescriptor, "msds-allowedtoactonbehalfofotheridentity": kindSecurityDescriptor,
+	"msds-groupmsamembership": kindSecurityDescriptor, "frsrootsecurity": kindSecurityDescriptor,
+	"msexchmailboxsecuritydescriptor": kindSecurityDescriptor,
+
+	"objectguid": kindObjectGUID, "netbootguid": kindObjectGUID, "msexchmailboxguid": kindObjectGUID,
+
+	"gplink": kindGPLink,
+
+	// Binary blobs that identify people or hosts and that analysis does not use
+	"usercertificate": kindDrop, "usersmimecertificate": kindDrop, "cacertificate": kindDrop,
+	"thumbnailphoto": kindDrop, "jpegphoto": kindDrop, "photo": kindDrop, "dnsrecord": kindDrop,
+	"msds-managedpassword": kindDrop, "mslaps-encryptedpassword": kindDrop,
+	"mslaps-encryptedpasswordhistory": kindDrop, "mslaps-encrypteddsrmpassword": kindDrop,
+	"mslaps-encrypteddsrmpasswordhistory": kindDrop, "msmqsigncertificates": kindDrop,
+	"msmqdigests": kindDrop, "msexchsafesendershash": kindDrop, "msexchblockedsendershash": kindDrop,
+}
+
+var (
+	// Distinguished names in attributes like member, managedBy, objectCategory
+	dnPattern = regexp.MustCompile(`(?i)^(cn|ou|dc|o|l|c|st|uid)=`)
+
+	// DN-Binary and DN-String syntax (wellKnownObjects, msDS-KeyCredentialLink, msDS-RevealedUsers)
+	dnWithDataPattern = regexp.MustCompile(`^[BS]:\d+:[^:]*:`)
+
+	gplinkPattern = regexp.MustCompile(`(?i)(LDAP://)([^;\]]+)`)
+)
+
+func lowerDN(ro *activedirectory.RawObject) string {
+	return strings.ToLower(ro.DistinguishedName)
+}
+
+// Objects that are identical in all ADs are kept (apart from their DN, SIDs and references to other objects)
+func isWellKnownObject(ro *activedirectory.RawObject) bool {
+	dn := lowerDN(ro)
+	for _, container := range []string{
+		"cn=schema,cn=configuration,",
+		"cn=extended-rights,cn=configuration,",
+		"cn=displayspecifiers,cn=configuration,",
+		"cn=wellknown security principals,cn=configuration,",
+		"cn=forestupdates,cn=configuration,",
+		"cn=domainupdates,cn=system,",
+	} {
+		if strings.HasPrefix(dn, container) || strings.Contains(dn, ","+container) {
+			return true
+		}
+	}
+
+	for _, value := range ro.Attributes["objectSid"] {
+		if len(value) < 8 || len(value) < 8+4*int(value[1]) {
+			continue
+		}
+		sid, _, err := windowssecurity.BytesToSID([]byte(value))
+		if err != nil {
+			continue
+		}
+		if isWellKnownSID(sid) {
+			return true
+		}
+	}
+	return false
+}
+
+// Learn the names that must not be changed
+func (p *Pseudonymizer) preserveRawObject(ro *activedirectory.RawObject) {
+	for _, class := range ro.Attributes["objectClass"] {
+		p.Preserve(class)
+	}
+	if !isWellKnownObject(ro) {
+		return
+	}
+	for _, attribute := range []string{"cn", "name", "lDAPDisplayName", "sAMAccountName", "displayName"} {
+		for _, value := range ro.Attributes[attribute] {
+			p.Preserve(value)
+		}
+	}
+	if rdns := splitDN(ro.DistinguishedName); len(rdns) > 0 {
+		if _, value, found := strings.Cut(rdns[0], "="); found {
+			p.Preserve(unescapeDNValue(value))
+		}
+	}
+}
+
+func (p *Pseudonymizer) RawObject(ro *activedirectory.RawObject) *activedirectory.RawObject {
+	wellknown := isWellKnownObject(ro)
+
+	result := &activedirectory.RawObject{}
+	result.Init()
+	result.DistinguishedName = p.DN(ro.DistinguishedName)
+
+	for attribute, values := range ro.Attributes {
+		kind := attributeKinds[strings.ToLower(attribute)]
+		if kind == kindDrop {
+			continue
+		}
+		if wellknown {
+			// Only rewrite what points to domain specific things
+			switch kind {
+			case kindSID, kindSecurityDescriptor, kindObjectGUID, kindGPLink:
+			default:
+				kind = kindDefault
+			}
+		}
+
+		newvalues := make([]string, len(values))
+		for i, value := range values {
+			newvalues[i] = p.rawValue(kind, value, wellknown)
+		}
+		result.Attributes[attribute] = newvalues
+	}
+	return result
+}
+
+func (p *Pseudonymizer) rawValue(kind attributeKind, value string, wellknown bool) string {
+	switch kind {
+	case kindName:
+		if strings.Contains(value, "=") {
+			return p.DN(value)
+		}
+		return p.Name(value)
+	case kindDNS:
+		return p.DNS(value)
+	case kindEmail:
+		return p.Email(value)
+	case kindSPN:
+		return p.SPN(value)
+	case kindText:
+		return p.Scramble(value)
+	case kindReplace:
+		return p.Replace(value)
+	case kindIP:
+		return p.IP(value)
+	case kindSID:
+		return string(p.SIDBytes([]byte(value)))
+	case kindSecurityDescriptor:
+		return string(p.SecurityDescriptor([]byte(value)))
+	case kindObjectGUID:
+		if len(value) != 16 {
+			return value
+		}
+		u, _ := uuid.FromBytes([]byte(value))
+		return string(p.GUID(u).Bytes())
+	case kindGPLink:
+		return gplinkPattern.ReplaceAllStringFunc(value, func(match string) string {
+			parts := gplinkPattern.FindStringSubmatch(match)
+			return parts[1] + p.DN(parts[2])
+		})
+	}
+
+	if !isText(value) {
+		// Binary data we know nothing about
+		return value
+	}
+	if prefix := dnWithDataPattern.FindString(value); prefix != "" {
+		return prefix + p.DN(value[len(prefix):])
+	}
+	if dnPattern.MatchString(value) {
+		return p.DN(value)
+	}
+	if wellknown {
+		return value
+	}
+	return p.Replace(value)
+}
+
+func isText(value string) bool {
+	if !utf8.ValidString(value) {
+		return false
+	}
+	for _, r := range value {
+		if r < 0x20 && r != '\t' && r != '\r' && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
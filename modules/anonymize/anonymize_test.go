@@ -0,0 +1,430 @@
+package anonymize
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+const (
+	testSeed      = "0123456789abcdef"
+	userSID       = "S-1-5-21-1111111111-2222222222-3333333333-1105"
+	computerSID   = "S-1-5-21-1111111111-2222222222-3333333333-1106"
+	localSID      = "S-1-5-21-4044444444-555555555-666666666"
+	machineIP     = "10.1.2.3"
+	gpoGUIDString = "31b2f340-016d-11d2-945f-00c04fb984f9"
+)
+
+// Everything that identifies the customer in the test data, none of it may survive
+var secrets = []string{
+	"contoso", "asmith", "alice", "smith", "web01", "staff", "projects", "webshop",
+	"1111111111", "2222222222", "3333333333", "4044444444", machineIP, "02:00:5e:10:00:01",
+	"s3cretpassw0rd", "hunter2",
+}
+
+func sidBytes(t *testing.T, s string) string {
+	t.Helper()
+	sid, err := windowssecurity.ParseStringSID(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(sid.Bytes())
+}
+
+func rawObject(dn string, attributes map[string][]string) *activedirectory.RawObject {
+	ro := &activedirectory.RawObject{}
+	ro.Init()
+	ro.DistinguishedName = dn
+	for name, values := range attributes {
+		ro.Attributes[name] = values
+	}
+	return ro
+}
+
+func utf16File(text string) []byte {
+	result := []byte{0xff, 0xfe}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		result = append(result, byte(unit), byte(unit>>8))
+	}
+	return result
+}
+
+func writeTestData(t *testing.T, datapath string) {
+	t.Helper()
+
+	objects := []*activedirectory.RawObject{
+		rawObject("DC=contoso,DC=local", map[string][]string{
+			"objectClass": {"top", "domain", "domainDNS"},
+			"dc":          {"contoso"},
+			"objectSid":   {sidBytes(t, "S-1-5-21-1111111111-2222222222-3333333333")},
+		}),
+		rawObject("CN=Alice Smith,OU=Staff,DC=contoso,DC=local", map[string][]string{
+			"objectClass":       {"top", "person", "organizationalPerson", "user"},
+			"cn":                {"Alice Smith"},
+			"givenName":         {"Alice"},
+			"sn":                {"Smith"},
+			"sAMAccountName":    {"asmith"},
+			"userPrincipalName": {"asmith@contoso.local"},
+			"description":       {"Alice runs the webshop, password hunter2"},
+			"homeDirectory":     {`\\web01\home\asmith`},
+			"objectSid":         {sidBytes(t, userSID)},
+			"memberOf":          {"CN=Domain Admins,CN=Users,DC=contoso,DC=local"},
+		}),
+		rawObject("CN=WEB01,OU=Staff,DC=contoso,DC=local", map[string][]string{
+			"objectClass":          {"top", "computer"},
+			"cn":                   {"WEB01"},
+			"sAMAccountName":       {"WEB01$"},
+			"dNSHostName":          {"web01.contoso.local"},
+			"servicePrincipalName": {"HTTP/web01.contoso.local:8080"},
+			"networkAddress":       {machineIP},
+			"objectSid":            {sidBytes(t, computerSID)},
+		}),
+	}
+	if err := writeRawObjects(filepath.Join(datapath, "DC=contoso,DC=local.objects.msgp.lz4"), objects); err != nil {
+		t.Fatal(err)
+	}
+
+	var gpo activedirectory.GPOdump
+	gpo.GUID = uuid.FromStringOrNil(gpoGUIDString)
+	gpo.DomainDN = "DC=contoso,DC=local"
+	gpo.DomainNetbios = "CONTOSO"
+	gpo.Path = `\\contoso.local\SysVol\contoso.local\Policies\{` + gpoGUIDString + `}`
+	gpo.Files = []activedirectory.GPOfileinfo{
+		{
+			RelativePath: `\Machine\Microsoft\Windows NT\SecEdit\GptTmpl.inf`,
+			Contents:     utf16File("[Group Membership]\r\n*S-1-5-32-544__Members = *" + userSID + "\r\n"),
+		},
+		{
+			RelativePath: `\Machine\Preferences\Groups\Groups.xml`,
+			Contents:     []byte(`<User name="asmith" cpassword="s3cretpassw0rd" userName="CONTOSO\asmith"/>`),
+		},
+	}
+	data, err := json.Marshal(gpo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(datapath, gpoGUIDString+".gpodata.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var info localmachine.Info
+	info.Machine.Name = "WEB01"
+	info.Machine.Domain = "contoso.local"
+	info.Machine.LocalSID = localSID
+	info.Machine.ComputerDomainSID = computerSID
+	info.Network.NetworkInterfaces = []localmachine.NetworkInterfaceInfo{
+		{Name: "Ethernet", MACAddress: "02:00:5e:10:00:01", Addresses: []string{machineIP + "/24"}},
+	}
+	info.Users = localmachine.Users{
+		{Name: "Administrator", SID: localSID + "-500"},
+		{Name: "webshop", SID: localSID + "-1001", FullName: "Webshop Service"},
+	}
+	info.Groups = localmachine.Groups{
+		{Name: "Administrators", SID: "S-1-5-32-544", Members: []localmachine.Member{
+			{Name: `CONTOSO\asmith`, SID: userSID},
+			{Name: `WEB01\webshop`, SID: localSID + "-1001"},
+		}},
+	}
+	info.Shares = localmachine.Shares{
+		{Name: "Projects", Path: `C:\Projects`, Remark: "Alice's projects"},
+	}
+	data, err = json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(datapath, "WEB01$contoso.local"+localmachine.Suffix), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	nmap := `<?xml version="1.0"?>
+<nmaprun scanner="nmap" args="nmap -oX staff.nmap.xml 10.1.2.0/24">
+<scaninfo type="syn" protocol="tcp" numservices="3" services="22,80,3389"/>
+<host><status state="up"/><address addr="` + machineIP + `" addrtype="ipv4"/><address addr="02:00:5E:10:00:01" addrtype="mac"/>
+<hostnames><hostname name="web01.contoso.local" type="PTR"/></hostnames>
+<ports><port protocol="tcp" portid="80"><state state="open"/><service name="http" product="Webshop"/></port>
+<port protocol="tcp" portid="3389"><state state="closed"/></port></ports></host>
+</nmaprun>`
+	if err = os.WriteFile(filepath.Join(datapath, "staff.nmap.xml"), []byte(nmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+	masscan := `{"ip": "` + machineIP + `", "timestamp": "1700000000", "ports": [{"port": 445, "proto": "tcp", "status": "open"}]}` + "\n"
+	if err = os.WriteFile(filepath.Join(datapath, "staff.masscan.json"), []byte(masscan), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Reads back what was written, with binary SIDs and file contents turned into text so they can be searched
+func readOutput(t *testing.T, output string) (objects, gpos, machines, scans string) {
+	t.Helper()
+	err := filepath.WalkDir(output, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relative, _ := filepath.Rel(output, path)
+		switch {
+		case isRawObjectFile(path):
+			objects += relative + "\n"
+			return readRawObjects(path, func(ro *activedirectory.RawObject) {
+				objects += ro.DistinguishedName + "\n"
+				for name, values := range ro.Attributes {
+					for _, value := range values {
+						objects += name + ": " + value + "\n"
+						if name == "objectSid" {
+							sid, _, err := windowssecurity.BytesToSID([]byte(value))
+							if err != nil {
+								t.Fatal(err)
+							}
+							objects += name + ": " + sid.String() + "\n"
+						}
+					}
+				}
+			})
+		case strings.HasSuffix(path, gpoSuffix):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var gpo activedirectory.GPOdump
+			if err = json.Unmarshal(data, &gpo); err != nil {
+				return err
+			}
+			gpos += relative + "\n" + string(data) + "\n"
+			for _, file := range gpo.Files {
+				gpos += string(file.Contents) + "\n"
+				if len(file.Contents) > 2 && file.Contents[0] == 0xff {
+					units := make([]uint16, 0, len(file.Contents)/2)
+					for i := 2; i+1 < len(file.Contents); i += 2 {
+						units = append(units, uint16(file.Contents[i])|uint16(file.Contents[i+1])<<8)
+					}
+					gpos += string(utf16.Decode(units)) + "\n"
+				}
+			}
+		case strings.HasSuffix(path, localmachine.Suffix):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			machines += relative + "\n" + string(data) + "\n"
+		case isPortScanFile(path):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			scans += relative + "\n" + string(data) + "\n"
+		default:
+			t.Errorf("unexpected output file %v", relative)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestAnonymize(t *testing.T) {
+	datapath := t.TempDir()
+	writeTestData(t, datapath)
+	output := t.TempDir()
+	if err := Anonymize(datapath, output, testSeed); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, gpos, machines, scans := readOutput(t, output)
+	if objects == "" || gpos == "" || machines == "" || scans == "" {
+		t.Fatalf("missing output, got objects %v, gpo %v, localmachine %v and port scan %v bytes", len(objects), len(gpos), len(machines), len(scans))
+	}
+
+	files := map[string]string{"objects": objects, "gpo": gpos, "localmachine": machines, "portscan": scans}
+	for kind, text := range files {
+		lower := strings.ToLower(text)
+		for _, secret := range secrets {
+			if strings.Contains(lower, secret) {
+				t.Errorf("%v output contains %q", kind, secret)
+			}
+		}
+		for _, sid := range []string{userSID, computerSID, localSID} {
+			if strings.Contains(text, sidBytes(t, sid)[8:20]) {
+				t.Errorf("%v output contains binary domain part of %v", kind, sid)
+			}
+		}
+	}
+
+	// Built in names stay, so the data still looks like AD
+	for _, kept := range []string{"Administrators", "S-1-5-32-544", "Users"} {
+		if !strings.Contains(objects+machines+gpos, kept) {
+			t.Errorf("well known name %v was replaced", kept)
+		}
+	}
+
+	// The same thing gets the same pseudonym, no matter which file it is in
+	p := NewPseudonymizer(testSeed)
+	tests := []struct {
+		name      string
+		pseudonym string
+		in        []string
+	}{
+		{"user SID", p.SIDString(userSID), []string{"objects", "gpo", "localmachine"}},
+		{"computer SID", p.SIDString(computerSID), []string{"objects", "localmachine"}},
+		{"machine name", p.Name("WEB01"), []string{"objects", "localmachine", "portscan"}},
+		{"domain", p.DNS("contoso.local"), []string{"objects", "gpo", "localmachine"}},
+		{"user name", p.Name("asmith"), []string{"objects", "gpo", "localmachine"}},
+		{"domain DN", p.DN("DC=contoso,DC=local"), []string{"objects", "gpo"}},
+		{"IP address", p.IP(machineIP), []string{"objects", "localmachine", "portscan"}},
+	}
+	for _, tt := range tests {
+		for _, kind := range tt.in {
+			if !strings.Contains(files[kind], tt.pseudonym) {
+				t.Errorf("%v output does not contain %v pseudonym %v", kind, tt.name, tt.pseudonym)
+			}
+		}
+	}
+
+	// Port scans still say what was scanned and found open
+	for _, name := range []string{p.Name("staff") + ".nmap.xml", p.Name("staff") + portscan.MasscanSuffix} {
+		raw, err := os.ReadFile(filepath.Join(output, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var scan portscan.Scan
+		if strings.HasSuffix(name, portscan.MasscanSuffix) {
+			scan, err = portscan.ParseMasscan(raw)
+		} else {
+			scan, err = portscan.ParseNmap(raw)
+		}
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if len(scan.Hosts) != 1 || len(scan.Hosts[0].Open) != 1 || scan.Hosts[0].Addresses[0] != p.IP(machineIP) {
+			t.Errorf("%v hosts = %+v", name, scan.Hosts)
+		}
+		if _, scanned := scan.Scanned[portscan.Port{Number: 3389, Protocol: "tcp"}]; scanned != strings.HasSuffix(name, ".xml") {
+			t.Errorf("%v has 3389/tcp scanned %v", name, scanned)
+		}
+	}
+
+	// Running again with the same seed gives the same result
+	again := t.TempDir()
+	if err := Anonymize(datapath, again, testSeed); err != nil {
+		t.Fatal(err)
+	}
+	if objects2, gpos2, machines2, scans2 := readOutput(t, again); gpos2 != gpos || machines2 != machines || scans2 != scans || len(objects2) != len(objects) {
+		t.Errorf("same seed gave different output")
+	}
+}
+
+func TestPseudonymizerValues(t *testing.T) {
+	p := NewPseudonymizer(testSeed)
+	other := NewPseudonymizer("another seed")
+
+	tests := []struct {
+		name  string
+		input string
+		apply func(p *Pseudonymizer, s string) string
+		same  bool // Kept as is
+	}{
+		{"name", "asmith", (*Pseudonymizer).Name, false},
+		{"computer account", "WEB01$", (*Pseudonymizer).Name, false},
+		{"well known name", "Domain Controllers", (*Pseudonymizer).Name, true},
+		{"builtin SID", "S-1-5-32-544", (*Pseudonymizer).SIDString, true},
+		{"domain SID", userSID, (*Pseudonymizer).SIDString, false},
+		{"loopback", "127.0.0.1", (*Pseudonymizer).IP, true},
+		{"address", machineIP, (*Pseudonymizer).IP, false},
+		{"builtin account", `NT AUTHORITY\SYSTEM`, (*Pseudonymizer).Account, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.apply(p, tt.input)
+			if (got == tt.input) != tt.same {
+				t.Errorf("%v = %v, kept as is %v", tt.input, got, got == tt.input)
+			}
+			if again := tt.apply(p, strings.ToUpper(tt.input)); !tt.same && tt.name != "domain SID" && !strings.EqualFold(again, got) {
+				t.Errorf("%v in upper case = %v, want %v", tt.input, again, got)
+			}
+			if !tt.same && tt.apply(other, tt.input) == got {
+				t.Errorf("%v gives %v with another seed too", tt.input, got)
+			}
+		})
+	}
+
+	// Addresses in the same subnet stay in the same (other) subnet
+	a, b := p.IP("10.1.2.3"), p.IP("10.1.2.200")
+	if a[:strings.LastIndex(a, ".")] != b[:strings.LastIndex(b, ".")] {
+		t.Errorf("10.1.2.3 and 10.1.2.200 became %v and %v, not in the same subnet", a, b)
+	}
+}
+
+func TestLinuxMachine(t *testing.T) {
+	var info linuxmachine.Info
+	info.Machine = linuxmachine.Machine{Name: "web01", DNSHostName: "web01.contoso.local", Domain: "CONTOSO", Realm: "CONTOSO.LOCAL", MachineAccount: "WEB01$"}
+	info.Users = []linuxmachine.User{
+		{Name: "root", UID: 0, Home: "/root"},
+		{Name: "asmith", UID: 1001, GECOS: "Alice Smith", Home: "/home/asmith"},
+	}
+	info.Groups = []linuxmachine.Group{{Name: "wheel", GID: 10, Members: []string{"asmith"}}}
+	info.ActiveSessions = []linuxmachine.Session{{Name: "asmith", Line: "pts/0", Host: machineIP}}
+	info.Sudoers = []linuxmachine.SudoRule{{
+		Source:   "/etc/sudoers.d/webshop",
+		Users:    []string{"asmith", "%wheel", "%:webshop@contoso.local", "!#1002"},
+		Hosts:    []string{"ALL", "web01"},
+		RunAs:    []string{"root"},
+		Commands: []string{"/usr/bin/systemctl restart webshop"},
+	}}
+	info.SSSD = []linuxmachine.SSSDomain{{Name: "contoso.local", ADDomain: "contoso.local", SimpleAllowUsers: []string{"asmith@contoso.local"}}}
+	info.Keytab = []linuxmachine.Principal{{Name: "host/web01.contoso.local", Realm: "CONTOSO.LOCAL"}, {Name: "WEB01$", Realm: "CONTOSO.LOCAL"}}
+
+	p := NewPseudonymizer(testSeed)
+	p.preserveLinuxMachine(info)
+	// Twice, like the command does, so names seen anywhere are replaced in the free text too
+	p.LinuxMachine(info)
+	result := p.LinuxMachine(info)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.ToLower(string(data))
+	for _, secret := range secrets {
+		if strings.Contains(text, secret) {
+			t.Errorf("output contains %q: %s", secret, data)
+		}
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"system user", result.Users[0].Name, "root"},
+		{"system group", result.Groups[0].Name, "wheel"},
+		{"group member", result.Groups[0].Members[0], p.Name("asmith")},
+		{"session", result.ActiveSessions[0].Name, p.Name("asmith")},
+		{"session host", result.ActiveSessions[0].Host, p.IP(machineIP)},
+		{"sudo user", result.Sudoers[0].Users[0], p.Name("asmith")},
+		{"sudo group", result.Sudoers[0].Users[1], "%wheel"},
+		{"sudo non-Unix group", result.Sudoers[0].Users[2], "%:" + p.Email("webshop@contoso.local")},
+		{"sudo uid", result.Sudoers[0].Users[3], "!#1002"},
+		{"sudo any host", result.Sudoers[0].Hosts[0], "ALL"},
+		{"sudo run as", result.Sudoers[0].RunAs[0], "root"},
+		{"home", result.Users[1].Home, "/home/" + p.Name("asmith")},
+		{"realm", result.Machine.Realm, p.DNS("contoso.local")},
+		{"machine account", result.Keytab[1].Name, p.Name("WEB01") + "$"},
+		{"service principal", result.Keytab[0].Name, "host/" + p.DNS("web01.contoso.local")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%v = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
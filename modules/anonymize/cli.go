@@ -0,0 +1,368 @@
+package anonymize
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/cli"
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/integrations/evtx"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/mailru/easyjson"
+	"github.com/pierrec/lz4/v4"
+	"github.com/spf13/cobra"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "anonymize [-options]",
+		Short: "Writes a copy of the collected data with names, SIDs, GUIDs and addresses consistently pseudonymized, so it can be shared",
+	}
+
+	output = Command.Flags().String("output", "", "Path to write the pseudonymized data to")
+	seed   = Command.Flags().String("seed", "", "Secret seed for the pseudonyms, using the same seed gives the same result (default random)")
+)
+
+func init() {
+	cli.Root.AddCommand(Command)
+	Command.RunE = Execute
+}
+
+const (
+	objectsSuffix = ".objects.msgp.lz4"
+	gpoSuffix     = ".gpodata.json"
+)
+
+func Execute(cmd *cobra.Command, args []string) error {
+	datapath := cmd.InheritedFlags().Lookup("datapath").Value.String()
+
+	if *output == "" {
+		return errors.New("you need to specify where to write the pseudonymized data with --output")
+	}
+	absdata, _ := filepath.Abs(datapath)
+	absoutput, _ := filepath.Abs(*output)
+	if absdata == absoutput || strings.HasPrefix(absoutput, absdata+string(filepath.Separator)) {
+		return errors.New("output path can not be the datapath or inside it")
+	}
+
+	if *seed == "" {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			return err
+		}
+		*seed = hex.EncodeToString(random)
+		ui.Info().Msgf("Using random seed %v, use --seed %v to get the same pseudonyms again", *seed, *seed)
+	}
+
+	return Anonymize(datapath, *output, *seed)
+}
+
+// Anonymize writes a pseudonymized copy of the collected files in datapath to outputpath
+func Anonymize(datapath, outputpath, seed string) error {
+	var files []string
+	err := filepath.WalkDir(datapath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(path, objectsSuffix), strings.HasSuffix(path, activedirectory.DeltaSuffix),
+			strings.HasSuffix(path, gpoSuffix), strings.HasSuffix(path, localmachine.Suffix),
+			strings.HasSuffix(path, linuxmachine.Suffix), isPortScanFile(path):
+			files = append(files, path)
+		case strings.HasSuffix(path, ".checkpoint.json"), strings.HasSuffix(path, ".incremental.json"),
+			strings.HasSuffix(path, ".partial"):
+			// Collection state, not needed for analysis
+		case strings.HasSuffix(strings.ToLower(path), evtx.Suffix):
+			// Names, SIDs and addresses are spread over BinXML string tables and templates with checksums
+			ui.Warn().Msgf("Event log %v can not be pseudonymized, it is not copied to the output", path)
+		default:
+			ui.Warn().Msgf("Skipping unknown file %v, it is not copied to the output", path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p := NewPseudonymizer(seed)
+
+	// First learn which names are built in, then which names exist so they can be found in free text,
+	// and only then write everything - otherwise a name could be replaced in one file but not another
+	ui.Info().Msgf("Finding built in names in %v files", len(files))
+	for _, path := range files {
+		switch {
+		case isRawObjectFile(path):
+			err = readRawObjects(path, p.preserveRawObject)
+		case strings.HasSuffix(path, linuxmachine.Suffix):
+			var info linuxmachine.Info
+			if info, err = readLinuxMachine(path); err == nil {
+				p.preserveLinuxMachine(info)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("problem reading %v: %v", path, err)
+		}
+	}
+
+	ui.Info().Msg("Finding names to replace")
+	for _, path := range files {
+		if err = p.anonymizeFile(path, ""); err != nil {
+			return fmt.Errorf("problem reading %v: %v", path, err)
+		}
+	}
+
+	ui.Info().Msgf("Writing pseudonymized data to %v", outputpath)
+	for _, path := range files {
+		relative, err := filepath.Rel(datapath, path)
+		if err != nil {
+			return err
+		}
+		outpath := filepath.Join(outputpath, p.path(relative))
+		if err = os.MkdirAll(filepath.Dir(outpath), 0755); err != nil {
+			return err
+		}
+		if err = p.anonymizeFile(path, outpath); err != nil {
+			return fmt.Errorf("problem anonymizing %v: %v", path, err)
+		}
+	}
+	ui.Info().Msgf("Done, %v files written", len(files))
+	return nil
+}
+
+// Nmap XML can only be told from other XML files by the contents, so those are checked when reading
+func isPortScanFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, portscan.MasscanSuffix) || strings.HasSuffix(lower, portscan.NmapSuffix)
+}
+
+func isRawObjectFile(path string) bool {
+	return strings.HasSuffix(path, objectsSuffix) || strings.HasSuffix(path, activedirectory.DeltaSuffix)
+}
+
+// Pseudonymizes a file, and writes it to outpath unless that is empty
+func (p *Pseudonymizer) anonymizeFile(path, outpath string) error {
+	switch {
+	case isRawObjectFile(path):
+		var objects []*activedirectory.RawObject
+		err := readRawObjects(path, func(ro *activedirectory.RawObject) {
+			anonymized := p.RawObject(ro)
+			if outpath != "" {
+				objects = append(objects, anonymized)
+			}
+		})
+		if err != nil || outpath == "" {
+			return err
+		}
+		return writeRawObjects(outpath, objects)
+	case strings.HasSuffix(path, gpoSuffix):
+		raw, err := encryption.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var gpo activedirectory.GPOdump
+		if err = json.Unmarshal(raw, &gpo); err != nil {
+			return err
+		}
+		gpo = p.GPO(gpo)
+		if outpath == "" {
+			return nil
+		}
+		data, err := json.MarshalIndent(gpo, "", "  ")
+		if err != nil {
+			return err
+		}
+		return encryption.WriteFile(outpath, data, 0644)
+	case strings.HasSuffix(path, localmachine.Suffix):
+		raw, err := encryption.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var info localmachine.Info
+		if err = easyjson.Unmarshal(raw, &info); err != nil {
+			return err
+		}
+		info = p.LocalMachine(info)
+		if outpath == "" {
+			return nil
+		}
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		return encryption.WriteFile(outpath, data, 0644)
+	case strings.HasSuffix(path, linuxmachine.Suffix):
+		info, err := readLinuxMachine(path)
+		if err != nil {
+			return err
+		}
+		info = p.LinuxMachine(info)
+		if outpath == "" {
+			return nil
+		}
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		return encryption.WriteFile(outpath, data, 0644)
+	case isPortScanFile(path):
+		raw, err := encryption.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		masscan := strings.HasSuffix(strings.ToLower(path), portscan.MasscanSuffix)
+		var scan portscan.Scan
+		switch {
+		case masscan:
+			scan, err = portscan.ParseMasscan(raw)
+		case portscan.IsNmap(raw):
+			scan, err = portscan.ParseNmap(raw)
+		default:
+			if outpath != "" {
+				ui.Warn().Msgf("Skipping %v, it is not an Nmap scan and is not copied to the output", path)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		scan = p.PortScan(scan)
+		if outpath == "" {
+			return nil
+		}
+		var data []byte
+		if masscan {
+			data, err = scan.MarshalMasscan()
+		} else {
+			data, err = scan.MarshalNmap()
+		}
+		if err != nil {
+			return err
+		}
+		return encryption.WriteFile(outpath, data, 0644)
+	}
+	return nil
+}
+
+func readLinuxMachine(path string) (linuxmachine.Info, error) {
+	var info linuxmachine.Info
+	raw, err := encryption.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	err = easyjson.Unmarshal(raw, &info)
+	return info, err
+}
+
+func readRawObjects(path string, each func(ro *activedirectory.RawObject)) error {
+	infile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer infile.Close()
+
+	decrypted, err := encryption.NewReader(infile)
+	if err != nil {
+		return err
+	}
+	d := msgp.NewReaderSize(lz4.NewReader(decrypted), 4*1024*1024)
+
+	for {
+		var ro activedirectory.RawObject
+		err = ro.DecodeMsg(d)
+		if msgp.Cause(err) == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		each(&ro)
+	}
+}
+
+func writeRawObjects(path string, objects []*activedirectory.RawObject) error {
+	outfile, err := encryption.Create(path)
+	if err != nil {
+		return err
+	}
+	boutfile := lz4.NewWriter(outfile)
+	boutfile.Apply(
+		lz4.BlockChecksumOption(true),
+		lz4.ChecksumOption(true),
+		lz4.CompressionLevelOption(lz4.Level9),
+		lz4.ConcurrencyOption(-1),
+	)
+	e := msgp.NewWriter(boutfile)
+
+	for _, ro := range objects {
+		if err = ro.EncodeMsg(e); err != nil {
+			outfile.Close()
+			return err
+		}
+	}
+	if err = e.Flush(); err != nil {
+		outfile.Close()
+		return err
+	}
+	if err = boutfile.Close(); err != nil {
+		outfile.Close()
+		return err
+	}
+	return outfile.Close()
+}
+
+// The names of collected files contain naming contexts, machine and domain names
+func (p *Pseudonymizer) path(relative string) string {
+	parts := strings.Split(filepath.ToSlash(relative), "/")
+	for i, part := range parts[:len(parts)-1] {
+		parts[i] = p.Replace(part)
+	}
+
+	name := parts[len(parts)-1]
+	for _, suffix := range []string{objectsSuffix, activedirectory.DeltaSuffix, gpoSuffix, localmachine.Suffix, linuxmachine.Suffix,
+		portscan.MasscanSuffix, portscan.NmapSuffix} {
+		prefix, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+		switch {
+		case suffix == localmachine.Suffix, suffix == linuxmachine.Suffix:
+			// machine$domain or just machine
+			machine, domain, hasdomain := strings.Cut(prefix, "$")
+			prefix = p.Name(machine)
+			if hasdomain {
+				prefix += "$" + p.DNS(domain)
+			}
+		case suffix == gpoSuffix:
+			// GPO GUID
+		case dnPattern.MatchString(prefix):
+			// Naming context followed by .part0001, .RootDSE or the time of an incremental collection
+			context, rest, _ := strings.Cut(prefix, ".")
+			prefix = p.DN(context)
+			if rest != "" {
+				prefix += "." + rest
+			}
+		default:
+			prefix = p.Replace(prefix)
+		}
+		name = prefix + suffix
+		break
+	}
+	parts[len(parts)-1] = name
+	return filepath.FromSlash(strings.Join(parts, "/"))
+}
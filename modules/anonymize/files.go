@@ -0,0 +1,498 @@
+package anonymize
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+func (p *Pseudonymizer) GPO(gpo activedirectory.GPOdump) activedirectory.GPOdump {
+	gpo.DomainDN = p.DN(gpo.DomainDN)
+	gpo.DomainNetbios = p.Name(gpo.DomainNetbios)
+	gpo.Path = p.Replace(gpo.Path)
+
+	files := make([]activedirectory.GPOfileinfo, len(gpo.Files))
+	for i, file := range gpo.Files {
+		file.OwnerSID = p.SID(file.OwnerSID)
+		if file.DACL != nil {
+			file.DACL = p.SecurityDescriptor(file.DACL)
+		}
+		if len(file.Contents) > 0 {
+			file.Contents = p.fileContents(file.Contents)
+			file.Size = int64(len(file.Contents))
+		}
+		files[i] = file
+	}
+	gpo.Files = files
+	return gpo
+}
+
+// Text files (UTF-8 or UTF-16 with BOM like GptTmpl.inf) get names, SIDs and passwords replaced, binary files are emptied
+func (p *Pseudonymizer) fileContents(contents []byte) []byte {
+	if len(contents) == 0 {
+		return contents
+	}
+	if len(contents) >= 2 && contents[0] == 0xff && contents[1] == 0xfe && len(contents)%2 == 0 {
+		units := make([]uint16, 0, len(contents)/2-1)
+		for i := 2; i < len(contents); i += 2 {
+			units = append(units, uint16(contents[i])|uint16(contents[i+1])<<8)
+		}
+		replaced := utf16.Encode([]rune(p.Replace(string(utf16.Decode(units)))))
+		result := make([]byte, 2, 2+len(replaced)*2)
+		copy(result, contents[:2])
+		for _, unit := range replaced {
+			result = append(result, byte(unit), byte(unit>>8))
+		}
+		return result
+	}
+	if utf8.Valid(contents) && !bytes.ContainsRune(contents, 0) {
+		return []byte(p.Replace(string(contents)))
+	}
+	return nil
+}
+
+func (p *Pseudonymizer) LocalMachine(info localmachine.Info) localmachine.Info {
+	m := &info.Machine
+	m.Name = p.Name(m.Name)
+	m.LocalSID = p.SIDString(m.LocalSID)
+	m.Domain = p.DNS(m.Domain)
+	m.ComputerDomainSID = p.SIDString(m.ComputerDomainSID)
+	m.DefaultUsername = p.Account(m.DefaultUsername)
+	m.DefaultDomain = p.DNS(m.DefaultDomain)
+	m.AltDefaultUsername = p.Account(m.AltDefaultUsername)
+	m.AltDefaultDomain = p.DNS(m.AltDefaultDomain)
+	m.AppCache = nil // Paths of executables that were run, and analysis does not use it
+	m.SCCMLastValidMP = p.URL(m.SCCMLastValidMP)
+	siteaccounts := make([]string, len(m.SCCMSiteAccounts))
+	for i, account := range m.SCCMSiteAccounts {
+		siteaccounts[i] = p.Account(account)
+	}
+	m.SCCMSiteAccounts = siteaccounts
+	m.WUServer = p.URL(m.WUServer)
+	m.WUStatusServer = p.URL(m.WUStatusServer)
+	m.PathDirectories = p.pathSecurities(m.PathDirectories)
+
+	interfaces := make([]localmachine.NetworkInterfaceInfo, len(info.Network.NetworkInterfaces))
+	for i, ni := range info.Network.NetworkInterfaces {
+		ni.MACAddress = p.MAC(ni.MACAddress)
+		addresses := make([]string, len(ni.Addresses))
+		for j, address := range ni.Addresses {
+			ip, mask, hasmask := strings.Cut(address, "/")
+			addresses[j] = p.IP(ip)
+			if hasmask {
+				addresses[j] += "/" + mask
+			}
+		}
+		ni.Addresses = addresses
+		interfaces[i] = ni
+	}
+	info.Network.NetworkInterfaces = interfaces
+
+	info.LoginPopularity.Day = p.loginCounts(info.LoginPopularity.Day)
+	info.LoginPopularity.Week = p.loginCounts(info.LoginPopularity.Week)
+	info.LoginPopularity.Month = p.loginCounts(info.LoginPopularity.Month)
+
+	users := make(localmachine.Users, len(info.Users))
+	for i, user := range info.Users {
+		if !isWellKnownSIDString(user.SID) {
+			user.Name = p.Name(user.Name)
+			user.FullName = p.Scramble(user.FullName)
+		}
+		user.SID = p.SIDString(user.SID)
+		users[i] = user
+	}
+	info.Users = users
+
+	groups := make(localmachine.Groups, len(info.Groups))
+	for i, group := range info.Groups {
+		if !isWellKnownSIDString(group.SID) {
+			group.Name = p.Name(group.Name)
+			group.Comment = p.Scramble(group.Comment)
+		}
+		group.SID = p.SIDString(group.SID)
+		members := make([]localmachine.Member, len(group.Members))
+		for j, member := range group.Members {
+			members[j] = localmachine.Member{
+				Name: p.Account(member.Name),
+				SID:  p.SIDString(member.SID),
+			}
+		}
+		group.Members = members
+		groups[i] = group
+	}
+	info.Groups = groups
+
+	shares := make(localmachine.Shares, len(info.Shares))
+	for i, share := range info.Shares {
+		if !strings.HasSuffix(share.Name, "$") && !strings.EqualFold(share.Name, "NETLOGON") && !strings.EqualFold(share.Name, "SYSVOL") {
+			share.Name = p.Name(share.Name)
+			share.Remark = p.Scramble(share.Remark)
+		}
+		share.Path = p.Replace(share.Path)
+		share.DACL = p.securityDescriptorIfSet(share.DACL)
+		share.PathDACL = p.securityDescriptorIfSet(share.PathDACL)
+		share.PathOwner = p.SIDString(share.PathOwner)
+		shares[i] = share
+	}
+	info.Shares = shares
+
+	services := make(localmachine.Services, len(info.Services))
+	for i, service := range info.Services {
+		service.RegistryOwner = p.SIDString(service.RegistryOwner)
+		service.RegistryDACL = p.securityDescriptorIfSet(service.RegistryDACL)
+		service.Name = p.Replace(service.Name)
+		service.DisplayName = p.Replace(service.DisplayName)
+		service.Description = p.Replace(service.Description)
+		service.ImagePath = p.Replace(service.ImagePath)
+		service.ImageExecutable = p.Replace(service.ImageExecutable)
+		service.ImageExecutableOwner = p.SIDString(service.ImageExecutableOwner)
+		service.ImageExecutableDACL = p.securityDescriptorIfSet(service.ImageExecutableDACL)
+		service.ImageDirectory = p.pathSecurity(service.ImageDirectory)
+		service.UnquotedCandidates = p.pathSecurities(service.UnquotedCandidates)
+		service.Account = p.Account(service.Account)
+		service.AccountSID = p.SIDString(service.AccountSID)
+		services[i] = service
+	}
+	info.Services = services
+
+	software := make([]localmachine.Software, len(info.Software))
+	for i, s := range info.Software {
+		s.InstallSource = p.Replace(s.InstallSource)
+		s.InstallLocation = p.Replace(s.InstallLocation)
+		s.UninstallString = p.Replace(s.UninstallString)
+		software[i] = s
+	}
+	info.Software = software
+
+	tasks := make([]localmachine.RegisteredTask, len(info.Tasks))
+	for i, task := range info.Tasks {
+		task.Name = p.Replace(task.Name)
+		task.Path = p.Replace(task.Path)
+		d := &task.Definition
+		actions := make([]localmachine.TaskAction, len(d.Actions))
+		for j, action := range d.Actions {
+			action.PathDACL = p.securityDescriptorIfSet(action.PathDACL)
+			action.PathOwner = p.SIDString(action.PathOwner)
+			action.Path = p.Replace(action.Path)
+			action.Args = p.Replace(action.Args)
+			action.WorkingDir = p.Replace(action.WorkingDir)
+			actions[j] = action
+		}
+		d.Actions = actions
+		d.Data = p.Replace(d.Data)
+		d.Principal.Name = p.Replace(d.Principal.Name)
+		d.Principal.GroupID = p.Account(d.Principal.GroupID)
+		d.Principal.UserID = p.Account(d.Principal.UserID)
+		d.RegistrationInfo.Author = p.Account(d.RegistrationInfo.Author)
+		d.RegistrationInfo.Description = p.Replace(d.RegistrationInfo.Description)
+		d.RegistrationInfo.SecurityDescriptor = p.Replace(d.RegistrationInfo.SecurityDescriptor)
+		d.RegistrationInfo.Source = p.Replace(d.RegistrationInfo.Source)
+		d.RegistrationInfo.URI = p.Replace(d.RegistrationInfo.URI)
+		triggers := make([]string, len(d.Triggers))
+		for j, trigger := range d.Triggers {
+			triggers[j] = p.Replace(trigger)
+		}
+		d.Triggers = triggers
+		d.XMLText = p.Replace(d.XMLText)
+		tasks[i] = task
+	}
+	info.Tasks = tasks
+
+	privileges := make(localmachine.Privileges, len(info.Privileges))
+	for i, privilege := range info.Privileges {
+		sids := make([]string, len(privilege.AssignedSIDs))
+		for j, sid := range privilege.AssignedSIDs {
+			sids[j] = p.SIDString(sid)
+		}
+		privilege.AssignedSIDs = sids
+		privileges[i] = privilege
+	}
+	info.Privileges = privileges
+
+	return info
+}
+
+// System accounts and groups (root, daemon, wheel, ...) are the same on every Linux machine
+const linuxFirstRegularID = 1000
+
+// Learn the Linux system account and group names, so they're kept wherever they appear
+func (p *Pseudonymizer) preserveLinuxMachine(info linuxmachine.Info) {
+	for _, user := range info.Users {
+		if user.UID < linuxFirstRegularID {
+			p.Preserve(user.Name)
+		}
+	}
+	for _, group := range info.Groups {
+		if group.GID < linuxFirstRegularID {
+			p.Preserve(group.Name)
+		}
+	}
+}
+
+func (p *Pseudonymizer) LinuxMachine(info linuxmachine.Info) linuxmachine.Info {
+	m := &info.Machine
+	m.Name = p.Name(m.Name)
+	m.DNSHostName = p.DNS(m.DNSHostName)
+	m.Domain = p.Name(m.Domain)
+	m.Realm = p.DNS(m.Realm)
+	m.MachineAccount = p.Name(m.MachineAccount)
+
+	interfaces := make([]linuxmachine.NetworkInterfaceInfo, len(info.Network.NetworkInterfaces))
+	for i, ni := range info.Network.NetworkInterfaces {
+		ni.MACAddress = p.MAC(ni.MACAddress)
+		addresses := make([]string, len(ni.Addresses))
+		for j, address := range ni.Addresses {
+			ip, mask, hasmask := strings.Cut(address, "/")
+			addresses[j] = p.IP(ip)
+			if hasmask {
+				addresses[j] += "/" + mask
+			}
+		}
+		ni.Addresses = addresses
+		interfaces[i] = ni
+	}
+	info.Network.NetworkInterfaces = interfaces
+
+	for _, counts := range []*[]linuxmachine.LoginCount{&info.LoginPopularity.Day, &info.LoginPopularity.Week, &info.LoginPopularity.Month} {
+		result := make([]linuxmachine.LoginCount, len(*counts))
+		for i, count := range *counts {
+			count.Name = p.Account(count.Name)
+			result[i] = count
+		}
+		*counts = result
+	}
+
+	sessions := make([]linuxmachine.Session, len(info.ActiveSessions))
+	for i, session := range info.ActiveSessions {
+		session.Name = p.Account(session.Name)
+		session.Host = p.host(session.Host)
+		sessions[i] = session
+	}
+	info.ActiveSessions = sessions
+
+	users := make([]linuxmachine.User, len(info.Users))
+	for i, user := range info.Users {
+		user.Name = p.Account(user.Name)
+		user.GECOS = p.Scramble(user.GECOS)
+		user.Home = p.Replace(user.Home)
+		users[i] = user
+	}
+	info.Users = users
+
+	groups := make([]linuxmachine.Group, len(info.Groups))
+	for i, group := range info.Groups {
+		group.Name = p.Account(group.Name)
+		group.Members = p.accounts(group.Members)
+		groups[i] = group
+	}
+	info.Groups = groups
+
+	rules := make([]linuxmachine.SudoRule, len(info.Sudoers))
+	for i, rule := range info.Sudoers {
+		rule.Source = p.Replace(rule.Source)
+		rule.Users = p.sudoPrincipals(rule.Users)
+		rule.RunAs = p.sudoPrincipals(rule.RunAs)
+		hosts := make([]string, len(rule.Hosts))
+		for j, host := range rule.Hosts {
+			hosts[j] = p.host(host)
+		}
+		rule.Hosts = hosts
+		commands := make([]string, len(rule.Commands))
+		for j, command := range rule.Commands {
+			commands[j] = p.Replace(command)
+		}
+		rule.Commands = commands
+		rules[i] = rule
+	}
+	info.Sudoers = rules
+
+	domains := make([]linuxmachine.SSSDomain, len(info.SSSD))
+	for i, domain := range info.SSSD {
+		domain.Name = p.DNS(domain.Name)
+		domain.ADDomain = p.DNS(domain.ADDomain)
+		domain.SimpleAllowUsers = p.accounts(domain.SimpleAllowUsers)
+		domain.SimpleAllowGroups = p.accounts(domain.SimpleAllowGroups)
+		domain.SimpleDenyUsers = p.accounts(domain.SimpleDenyUsers)
+		domain.SimpleDenyGroups = p.accounts(domain.SimpleDenyGroups)
+		domain.ADAccessFilter = p.Replace(domain.ADAccessFilter)
+		domains[i] = domain
+	}
+	info.SSSD = domains
+
+	ssh := &info.SSH
+	ssh.AuthorizedKeysCommand = p.Replace(ssh.AuthorizedKeysCommand)
+	ssh.AuthorizedKeysCommandUser = p.Account(ssh.AuthorizedKeysCommandUser)
+	ssh.AllowUsers = p.accounts(ssh.AllowUsers)
+	ssh.AllowGroups = p.accounts(ssh.AllowGroups)
+	ssh.DenyUsers = p.accounts(ssh.DenyUsers)
+	ssh.DenyGroups = p.accounts(ssh.DenyGroups)
+
+	principals := make([]linuxmachine.Principal, len(info.Keytab))
+	for i, principal := range info.Keytab {
+		if strings.Contains(principal.Name, "/") {
+			principal.Name = p.SPN(principal.Name)
+		} else {
+			principal.Name = p.Name(principal.Name)
+		}
+		principal.Realm = p.DNS(principal.Realm)
+		principals[i] = principal
+	}
+	info.Keytab = principals
+
+	return info
+}
+
+func (p *Pseudonymizer) PortScan(scan portscan.Scan) portscan.Scan {
+	hosts := make([]portscan.Host, len(scan.Hosts))
+	for i, host := range scan.Hosts {
+		addresses := make([]string, len(host.Addresses))
+		for j, address := range host.Addresses {
+			addresses[j] = p.IP(address)
+		}
+		host.Addresses = addresses
+		hostnames := make([]string, len(host.Hostnames))
+		for j, hostname := range host.Hostnames {
+			hostnames[j] = p.DNS(hostname)
+		}
+		host.Hostnames = hostnames
+		hosts[i] = host
+	}
+	scan.Hosts = hosts
+	return scan
+}
+
+// Sudoers users and run as lists: name, %group, %:group, #uid, %#gid, ALL and aliases, all possibly negated with !
+func (p *Pseudonymizer) sudoPrincipals(principals []string) []string {
+	if principals == nil {
+		return nil
+	}
+	result := make([]string, len(principals))
+	for i, principal := range principals {
+		name := strings.TrimLeft(principal, "!%:")
+		prefix := principal[:len(principal)-len(name)]
+		if name == "ALL" || strings.HasPrefix(name, "#") {
+			result[i] = principal
+			continue
+		}
+		result[i] = prefix + p.Account(name)
+	}
+	return result
+}
+
+func (p *Pseudonymizer) accounts(accounts []string) []string {
+	if accounts == nil {
+		return nil
+	}
+	result := make([]string, len(accounts))
+	for i, account := range accounts {
+		result[i] = p.Account(account)
+	}
+	return result
+}
+
+// Host name or address, as found in wtmp and sudoers
+func (p *Pseudonymizer) host(host string) string {
+	if host == "" || host == "ALL" {
+		return host
+	}
+	if net.ParseIP(host) != nil {
+		return p.IP(host)
+	}
+	return p.DNS(host)
+}
+
+// Account handles DOMAIN\user, user@domain, SIDs and plain names
+func (p *Pseudonymizer) Account(account string) string {
+	if account == "" {
+		return account
+	}
+	if domain, user, found := strings.Cut(account, `\`); found {
+		if strings.EqualFold(domain, "NT AUTHORITY") || strings.EqualFold(domain, "NT SERVICE") || strings.EqualFold(domain, "BUILTIN") {
+			return account
+		}
+		return p.Name(domain) + `\` + p.Name(user)
+	}
+	if strings.Contains(account, "@") {
+		return p.Email(account)
+	}
+	if strings.HasPrefix(strings.ToUpper(account), "S-1-") {
+		return p.SIDString(account)
+	}
+	return p.Name(account)
+}
+
+// URL pseudonymizes the host part of http://host:port/path and leaves the rest to Replace
+func (p *Pseudonymizer) URL(url string) string {
+	scheme, rest, found := strings.Cut(url, "://")
+	if !found {
+		return p.Replace(url)
+	}
+	hostport, path, _ := strings.Cut(rest, "/")
+	host, port, hasport := strings.Cut(hostport, ":")
+	if net.ParseIP(host) != nil {
+		host = p.IP(host)
+	} else {
+		host = p.DNS(host)
+	}
+	if hasport {
+		host += ":" + port
+	}
+	result := scheme + "://" + host
+	if path != "" || strings.HasSuffix(rest, "/") {
+		result += "/" + p.Replace(path)
+	}
+	return result
+}
+
+func (p *Pseudonymizer) loginCounts(counts []localmachine.LoginCount) []localmachine.LoginCount {
+	result := make([]localmachine.LoginCount, len(counts))
+	for i, count := range counts {
+		count.Name = p.Account(count.Name)
+		count.SID = p.SIDString(count.SID)
+		result[i] = count
+	}
+	return result
+}
+
+func (p *Pseudonymizer) pathSecurity(ps localmachine.PathSecurity) localmachine.PathSecurity {
+	ps.Path = p.Replace(ps.Path)
+	ps.Owner = p.SIDString(ps.Owner)
+	ps.DACL = p.securityDescriptorIfSet(ps.DACL)
+	return ps
+}
+
+func (p *Pseudonymizer) pathSecurities(pss []localmachine.PathSecurity) []localmachine.PathSecurity {
+	if pss == nil {
+		return nil
+	}
+	result := make([]localmachine.PathSecurity, len(pss))
+	for i, ps := range pss {
+		result[i] = p.pathSecurity(ps)
+	}
+	return result
+}
+
+func (p *Pseudonymizer) securityDescriptorIfSet(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	return p.SecurityDescriptor(data)
+}
+
+func isWellKnownSIDString(s string) bool {
+	sid, err := windowssecurity.ParseStringSID(s)
+	return err == nil && isWellKnownSID(sid)
+}
+
+// Built in principals, and the accounts and groups every domain and machine has (Administrator, Domain Admins, ...)
+func isWellKnownSID(sid windowssecurity.SID) bool {
+	return sid.Component(2) != 21 || (sid.Components() > 6 && sid.RID() < 1000)
+}
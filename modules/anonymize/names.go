@@ -0,0 +1,39 @@
+package anonymize
+
+// Names of containers and objects that exist in every AD, they tell nothing about the customer and
+// keeping them makes the result look like a real domain
+var wellKnownNames = []string{
+	// Domain partition
+	"Users", "Computers", "Builtin", "System", "Program Data", "Microsoft", "ForeignSecurityPrincipals",
+	"Managed Service Accounts", "Domain Controllers", "Infrastructure", "LostAndFound", "Deleted Objects",
+	"NTDS Quotas", "TPM Devices", "Keys", "AdminSDHolder", "ComPartitions", "ComPartitionSets",
+	"Default Domain Policy", "Dfs-Configuration", "DFSR-GlobalSettings", "Domain System Volume",
+	"DomainUpdates", "Operations", "File Replication Service", "FileLinks", "IP Security", "Meetings",
+	"MicrosoftDNS", "Password Settings Container", "PSPs", "Policies", "Machine", "User",
+	"RAS and IAS Servers Access Check", "RpcServices", "WinsockServices", "WMIPolicy", "SOM",
+	"PolicyTemplate", "PolicyType", "WMIGPO", "DomainDnsZones", "ForestDnsZones", "RootDNSServers",
+	"AppCategories", "Authn Silo Configuration", "AuthN Policy Configuration", "AuthN Policies", "AuthN Silos",
+	"Claims Configuration", "Claim Types", "Resource Properties", "Resource Property Lists", "Value Types",
+	"Central Access Policies", "Central Access Rules", "Group Key Distribution Service", "Master Root Keys",
+	"Server Configuration", "Microsoft Exchange System Objects", "Default Domain Controllers Policy",
+	"SYSVOL Subscription", "DFSR-LocalSettings", "Topology", "Content",
+
+	// Configuration partition
+	"Configuration", "Schema", "Aggregate", "Sites", "Subnets", "Inter-Site Transports", "IP", "SMTP",
+	"Default-First-Site-Name", "Servers", "NTDS Settings", "NTDS Site Settings", "DEFAULTIPSITELINK",
+	"Services", "Partitions", "Extended-Rights", "DisplaySpecifiers", "WellKnown Security Principals",
+	"Physical Locations", "ForestUpdates", "LostAndFoundConfig", "Windows NT", "Directory Service",
+	"Query-Policies", "Default Query Policy", "Optional Features", "Recycle Bin Feature",
+	"Privileged Access Management Feature", "Public Key Services", "AIA", "CDP", "Certificate Templates",
+	"Certification Authorities", "Enrollment Services", "KRA", "OID", "NTAuthCertificates",
+	"Claims Transformation Policies", "Group Key Distribution Service Server Configuration",
+	"MsmqServices", "NetServices", "RRAS", "IdentityDashboard", "Dynamic Access Control Service",
+
+	// Default certificate templates
+	"Administrator", "CA", "CAExchange", "CEPEncryption", "ClientAuth", "CodeSigning", "CrossCA",
+	"CTLSigning", "DirectoryEmailReplication", "DomainController", "DomainControllerAuthentication",
+	"EFS", "EFSRecovery", "EnrollmentAgent", "EnrollmentAgentOffline", "ExchangeUser", "ExchangeUserSignature",
+	"IPSECIntermediateOffline", "IPSECIntermediateOnline", "KerberosAuthentication", "KeyRecoveryAgent",
+	"MachineEnrollmentAgent", "OCSPResponseSigning", "OfflineRouter", "RASAndIASServer", "SmartcardLogon",
+	"SmartcardUser", "SubCA", "UserSignature", "WebServer", "Workstation",
+}
@@ -0,0 +1,374 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"net"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+// Pseudonymizer maps identifying values to replacements derived from a keyed hash, so the same input
+// always gives the same output for a given seed, no matter which file or attribute it appears in
+type Pseudonymizer struct {
+	key []byte
+
+	// Lowercased names that are the same in every AD (built in objects, schema) and are kept as is
+	preserved map[string]struct{}
+
+	// Lowercased real name to pseudonym, used for replacing names found inside other text
+	names map[string]string
+}
+
+func NewPseudonymizer(seed string) *Pseudonymizer {
+	p := &Pseudonymizer{
+		key:       []byte(seed),
+		preserved: make(map[string]struct{}),
+		names:     make(map[string]string),
+	}
+	for _, name := range wellKnownNames {
+		p.Preserve(name)
+	}
+	return p
+}
+
+func (p *Pseudonymizer) mac(kind string, data []byte) []byte {
+	h := hmac.New(sha256.New, p.key)
+	h.Write([]byte(kind))
+	h.Write([]byte{0})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (p *Pseudonymizer) Preserve(name string) {
+	if name != "" {
+		p.preserved[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+func (p *Pseudonymizer) IsPreserved(name string) bool {
+	_, found := p.preserved[strings.ToLower(name)]
+	return found
+}
+
+// Name pseudonymizes a single name like an account, a host name label or the value of an RDN
+func (p *Pseudonymizer) Name(name string) string {
+	if name == "" || p.IsPreserved(name) || isBracedGUID(name) {
+		return name
+	}
+	if sid, err := windowssecurity.ParseStringSID(name); err == nil {
+		return p.SID(sid).String()
+	}
+
+	var suffix string
+	if strings.HasSuffix(name, "$") {
+		// Computer and managed service accounts
+		name = name[:len(name)-1]
+		suffix = "$"
+		if p.IsPreserved(name) {
+			return name + suffix
+		}
+	}
+
+	lower := strings.ToLower(name)
+	pseudonym := "x" + hex.EncodeToString(p.mac("name", []byte(lower))[:6])
+
+	// Remember single words for replacing inside free text, but not tiny or numeric ones that would match all sorts of things
+	if len(lower) >= 3 && strings.IndexFunc(lower, func(r rune) bool { return !isTokenRune(r) }) == -1 && strings.IndexFunc(lower, unicode.IsLetter) != -1 {
+		p.names[lower] = pseudonym
+	}
+	return pseudonym + suffix
+}
+
+// DNS pseudonymizes each label of a host or domain name
+func (p *Pseudonymizer) DNS(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		labels[i] = p.Name(label)
+	}
+	return strings.Join(labels, ".")
+}
+
+// Email pseudonymizes user@domain, also with an address type prefix like SMTP:
+func (p *Pseudonymizer) Email(address string) string {
+	var prefix string
+	if colon := strings.Index(address, ":"); colon != -1 && colon < strings.Index(address, "@") {
+		prefix, address = address[:colon+1], address[colon+1:]
+	}
+	user, domain, found := strings.Cut(address, "@")
+	if !found {
+		return prefix + p.Replace(address)
+	}
+	return prefix + p.Name(user) + "@" + p.DNS(domain)
+}
+
+// SPN pseudonymizes the host and service name parts of serviceclass/host:port/servicename
+func (p *Pseudonymizer) SPN(spn string) string {
+	parts := strings.Split(spn, "/")
+	for i := 1; i < len(parts); i++ {
+		host, port, hasport := strings.Cut(parts[i], ":")
+		switch {
+		case isBracedGUID(host) || isGUID(host):
+		case strings.Contains(host, "="):
+			host = p.DN(host)
+		default:
+			host = p.DNS(host)
+		}
+		if hasport {
+			host += ":" + port
+		}
+		parts[i] = host
+	}
+	return strings.Join(parts, "/")
+}
+
+// DN pseudonymizes the values of all RDNs, keeping the structure intact
+func (p *Pseudonymizer) DN(dn string) string {
+	rdns := splitDN(dn)
+	for i, rdn := range rdns {
+		attr, value, found := strings.Cut(rdn, "=")
+		if !found {
+			continue
+		}
+		unescaped := unescapeDNValue(value)
+
+		// Deleted and conflicting objects have the GUID added after a newline
+		var mangled string
+		if newline := strings.Index(unescaped, "\n"); newline != -1 {
+			unescaped = unescaped[:newline]
+			if escaped := strings.Index(strings.ToUpper(value), `\0A`); escaped != -1 {
+				mangled = value[escaped:]
+			}
+		}
+
+		pseudonym := p.Name(unescaped)
+		if pseudonym == unescaped {
+			// Keep the original escaping
+			continue
+		}
+		rdns[i] = attr + "=" + pseudonym + mangled
+	}
+	return strings.Join(rdns, ",")
+}
+
+// SID replaces the domain part of domain and machine SIDs, keeping the RID so well known accounts and groups are still recognized
+func (p *Pseudonymizer) SID(sid windowssecurity.SID) windowssecurity.SID {
+	// Our representation: 6 bytes authority, then 4 bytes per subauthority - S-1-5-21-a-b-c is 22 bytes
+	if len(sid) < 22 || sid.Component(2) != 21 {
+		return sid
+	}
+	rekeyed := []byte(sid)
+	copy(rekeyed[10:22], p.mac("sid", rekeyed[10:22]))
+	return windowssecurity.SID(rekeyed)
+}
+
+func (p *Pseudonymizer) SIDString(s string) string {
+	sid, err := windowssecurity.ParseStringSID(s)
+	if err != nil {
+		return p.Replace(s)
+	}
+	return p.SID(sid).String()
+}
+
+// SIDBytes handles the Windows binary representation of a SID
+func (p *Pseudonymizer) SIDBytes(data []byte) []byte {
+	if len(data) < 8 || len(data) < 8+4*int(data[1]) {
+		return data
+	}
+	sid, _, err := windowssecurity.BytesToSID(data)
+	if err != nil {
+		return data
+	}
+	return p.SID(sid).Bytes()
+}
+
+func (p *Pseudonymizer) GUID(u uuid.UUID) uuid.UUID {
+	var result uuid.UUID
+	copy(result[:], p.mac("guid", u[:]))
+	result.SetVersion(uuid.V4)
+	result.SetVariant(uuid.VariantRFC4122)
+	return result
+}
+
+// IP maps addresses so that addresses sharing a prefix still share a (different) prefix, keeping subnets recognizable
+func (p *Pseudonymizer) IP(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		return address
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	result := make(net.IP, len(ip))
+	for i := range ip {
+		result[i] = ip[i] ^ p.mac("ip", ip[:i])[0]
+	}
+	return result.String()
+}
+
+// MAC gives a locally administered address, keeping the separators used
+func (p *Pseudonymizer) MAC(address string) string {
+	hw, err := net.ParseMAC(address)
+	if err != nil {
+		return address
+	}
+	fake := p.mac("mac", hw)[:len(hw)]
+	fake[0] = fake[0]&0xfc | 0x02
+	result := hex.EncodeToString(fake)
+	separator := ":"
+	if strings.Contains(address, "-") {
+		separator = "-"
+	}
+	var parts []string
+	for i := 0; i < len(result); i += 2 {
+		part := result[i : i+2]
+		if strings.ToUpper(address) == address {
+			part = strings.ToUpper(part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, separator)
+}
+
+// Scramble replaces letters and digits in free text, keeping punctuation and length so the shape is recognizable
+func (p *Pseudonymizer) Scramble(text string) string {
+	if text == "" {
+		return text
+	}
+	rnd := rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(p.mac("text", []byte(text))))))
+	var result strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			result.WriteByte(byte('0' + rnd.Intn(10)))
+		case r >= 'A' && r <= 'Z':
+			result.WriteByte(byte('A' + rnd.Intn(26)))
+		case unicode.IsUpper(r):
+			result.WriteByte('X')
+		case r >= 'a' && r <= 'z':
+			result.WriteByte(byte('a' + rnd.Intn(26)))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			result.WriteByte('x')
+		default:
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+var (
+	domainSIDPattern = regexp.MustCompile(`(?i)S-1-5-21-\d+-\d+-\d+`)
+	ipv4Pattern      = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+	cpasswordPattern = regexp.MustCompile(`(?i)(cpassword=")([^"]+)(")`)
+)
+
+// Replace handles text that is mostly structure (paths, command lines, configuration files): SIDs, IPv4 addresses,
+// passwords from group policy preferences and all names seen elsewhere are replaced, everything else is kept
+func (p *Pseudonymizer) Replace(text string) string {
+	if text == "" {
+		return text
+	}
+	text = domainSIDPattern.ReplaceAllStringFunc(text, func(match string) string {
+		sid, err := windowssecurity.ParseStringSID(match)
+		if err != nil {
+			// Sub authorities out of range, SIDString would end up back here
+			return p.Scramble(match)
+		}
+		return p.SID(sid).String()
+	})
+	text = ipv4Pattern.ReplaceAllStringFunc(text, func(match string) string {
+		if net.ParseIP(match) == nil {
+			// Version numbers and such
+			return match
+		}
+		return p.IP(match)
+	})
+	text = cpasswordPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := cpasswordPattern.FindStringSubmatch(match)
+		return parts[1] + p.Scramble(parts[2]) + parts[3]
+	})
+
+	var result strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start == -1 {
+			return
+		}
+		token := text[start:end]
+		if pseudonym, found := p.names[strings.ToLower(token)]; found {
+			result.WriteString(pseudonym)
+		} else {
+			result.WriteString(token)
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if isTokenRune(r) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		result.WriteRune(r)
+	}
+	flush(len(text))
+	return result.String()
+}
+
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+func isBracedGUID(s string) bool {
+	return len(s) == 38 && s[0] == '{' && s[37] == '}' && isGUID(s[1:37])
+}
+
+func isGUID(s string) bool {
+	_, err := uuid.FromString(s)
+	return len(s) == 36 && err == nil
+}
+
+// Splits a DN on the commas that are not escaped
+func splitDN(dn string) []string {
+	var rdns []string
+	var start int
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case ',':
+			rdns = append(rdns, dn[start:i])
+			start = i + 1
+		}
+	}
+	return append(rdns, dn[start:])
+}
+
+func unescapeDNValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var result []byte
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			if i+2 < len(value) {
+				if decoded, err := hex.DecodeString(value[i+1 : i+3]); err == nil {
+					result = append(result, decoded[0])
+					i += 2
+					continue
+				}
+			}
+			i++
+		}
+		result = append(result, value[i])
+	}
+	return string(result)
+}
@@ -0,0 +1,67 @@
+package anonymize
+
+import (
+	"encoding/binary"
+
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+// SecurityDescriptor rewrites the SIDs in a self relative security descriptor in place. Replaced SIDs
+// have the same length, so everything else (ACE order, masks, object types, SACL) is kept byte for byte.
+func (p *Pseudonymizer) SecurityDescriptor(data []byte) []byte {
+	if len(data) < 20 || data[0] != 1 {
+		return data
+	}
+	result := make([]byte, len(data))
+	copy(result, data)
+
+	p.rekeySIDAt(result, int(binary.LittleEndian.Uint32(result[4:])))
+	p.rekeySIDAt(result, int(binary.LittleEndian.Uint32(result[8:])))
+	p.rekeyACLAt(result, int(binary.LittleEndian.Uint32(result[12:])))
+	p.rekeyACLAt(result, int(binary.LittleEndian.Uint32(result[16:])))
+	return result
+}
+
+func (p *Pseudonymizer) rekeySIDAt(data []byte, offset int) {
+	if offset <= 0 || offset+2 > len(data) || offset+8+4*int(data[offset+1]) > len(data) {
+		return
+	}
+	sid, _, err := windowssecurity.BytesToSID(data[offset:])
+	if err != nil {
+		return
+	}
+	copy(data[offset+2:], p.SID(sid))
+}
+
+func (p *Pseudonymizer) rekeyACLAt(data []byte, offset int) {
+	if offset <= 0 || offset+8 > len(data) {
+		return
+	}
+	count := int(binary.LittleEndian.Uint16(data[offset+4:]))
+	position := offset + 8
+	for i := 0; i < count && position+8 <= len(data); i++ {
+		acetype := data[position]
+		size := int(binary.LittleEndian.Uint16(data[position+2:]))
+		if size < 8 || position+size > len(data) {
+			return
+		}
+
+		sidoffset := position + 8
+		switch acetype {
+		case 0x05, 0x06, 0x07, 0x08, 0x0b, 0x0c, 0x0f, 0x10:
+			// Object ACEs have flags and up to two GUIDs before the SID
+			flags := binary.LittleEndian.Uint32(data[position+8:])
+			sidoffset += 4
+			if flags&0x01 != 0 {
+				sidoffset += 16
+			}
+			if flags&0x02 != 0 {
+				sidoffset += 16
+			}
+		}
+		if sidoffset < position+size {
+			p.rekeySIDAt(data[:position+size], sidoffset)
+		}
+		position += size
+	}
+}
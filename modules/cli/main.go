@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/felixge/fgtrace"
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
 	"github.com/lkarlslund/adalanche/modules/ui"
 	"github.com/lkarlslund/adalanche/modules/version"
 	"github.com/spf13/cobra"
@@ -37,6 +39,16 @@ var (
 
 	datapath = Root.PersistentFlags().String("datapath", "data", "folder to store and read data")
 
+	// Custom analyzers, Starlark scripts (*.star) and ACL edge rules (*.yaml)
+	RulesPath = Root.PersistentFlags().String("rules", "", "Directory with custom analyzer rules (*.star scripts and *.yaml ACL edge rules)")
+
+	encryptionkey = Root.PersistentFlags().String("encryptionkey", "", "Encrypt/decrypt files in datapath with age key (age1... public key to only encrypt, AGE-SECRET-KEY-1... or identity file to also decrypt), defaults to $"+encryption.KeyEnvironment)
+	passphrase    = Root.PersistentFlags().String("passphrase", "", "Encrypt/decrypt files in datapath with passphrase, defaults to $"+encryption.PassphraseEnvironment)
+
+	storage      = Root.PersistentFlags().String("storage", engine.StorageDefault, "Object storage ("+engine.StorageDefault+", "+engine.StorageColumnar+" which uses less memory on large datasets but is slower to query, or "+engine.StorageDisk+" which moves scalar attribute values to disk to use even less memory, while objects, edges and indexes stay in memory)")
+	storagepath  = Root.PersistentFlags().String("storagepath", "", "Folder for the disk storage database (temporary folder if blank)")
+	storagecache = Root.PersistentFlags().Int("storagecache", engine.DefaultStorageCache, "Number of objects to keep values for in memory with disk storage")
+
 	versionCmd = &cobra.Command{
 		Use:   "version",
 		Short: "Show adalanche version information",
@@ -82,6 +94,15 @@ func Run() error {
 
 	ui.Info().Msg(version.VersionString())
 
+	if err = encryption.Configure(*encryptionkey, *passphrase); err != nil {
+		return err
+	}
+
+	if err = engine.SetStorage(*storage, *storagepath, *storagecache); err != nil {
+		return err
+	}
+	defer engine.CloseStorage()
+
 	if *embeddedprofiler {
 		go func() {
 			err := http.ListenAndServe("localhost:6060", nil)
@@ -0,0 +1,212 @@
+// Package encryption optionally protects the files collectors write to the datapath.
+//
+// Files are either encrypted to an age X25519 recipient, or with a passphrase using scrypt and AES-GCM.
+// Readers recognize both formats from the first bytes of the file, so plain files keep working.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+const (
+	KeyEnvironment        = "ADALANCHE_ENCRYPTIONKEY"
+	PassphraseEnvironment = "ADALANCHE_PASSPHRASE"
+
+	ageMagic = "age-encryption.org/"
+)
+
+var (
+	ErrNoKey = errors.New("file is encrypted, supply the key with --encryptionkey or --passphrase (or the " + KeyEnvironment + " / " + PassphraseEnvironment + " environment variables)")
+
+	recipient  age.Recipient
+	identities []age.Identity
+	passphrase string
+)
+
+// Configure sets up encryption and decryption for the rest of the run. The key is either an age public key
+// (age1...) which only allows writing, an age identity (AGE-SECRET-KEY-1...) which allows both,
+// or the name of a file containing identities. Empty values are taken from the environment.
+func Configure(key, pass string) error {
+	if key == "" {
+		key = os.Getenv(KeyEnvironment)
+	}
+	if pass == "" {
+		pass = os.Getenv(PassphraseEnvironment)
+	}
+
+	recipient = nil
+	identities = nil
+	passphrase = pass
+
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+	case strings.HasPrefix(key, "age1"):
+		r, err := age.ParseX25519Recipient(key)
+		if err != nil {
+			return fmt.Errorf("problem parsing encryption key: %v", err)
+		}
+		recipient = r
+	default:
+		var keydata io.Reader = strings.NewReader(key)
+		if !strings.HasPrefix(strings.ToUpper(key), "AGE-SECRET-KEY-") {
+			f, err := os.Open(key)
+			if err != nil {
+				return fmt.Errorf("problem opening encryption key file: %v", err)
+			}
+			defer f.Close()
+			keydata = f
+		}
+		ids, err := age.ParseIdentities(keydata)
+		if err != nil {
+			return fmt.Errorf("problem parsing encryption key: %v", err)
+		}
+		identities = ids
+		for _, id := range ids {
+			if x, ok := id.(*age.X25519Identity); ok {
+				recipient = x.Recipient()
+				break
+			}
+		}
+	}
+
+	if recipient != nil && passphrase != "" {
+		ui.Warn().Msg("Both encryption key and passphrase given, new files will be encrypted with the key")
+	}
+	if Enabled() {
+		ui.Info().Msg("Files written to the datapath will be encrypted")
+	}
+	return nil
+}
+
+// Enabled reports whether new files will be encrypted
+func Enabled() bool {
+	return recipient != nil || passphrase != ""
+}
+
+// NewWriter encrypts what is written to w if encryption is configured, otherwise it passes data through.
+// Close must be called to finish the encrypted stream, but does not close w.
+func NewWriter(w io.Writer) (io.WriteCloser, error) {
+	switch {
+	case recipient != nil:
+		return age.Encrypt(w, recipient)
+	case passphrase != "":
+		return newPassphraseWriter(w, passphrase)
+	}
+	return nopCloser{w}, nil
+}
+
+// NewReader decrypts r if it is encrypted, otherwise it returns the data as is
+func NewReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(ageMagic))
+	switch {
+	case bytes.Equal(header, []byte(ageMagic)):
+		if len(identities) == 0 {
+			return nil, ErrNoKey
+		}
+		return age.Decrypt(br, identities...)
+	case len(header) >= len(passphraseMagic) && bytes.Equal(header[:len(passphraseMagic)], []byte(passphraseMagic)):
+		if passphrase == "" {
+			return nil, ErrNoKey
+		}
+		return newPassphraseReader(br, passphrase)
+	}
+	return br, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// File is an output file that is encrypted if configured
+type File struct {
+	w      io.WriteCloser
+	f      *os.File
+	closed bool
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+// Flush pushes buffered data to the file where the format allows it, so a partially written file can be read back
+func (f *File) Flush() error {
+	if fl, ok := f.w.(interface{ Flush() error }); ok {
+		return fl.Flush()
+	}
+	return nil
+}
+
+// Close finishes the encrypted stream and closes the file, it is safe to call more than once
+func (f *File) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.w.Close()
+	if closeerr := f.f.Close(); err == nil {
+		err = closeerr
+	}
+	return err
+}
+
+// Create creates the named file, encrypting it if configured
+func Create(path string) (*File, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWriter(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &File{w: w, f: f}, nil
+}
+
+// WriteFile is os.WriteFile with encryption if configured
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	w, err := NewWriter(f)
+	if err == nil {
+		_, err = w.Write(data)
+		if closeerr := w.Close(); err == nil {
+			err = closeerr
+		}
+	}
+	if closeerr := f.Close(); err == nil {
+		err = closeerr
+	}
+	return err
+}
+
+// ReadFile is os.ReadFile that transparently decrypts
+func ReadFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return io.ReadAll(r)
+}
@@ -0,0 +1,259 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Passphrase format:
+//   magic (8) | scrypt log2(N) (1) | salt (16) | nonce prefix (7)
+// followed by chunks:
+//   length with final flag in the top bit (4) | AES-256-GCM sealed data
+// The nonce for a chunk is the prefix, the chunk counter and the final flag, so chunks can not be
+// reordered, dropped or truncated without failing authentication
+
+const (
+	passphraseMagic = "ADLNCHE1"
+	scryptLogN      = 17
+	maxScryptLogN   = scryptLogN + 3 // Work factor accepted from a file header, the memory needed doubles with each step
+	chunkSize       = 64 * 1024
+	finalFlag       = 1 << 31
+)
+
+var ErrCorrupt = errors.New("encrypted data is corrupt or passphrase is wrong")
+
+type derivedKey struct {
+	passphrase string
+	salt       [16]byte
+	logn       int
+}
+
+// Deriving a key takes a good fraction of a second on purpose, so it's done once per passphrase and salt.
+// Writers share one salt per passphrase for the whole run, files differ by their random nonce prefix
+var (
+	keyLock    sync.Mutex
+	keys       = make(map[derivedKey]cipher.AEAD)
+	writerSalt = make(map[string][16]byte)
+)
+
+func deriveKey(passphrase string, salt [16]byte, logn int) (cipher.AEAD, error) {
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	dk := derivedKey{passphrase: passphrase, salt: salt, logn: logn}
+	if aead, found := keys[dk]; found {
+		return aead, nil
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt[:], 1<<logn, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	keys[dk] = aead
+	return aead, nil
+}
+
+func saltFor(passphrase string) ([16]byte, error) {
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	salt, found := writerSalt[passphrase]
+	if !found {
+		if _, err := rand.Read(salt[:]); err != nil {
+			return salt, err
+		}
+		writerSalt[passphrase] = salt
+	}
+	return salt, nil
+}
+
+type passphraseWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   [12]byte
+	counter uint32
+	buffer  []byte
+	closed  bool
+}
+
+func newPassphraseWriter(w io.Writer, passphrase string) (*passphraseWriter, error) {
+	salt, err := saltFor(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(passphraseMagic)+1+16+7)
+	copy(header, passphraseMagic)
+	header[len(passphraseMagic)] = scryptLogN
+	copy(header[len(passphraseMagic)+1:], salt[:])
+	if _, err = rand.Read(header[len(passphraseMagic)+17:]); err != nil {
+		return nil, err
+	}
+	aead, err := deriveKey(passphrase, salt, scryptLogN)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(header); err != nil {
+		return nil, err
+	}
+	pw := &passphraseWriter{
+		w:      w,
+		aead:   aead,
+		buffer: make([]byte, 0, chunkSize),
+	}
+	copy(pw.nonce[:7], header[len(passphraseMagic)+17:])
+	return pw, nil
+}
+
+func (pw *passphraseWriter) Write(p []byte) (int, error) {
+	if pw.closed {
+		return 0, errors.New("write to closed encrypted file")
+	}
+	written := len(p)
+	for len(p) > 0 {
+		n := copy(pw.buffer[len(pw.buffer):cap(pw.buffer)], p)
+		pw.buffer = pw.buffer[:len(pw.buffer)+n]
+		p = p[n:]
+		if len(pw.buffer) == cap(pw.buffer) {
+			if err := pw.seal(false); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Flush writes out what is buffered as a chunk of its own
+func (pw *passphraseWriter) Flush() error {
+	if len(pw.buffer) == 0 || pw.closed {
+		return nil
+	}
+	return pw.seal(false)
+}
+
+func (pw *passphraseWriter) Close() error {
+	if pw.closed {
+		return nil
+	}
+	err := pw.seal(true)
+	pw.closed = true
+	return err
+}
+
+func (pw *passphraseWriter) seal(final bool) error {
+	length := uint32(len(pw.buffer) + pw.aead.Overhead())
+	binary.BigEndian.PutUint32(pw.nonce[7:11], pw.counter)
+	if final {
+		pw.nonce[11] = 1
+		length |= finalFlag
+	}
+	sealed := pw.aead.Seal(nil, pw.nonce[:], pw.buffer, nil)
+	var lengthbytes [4]byte
+	binary.BigEndian.PutUint32(lengthbytes[:], length)
+	if _, err := pw.w.Write(lengthbytes[:]); err != nil {
+		return err
+	}
+	if _, err := pw.w.Write(sealed); err != nil {
+		return err
+	}
+	pw.counter++
+	pw.buffer = pw.buffer[:0]
+	return nil
+}
+
+type passphraseReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	nonce   [12]byte
+	counter uint32
+	plain   []byte
+	done    bool
+}
+
+func newPassphraseReader(r io.Reader, passphrase string) (*passphraseReader, error) {
+	header := make([]byte, len(passphraseMagic)+1+16+7)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrCorrupt
+	}
+	logn := int(header[len(passphraseMagic)])
+	if logn < 1 || logn > maxScryptLogN {
+		return nil, ErrCorrupt
+	}
+	var salt [16]byte
+	copy(salt[:], header[len(passphraseMagic)+1:])
+	aead, err := deriveKey(passphrase, salt, logn)
+	if err != nil {
+		return nil, err
+	}
+	pr := &passphraseReader{
+		r:    r,
+		aead: aead,
+	}
+	copy(pr.nonce[:7], header[len(passphraseMagic)+17:])
+	return pr, nil
+}
+
+func (pr *passphraseReader) Read(p []byte) (int, error) {
+	for len(pr.plain) == 0 {
+		if pr.done {
+			return 0, io.EOF
+		}
+		if err := pr.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, pr.plain)
+	pr.plain = pr.plain[n:]
+	return n, nil
+}
+
+func (pr *passphraseReader) open() error {
+	var lengthbytes [4]byte
+	if _, err := io.ReadFull(pr.r, lengthbytes[:]); err != nil {
+		if err == io.EOF {
+			// Missing the final chunk, so the file was cut short
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	length := binary.BigEndian.Uint32(lengthbytes[:])
+	final := length&finalFlag != 0
+	length &^= finalFlag
+	if length < uint32(pr.aead.Overhead()) || length > chunkSize+uint32(pr.aead.Overhead()) {
+		return ErrCorrupt
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(pr.r, sealed); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	binary.BigEndian.PutUint32(pr.nonce[7:11], pr.counter)
+	pr.nonce[11] = 0
+	if final {
+		pr.nonce[11] = 1
+	}
+	plain, err := pr.aead.Open(sealed[:0], pr.nonce[:], sealed, nil)
+	if err != nil {
+		return fmt.Errorf("chunk %v: %w", pr.counter, ErrCorrupt)
+	}
+	pr.counter++
+	pr.plain = plain
+	pr.done = final
+	return nil
+}
@@ -0,0 +1,149 @@
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+const headerSize = len(passphraseMagic) + 1 + 16 + 7
+
+// A bit more than three chunks, so there are full chunks to shuffle and a short final one
+func testData() []byte {
+	data := make([]byte, 3*chunkSize+1234)
+	rand.New(rand.NewSource(1)).Read(data)
+	return data
+}
+
+func encrypt(t *testing.T, pass string, data []byte) []byte {
+	t.Helper()
+	if err := Configure("", pass); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	w, err := NewWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func decrypt(pass string, encrypted []byte) ([]byte, error) {
+	if err := Configure("", pass); err != nil {
+		return nil, err
+	}
+	r, err := NewReader(bytes.NewReader(encrypted))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// Offset of the chunk in the encrypted data
+func chunkOffset(n int) int {
+	return headerSize + n*(4+chunkSize+16)
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, chunkSize, 3*chunkSize + 1234} {
+		data := testData()[:size]
+		encrypted := encrypt(t, "correct horse", data)
+		if !bytes.HasPrefix(encrypted, []byte(passphraseMagic)) {
+			t.Fatalf("size %v: encrypted data doesn't start with the magic", size)
+		}
+		got, err := decrypt("correct horse", encrypted)
+		if err != nil {
+			t.Fatalf("size %v: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("size %v: decrypted data differs", size)
+		}
+	}
+}
+
+func TestPassphraseDamaged(t *testing.T) {
+	encrypted := encrypt(t, "correct horse", testData())
+
+	reordered := append([]byte{}, encrypted...)
+	copy(reordered[chunkOffset(0):chunkOffset(1)], encrypted[chunkOffset(1):chunkOffset(2)])
+	copy(reordered[chunkOffset(1):chunkOffset(2)], encrypted[chunkOffset(0):chunkOffset(1)])
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[chunkOffset(1)+100] ^= 0x01
+
+	// Dropping the final chunk leaves a valid looking stream that just ends
+	withoutfinal := encrypted[:chunkOffset(3)]
+
+	// A header asking for a huge scrypt work factor would need gigabytes of memory
+	expensive := append([]byte{}, encrypted...)
+	expensive[len(passphraseMagic)] = maxScryptLogN + 1
+	zerowork := append([]byte{}, encrypted...)
+	zerowork[len(passphraseMagic)] = 0
+
+	tests := []struct {
+		name      string
+		pass      string
+		encrypted []byte
+		want      error
+	}{
+		{"wrong passphrase", "battery staple", encrypted, ErrCorrupt},
+		{"tampered", "correct horse", tampered, ErrCorrupt},
+		{"reordered chunks", "correct horse", reordered, ErrCorrupt},
+		{"truncated header", "correct horse", encrypted[:headerSize-1], ErrCorrupt},
+		{"truncated chunk", "correct horse", encrypted[:chunkOffset(1)+1000], io.ErrUnexpectedEOF},
+		{"missing final chunk", "correct horse", withoutfinal, io.ErrUnexpectedEOF},
+		{"work factor too high", "correct horse", expensive, ErrCorrupt},
+		{"no work factor", "correct horse", zerowork, ErrCorrupt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.pass, tt.encrypted)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("decrypt() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPassphraseRequired(t *testing.T) {
+	encrypted := encrypt(t, "correct horse", []byte("secret"))
+	if _, err := decrypt("", encrypted); !errors.Is(err, ErrNoKey) {
+		t.Errorf("decrypt() without passphrase error = %v, want %v", err, ErrNoKey)
+	}
+}
+
+func TestPassphraseKeyReuse(t *testing.T) {
+	first := encrypt(t, "correct horse", []byte("one"))
+	second := encrypt(t, "correct horse", []byte("two"))
+	other := encrypt(t, "battery staple", []byte("three"))
+
+	salt := func(encrypted []byte) []byte { return encrypted[len(passphraseMagic)+1 : len(passphraseMagic)+17] }
+	nonce := func(encrypted []byte) []byte { return encrypted[len(passphraseMagic)+17 : headerSize] }
+	if !bytes.Equal(salt(first), salt(second)) {
+		t.Errorf("files written with the same passphrase have different salts, so the key is derived again")
+	}
+	if bytes.Equal(nonce(first), nonce(second)) {
+		t.Errorf("files written with the same key have the same nonce prefix")
+	}
+	if bytes.Equal(salt(first), salt(other)) {
+		t.Errorf("different passphrases share a salt")
+	}
+
+	before := len(keys)
+	for _, encrypted := range [][]byte{first, second} {
+		if got, err := decrypt("correct horse", encrypted); err != nil || len(got) != 3 {
+			t.Errorf("decrypt() = %q, %v", got, err)
+		}
+	}
+	if len(keys) != before {
+		t.Errorf("reading files with a known salt derived %v new keys", len(keys)-before)
+	}
+}
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+// ACLAnalyzer is prepared once per processing run, and then gets the ACEs in the DACLs of the objects it is interested in
+type ACLAnalyzer struct {
+	Object func(ctx *ACLContext) bool       // Optional, returning false skips the ACEs of this object (Allowed and Trustee are not ready yet)
+	ACE    func(ctx *ACLContext, index int) // Called for each ACE in the DACL
+}
+
+// ACLAnalyzerFunc prepares an analyzer for the objects, returning nil skips it for this run
+type ACLAnalyzerFunc func(ao *Objects) *ACLAnalyzer
+
+type aclAnalyzerInfo struct {
+	af          ACLAnalyzerFunc
+	description string
+	types       []ObjectType
+	priority    ProcessPriority
+	loader      LoaderID
+}
+
+var registeredACLAnalyzers []aclAnalyzerInfo
+
+// AddACLAnalyzer registers an analyzer in the shared ACL evaluation stage for the loader and priority. All analyzers
+// in a stage share one pass over the objects, so each DACL is only visited once. No types means all object types.
+func (l LoaderID) AddACLAnalyzer(af ACLAnalyzerFunc, description string, priority ProcessPriority, types ...ObjectType) {
+	var stageexists bool
+	for _, aai := range registeredACLAnalyzers {
+		if aai.loader == l && aai.priority == priority {
+			stageexists = true
+			break
+		}
+	}
+	registeredACLAnalyzers = append(registeredACLAnalyzers, aclAnalyzerInfo{
+		af:          af,
+		description: description,
+		types:       types,
+		priority:    priority,
+		loader:      l,
+	})
+	if !stageexists {
+		l.AddProcessor(func(ao *Objects) {
+			var stage []aclAnalyzerInfo
+			for _, aai := range registeredACLAnalyzers {
+				if aai.loader == l && aai.priority == priority {
+					stage = append(stage, aai)
+				}
+			}
+			evaluateACLs(ao, stage)
+		}, "Shared ACL evaluation", priority)
+	}
+}
+
+// ACLContext is the object and DACL being evaluated, shared by all analyzers looking at it
+type ACLContext struct {
+	Objects *Objects
+	Object  *Object
+	SD      *SecurityDescriptor
+
+	classes      []uuid.UUID
+	trustees     []*Object
+	propertysets map[uuid.UUID]uuid.UUID
+}
+
+// Allowed is IsObjectClassAccessAllowed for the ACE at index, using the class GUIDs looked up for the object
+func (ctx *ACLContext) Allowed(index int, mask Mask, guid uuid.UUID) bool {
+	return ctx.SD.DACL.isObjectClassAccessAllowed(index, ctx.Object, ctx.classes, ctx.propertysets, mask, guid, ctx.Objects)
+}
+
+// Trustee returns the object for the SID in the ACE at index, it's only looked up once per DACL
+func (ctx *ACLContext) Trustee(index int) *Object {
+	if ctx.trustees[index] == nil {
+		ctx.trustees[index] = ctx.Objects.FindOrAddAdjacentSID(ctx.SD.DACL.Entries[index].SID, ctx.Object)
+	}
+	return ctx.trustees[index]
+}
+
+func evaluateACLs(ao *Objects, stage []aclAnalyzerInfo) {
+	var all []*ACLAnalyzer
+	bytype := make(map[ObjectType][]*ACLAnalyzer)
+	for _, aai := range stage {
+		analyzer := aai.af(ao)
+		if analyzer == nil {
+			ui.Debug().Msgf("Skipping ACL analyzer: %v", aai.description)
+			continue
+		}
+		if len(aai.types) == 0 {
+			all = append(all, analyzer)
+			continue
+		}
+		for _, ot := range aai.types {
+			bytype[ot] = append(bytype[ot], analyzer)
+		}
+	}
+	if len(all) == 0 && len(bytype) == 0 {
+		return
+	}
+
+	workers := runtime.NumCPU()
+	queue := make(chan *Object, workers*64)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			// Each worker has its own scratch space, so nothing here needs locking
+			ctx := ACLContext{
+				Objects:      ao,
+				propertysets: make(map[uuid.UUID]uuid.UUID),
+			}
+			var active []*ACLAnalyzer
+			for o := range queue {
+				sd, err := o.SecurityDescriptor()
+				if err != nil {
+					continue
+				}
+				ctx.Object = o
+				ctx.SD = sd
+
+				active = active[:0]
+				for _, analyzers := range [2][]*ACLAnalyzer{all, bytype[o.Type()]} {
+					for _, analyzer := range analyzers {
+						if analyzer.Object == nil || analyzer.Object(&ctx) {
+							active = append(active, analyzer)
+						}
+					}
+				}
+				if len(active) == 0 || len(sd.DACL.Entries) == 0 {
+					continue
+				}
+
+				// Every ACE restricted to some object classes would otherwise look these up again
+				ctx.classes = ctx.classes[:0]
+				o.Attr(ObjectClassGUIDs).Iterate(func(class AttributeValue) bool {
+					if guid, ok := class.Raw().(uuid.UUID); ok {
+						ctx.classes = append(ctx.classes, guid)
+					}
+					return true
+				})
+				if ctx.classes == nil {
+					ctx.classes = []uuid.UUID{} // Looked up, but there are none
+				}
+				ctx.trustees = slices.Grow(ctx.trustees[:0], len(sd.DACL.Entries))[:len(sd.DACL.Entries)]
+				clear(ctx.trustees)
+
+				for index := range sd.DACL.Entries {
+					for _, analyzer := range active {
+						analyzer.ACE(&ctx, index)
+					}
+				}
+			}
+			wg.Done()
+		}()
+	}
+
+	ao.Iterate(func(o *Object) bool {
+		queue <- o
+		return true
+	})
+	close(queue)
+	wg.Wait()
+}
@@ -0,0 +1,300 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+const (
+	benchObjects   = 20000
+	benchTrustees  = 200
+	benchACEs      = 24
+	benchAnalyzers = 40
+)
+
+var benchEdge = NewEdge("BenchmarkACL")
+
+// Objects with DACLs looking roughly like those in AD: some generic ACEs, and many restricted to properties and
+// object classes, where only a few of the properties are interesting to the analyzers
+func benchmarkACLObjects() (*Objects, []uuid.UUID) {
+	ao := NewObjects()
+
+	guids := make([]uuid.UUID, benchAnalyzers*20)
+	for i := range guids {
+		guids[i] = uuid.Must(uuid.NewV4())
+	}
+	classes := []AttributeValue{AttributeValueGUID(uuid.Must(uuid.NewV4())), AttributeValueGUID(uuid.Must(uuid.NewV4()))}
+
+	trustees := make([]windowssecurity.SID, benchTrustees)
+	for i := range trustees {
+		trustees[i], _ = windowssecurity.ParseStringSID(fmt.Sprintf("S-1-5-21-1-2-3-%v", 1000+i))
+		ao.Add(NewObject(ObjectSid, AttributeValueSID(trustees[i])))
+	}
+
+	for i := 0; i < benchObjects; i++ {
+		sd := &SecurityDescriptor{}
+		for j := 0; j < benchACEs; j++ {
+			ace := ACE{
+				SID:  trustees[(i+j*7)%benchTrustees],
+				Type: ACETYPE_ACCESS_ALLOWED_OBJECT,
+				Mask: RIGHT_DS_READ_PROPERTY | RIGHT_DS_WRITE_PROPERTY,
+			}
+			switch j % 3 {
+			case 0:
+				ace.Type = ACETYPE_ACCESS_ALLOWED
+				ace.Mask = RIGHT_GENERIC_READ
+			case 1:
+				ace.Flags = OBJECT_TYPE_PRESENT
+				ace.ObjectType = guids[(i+j)%len(guids)]
+			case 2:
+				ace.Flags = OBJECT_TYPE_PRESENT | INHERITED_OBJECT_TYPE_PRESENT
+				ace.ObjectType = guids[(i*j)%len(guids)]
+				ace.InheritedObjectType = classes[j%2].Raw().(uuid.UUID)
+			}
+			sd.DACL.Entries = append(sd.DACL.Entries, ace)
+		}
+		ao.Add(NewObject(
+			Type, ObjectTypeUser.ValueString(),
+			ObjectClassGUIDs, classes,
+			NTSecurityDescriptor, AttributeValueSecurityDescriptor{SD: sd},
+		))
+	}
+	return ao, guids[:benchAnalyzers]
+}
+
+// One full pass per analyzer, like separately registered processors
+func BenchmarkACLSeparatePasses(b *testing.B) {
+	ao, guids := benchmarkACLObjects()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var wg sync.WaitGroup
+		for _, guid := range guids {
+			wg.Add(1)
+			go func(guid uuid.UUID) {
+				ao.Iterate(func(o *Object) bool {
+					if o.Type() != ObjectTypeUser {
+						return true
+					}
+					sd, err := o.SecurityDescriptor()
+					if err != nil {
+						return true
+					}
+					for index, acl := range sd.DACL.Entries {
+						if sd.DACL.IsObjectClassAccessAllowed(index, o, RIGHT_DS_WRITE_PROPERTY, guid, ao) {
+							ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, benchEdge)
+						}
+					}
+					return true
+				})
+				wg.Done()
+			}(guid)
+		}
+		wg.Wait()
+	}
+}
+
+// All analyzers in the shared ACL evaluation stage
+func BenchmarkACLSinglePass(b *testing.B) {
+	ao, guids := benchmarkACLObjects()
+	var stage []aclAnalyzerInfo
+	for _, guid := range guids {
+		guid := guid
+		stage = append(stage, aclAnalyzerInfo{
+			af: func(ao *Objects) *ACLAnalyzer {
+				return &ACLAnalyzer{
+					ACE: func(ctx *ACLContext, index int) {
+						if ctx.Allowed(index, RIGHT_DS_WRITE_PROPERTY, guid) {
+							ctx.Trustee(index).EdgeTo(ctx.Object, benchEdge)
+						}
+					},
+				}
+			},
+			types: []ObjectType{ObjectTypeUser},
+		})
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		evaluateACLs(ao, stage)
+	}
+}
+
+// What the analyzers in the equivalence test look for
+var aclEvalChecks = []struct {
+	mask  Mask
+	guid  uuid.UUID
+	types []ObjectType
+}{
+	{mask: RIGHT_GENERIC_ALL},
+	{mask: RIGHT_WRITE_DACL},
+	{mask: RIGHT_DS_WRITE_PROPERTY, guid: uuid.FromStringOrNil("bf9679c0-0de6-11d0-a285-00aa003049e2")}, // Attribute in a property set
+	{mask: RIGHT_DS_WRITE_PROPERTY, guid: uuid.FromStringOrNil("f3a64788-5306-11d1-a9c5-0000f80367c1")}, // Attribute without one
+	{mask: RIGHT_DS_WRITE_PROPERTY, guid: uuid.FromStringOrNil("bc0ac240-79a9-11d0-9020-00c04fc2d4cf")}, // The property set
+	{mask: RIGHT_DS_CONTROL_ACCESS, guid: uuid.FromStringOrNil("00299570-246d-11d0-a768-00aa006e0529"), types: []ObjectType{ObjectTypeUser}},
+	{mask: RIGHT_DS_WRITE_PROPERTY_EXTENDED, guid: uuid.FromStringOrNil("f3a64788-5306-11d1-a9c5-0000f80367c1"), types: []ObjectType{ObjectTypeComputer}},
+}
+
+// Users and computers with DACLs mixing allow and deny, direct and inherited, and ACEs restricted to properties,
+// property sets, extended rights and object classes. The same seed gives the same objects.
+func aclEvalObjects() *Objects {
+	rnd := rand.New(rand.NewSource(1))
+	ao := NewObjects()
+
+	// Schema objects, so property sets can be looked up
+	propertyset := aclEvalChecks[4].guid
+	ao.Add(NewObject(SchemaIDGUID, AttributeValueGUID(aclEvalChecks[2].guid), AttributeSecurityGUID, AttributeValueGUID(propertyset)))
+	ao.Add(NewObject(SchemaIDGUID, AttributeValueGUID(aclEvalChecks[3].guid)))
+
+	classes := map[ObjectType]uuid.UUID{
+		ObjectTypeUser:     uuid.FromStringOrNil("bf967aba-0de6-11d0-a285-00aa003049e2"),
+		ObjectTypeComputer: uuid.FromStringOrNil("bf967a86-0de6-11d0-a285-00aa003049e2"),
+	}
+	objecttypes := []uuid.UUID{uuid.Nil}
+	for _, check := range aclEvalChecks {
+		objecttypes = append(objecttypes, check.guid)
+	}
+	masks := []Mask{RIGHT_GENERIC_ALL, RIGHT_WRITE_DACL, RIGHT_DS_WRITE_PROPERTY, RIGHT_DS_CONTROL_ACCESS, RIGHT_DS_WRITE_PROPERTY_EXTENDED, RIGHT_DS_WRITE_PROPERTY | RIGHT_DS_CONTROL_ACCESS, RIGHT_GENERIC_READ}
+
+	trustees := make([]windowssecurity.SID, 6)
+	for i := range trustees {
+		trustees[i], _ = windowssecurity.ParseStringSID(fmt.Sprintf("S-1-5-21-1-2-3-%v", 1100+i))
+		ao.Add(NewObject(ObjectSid, AttributeValueSID(trustees[i])))
+	}
+
+	for i := 0; i < 300; i++ {
+		ot := ObjectTypeUser
+		if i%3 == 0 {
+			ot = ObjectTypeComputer
+		}
+		sd := SecurityDescriptor{
+			Control: CONTROLFLAG_DACL_PRESENT,
+			DACL:    ACL{Revision: 4},
+		}
+		for j := 0; j < 4+rnd.Intn(10); j++ {
+			ace := ACE{
+				SID:  trustees[rnd.Intn(len(trustees))],
+				Type: []ACEType{ACETYPE_ACCESS_ALLOWED, ACETYPE_ACCESS_ALLOWED_OBJECT, ACETYPE_ACCESS_DENIED, ACETYPE_ACCESS_DENIED_OBJECT}[rnd.Intn(4)],
+				Mask: masks[rnd.Intn(len(masks))],
+			}
+			if rnd.Intn(2) == 0 {
+				ace.ACEFlags = ACEFLAG_INHERITED_ACE
+			}
+			if ace.Type == ACETYPE_ACCESS_ALLOWED_OBJECT || ace.Type == ACETYPE_ACCESS_DENIED_OBJECT {
+				if objecttype := objecttypes[rnd.Intn(len(objecttypes))]; !objecttype.IsNil() {
+					ace.Flags |= OBJECT_TYPE_PRESENT
+					ace.ObjectType = objecttype
+				}
+				if rnd.Intn(3) == 0 {
+					ace.Flags |= INHERITED_OBJECT_TYPE_PRESENT
+					ace.InheritedObjectType = classes[[]ObjectType{ObjectTypeUser, ObjectTypeComputer}[rnd.Intn(2)]]
+				}
+			}
+			sd.DACL.Entries = append(sd.DACL.Entries, ace)
+		}
+
+		// Parsing sorts the DACL and finds the deny ACEs, like for real data
+		parsed, err := ParseSecurityDescriptor(sd.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		ao.Add(NewObject(
+			Name, AttributeValueString(fmt.Sprintf("Object %v", i)),
+			Type, ot.ValueString(),
+			ObjectClassGUIDs, AttributeValueGUID(classes[ot]),
+			NTSecurityDescriptor, AttributeValueSecurityDescriptor{SD: &parsed},
+		))
+	}
+	return ao
+}
+
+func aclEvalEdges(ao *Objects, edges []Edge) []string {
+	var result []string
+	ao.Iterate(func(o *Object) bool {
+		o.Edges(Out).Range(func(target *Object, eb EdgeBitmap) bool {
+			for _, edge := range edges {
+				if eb.IsSet(edge) {
+					result = append(result, fmt.Sprintf("%v --%v--> %v", o.SID(), edge, target.Label()))
+				}
+			}
+			return true
+		})
+		return true
+	})
+	slices.Sort(result)
+	return result
+}
+
+// The shared stage must give the same edges as one IsObjectClassAccessAllowed pass per analyzer
+func TestACLSinglePassMatchesSeparatePasses(t *testing.T) {
+	edges := make([]Edge, len(aclEvalChecks))
+	for i := range aclEvalChecks {
+		edges[i] = NewEdge(fmt.Sprintf("ACLEvalTest%v", i))
+	}
+
+	separate := aclEvalObjects()
+	for i, check := range aclEvalChecks {
+		separate.Iterate(func(o *Object) bool {
+			if len(check.types) > 0 && !slices.Contains(check.types, o.Type()) {
+				return true
+			}
+			sd, err := o.SecurityDescriptor()
+			if err != nil {
+				return true
+			}
+			for index, acl := range sd.DACL.Entries {
+				if sd.DACL.IsObjectClassAccessAllowed(index, o, check.mask, check.guid, separate) {
+					separate.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, edges[i])
+				}
+			}
+			return true
+		})
+	}
+
+	single := aclEvalObjects()
+	var stage []aclAnalyzerInfo
+	for i, check := range aclEvalChecks {
+		i, check := i, check
+		stage = append(stage, aclAnalyzerInfo{
+			af: func(ao *Objects) *ACLAnalyzer {
+				return &ACLAnalyzer{
+					ACE: func(ctx *ACLContext, index int) {
+						if ctx.Allowed(index, check.mask, check.guid) {
+							ctx.Trustee(index).EdgeTo(ctx.Object, edges[i])
+						}
+					},
+				}
+			},
+			types: check.types,
+		})
+	}
+	evaluateACLs(single, stage)
+
+	want := aclEvalEdges(separate, edges)
+	got := aclEvalEdges(single, edges)
+	if len(want) == 0 {
+		t.Fatal("fixture gives no edges")
+	}
+	for _, edge := range edges {
+		if !slices.ContainsFunc(want, func(e string) bool { return strings.Contains(e, " --"+edge.String()+"--> ") }) {
+			t.Errorf("fixture gives no %v edges", edge)
+		}
+	}
+	if !slices.Equal(got, want) {
+		for _, e := range want {
+			if !slices.Contains(got, e) {
+				t.Errorf("missing from single pass: %v", e)
+			}
+		}
+		for _, e := range got {
+			if !slices.Contains(want, e) {
+				t.Errorf("only in single pass: %v", e)
+			}
+		}
+	}
+}
@@ -10,6 +10,8 @@ type AttributeValueMap struct {
 	// m *haxmap.Map[Attribute, AttributeValues]
 	// m gsync.MapOf[Attribute, AttributeValues]
 	m gonk.Gonk[AttributeValuesEvaluator]
+	c *columnarAttributes // Values are in the attribute columns when using columnar storage
+	d *diskAttributes     // Values are on disk when using disk storage
 }
 
 type AttributeValuesEvaluator struct {
@@ -39,6 +41,12 @@ func (avm *AttributeValueMap) init(preloadAttributes int) {
 }
 
 func (avm *AttributeValueMap) Get(a Attribute) (av AttributeValues, found bool) {
+	if avm.d != nil {
+		return avm.d.get(a)
+	}
+	if avm.c != nil {
+		return avm.c.get(a)
+	}
 	// av, found = avm.m.Get(a)
 	// if found && av.Len() == 0 {
 	// 	found = false // workaround until haxmap performance for deletes is fixed
@@ -52,6 +60,14 @@ func (avm *AttributeValueMap) Get(a Attribute) (av AttributeValues, found bool)
 }
 
 func (avm *AttributeValueMap) Set(a Attribute, av AttributeValues) {
+	if avm.d != nil {
+		avm.d.set(a, av)
+		return
+	}
+	if avm.c != nil {
+		avm.c.set(a, av)
+		return
+	}
 	// avm.m.Set(a, av)
 	// avm.m[a] = av
 	// avm.m.Store(a, av)
@@ -60,6 +76,12 @@ func (avm *AttributeValueMap) Set(a Attribute, av AttributeValues) {
 }
 
 func (avm *AttributeValueMap) Len() int {
+	if avm.d != nil {
+		return avm.d.len()
+	}
+	if avm.c != nil {
+		return avm.c.len()
+	}
 	// var count int
 	// avm.m.Range(func(u Attribute, av AttributeValues) bool {
 	// 	// if av.Len() > 0 {
@@ -74,6 +96,14 @@ func (avm *AttributeValueMap) Len() int {
 }
 
 func (avm *AttributeValueMap) Clear(a Attribute) {
+	if avm.d != nil {
+		avm.d.clear(a)
+		return
+	}
+	if avm.c != nil {
+		avm.c.clear(a)
+		return
+	}
 	// avm.m.Set(a, NoValues{}) // Workaround until haxmap performance
 	// delete(avm.m, a)
 	// avm.m.Delete(a)
@@ -82,6 +112,14 @@ func (avm *AttributeValueMap) Clear(a Attribute) {
 }
 
 func (avm *AttributeValueMap) Iterate(f func(attr Attribute, values AttributeValues) bool) {
+	if avm.d != nil {
+		avm.d.iterate(f)
+		return
+	}
+	if avm.c != nil {
+		avm.c.iterate(f)
+		return
+	}
 	avm.m.Range(func(item AttributeValuesEvaluator) bool {
 		return f(item.a, item.v)
 	})
@@ -94,3 +132,13 @@ func (avm *AttributeValueMap) Iterate(f func(attr Attribute, values AttributeVal
 	// 	return f(a, av)
 	// })
 }
+
+// Drops all values, used when the object has been absorbed into another
+func (avm *AttributeValueMap) release() {
+	if avm.c != nil {
+		avm.c.release()
+	}
+	if avm.d != nil {
+		avm.d.release()
+	}
+}
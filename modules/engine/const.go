@@ -24,6 +24,13 @@ const (
 	UAC_TRUSTED_TO_AUTH_FOR_DELEGATION = 0x1000000
 	UAC_PARTIAL_SECRETS_ACCOUNT        = 0x04000000
 
+	// msDS-SupportedEncryptionTypes
+	ENCTYPE_DES_CBC_CRC             = 0x01
+	ENCTYPE_DES_CBC_MD5             = 0x02
+	ENCTYPE_RC4_HMAC                = 0x04
+	ENCTYPE_AES128_CTS_HMAC_SHA1_96 = 0x08
+	ENCTYPE_AES256_CTS_HMAC_SHA1_96 = 0x10
+
 	RIGHT_GENERIC_READ Mask = RIGHT_READ_CONTROL | RIGHT_DS_LIST_CONTENTS | RIGHT_DS_READ_PROPERTY | RIGHT_DS_LIST_OBJECT /*
 		** Mask value is not stored in AD but deduced from mask bits combined **
 		RIGHT_GENERIC_READ = 0x80000000 /*
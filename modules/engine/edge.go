@@ -20,6 +20,10 @@ func (pm Edge) Describe(description string) Edge {
 }
 
 func (pm Edge) Probability(source, target *Object) Probability {
+	if !Reachable(target, "", edgeInfos[pm].ports) {
+		// Port scans say we can't get there
+		return 0
+	}
 	if f := edgeInfos[pm].probability; f != nil {
 		return f(source, target)
 	}
@@ -193,6 +197,7 @@ var edgeInfos []*edgeInfo
 
 type edgeInfo struct {
 	probability                  ProbabilityCalculatorFunction
+	ports                        []int // TCP ports on the target, one of which must be reachable
 	Name                         string
 	Description                  string
 	Tags                         map[string]struct{}
@@ -34,6 +34,13 @@ type ppfInfo struct {
 
 var registeredProcessors []ppfInfo
 
+const (
+	// AllLoaders is passed to Process to run the processors of every loader, used on the merged objects
+	AllLoaders LoaderID = -1
+	// Processors added with AnyLoader run on the objects from every loader before merge, and once on the merged objects after
+	AnyLoader LoaderID = -2
+)
+
 func (l LoaderID) AddProcessor(pf ProcessorFunc, description string, priority ProcessPriority) {
 	registeredProcessors = append(registeredProcessors, ppfInfo{
 		loader:      l,
@@ -43,11 +50,11 @@ func (l LoaderID) AddProcessor(pf ProcessorFunc, description string, priority Pr
 	})
 }
 
-// LoaderID = wildcard
+// Process runs the processors for loader l with the given priority, or those of all loaders if l is AllLoaders
 func Process(ao *Objects, statustext string, l LoaderID, priority ProcessPriority) error {
 	var priorityProcessors []ppfInfo
 	for _, potentialProcessor := range registeredProcessors {
-		if potentialProcessor.loader == l || l == -1 {
+		if potentialProcessor.loader == l || l == AllLoaders || potentialProcessor.loader == AnyLoader {
 			if potentialProcessor.priority == priority {
 				priorityProcessors = append(priorityProcessors, potentialProcessor)
 			}
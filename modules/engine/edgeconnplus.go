@@ -1,11 +1,14 @@
 package engine
 
 import (
+	"sync/atomic"
+
 	"github.com/lkarlslund/gonk"
 )
 
 type EdgeConnectionsPlus struct {
 	gonk.Gonk[Connection]
+	packed atomic.Pointer[[]packedConnection] // Set when edges are packed, see Objects.PackEdges
 }
 
 type Connection struct {
@@ -22,18 +25,50 @@ func (c Connection) LessThan(c2 Connection) bool {
 }
 
 func (ecp *EdgeConnectionsPlus) Range(rf func(o *Object, eb EdgeBitmap) bool) {
+	if packed := ecp.packed.Load(); packed != nil {
+		bitmaps := *packedBitmaps.Load()
+		for _, c := range *packed {
+			if !rf(c.target, bitmaps[c.edges]) {
+				break
+			}
+		}
+		return
+	}
 	ecp.Gonk.Range(func(c Connection) bool {
 		return rf(c.target, c.edges)
 	})
 }
 
+// Get returns the edges to or from the object, if there are any
+func (ecp *EdgeConnectionsPlus) Get(o *Object) (EdgeBitmap, bool) {
+	if packed := ecp.packed.Load(); packed != nil {
+		for _, c := range *packed {
+			if c.target == o {
+				return (*packedBitmaps.Load())[c.edges], true
+			}
+		}
+		return EdgeBitmap{}, false
+	}
+	c, found := ecp.Gonk.Load(Connection{target: o})
+	return c.edges, found
+}
+
+func (ecp *EdgeConnectionsPlus) Len() int {
+	if packed := ecp.packed.Load(); packed != nil {
+		return len(*packed)
+	}
+	return ecp.Gonk.Len()
+}
+
 func (ecp *EdgeConnectionsPlus) del(o *Object) {
+	ecp.unpack()
 	ecp.Gonk.Delete(Connection{
 		target: o,
 	})
 }
 
 func (e *EdgeConnectionsPlus) setEdges(target *Object, edge EdgeBitmap) {
+	e.unpack()
 	e.Gonk.AtomicMutate(Connection{
 		target: target,
 	}, func(c *Connection) {
@@ -42,6 +77,7 @@ func (e *EdgeConnectionsPlus) setEdges(target *Object, edge EdgeBitmap) {
 }
 
 func (e *EdgeConnectionsPlus) clearEdge(target *Object, edge Edge) {
+	e.unpack()
 	e.Gonk.AtomicMutate(Connection{
 		target: target,
 	}, func(c *Connection) {
@@ -50,6 +86,7 @@ func (e *EdgeConnectionsPlus) clearEdge(target *Object, edge Edge) {
 }
 
 func (e *EdgeConnectionsPlus) setEdge(target *Object, edge Edge) {
+	e.unpack()
 	e.Gonk.AtomicMutate(Connection{
 		target: target,
 	}, func(c *Connection) {
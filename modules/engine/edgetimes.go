@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"time"
+)
+
+// EdgeTimes is what we know about when an edge appeared, from replication metadata or similar
+type EdgeTimes struct {
+	FirstSeen    time.Time // When the edge was created
+	PresentSince time.Time // The edge has been there at least since this, but might be older
+}
+
+type edgeTimesKey struct {
+	source, target ObjectID
+	edge           Edge
+}
+
+func (os *Objects) updateEdgeTimes(source, target *Object, edge Edge, uf func(et *EdgeTimes)) {
+	key := edgeTimesKey{source.ID(), target.ID(), edge}
+	os.edgetimesmutex.Lock()
+	if os.edgetimes == nil {
+		os.edgetimes = make(map[edgeTimesKey]EdgeTimes)
+	}
+	et := os.edgetimes[key]
+	uf(&et)
+	os.edgetimes[key] = et
+	os.edgetimesmutex.Unlock()
+}
+
+// SetEdgeFirstSeen registers when the edge was created
+func (os *Objects) SetEdgeFirstSeen(source, target *Object, edge Edge, seen time.Time) {
+	if seen.IsZero() {
+		return
+	}
+	os.updateEdgeTimes(source, target, edge, func(et *EdgeTimes) {
+		if et.FirstSeen.IsZero() || seen.Before(et.FirstSeen) {
+			et.FirstSeen = seen
+		}
+	})
+}
+
+// SetEdgePresentSince registers that the edge existed at the given time, typically because that's when whatever
+// grants it was last changed. The edge could be older than that
+func (os *Objects) SetEdgePresentSince(source, target *Object, edge Edge, since time.Time) {
+	if since.IsZero() {
+		return
+	}
+	os.updateEdgeTimes(source, target, edge, func(et *EdgeTimes) {
+		if et.PresentSince.IsZero() || since.Before(et.PresentSince) {
+			et.PresentSince = since
+		}
+	})
+}
+
+// EdgeTimes returns the earliest times known for any of the edges in the bitmap between source and target
+func (os *Objects) EdgeTimes(source, target *Object, edges EdgeBitmap) (EdgeTimes, bool) {
+	var result EdgeTimes
+	var found bool
+	os.edgetimesmutex.RLock()
+	defer os.edgetimesmutex.RUnlock()
+	if len(os.edgetimes) == 0 {
+		return result, false
+	}
+	for _, edge := range edges.Edges() {
+		et, ok := os.edgetimes[edgeTimesKey{source.ID(), target.ID(), edge}]
+		if !ok {
+			continue
+		}
+		found = true
+		if !et.FirstSeen.IsZero() && (result.FirstSeen.IsZero() || et.FirstSeen.Before(result.FirstSeen)) {
+			result.FirstSeen = et.FirstSeen
+		}
+		if !et.PresentSince.IsZero() && (result.PresentSince.IsZero() || et.PresentSince.Before(result.PresentSince)) {
+			result.PresentSince = et.PresentSince
+		}
+	}
+	return result, found
+}
@@ -0,0 +1,294 @@
+// Package golden runs datasets through the engine and checks the results against expectations in YAML,
+// so changes to analyzers, ACL rules or probabilities that alter the graph are caught by tests
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/analyze"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/generate"
+	"github.com/lkarlslund/adalanche/modules/query"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a dataset and what the analysis of it should give
+type Fixture struct {
+	Description string `yaml:"description"`
+
+	// Either a directory with collected data relative to the fixture file, or options for a generated forest
+	Data     string            `yaml:"data"`
+	Generate *generate.Options `yaml:"generate"`
+
+	Edges   []EdgeExpectation `yaml:"edges"`   // Edges that must exist
+	NoEdges []EdgeExpectation `yaml:"noedges"` // Edges that must not exist
+	Paths   []PathExpectation `yaml:"paths"`
+
+	path string
+}
+
+// EdgeExpectation is written as "source --Edge--> target", where objects are matched on their label and optionally type as "label [Type]".
+// Using the long form the probability of the edge can be checked too
+type EdgeExpectation struct {
+	Edge        string `yaml:"edge"`
+	Probability *int   `yaml:"probability"`
+}
+
+func (ee *EdgeExpectation) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&ee.Edge)
+	}
+	type plain EdgeExpectation
+	return node.Decode((*plain)(ee))
+}
+
+// PathExpectation runs AnalyzeObjects like the UI does, and checks which objects end up in the graph
+type PathExpectation struct {
+	Description    string   `yaml:"description"`
+	Query          string   `yaml:"query"`     // LDAP query for the start of the analysis
+	Direction      string   `yaml:"direction"` // "in" (default, who can reach the targets) or "out"
+	Edges          []string `yaml:"edges"`     // Edges to follow, all of them if empty
+	MaxDepth       int      `yaml:"maxdepth"`
+	MinProbability int      `yaml:"minprobability"`
+	Contains       []string `yaml:"contains"` // Objects that must be in the result, as "label" or "label [Type]"
+	Excludes       []string `yaml:"excludes"` // Objects that must not be in the result
+}
+
+// LoadFixture reads a fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture := Fixture{
+		path: path,
+	}
+	if err = yaml.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("problem parsing fixture %v: %w", path, err)
+	}
+	if (fixture.Data == "") == (fixture.Generate == nil) {
+		return nil, fmt.Errorf("fixture %v must have either data or generate", path)
+	}
+	if fixture.Generate != nil {
+		// Unset options are taken from the defaults
+		options := generate.DefaultOptions()
+		if err = yaml.Unmarshal(data, &struct {
+			Generate *generate.Options `yaml:"generate"`
+		}{&options}); err != nil {
+			return nil, fmt.Errorf("problem parsing fixture %v: %w", path, err)
+		}
+		fixture.Generate = &options
+	}
+	return &fixture, nil
+}
+
+// Objects loads the dataset through the engine, generating it in scratch first if needed, and waits for the analysis to complete
+func (f *Fixture) Objects(scratch string) (*engine.Objects, error) {
+	datapath := filepath.Join(filepath.Dir(f.path), f.Data)
+	if f.Generate != nil {
+		datapath = scratch
+		if err := generate.Generate(datapath, *f.Generate); err != nil {
+			return nil, err
+		}
+	}
+	ao, err := engine.Run(datapath)
+	if err != nil {
+		return nil, err
+	}
+	engine.WaitForAnalysis()
+	return ao, nil
+}
+
+// Check returns a problem for every expectation that isn't met
+func (f *Fixture) Check(ao *engine.Objects) []error {
+	var problems []error
+	for _, ee := range f.Edges {
+		if err := checkEdge(ao, ee, true); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	for _, ee := range f.NoEdges {
+		if err := checkEdge(ao, ee, false); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	for _, pe := range f.Paths {
+		problems = append(problems, checkPath(ao, pe)...)
+	}
+	return problems
+}
+
+// Run checks every fixture matching the glob pattern as a subtest
+func Run(t *testing.T, pattern string) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no fixtures match %v", pattern)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), func(t *testing.T) {
+			fixture, err := LoadFixture(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ao, err := fixture.Objects(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, problem := range fixture.Check(ao) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+var edgeSyntax = regexp.MustCompile(`^(.+?)\s+--(\S+)-->\s+(.+)$`)
+
+func checkEdge(ao *engine.Objects, ee EdgeExpectation, wanted bool) error {
+	parts := edgeSyntax.FindStringSubmatch(strings.TrimSpace(ee.Edge))
+	if parts == nil {
+		return fmt.Errorf("can't parse edge %q, use 'source --Edge--> target'", ee.Edge)
+	}
+	edge := engine.LookupEdge(parts[2])
+	if edge == engine.NonExistingEdge {
+		return fmt.Errorf("%v: unknown edge %v", ee.Edge, parts[2])
+	}
+	sources, err := find(ao, parts[1])
+	if err != nil {
+		return fmt.Errorf("%v: %w", ee.Edge, err)
+	}
+	targets, err := find(ao, parts[3])
+	if err != nil {
+		return fmt.Errorf("%v: %w", ee.Edge, err)
+	}
+
+	var found bool
+	var probabilities []engine.Probability
+	for source := range sources {
+		source.Edges(engine.Out).Range(func(target *engine.Object, eb engine.EdgeBitmap) bool {
+			if targets[target] && eb.IsSet(edge) {
+				found = true
+				probabilities = append(probabilities, edge.Probability(source, target))
+			}
+			return true
+		})
+	}
+
+	switch {
+	case wanted && !found:
+		return fmt.Errorf("%v: edge is missing", ee.Edge)
+	case !wanted && found:
+		return fmt.Errorf("%v: edge exists but shouldn't", ee.Edge)
+	case wanted && ee.Probability != nil:
+		for _, probability := range probabilities {
+			if int(probability) == *ee.Probability {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v: expected probability %v, got %v", ee.Edge, *ee.Probability, probabilities)
+	}
+	return nil
+}
+
+func checkPath(ao *engine.Objects, pe PathExpectation) []error {
+	name := pe.Description
+	if name == "" {
+		name = pe.Query
+	}
+	fail := func(err error) []error {
+		return []error{fmt.Errorf("path %v: %w", name, err)}
+	}
+
+	opts := analyze.NewAnalyzeObjectsOptions()
+	opts.Objects = ao
+	filter, err := query.ParseLDAPQueryStrict(pe.Query, ao)
+	if err != nil {
+		return fail(err)
+	}
+	opts.StartFilter = filter
+	switch strings.ToLower(pe.Direction) {
+	case "", "in":
+		opts.Direction = engine.In
+	case "out":
+		opts.Direction = engine.Out
+	default:
+		return fail(fmt.Errorf("unknown direction %v", pe.Direction))
+	}
+	if len(pe.Edges) > 0 {
+		var edges engine.EdgeBitmap
+		for _, edgename := range pe.Edges {
+			edge := engine.LookupEdge(edgename)
+			if edge == engine.NonExistingEdge {
+				return fail(fmt.Errorf("unknown edge %v", edgename))
+			}
+			edges = edges.Set(edge)
+		}
+		opts.MethodsF, opts.MethodsM, opts.MethodsL = edges, edges, edges
+	}
+	if pe.MaxDepth > 0 {
+		opts.MaxDepth = pe.MaxDepth
+	}
+	opts.MinEdgeProbability = engine.Probability(pe.MinProbability)
+
+	results := analyze.AnalyzeObjects(opts)
+	nodes := results.Graph.Nodes()
+	if len(nodes) == 0 {
+		return fail(errors.New("query found nothing"))
+	}
+
+	var problems []error
+	check := func(specs []string, wanted bool) {
+		for _, spec := range specs {
+			objects, err := find(ao, spec)
+			if err != nil {
+				problems = append(problems, fail(err)...)
+				continue
+			}
+			var found bool
+			for o := range objects {
+				if _, innodes := nodes[o]; innodes {
+					found = true
+				}
+			}
+			if found != wanted {
+				state := "missing from"
+				if found {
+					state = "included in"
+				}
+				problems = append(problems, fail(fmt.Errorf("%v is %v the result", spec, state))...)
+			}
+		}
+	}
+	check(pe.Contains, true)
+	check(pe.Excludes, false)
+	return problems
+}
+
+var objectSyntax = regexp.MustCompile(`^(.+?)(?:\s+\[(\w+)\])?$`)
+
+// Objects with the label, and the type if it's given as "label [Type]"
+func find(ao *engine.Objects, spec string) (map[*engine.Object]bool, error) {
+	parts := objectSyntax.FindStringSubmatch(strings.TrimSpace(spec))
+	label, typename := parts[1], parts[2]
+	result := make(map[*engine.Object]bool)
+	ao.Iterate(func(o *engine.Object) bool {
+		if strings.EqualFold(o.Label(), label) && (typename == "" || strings.EqualFold(o.Type().String(), typename)) {
+			result[o] = true
+		}
+		return true
+	})
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no object matches %v", spec)
+	}
+	return result, nil
+}
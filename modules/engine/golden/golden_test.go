@@ -0,0 +1,14 @@
+package golden_test
+
+import (
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/engine/golden"
+	adanalyze "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	_ "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+)
+
+func TestFixtures(t *testing.T) {
+	adanalyze.LoadACLRules()
+	golden.Run(t, "testdata/*.yaml")
+}
@@ -285,6 +285,8 @@ func (target *Object) AbsorbEx(source *Object, fast bool) {
 
 	target.objecttype = 0 // Recalculate this
 
+	source.values.release()
+
 	// Nom nommed
 	if !source.status.CompareAndSwap(2, 3) {
 		panic("Unpossible absorption mutation occurred")
@@ -968,8 +970,15 @@ func (o *Object) init(preloadAttributes int) {
 	o.id = ObjectID(atomic.AddUint32(&idcounter, 1))
 	// o.edges[In].init()
 	// o.edges[Out].init()
-	if preloadAttributes > 0 {
-		o.values.init(preloadAttributes)
+	switch storage.Load().(string) {
+	case StorageColumnar:
+		o.values.c = newColumnarAttributes(o.id)
+	case StorageDisk:
+		o.values.d = &diskAttributes{id: o.id, storage: diskstorage.Load()}
+	default:
+		if preloadAttributes > 0 {
+			o.values.init(preloadAttributes)
+		}
 	}
 
 	o.status.Store(1)
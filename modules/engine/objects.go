@@ -33,6 +33,9 @@ type Objects struct {
 	indexlock sync.RWMutex
 
 	typecount typestatistics
+
+	edgetimesmutex sync.RWMutex
+	edgetimes      map[edgeTimesKey]EdgeTimes
 }
 
 func NewObjects() *Objects {
@@ -40,6 +40,7 @@ var (
 	ObjectTypePKIEnrollmentService       = NewObjectType("PKIEnrollmentService", "PKI-Enrollment-Service")
 	ObjectTypeCertificationAuthority     = NewObjectType("CertificationAuthority", "Certification-Authority")
 	ObjectTypeForeignSecurityPrincipal   = NewObjectType("ForeignSecurityPrincipal", "Foreign-Security-Principal")
+	ObjectTypePasswordSettings           = NewObjectType("PasswordSettings", "ms-DS-Password-Settings")
 	ObjectTypeService                    = NewObjectType("Service", "Service").SetDefault(Last, false)
 	ObjectTypeExecutable                 = NewObjectType("Executable", "Executable").SetDefault(Last, false)
 	ObjectTypeDirectory                  = NewObjectType("Directory", "Directory").SetDefault(Last, false)
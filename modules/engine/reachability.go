@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Port scan results on machines, values are "3389/tcp" or "segment 3389/tcp" where segment is where the scan was done from
+var (
+	OpenPorts       = NewAttribute("openPorts")
+	OpenPortsFrom   = NewAttribute("openPortsFrom")
+	ClosedPortsFrom = NewAttribute("closedPortsFrom") // Only ports that some edge requires
+)
+
+// RequiresPorts marks the edge as needing network access to one of these TCP ports on the target
+func (pm Edge) RequiresPorts(ports ...int) Edge {
+	edgeInfos[pm].ports = ports
+	return pm
+}
+
+func (pm Edge) RequiredPorts() []int {
+	return edgeInfos[pm].ports
+}
+
+// RequiredPorts returns all the TCP ports that any edge requires
+func RequiredPorts() []int {
+	found := make(map[int]struct{})
+	for _, ei := range edgeInfos {
+		for _, port := range ei.ports {
+			found[port] = struct{}{}
+		}
+	}
+	result := make([]int, 0, len(found))
+	for port := range found {
+		result = append(result, port)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Reachable tells if one of the TCP ports is open on the target when scanned from the segment, or from anywhere if
+// segment is blank. Ports that were never scanned count as reachable, so missing scan data doesn't remove anything
+func Reachable(target *Object, segment string, ports []int) bool {
+	if len(ports) == 0 || !target.HasAttr(ClosedPortsFrom) {
+		return true
+	}
+	open := target.Attr(OpenPortsFrom)
+	closed := target.Attr(ClosedPortsFrom)
+	for _, port := range ports {
+		portname := strconv.Itoa(port) + "/tcp"
+		if hasPortFrom(open, segment, portname) || !hasPortFrom(closed, segment, portname) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPortFrom(values AttributeValues, segment, portname string) bool {
+	var found bool
+	values.Iterate(func(value AttributeValue) bool {
+		from, port, _ := strings.Cut(value.String(), " ")
+		found = port == portname && (segment == "" || strings.EqualFold(from, segment))
+		return !found
+	})
+	return found
+}
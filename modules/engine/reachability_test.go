@@ -0,0 +1,50 @@
+package engine
+
+import "testing"
+
+func TestReachable(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      []string // OpenPortsFrom
+		closed    []string // ClosedPortsFrom
+		segment   string
+		ports     []int
+		reachable bool
+	}{
+		{"no scan data", nil, nil, "", []int{3389}, true},
+		{"no ports required", nil, []string{"office 3389/tcp"}, "", nil, true},
+		{"closed", nil, []string{"office 3389/tcp"}, "", []int{3389}, false},
+		{"closed from segment", nil, []string{"office 3389/tcp"}, "office", []int{3389}, false},
+		{"closed from segment is case insensitive", nil, []string{"office 3389/tcp"}, "Office", []int{3389}, false},
+		{"not scanned from segment", nil, []string{"office 3389/tcp"}, "dmz", []int{3389}, true},
+		{"other port closed", nil, []string{"office 445/tcp"}, "", []int{3389}, true},
+		{"open from another segment", []string{"dmz 3389/tcp"}, []string{"office 3389/tcp"}, "", []int{3389}, true},
+		{"open from another segment only", []string{"dmz 3389/tcp"}, []string{"office 3389/tcp"}, "office", []int{3389}, false},
+		{"open from segment", []string{"dmz 3389/tcp"}, []string{"office 3389/tcp"}, "dmz", []int{3389}, true},
+		{"one of the ports open", []string{"office 5985/tcp"}, []string{"office 445/tcp"}, "office", []int{445, 5985}, true},
+		{"one of the ports not scanned", nil, []string{"office 445/tcp"}, "office", []int{445, 5985}, true},
+		{"all ports closed", nil, []string{"office 445/tcp", "office 5985/tcp"}, "office", []int{445, 5985}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := NewObject(IgnoreBlanks, OpenPortsFrom, tt.open, ClosedPortsFrom, tt.closed)
+			if reachable := Reachable(target, tt.segment, tt.ports); reachable != tt.reachable {
+				t.Errorf("reachable is %v, expected %v", reachable, tt.reachable)
+			}
+		})
+	}
+}
+
+func TestProbabilityRequiresPorts(t *testing.T) {
+	edge := NewEdge("ReachabilityTest").RequiresPorts(3389)
+	source := NewObject(Name, "source")
+
+	closed := NewObject(ClosedPortsFrom, "office 3389/tcp")
+	if probability := edge.Probability(source, closed); probability != 0 {
+		t.Errorf("probability is %v with the port closed, expected 0", probability)
+	}
+	unscanned := NewObject(Name, "unscanned")
+	if probability := edge.Probability(source, unscanned); probability != 100 {
+		t.Errorf("probability is %v without scan data, expected 100", probability)
+	}
+}
@@ -13,6 +13,14 @@ import (
 	"github.com/lkarlslund/gonk"
 )
 
+// Global post-processing still running for objects returned from Run
+var postprocessing sync.WaitGroup
+
+// WaitForAnalysis blocks until global post-processing started by Run is done, and all edges are in place
+func WaitForAnalysis() {
+	postprocessing.Wait()
+}
+
 // Loads, processes and merges everything. It's magic, just in code
 func Run(path string) (*Objects, error) {
 	starttime := time.Now()
@@ -90,9 +98,12 @@ func Run(path string) (*Objects, error) {
 	ui.Info().Msgf("Time to UI done in %v", time.Since(starttime))
 
 	// Do global post-processing
-	go func() {
+	postprocessing.Add(1)
+	postprocess := func() {
+		defer postprocessing.Done()
+
 		for priority := AfterMergeLow; priority <= AfterMergeFinal; priority++ {
-			Process(ao, fmt.Sprintf("Postprocessing global objects priority %v", priority.String()), -1, priority)
+			Process(ao, fmt.Sprintf("Postprocessing global objects priority %v", priority.String()), AllLoaders, priority)
 		}
 
 		// Free deduplication map
@@ -161,6 +172,10 @@ func Run(path string) (*Objects, error) {
 			obj.edges[Out].Optimize(gonk.Minimize)
 			return true
 		})
+		if packingEdges() {
+			optimizeColumns()
+			ao.PackEdges()
+		}
 
 		// Force GC
 		runtime.GC()
@@ -169,7 +184,14 @@ func Run(path string) (*Objects, error) {
 		debug.FreeOSMemory()
 
 		gonk.SetGrowStrategy(gonk.FourItems)
-	}()
+	}
+
+	if packingEdges() {
+		// Packing replaces the edge storage of every object, so it's done before anyone else gets to read them
+		postprocess()
+	} else {
+		go postprocess()
+	}
 
 	return ao, err
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 
@@ -368,10 +369,15 @@ var ExtendedRightCertificateEnroll, _ = uuid.FromString("0e10c968-78fb-11d2-90d4
 var ExtendedRightCertificateAutoEnroll, _ = uuid.FromString("a05b8cc2-17bc-4802-a710-e7c15ab866a2")
 
 func (a ACL) IsObjectClassAccessAllowed(index int, testObject *Object, mask Mask, guid uuid.UUID, ao *Objects) bool {
+	return a.isObjectClassAccessAllowed(index, testObject, nil, nil, mask, guid, ao)
+}
+
+// Same as IsObjectClassAccessAllowed, but with the class GUIDs of the object looked up in advance, and a private cache of property sets
+func (a ACL) isObjectClassAccessAllowed(index int, testObject *Object, classes []uuid.UUID, propertysets map[uuid.UUID]uuid.UUID, mask Mask, guid uuid.UUID, ao *Objects) bool {
 	if a.Entries[index].Type == ACETYPE_ACCESS_DENIED || a.Entries[index].Type == ACETYPE_ACCESS_DENIED_OBJECT {
 		return false
 	}
-	if a.Entries[index].matchObjectClassAndGUID(testObject, mask, guid, ao) {
+	if a.Entries[index].matchObjectClassAndGUID(testObject, classes, propertysets, mask, guid, ao) {
 		// It's allowed, unless there's a prior DENY rule that matches
 		if a.containsdeny && index > 0 {
 			allowedSid := a.Entries[index].SID
@@ -415,7 +421,7 @@ func (a ACL) IsObjectClassAccessAllowed(index int, testObject *Object, mask Mask
 					// }
 				}
 
-				if sidmatch && a.Entries[i].matchObjectClassAndGUID(testObject, mask, guid, ao) {
+				if sidmatch && a.Entries[i].matchObjectClassAndGUID(testObject, classes, propertysets, mask, guid, ao) {
 					return false // Access denied
 				}
 			}
@@ -427,8 +433,27 @@ func (a ACL) IsObjectClassAccessAllowed(index int, testObject *Object, mask Mask
 
 var objectSecurityGUIDcache gsync.MapOf[uuid.UUID, uuid.UUID]
 
-// Is the ACE something that allows or denies this type of GUID?
-func (a ACE) matchObjectClassAndGUID(o *Object, requestedAccess Mask, g uuid.UUID, ao *Objects) bool {
+// Returns the property set an attribute belongs to, or UnknownGUID
+func propertySet(g uuid.UUID, ao *Objects) uuid.UUID {
+	cachedset, found := objectSecurityGUIDcache.Load(g)
+	if !found {
+		// Not in cache, let's populate it
+		cachedset = UnknownGUID // Assume failure
+		if s, found := ao.Find(SchemaIDGUID, AttributeValueGUID(g)); found {
+			if set, ok := s.OneAttrRaw(AttributeSecurityGUID).(uuid.UUID); ok {
+				cachedset = set
+				if cachedset.IsNil() {
+					cachedset = UnknownGUID
+				}
+			}
+		}
+		objectSecurityGUIDcache.Store(g, cachedset)
+	}
+	return cachedset
+}
+
+// Is the ACE something that allows or denies this type of GUID? Classes are the objects class GUIDs if already known
+func (a ACE) matchObjectClassAndGUID(o *Object, classes []uuid.UUID, propertysets map[uuid.UUID]uuid.UUID, requestedAccess Mask, g uuid.UUID, ao *Objects) bool {
 	// http://www.selfadsi.org/deep-inside/ad-security-descriptors.htm
 	// Don't to drugs while reading the above ^^^^^
 
@@ -449,19 +474,15 @@ func (a ACE) matchObjectClassAndGUID(o *Object, requestedAccess Mask, g uuid.UUI
 		}
 		if !typematch {
 			// Lets chack if this requested guid is part of a group which is allowed
-			cachedset, found := objectSecurityGUIDcache.Load(g)
-			if !found {
-				// Not in cache, let's populate it
-				cachedset = UnknownGUID // Assume failure
-				if s, found := ao.Find(SchemaIDGUID, AttributeValueGUID(g)); found {
-					if set, ok := s.OneAttrRaw(AttributeSecurityGUID).(uuid.UUID); ok {
-						cachedset = set
-						if cachedset.IsNil() {
-							cachedset = UnknownGUID
-						}
-					}
+			var cachedset uuid.UUID
+			if propertysets != nil {
+				var found bool
+				if cachedset, found = propertysets[g]; !found {
+					cachedset = propertySet(g, ao)
+					propertysets[g] = cachedset
 				}
-				objectSecurityGUIDcache.Store(g, cachedset)
+			} else {
+				cachedset = propertySet(g, ao)
 			}
 			if a.ObjectType == cachedset {
 				typematch = true
@@ -478,6 +499,10 @@ func (a ACE) matchObjectClassAndGUID(o *Object, requestedAccess Mask, g uuid.UUI
 			return false
 		}
 
+		if classes != nil {
+			return slices.Contains(classes, a.InheritedObjectType)
+		}
+
 		result := false
 
 		ocg := o.Attr(ObjectClassGUIDs)
@@ -744,6 +769,57 @@ type SecurityDescriptor struct {
 	Control SecurityDescriptorControlFlag
 }
 
+// Bytes encodes the security descriptor in self relative format. SACLs are not parsed, so they are never included
+func (sd SecurityDescriptor) Bytes() []byte {
+	result := make([]byte, 20)
+	control := (sd.Control | CONTROLFLAG_SELF_RELATIVE) &^ CONTROLFLAG_SACL_PRESENT
+	result[0] = 1 // Revision
+	binary.LittleEndian.PutUint16(result[2:], uint16(control))
+	if !sd.Owner.IsNull() {
+		binary.LittleEndian.PutUint32(result[4:], uint32(len(result)))
+		result = append(result, sd.Owner.Bytes()...)
+	}
+	if !sd.Group.IsNull() {
+		binary.LittleEndian.PutUint32(result[8:], uint32(len(result)))
+		result = append(result, sd.Group.Bytes()...)
+	}
+	if control&CONTROLFLAG_DACL_PRESENT != 0 {
+		binary.LittleEndian.PutUint32(result[16:], uint32(len(result)))
+		result = append(result, sd.DACL.Bytes()...)
+	}
+	return result
+}
+
+func (a ACL) Bytes() []byte {
+	result := make([]byte, 8)
+	result[0] = a.Revision
+	for _, ace := range a.Entries {
+		result = append(result, ace.Bytes()...)
+	}
+	binary.LittleEndian.PutUint16(result[2:], uint16(len(result)))
+	binary.LittleEndian.PutUint16(result[4:], uint16(len(a.Entries)))
+	return result
+}
+
+func (a ACE) Bytes() []byte {
+	result := make([]byte, 8, 64)
+	result[0] = byte(a.Type)
+	result[1] = byte(a.ACEFlags)
+	binary.LittleEndian.PutUint32(result[4:], uint32(a.Mask))
+	if a.Type == ACETYPE_ACCESS_ALLOWED_OBJECT || a.Type == ACETYPE_ACCESS_DENIED_OBJECT {
+		result = binary.LittleEndian.AppendUint32(result, uint32(a.Flags))
+		if a.Flags&OBJECT_TYPE_PRESENT != 0 {
+			result = append(result, util.SwapUUIDEndianess(a.ObjectType).Bytes()...)
+		}
+		if a.Flags&INHERITED_OBJECT_TYPE_PRESENT != 0 {
+			result = append(result, util.SwapUUIDEndianess(a.InheritedObjectType).Bytes()...)
+		}
+	}
+	result = append(result, a.SID.Bytes()...)
+	binary.LittleEndian.PutUint16(result[2:], uint16(len(result)))
+	return result
+}
+
 func (sd *SecurityDescriptor) Equals(sd2 *SecurityDescriptor) bool {
 	return reflect.DeepEqual(sd, sd2)
 }
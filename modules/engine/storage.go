@@ -0,0 +1,374 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/gonk"
+)
+
+// Object storage backends
+const (
+	StorageDefault  = "default"  // Attributes and edges in sorted maps on each object
+	StorageColumnar = "columnar" // Attribute values kept per attribute with each distinct value stored once, edges packed after processing
+	StorageDisk     = "disk"     // Scalar attribute values in a database on disk with the most used objects cached, edges packed after processing. Reduces memory use, it doesn't remove the limit
+)
+
+var (
+	storageLock sync.Mutex
+	storage     atomic.Value // string, the backend used for new objects
+)
+
+func init() {
+	storage.Store(StorageDefault)
+}
+
+// SetStorage selects how new objects keep their attributes and edges. For disk storage the database is created in path
+// (temporary folder if blank), with values for up to cachesize objects kept in memory (default if zero)
+func SetStorage(backend, path string, cachesize int) error {
+	storageLock.Lock()
+	defer storageLock.Unlock()
+	switch backend {
+	case StorageDefault, StorageColumnar:
+	case StorageDisk:
+		ds, err := openDiskStorage(path, cachesize)
+		if err != nil {
+			return err
+		}
+		closeDiskStorage()
+		diskstorage.Store(ds)
+	default:
+		return fmt.Errorf("unknown storage %v, use %v, %v or %v", backend, StorageDefault, StorageColumnar, StorageDisk)
+	}
+	storage.Store(backend)
+	return nil
+}
+
+// CloseStorage closes and removes the disk storage database if it is used
+func CloseStorage() {
+	storageLock.Lock()
+	closeDiskStorage()
+	storage.Store(StorageDefault)
+	storageLock.Unlock()
+}
+
+// Objects have compact edges after processing, unless the default storage is used
+func packingEdges() bool {
+	return storage.Load().(string) != StorageDefault
+}
+
+// Attributes of an object using columnar storage, the values are in the columns
+type columnarAttributes struct {
+	id         ObjectID
+	attributes []Attribute // Sorted
+}
+
+var columnarObjectMutexes [1024]sync.RWMutex
+
+// The values live in the columns and not on the object, so they're removed when the object is garbage collected
+func newColumnarAttributes(id ObjectID) *columnarAttributes {
+	ca := &columnarAttributes{id: id}
+	runtime.SetFinalizer(ca, (*columnarAttributes).release)
+	return ca
+}
+
+func (ca *columnarAttributes) mutex() *sync.RWMutex {
+	return &columnarObjectMutexes[int(ca.id)%len(columnarObjectMutexes)]
+}
+
+// All values of one attribute, with values stored once in a dictionary and objects referring to them by index
+type attributeColumn struct {
+	lock       sync.RWMutex
+	dictionary []AttributeValue
+	lookup     map[AttributeValue]uint32 // Dropped when processing is done, and rebuilt if values are added later
+	single     map[ObjectID]uint32
+	multi      map[ObjectID][]uint32
+}
+
+var (
+	columnsLock sync.RWMutex
+	columns     []*attributeColumn
+)
+
+func column(a Attribute) *attributeColumn {
+	columnsLock.RLock()
+	if int(a) < len(columns) && columns[a] != nil {
+		c := columns[a]
+		columnsLock.RUnlock()
+		return c
+	}
+	columnsLock.RUnlock()
+
+	columnsLock.Lock()
+	if int(a) >= len(columns) {
+		columns = append(columns, make([]*attributeColumn, int(a)-len(columns)+1)...)
+	}
+	if columns[a] == nil {
+		columns[a] = &attributeColumn{
+			lookup: make(map[AttributeValue]uint32),
+			single: make(map[ObjectID]uint32),
+			multi:  make(map[ObjectID][]uint32),
+		}
+	}
+	c := columns[a]
+	columnsLock.Unlock()
+	return c
+}
+
+// Must be called with the column locked
+func (c *attributeColumn) encode(value AttributeValue) uint32 {
+	if c.lookup == nil {
+		c.lookup = make(map[AttributeValue]uint32, len(c.dictionary))
+		for index, existing := range c.dictionary {
+			if reflect.TypeOf(existing).Comparable() {
+				c.lookup[existing] = uint32(index)
+			}
+		}
+	}
+	if reflect.TypeOf(value).Comparable() {
+		if index, found := c.lookup[value]; found {
+			return index
+		}
+		c.lookup[value] = uint32(len(c.dictionary))
+	}
+	c.dictionary = append(c.dictionary, value)
+	return uint32(len(c.dictionary) - 1)
+}
+
+func (c *attributeColumn) set(id ObjectID, values AttributeValues) {
+	c.lock.Lock()
+	delete(c.single, id)
+	delete(c.multi, id)
+	switch values.Len() {
+	case 0:
+	case 1:
+		c.single[id] = c.encode(values.First())
+	default:
+		indexes := make([]uint32, 0, values.Len())
+		values.Iterate(func(value AttributeValue) bool {
+			indexes = append(indexes, c.encode(value))
+			return true
+		})
+		c.multi[id] = indexes
+	}
+	c.lock.Unlock()
+}
+
+func (c *attributeColumn) get(id ObjectID) (AttributeValues, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if index, found := c.single[id]; found {
+		return AttributeValueOne{Value: c.dictionary[index]}, true
+	}
+	if indexes, found := c.multi[id]; found {
+		values := make(AttributeValueSlice, len(indexes))
+		for i, index := range indexes {
+			values[i] = c.dictionary[index]
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+func (c *attributeColumn) clear(id ObjectID) {
+	c.lock.Lock()
+	delete(c.single, id)
+	delete(c.multi, id)
+	c.lock.Unlock()
+}
+
+func (ca *columnarAttributes) get(a Attribute) (AttributeValues, bool) {
+	ca.mutex().RLock()
+	_, found := slices.BinarySearch(ca.attributes, a)
+	ca.mutex().RUnlock()
+	if !found {
+		return nil, false
+	}
+	return column(a).get(ca.id)
+}
+
+func (ca *columnarAttributes) set(a Attribute, values AttributeValues) {
+	ca.mutex().Lock()
+	if index, found := slices.BinarySearch(ca.attributes, a); !found {
+		ca.attributes = slices.Insert(ca.attributes, index, a)
+	}
+	// Column is updated while holding the object lock, so readers never see the attribute without values
+	column(a).set(ca.id, values)
+	ca.mutex().Unlock()
+}
+
+func (ca *columnarAttributes) clear(a Attribute) {
+	ca.mutex().Lock()
+	if index, found := slices.BinarySearch(ca.attributes, a); found {
+		ca.attributes = slices.Delete(ca.attributes, index, index+1)
+		column(a).clear(ca.id)
+	}
+	ca.mutex().Unlock()
+}
+
+func (ca *columnarAttributes) len() int {
+	ca.mutex().RLock()
+	defer ca.mutex().RUnlock()
+	return len(ca.attributes)
+}
+
+func (ca *columnarAttributes) iterate(f func(attr Attribute, values AttributeValues) bool) {
+	ca.mutex().RLock()
+	attributes := slices.Clone(ca.attributes)
+	ca.mutex().RUnlock()
+	for _, a := range attributes {
+		if values, found := column(a).get(ca.id); found {
+			if !f(a, values) {
+				break
+			}
+		}
+	}
+}
+
+// Remove all values, used when the object is absorbed into another or garbage collected
+func (ca *columnarAttributes) release() {
+	ca.mutex().Lock()
+	for _, a := range ca.attributes {
+		column(a).clear(ca.id)
+	}
+	ca.attributes = nil
+	ca.mutex().Unlock()
+}
+
+// Drop the values no object uses anymore and the dictionary lookups, they are only needed while lots of values are added
+func optimizeColumns() {
+	columnsLock.RLock()
+	for _, c := range columns {
+		if c == nil {
+			continue
+		}
+		c.lock.Lock()
+		c.compact()
+		c.lock.Unlock()
+	}
+	columnsLock.RUnlock()
+}
+
+// Must be called with the column locked
+func (c *attributeColumn) compact() {
+	var dictionary []AttributeValue
+	moved := make(map[uint32]uint32, len(c.dictionary))
+	move := func(index uint32) uint32 {
+		if newindex, found := moved[index]; found {
+			return newindex
+		}
+		newindex := uint32(len(dictionary))
+		dictionary = append(dictionary, c.dictionary[index])
+		moved[index] = newindex
+		return newindex
+	}
+	for id, index := range c.single {
+		c.single[id] = move(index)
+	}
+	for _, indexes := range c.multi {
+		for i, index := range indexes {
+			indexes[i] = move(index)
+		}
+	}
+	c.dictionary = slices.Clip(dictionary)
+	c.lookup = nil
+}
+
+// Edges packed into one array per direction, with each distinct combination of edges stored once
+type packedConnection struct {
+	target *Object
+	edges  uint32 // Index in packedBitmaps
+}
+
+var (
+	packLock      sync.Mutex
+	packedBitmaps atomic.Pointer[[]EdgeBitmap]
+)
+
+// PackEdges moves the edges of all objects from their maps into shared compact arrays (compressed sparse rows).
+// It must be called when nothing else is using the objects, adding or removing edges on an object later unpacks it again.
+func (os *Objects) PackEdges() {
+	packLock.Lock()
+	defer packLock.Unlock()
+
+	var bitmaps []EdgeBitmap
+	if existing := packedBitmaps.Load(); existing != nil {
+		bitmaps = slices.Clone(*existing)
+	}
+	bitmapindex := make(map[EdgeBitmap]uint32, len(bitmaps))
+	for i, eb := range bitmaps {
+		bitmapindex[eb] = uint32(i)
+	}
+
+	var total int
+	os.Iterate(func(o *Object) bool {
+		for direction := range o.edges {
+			if o.edges[direction].packed.Load() == nil {
+				total += o.edges[direction].Gonk.Len()
+			}
+		}
+		return true
+	})
+
+	type packing struct {
+		ecp         *EdgeConnectionsPlus
+		connections []packedConnection
+	}
+	var packings []packing
+	all := make([]packedConnection, 0, total)
+	os.Iterate(func(o *Object) bool {
+		for direction := range o.edges {
+			ecp := &o.edges[direction]
+			if ecp.packed.Load() != nil {
+				continue
+			}
+			start := len(all)
+			ecp.Gonk.Range(func(c Connection) bool {
+				if c.edges.IsBlank() {
+					return true
+				}
+				index, found := bitmapindex[c.edges]
+				if !found {
+					index = uint32(len(bitmaps))
+					bitmaps = append(bitmaps, c.edges)
+					bitmapindex[c.edges] = index
+				}
+				all = append(all, packedConnection{target: c.target, edges: index})
+				return true
+			})
+			packings = append(packings, packing{ecp: ecp, connections: all[start:len(all):len(all)]})
+		}
+		return true
+	})
+
+	packedBitmaps.Store(&bitmaps)
+	for _, p := range packings {
+		connections := p.connections
+		p.ecp.packed.Store(&connections)
+		p.ecp.Gonk = gonk.Gonk[Connection]{}
+	}
+	ui.Debug().Msgf("Packed %v edges using %v distinct edge combinations", len(all), len(bitmaps))
+}
+
+func (ecp *EdgeConnectionsPlus) unpack() {
+	if ecp.packed.Load() == nil {
+		return
+	}
+	packLock.Lock()
+	if packed := ecp.packed.Load(); packed != nil {
+		bitmaps := *packedBitmaps.Load()
+		for _, c := range *packed {
+			edges := bitmaps[c.edges]
+			ecp.Gonk.AtomicMutate(Connection{target: c.target}, func(nc *Connection) {
+				nc.edges.AtomicOr(edges)
+			}, true)
+		}
+		ecp.packed.Store(nil)
+	}
+	packLock.Unlock()
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+var storageEdges = []Edge{NewEdge("StorageTestA"), NewEdge("StorageTestB")}
+
+func withStorage(tb testing.TB, backend string) {
+	// Tiny cache for disk storage, so values are written to and read from the database
+	if err := SetStorage(backend, tb.TempDir(), 128); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(CloseStorage)
+}
+
+// Objects looking a bit like users, lots of shared values and a few unique ones
+func storageObjects(count int) *Objects {
+	ao := NewObjects()
+	groups := make([]*Object, 50)
+	for i := range groups {
+		groups[i] = NewObject(Name, AttributeValueString(fmt.Sprintf("Group %v", i)))
+		ao.Add(groups[i])
+	}
+	for i := 0; i < count; i++ {
+		o := NewObject(
+			Name, AttributeValueString(fmt.Sprintf("User %v", i)),
+			DisplayName, AttributeValueString(fmt.Sprintf("User number %v", i)),
+			Type, ObjectTypeUser.ValueString(),
+			Description, AttributeValueString("Regular user account"),
+			DataSource, AttributeValueString("contoso.local"),
+			ObjectClass, []AttributeValue{AttributeValueString("top"), AttributeValueString("person"), AttributeValueString("user")},
+		)
+		ao.Add(o)
+		for j := 0; j < 5; j++ {
+			o.EdgeTo(groups[(i+j)%len(groups)], storageEdges[j%2])
+		}
+	}
+	return ao
+}
+
+func edgesTo(o, target *Object) (edges EdgeBitmap) {
+	o.Edges(Out).Range(func(t *Object, eb EdgeBitmap) bool {
+		if t == target {
+			edges = eb
+			return false
+		}
+		return true
+	})
+	return
+}
+
+func TestStorage(t *testing.T) {
+	for _, storage := range []string{StorageDefault, StorageColumnar, StorageDisk} {
+		t.Run(storage, func(t *testing.T) {
+			withStorage(t, storage)
+
+			o := NewObject(Name, AttributeValueString("one"), ObjectClass, []AttributeValue{AttributeValueString("top"), AttributeValueString("user")})
+			if o.OneAttrString(Name) != "one" || o.Attr(ObjectClass).Len() != 2 {
+				t.Fatalf("unexpected values %v", o.ValueMap())
+			}
+			o.Clear(Name)
+			if o.HasAttr(Name) || o.values.Len() != 1 {
+				t.Fatalf("value not cleared %v", o.ValueMap())
+			}
+
+			source := NewObject(Name, AttributeValueString("two"), Description, AttributeValueString("absorbed"))
+			other := NewObject(Name, AttributeValueString("three"))
+			source.EdgeTo(other, storageEdges[0])
+			o.Absorb(source)
+			if o.OneAttrString(Description) != "absorbed" || !o.HasAttrValue(Name, AttributeValueString("two")) {
+				t.Fatalf("values not absorbed %v", o.ValueMap())
+			}
+			if !slices.Contains(edgesTo(o, other).Edges(), storageEdges[0]) {
+				t.Fatal("edge not moved when absorbing")
+			}
+
+			ao := storageObjects(100)
+			ao.Add(o, other)
+			ao.PackEdges()
+			var count, users int
+			ao.Iterate(func(o *Object) bool {
+				if o.Type() == ObjectTypeUser && o.Attr(ObjectClass).Len() == 3 && o.OneAttrString(Description) == "Regular user account" {
+					users++
+				}
+				o.Edges(Out).Range(func(target *Object, edges EdgeBitmap) bool {
+					count += edges.Count()
+					return true
+				})
+				return true
+			})
+			if users != 100 {
+				t.Fatalf("expected 100 users with all values, got %v", users)
+			}
+			if count != 501 {
+				t.Fatalf("expected 501 edges after packing, got %v", count)
+			}
+
+			// Changes after packing
+			o.EdgeTo(other, storageEdges[1])
+			other.EdgeTo(o, storageEdges[1])
+			if edgesTo(o, other).Count() != 2 || o.Edges(Out).Len() != 1 {
+				t.Fatal("edges lost when adding to packed edges")
+			}
+		})
+	}
+}
+
+func columnLen(c *attributeColumn) (dictionary, objects int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.dictionary), len(c.single) + len(c.multi)
+}
+
+func TestColumnarValues(t *testing.T) {
+	withStorage(t, StorageColumnar)
+	attr := NewAttribute("storageTestColumn")
+
+	keep := NewObject(attr, AttributeValueString("shared"))
+	func() {
+		// Objects that are never added anywhere, their values must go away with them
+		for i := 0; i < 100; i++ {
+			NewObject(attr, []AttributeValue{AttributeValueString("shared"), AttributeValueString(fmt.Sprintf("dropped %v", i))})
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if _, objects := columnLen(column(attr)); objects == 1 {
+			break
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, objects := columnLen(column(attr)); objects != 1 {
+		t.Fatalf("values of %v dropped objects are still in the column", objects-1)
+	}
+
+	optimizeColumns()
+	if dictionary, _ := columnLen(column(attr)); dictionary != 1 {
+		t.Fatalf("expected 1 value left in the dictionary, got %v", dictionary)
+	}
+
+	// Values added after processing are still stored once
+	added := make([]*Object, 10)
+	for i := range added {
+		added[i] = NewObject(attr, AttributeValueString("shared"))
+	}
+	if dictionary, objects := columnLen(column(attr)); dictionary != 1 || objects != 11 {
+		t.Fatalf("expected 1 value for 11 objects, got %v values for %v objects", dictionary, objects)
+	}
+	runtime.KeepAlive(added)
+	if keep.OneAttrString(attr) != "shared" {
+		t.Fatalf("value lost, got %v", keep.ValueMap())
+	}
+}
+
+func benchmarkStorageMemory(b *testing.B, storage string) {
+	withStorage(b, storage)
+	var ms runtime.MemStats
+	for n := 0; n < b.N; n++ {
+		runtime.GC()
+		runtime.ReadMemStats(&ms)
+		before := ms.HeapAlloc
+
+		ao := storageObjects(20000)
+		if packingEdges() {
+			optimizeColumns()
+			ao.PackEdges()
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&ms)
+		b.ReportMetric(float64(ms.HeapAlloc-before)/float64(ao.Len()), "heapbytes/object")
+		runtime.KeepAlive(ao)
+	}
+}
+
+func BenchmarkStorageMemoryDefault(b *testing.B) {
+	benchmarkStorageMemory(b, StorageDefault)
+}
+
+func BenchmarkStorageMemoryColumnar(b *testing.B) {
+	benchmarkStorageMemory(b, StorageColumnar)
+}
+
+func BenchmarkStorageMemoryDisk(b *testing.B) {
+	benchmarkStorageMemory(b, StorageDisk)
+}
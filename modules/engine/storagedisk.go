@@ -0,0 +1,428 @@
+package engine
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	DefaultStorageCache = 65536 // Objects with values in memory when using disk storage
+
+	diskStorageShards = 64
+)
+
+var (
+	diskstorage  atomic.Pointer[diskStorage]
+	valuesBucket = []byte("values")
+)
+
+// Attribute values kept in a bbolt database, with a write back cache of recently used objects
+type diskStorage struct {
+	db     *bolt.DB
+	path   string
+	shards [diskStorageShards]diskCacheShard
+}
+
+type diskCacheShard struct {
+	lock     sync.Mutex
+	capacity int
+	entries  map[ObjectID]*list.Element
+	lru      list.List // Most recently used first
+}
+
+type diskCacheEntry struct {
+	attributes *diskAttributes
+	values     map[Attribute]AttributeValues
+	dirty      bool
+}
+
+// Attributes of an object using disk storage
+type diskAttributes struct {
+	id      ObjectID
+	storage *diskStorage
+	stored  bool                          // Values have been written to the database
+	pinned  map[Attribute]AttributeValues // Values that can't be written to disk (objects, security descriptors), these are pointers to data shared with other objects
+}
+
+func openDiskStorage(path string, cachesize int) (*diskStorage, error) {
+	if cachesize <= 0 {
+		cachesize = DefaultStorageCache
+	}
+	if path == "" {
+		path = os.TempDir()
+	}
+	f, err := os.CreateTemp(path, "adalanche-storage-*.db")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	// Durability is not needed, it's all thrown away when we exit
+	db, err := bolt.Open(f.Name(), 0600, &bolt.Options{NoSync: true, NoFreelistSync: true, NoGrowSync: true})
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(valuesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	ds := &diskStorage{
+		db:   db,
+		path: f.Name(),
+	}
+	for i := range ds.shards {
+		ds.shards[i].capacity = max(cachesize/diskStorageShards, 1)
+		ds.shards[i].entries = make(map[ObjectID]*list.Element)
+	}
+	ui.Info().Msgf("Using disk storage in %v with %v cached objects", f.Name(), cachesize)
+	return ds, nil
+}
+
+// Must be called with the storage lock held
+func closeDiskStorage() {
+	ds := diskstorage.Swap(nil)
+	if ds == nil {
+		return
+	}
+	ds.db.Close()
+	os.Remove(ds.path)
+}
+
+func (da *diskAttributes) shard() *diskCacheShard {
+	return &da.storage.shards[int(da.id)%diskStorageShards]
+}
+
+// Returns the cache entry for the object, loading it from disk if needed. Must be called with the shard locked
+func (da *diskAttributes) entry(dcs *diskCacheShard) *diskCacheEntry {
+	if element, found := dcs.entries[da.id]; found {
+		dcs.lru.MoveToFront(element)
+		return element.Value.(*diskCacheEntry)
+	}
+
+	dce := &diskCacheEntry{
+		attributes: da,
+		values:     make(map[Attribute]AttributeValues),
+	}
+	if da.stored {
+		err := da.storage.db.View(func(tx *bolt.Tx) error {
+			data := tx.Bucket(valuesBucket).Get(diskKey(da.id))
+			if data == nil {
+				return nil
+			}
+			return decodeDiskValues(data, dce.values)
+		})
+		if err != nil {
+			ui.Fatal().Msgf("Problem reading values for object %v from disk storage: %v", da.id, err)
+		}
+	}
+	dcs.entries[da.id] = dcs.lru.PushFront(dce)
+	if dcs.lru.Len() > dcs.capacity {
+		da.storage.evict(dcs)
+	}
+	return dce
+}
+
+// Writes the least recently used objects to disk and drops them from the cache, must be called with the shard locked
+func (ds *diskStorage) evict(dcs *diskCacheShard) {
+	keep := dcs.capacity - dcs.capacity/8
+	var evicted []*diskCacheEntry
+	for dcs.lru.Len() > keep {
+		element := dcs.lru.Back()
+		dce := element.Value.(*diskCacheEntry)
+		dcs.lru.Remove(element)
+		delete(dcs.entries, dce.attributes.id)
+		if dce.dirty {
+			evicted = append(evicted, dce)
+		}
+	}
+	if len(evicted) == 0 {
+		return
+	}
+
+	err := ds.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(valuesBucket)
+		for _, dce := range evicted {
+			if len(dce.values) == 0 {
+				if dce.attributes.stored {
+					if err := bucket.Delete(diskKey(dce.attributes.id)); err != nil {
+						return err
+					}
+				}
+				dce.attributes.stored = false
+				continue
+			}
+			data, err := encodeDiskValues(dce.values)
+			if err != nil {
+				return err
+			}
+			if err = bucket.Put(diskKey(dce.attributes.id), data); err != nil {
+				return err
+			}
+			dce.attributes.stored = true
+		}
+		return nil
+	})
+	if err != nil {
+		ui.Fatal().Msgf("Problem writing values to disk storage: %v", err)
+	}
+}
+
+func diskKey(id ObjectID) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(id))
+}
+
+func (da *diskAttributes) get(a Attribute) (AttributeValues, bool) {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	defer dcs.lock.Unlock()
+	if values, found := da.pinned[a]; found {
+		return values, true
+	}
+	values, found := da.entry(dcs).values[a]
+	return values, found
+}
+
+func (da *diskAttributes) set(a Attribute, values AttributeValues) {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	defer dcs.lock.Unlock()
+	dce := da.entry(dcs)
+	if storableOnDisk(values) {
+		delete(da.pinned, a)
+		dce.values[a] = values
+	} else {
+		if da.pinned == nil {
+			da.pinned = make(map[Attribute]AttributeValues)
+		}
+		da.pinned[a] = values
+		delete(dce.values, a)
+	}
+	dce.dirty = true
+}
+
+func (da *diskAttributes) clear(a Attribute) {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	defer dcs.lock.Unlock()
+	delete(da.pinned, a)
+	dce := da.entry(dcs)
+	if _, found := dce.values[a]; found {
+		delete(dce.values, a)
+		dce.dirty = true
+	}
+}
+
+func (da *diskAttributes) len() int {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	defer dcs.lock.Unlock()
+	return len(da.pinned) + len(da.entry(dcs).values)
+}
+
+func (da *diskAttributes) iterate(f func(attr Attribute, values AttributeValues) bool) {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	items := make([]AttributeValuesEvaluator, 0, len(da.pinned)+len(da.entry(dcs).values))
+	for a, values := range da.pinned {
+		items = append(items, AttributeValuesEvaluator{a: a, v: values})
+	}
+	for a, values := range da.entry(dcs).values {
+		items = append(items, AttributeValuesEvaluator{a: a, v: values})
+	}
+	dcs.lock.Unlock()
+
+	// Same order as the other storage backends, and f might use the object so it's called without the lock
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].a < items[j].a
+	})
+	for _, item := range items {
+		if !f(item.a, item.v) {
+			break
+		}
+	}
+}
+
+// Remove all values, used when the object is absorbed into another
+func (da *diskAttributes) release() {
+	dcs := da.shard()
+	dcs.lock.Lock()
+	da.pinned = nil
+	dce := da.entry(dcs)
+	clear(dce.values)
+	dce.dirty = true
+	dcs.lock.Unlock()
+}
+
+const (
+	diskString byte = iota + 1
+	diskBlob
+	diskBool
+	diskInt
+	diskTime
+	diskSID
+	diskGUID
+)
+
+func storableOnDisk(values AttributeValues) bool {
+	storable := true
+	values.Iterate(func(value AttributeValue) bool {
+		switch value.(type) {
+		case AttributeValueString, AttributeValueBlob, AttributeValueBool, AttributeValueInt, AttributeValueTime, AttributeValueSID, AttributeValueGUID:
+		default:
+			storable = false
+		}
+		return storable
+	})
+	return storable
+}
+
+func encodeDiskValues(values map[Attribute]AttributeValues) ([]byte, error) {
+	var data []byte
+	for a, avs := range values {
+		data = binary.BigEndian.AppendUint16(data, uint16(a))
+		data = binary.AppendUvarint(data, uint64(avs.Len()))
+		var err error
+		avs.Iterate(func(value AttributeValue) bool {
+			switch v := value.(type) {
+			case AttributeValueString:
+				data = appendDiskBytes(append(data, diskString), []byte(v))
+			case AttributeValueBlob:
+				data = appendDiskBytes(append(data, diskBlob), []byte(v))
+			case AttributeValueBool:
+				data = append(data, diskBool)
+				if v {
+					data = append(data, 1)
+				} else {
+					data = append(data, 0)
+				}
+			case AttributeValueInt:
+				data = binary.AppendVarint(append(data, diskInt), int64(v))
+			case AttributeValueTime:
+				var t []byte
+				t, err = time.Time(v).MarshalBinary()
+				data = appendDiskBytes(append(data, diskTime), t)
+			case AttributeValueSID:
+				data = appendDiskBytes(append(data, diskSID), []byte(v))
+			case AttributeValueGUID:
+				data = append(append(data, diskGUID), v[:]...)
+			default:
+				err = fmt.Errorf("can not store %T on disk", value)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func appendDiskBytes(data, b []byte) []byte {
+	return append(binary.AppendUvarint(data, uint64(len(b))), b...)
+}
+
+var errDiskValuesCorrupt = errors.New("corrupt values in disk storage")
+
+func decodeDiskValues(data []byte, values map[Attribute]AttributeValues) error {
+	readBytes := func() ([]byte, error) {
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return nil, errDiskValuesCorrupt
+		}
+		b := data[n : n+int(length)]
+		data = data[n+int(length):]
+		return b, nil
+	}
+
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return errDiskValuesCorrupt
+		}
+		a := Attribute(binary.BigEndian.Uint16(data))
+		count, n := binary.Uvarint(data[2:])
+		if n <= 0 {
+			return errDiskValuesCorrupt
+		}
+		data = data[2+n:]
+
+		avs := make(AttributeValueSlice, 0, count)
+		for i := uint64(0); i < count; i++ {
+			if len(data) == 0 {
+				return errDiskValuesCorrupt
+			}
+			kind := data[0]
+			data = data[1:]
+			switch kind {
+			case diskString, diskBlob, diskSID, diskTime:
+				b, err := readBytes()
+				if err != nil {
+					return err
+				}
+				switch kind {
+				case diskString:
+					avs = append(avs, AttributeValueString(b))
+				case diskBlob:
+					avs = append(avs, AttributeValueBlob(b))
+				case diskSID:
+					avs = append(avs, AttributeValueSID(windowssecurity.SID(b)))
+				case diskTime:
+					var t time.Time
+					if err = t.UnmarshalBinary(b); err != nil {
+						return err
+					}
+					avs = append(avs, AttributeValueTime(t))
+				}
+			case diskBool:
+				if len(data) == 0 {
+					return errDiskValuesCorrupt
+				}
+				avs = append(avs, AttributeValueBool(data[0] != 0))
+				data = data[1:]
+			case diskInt:
+				v, n := binary.Varint(data)
+				if n <= 0 {
+					return errDiskValuesCorrupt
+				}
+				avs = append(avs, AttributeValueInt(v))
+				data = data[n:]
+			case diskGUID:
+				if len(data) < 16 {
+					return errDiskValuesCorrupt
+				}
+				avs = append(avs, AttributeValueGUID(uuid.Must(uuid.FromBytes(data[:16]))))
+				data = data[16:]
+			default:
+				return errDiskValuesCorrupt
+			}
+		}
+
+		switch len(avs) {
+		case 0:
+			values[a] = NoValues{}
+		case 1:
+			values[a] = AttributeValueOne{Value: avs[0]}
+		default:
+			values[a] = avs
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "generate [-options]",
+		Short: "Writes a synthetic Active Directory forest with local machine data to the datapath, for testing and training",
+	}
+
+	defaults = DefaultOptions()
+
+	seed          = Command.Flags().Int64("seed", defaults.Seed, "Seed for the random generator, the same seed and options give the same forest")
+	domainname    = Command.Flags().String("domain", defaults.Domain, "DNS name of the forest root domain")
+	domains       = Command.Flags().Int("domains", defaults.Domains, "Number of domains in the forest, extra domains are children of the root")
+	date          = Command.Flags().String("date", defaults.Date.Format("2006-01-02"), "Date of the fake collection as YYYY-MM-DD, timestamps are relative to this")
+	ous           = Command.Flags().Int("ous", defaults.OUs, "Department OUs per domain")
+	users         = Command.Flags().Int("users", defaults.Users, "Users per domain")
+	groups        = Command.Flags().Int("groups", defaults.Groups, "Project groups per domain")
+	computers     = Command.Flags().Int("computers", defaults.Computers, "Member computers per domain, a fifth of them are servers")
+	localmachines = Command.Flags().Int("localmachines", defaults.LocalMachines, "Computers per domain that also get local machine data")
+	plant         = Command.Flags().StringSlice("misconfigurations", []string{"all"}, "Misconfigurations to plant in the root domain, 'all' or 'none'")
+	list          = Command.Flags().Bool("list", false, "List the misconfigurations that can be planted and exit")
+)
+
+func init() {
+	cli.Root.AddCommand(Command)
+	Command.RunE = Execute
+}
+
+func Execute(cmd *cobra.Command, args []string) error {
+	if *list {
+		for _, m := range Misconfigurations() {
+			fmt.Printf("%-26v %v\n", m.Name, m.Description)
+		}
+		return nil
+	}
+
+	datapath := cmd.InheritedFlags().Lookup("datapath").Value.String()
+	if entries, err := os.ReadDir(datapath); err == nil && len(entries) > 0 {
+		return fmt.Errorf("datapath %v is not empty, generated data would be mixed with what's already there", datapath)
+	}
+
+	options := Options{
+		Seed:          *seed,
+		Domain:        strings.ToLower(*domainname),
+		Domains:       *domains,
+		OUs:           *ous,
+		Users:         *users,
+		Groups:        *groups,
+		Computers:     *computers,
+		LocalMachines: *localmachines,
+	}
+	t, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		return fmt.Errorf("problem parsing date %v: %w", *date, err)
+	}
+	options.Date = t
+
+	for _, name := range *plant {
+		switch strings.ToLower(name) {
+		case "all":
+			options.Misconfigurations = append(options.Misconfigurations, MisconfigurationNames()...)
+		case "none", "":
+		default:
+			if findMisconfiguration(name) == nil {
+				return errors.New("unknown misconfiguration " + name + ", use --list to see the ones available")
+			}
+			options.Misconfigurations = append(options.Misconfigurations, name)
+		}
+	}
+
+	return Generate(datapath, options)
+}
@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+var (
+	extendedRightCertificateEnroll     = uuid.FromStringOrNil("0e10c968-78fb-11d2-90d4-00c04f79dc55")
+	extendedRightCertificateAutoEnroll = uuid.FromStringOrNil("a05b8cc2-17bc-4802-a710-e7c15ab866a2")
+)
+
+// The name of the certification authority machine in the root domain
+const caName = "CA01"
+
+// Adds partitions, PKI and the other forest wide objects to the configuration partition
+func (g *generator) addConfiguration() {
+	root := g.root()
+
+	configuration := g.addConfig(g.configdn, "configuration")
+	configuration.allow(root.rid(519), engine.RIGHT_GENERIC_ALL, uuid.Nil)
+
+	partitions := "CN=Partitions," + g.configdn
+	g.addConfig(partitions, "crossRefContainer")
+	for _, d := range g.domains {
+		crossref := g.addConfig("CN="+d.netbios+","+partitions, "crossRef")
+		crossref.set("nCName", d.dn)
+		crossref.set("nETBIOSName", d.netbios)
+		crossref.set("dnsRoot", d.dns)
+		crossref.set("systemFlags", "3")
+	}
+
+	services := "CN=Services," + g.configdn
+	g.addConfig(services, "container")
+	pki := "CN=Public Key Services," + services
+	g.addConfig(pki, "container")
+	g.addConfig("CN=Certificate Templates,"+pki, "container")
+	g.addConfig("CN=Enrollment Services,"+pki, "container")
+
+	ca := g.addConfig("CN="+root.netbios+"-"+caName+"-CA,CN=Enrollment Services,"+pki, "pKIEnrollmentService")
+	ca.set("dNSHostName", strings.ToLower(caName)+"."+root.dns)
+	ca.set("displayName", root.netbios+"-"+caName+"-CA")
+	ca.set("flags", "10")
+
+	// Default templates, only the domain controller and admin ones can't be used by regular accounts
+	user := g.addTemplate("User", "-1509949440", "1.3.6.1.4.1.311.10.3.4", "1.3.6.1.5.5.7.3.4", "1.3.6.1.5.5.7.3.2")
+	for _, d := range g.domains {
+		user.allow(d.rid(513), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateEnroll)
+	}
+	machine := g.addTemplate("Machine", "402653184", "1.3.6.1.5.5.7.3.2", "1.3.6.1.5.5.7.3.1")
+	for _, d := range g.domains {
+		machine.allow(d.rid(515), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateEnroll)
+		machine.allow(d.rid(515), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateAutoEnroll)
+	}
+	dc := g.addTemplate("DomainController", "402653184", "1.3.6.1.5.5.7.3.2", "1.3.6.1.5.5.7.3.1")
+	for _, d := range g.domains {
+		dc.allow(d.rid(516), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateEnroll)
+	}
+	webserver := g.addTemplate("WebServer", "1", "1.3.6.1.5.5.7.3.1")
+	webserver.allow(root.rid(512), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateEnroll)
+}
+
+// Adds a certificate template and publishes it on the CA
+func (g *generator) addTemplate(name, nameflags string, ekus ...string) *object {
+	pki := "CN=Public Key Services,CN=Services," + g.configdn
+	template := g.addConfig("CN="+name+",CN=Certificate Templates,"+pki, "pKICertificateTemplate")
+	template.set("displayName", name)
+	template.set("flags", "131642")
+	template.set("revision", "100")
+	template.set("msPKI-Certificate-Name-Flag", nameflags)
+	template.set("msPKI-Enrollment-Flag", "0")
+	template.set("msPKI-RA-Signature", "0")
+	template.set("msPKI-Template-Schema-Version", "2")
+	template.set("pKIExtendedKeyUsage", ekus...)
+	template.set("msPKI-Certificate-Application-Policy", ekus...)
+	template.allow(g.root().rid(519), engine.RIGHT_GENERIC_ALL, uuid.Nil)
+
+	for _, o := range g.config {
+		if o.class == "pKIEnrollmentService" {
+			o.add("certificateTemplates", name)
+		}
+	}
+	return template
+}
@@ -0,0 +1,347 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+var (
+	extendedRightResetPassword  = uuid.FromStringOrNil("00299570-246d-11d0-a768-00aa006e0529")
+	extendedRightGetChanges     = uuid.FromStringOrNil("1131f6aa-9c07-11d1-f79f-00c04fc2dcd2")
+	extendedRightGetChangesAll  = uuid.FromStringOrNil("1131f6ad-9c07-11d1-f79f-00c04fc2dcd2")
+	enterpriseDomainControllers = windowssecurity.MustParseStringSID("S-1-5-9")
+)
+
+// userAccountControl flags
+const (
+	uacAccountDisable          = 0x2
+	uacPasswordNotRequired     = 0x20
+	uacNormalAccount           = 0x200
+	uacWorkstationTrustAccount = 0x1000
+	uacServerTrustAccount      = 0x2000
+	uacDontExpirePassword      = 0x10000
+	uacTrustedForDelegation    = 0x80000
+	uacDontRequirePreauth      = 0x400000
+)
+
+// groupType values
+const (
+	groupGlobalSecurity    = "-2147483646"
+	groupDomainLocal       = "-2147483644"
+	groupUniversalSecurity = "-2147483640"
+	groupBuiltinLocal      = "-2147483643"
+)
+
+// Server names are a role followed by a number
+var serverRoles = []string{"FS", "SQL", "WEB", "APP", "RDS", "PRT", "EXCH", "BKP"}
+
+func (g *generator) populate(d *domain) {
+	root := g.root()
+
+	domainobject := g.add(d, d.dn, "domainDNS")
+	domainobject.sid = d.sid
+	domainobject.set("objectSid", string(d.sid.Bytes()))
+	domainobject.set("dc", strings.Split(d.dns, ".")[0])
+	domainobject.set("name", strings.Split(d.dns, ".")[0])
+	domainobject.set("systemFlags", "-1946157056")
+	domainobject.set("ms-DS-MachineAccountQuota", "10")
+	domainobject.set("minPwdLength", "7")
+	domainobject.set("lockoutThreshold", "0")
+	domainobject.delegate(root.rid(519), engine.RIGHT_GENERIC_ALL, uuid.Nil, "")
+	domainobject.delegate(windowssecurity.AdministratorsSID, engine.RIGHT_GENERIC_ALL, uuid.Nil, "")
+	for _, sid := range []windowssecurity.SID{windowssecurity.AdministratorsSID, d.rid(516), enterpriseDomainControllers} {
+		domainobject.allow(sid, engine.RIGHT_DS_CONTROL_ACCESS, extendedRightGetChanges)
+		domainobject.allow(sid, engine.RIGHT_DS_CONTROL_ACCESS, extendedRightGetChangesAll)
+	}
+
+	users := "CN=Users," + d.dn
+	builtin := "CN=Builtin," + d.dn
+	system := "CN=System," + d.dn
+	g.add(d, users, "container")
+	g.add(d, "CN=Computers,"+d.dn, "container")
+	g.add(d, system, "container")
+	g.add(d, "CN=AdminSDHolder,"+system, "container").protected = true
+	g.add(d, "CN=Policies,"+system, "container")
+	builtinobject := g.add(d, builtin, "builtinDomain")
+	builtinobject.sid = windowssecurity.MustParseStringSID("S-1-5-32")
+	builtinobject.set("objectSid", string(builtinobject.sid.Bytes()))
+
+	// Well known accounts and groups
+	administrator := g.addUser(d, users, "Administrator", "", 500)
+	administrator.setuac(uacNormalAccount | uacDontExpirePassword)
+	administrator.protected = true
+	guest := g.addUser(d, users, "Guest", "", 501)
+	guest.setuac(uacAccountDisable | uacPasswordNotRequired | uacNormalAccount | uacDontExpirePassword)
+	delete(guest.attributes, "lastLogonTimestamp")
+	krbtgt := g.addUser(d, users, "krbtgt", "", 502)
+	krbtgt.setuac(uacAccountDisable | uacNormalAccount)
+	krbtgt.set("servicePrincipalName", "kadmin/changepw")
+	krbtgt.protected = true
+	delete(krbtgt.attributes, "lastLogonTimestamp")
+
+	domainadmins := g.addGroup(d, users, "Domain Admins", groupGlobalSecurity, 512)
+	domainadmins.protected = true
+	domainadmins.members = append(domainadmins.members, administrator)
+	g.addGroup(d, users, "Domain Users", groupGlobalSecurity, 513)
+	g.addGroup(d, users, "Domain Computers", groupGlobalSecurity, 515)
+	g.addGroup(d, users, "Domain Controllers", groupGlobalSecurity, 516).protected = true
+	g.addGroup(d, users, "Group Policy Creator Owners", groupGlobalSecurity, 520)
+	g.addGroup(d, users, "Protected Users", groupGlobalSecurity, 525)
+	var enterpriseadmins *object
+	if d == root {
+		g.addGroup(d, users, "Schema Admins", groupUniversalSecurity, 518).protected = true
+		enterpriseadmins = g.addGroup(d, users, "Enterprise Admins", groupUniversalSecurity, 519)
+		enterpriseadmins.protected = true
+		enterpriseadmins.members = append(enterpriseadmins.members, administrator)
+	}
+
+	administrators := g.addBuiltinGroup(d, "Administrators", 544)
+	administrators.protected = true
+	administrators.members = append(administrators.members, administrator, domainadmins)
+	if enterpriseadmins != nil {
+		administrators.members = append(administrators.members, enterpriseadmins)
+	}
+	g.addBuiltinGroup(d, "Users", 545).members = append([]*object{}, d.find("Domain Users"))
+	g.addBuiltinGroup(d, "Account Operators", 548).protected = true
+	g.addBuiltinGroup(d, "Server Operators", 549).protected = true
+	g.addBuiltinGroup(d, "Backup Operators", 551).protected = true
+	g.addBuiltinGroup(d, "Remote Desktop Users", 555)
+	g.addBuiltinGroup(d, "Distributed COM Users", 562)
+
+	// Domain controllers
+	dcou := g.add(d, "OU=Domain Controllers,"+d.dn, "organizationalUnit")
+	for i := 1; i <= 2; i++ {
+		dc := g.addComputer(d, dcou.dn, fmt.Sprintf("DC%02d", i), g.pick(serverProducts))
+		dc.setuac(uacServerTrustAccount | uacTrustedForDelegation)
+		dc.set("primaryGroupID", "516")
+		dc.add("servicePrincipalName", "ldap/"+dc.get("dNSHostName"), "GC/"+dc.get("dNSHostName")+"/"+g.options.Domain)
+		d.controllers = append(d.controllers, dc)
+	}
+
+	// Tiered administration
+	tier0 := g.add(d, "OU=Tier 0,"+d.dn, "organizationalUnit")
+	admins := g.add(d, "OU=Admins,"+tier0.dn, "organizationalUnit")
+	g.add(d, "OU=Service Accounts,"+tier0.dn, "organizationalUnit")
+	for i := 0; i < 3; i++ {
+		admin := g.addPerson(d, admins.dn, "adm_")
+		admin.protected = true
+		domainadmins.members = append(domainadmins.members, admin)
+		d.admins = append(d.admins, admin)
+	}
+
+	// Departments, each in its own OU with a group for the members
+	staff := g.add(d, "OU=Staff,"+d.dn, "organizationalUnit")
+	groups := g.add(d, "OU=Groups,"+d.dn, "organizationalUnit")
+	ous := make([]*object, g.options.OUs)
+	for i := range ous {
+		name := departments[i%len(departments)]
+		if i >= len(departments) {
+			name += " " + strconv.Itoa(i/len(departments)+1)
+		}
+		ous[i] = g.add(d, "OU="+name+","+staff.dn, "organizationalUnit")
+		group := g.addGroup(d, groups.dn, name, groupGlobalSecurity, 0)
+		group.set("description", "Everyone in "+name)
+		d.departmentgroups = append(d.departmentgroups, group)
+	}
+	for i := 0; i < g.options.Users; i++ {
+		department := g.rnd.Intn(len(ous))
+		user := g.addPerson(d, ous[department].dn, "")
+		user.set("department", rdnValue(ous[department].dn))
+		d.departmentgroups[department].members = append(d.departmentgroups[department].members, user)
+		d.users = append(d.users, user)
+	}
+
+	helpdesk := g.addGroup(d, groups.dn, "Helpdesk", groupGlobalSecurity, 0)
+	helpdesk.set("description", "First line support, can reset passwords for staff")
+	helpdesk.members = append(helpdesk.members, g.sample(d.users, 3)...)
+	serveradmins := g.addGroup(d, groups.dn, "Server Admins", groupGlobalSecurity, 0)
+	serveradmins.set("description", "Local administrators on member servers")
+	for i := 0; i < 2; i++ {
+		admin := g.addPerson(d, admins.dn, "srvadm_")
+		serveradmins.members = append(serveradmins.members, admin)
+	}
+	workstationadmins := g.addGroup(d, groups.dn, "Workstation Admins", groupGlobalSecurity, 0)
+	workstationadmins.set("description", "Local administrators on workstations")
+	workstationadmins.members = append(workstationadmins.members, helpdesk)
+
+	// Helpdesk delegation, the usual way of doing it
+	staff.delegate(helpdesk.sid, engine.RIGHT_DS_CONTROL_ACCESS, extendedRightResetPassword, "user")
+	staff.delegate(helpdesk.sid, engine.RIGHT_DS_WRITE_PROPERTY, lookupAttribute("pwdLastSet").guid, "user")
+
+	for i := 0; i < g.options.Groups; i++ {
+		name := "Project " + projects[i%len(projects)]
+		if i >= len(projects) {
+			name += " " + strconv.Itoa(i/len(projects)+1)
+		}
+		group := g.addGroup(d, groups.dn, name, groupGlobalSecurity, 0)
+		group.members = append(group.members, g.sample(d.users, 3+g.rnd.Intn(10))...)
+		if g.rnd.Intn(4) == 0 {
+			// Some projects include a whole department
+			group.members = append(group.members, d.departmentgroups[g.rnd.Intn(len(d.departmentgroups))])
+		}
+		// The project lead manages the membership
+		lead := group.members[0]
+		group.set("managedBy", lead.dn)
+		group.allow(lead.sid, engine.RIGHT_DS_WRITE_PROPERTY, lookupAttribute("member").guid)
+		d.projects = append(d.projects, group)
+	}
+
+	// Machines, a fifth of them are servers
+	workstations := g.add(d, "OU=Workstations,"+d.dn, "organizationalUnit")
+	servers := g.add(d, "OU=Servers,"+d.dn, "organizationalUnit")
+	servercount := g.options.Computers / 5
+	if servercount < 2 {
+		servercount = 2
+	}
+	for i := 0; i < g.options.Computers-servercount; i++ {
+		ws := g.addComputer(d, workstations.dn, fmt.Sprintf("WS%04d", i+1), g.pick(workstationProducts))
+		d.workstations = append(d.workstations, ws)
+	}
+	if d == root {
+		d.servers = append(d.servers, g.addComputer(d, servers.dn, caName, g.pick(serverProducts)))
+		servercount--
+	}
+	for i := 0; i < servercount; i++ {
+		role := serverRoles[i%len(serverRoles)]
+		name := fmt.Sprintf("%v%02d", role, i/len(serverRoles)+1)
+		server := g.addComputer(d, servers.dn, name, g.pick(serverProducts))
+		if role == "SQL" {
+			server.add("servicePrincipalName", "MSSQLSvc/"+server.get("dNSHostName")+":1433")
+		}
+		d.servers = append(d.servers, server)
+	}
+
+	g.addBaselineGPOs(d, workstations, servers, dcou, workstationadmins, serveradmins)
+}
+
+// Picks count different objects at random
+func (g *generator) sample(objects []*object, count int) []*object {
+	if count > len(objects) {
+		count = len(objects)
+	}
+	result := make([]*object, count)
+	for i, index := range g.rnd.Perm(len(objects))[:count] {
+		result[i] = objects[index]
+	}
+	return result
+}
+
+func (g *generator) addUser(d *domain, container, samaccountname, displayname string, rid uint32) *object {
+	cn := samaccountname
+	if displayname != "" {
+		cn = displayname
+	}
+	// Don't collide with someone with the same name in the same place
+	dn := "CN=" + cn + "," + container
+	for i := 2; d.bydn[strings.ToLower(dn)] != nil; i++ {
+		dn = "CN=" + cn + " " + strconv.Itoa(i) + "," + container
+	}
+
+	user := g.addPrincipal(d, dn, "user", samaccountname, rid)
+	user.set("userPrincipalName", samaccountname+"@"+d.dns)
+	user.set("sAMAccountType", "805306368")
+	user.set("primaryGroupID", "513")
+	user.set("pwdLastSet", filetime(g.daysAgo(1, 400)))
+	user.set("lastLogonTimestamp", filetime(g.daysAgo(0, 14)))
+	user.set("accountExpires", "9223372036854775807")
+	if displayname != "" {
+		user.set("displayName", displayname)
+	}
+	user.setuac(uacNormalAccount)
+	return user
+}
+
+// Adds a person with a random name, the account name gets prefix in front of it
+func (g *generator) addPerson(d *domain, container, prefix string) *object {
+	given := g.pick(givenNames)
+	surname := g.pick(surnames)
+	base := prefix + strings.ToLower(given[:1]+surname)
+	samaccountname := base
+	for i := 2; d.find(samaccountname) != nil; i++ {
+		samaccountname = base + strconv.Itoa(i)
+	}
+
+	user := g.addUser(d, container, samaccountname, given+" "+surname, 0)
+	user.set("givenName", given)
+	user.set("sn", surname)
+	if prefix == "" {
+		user.set("mail", samaccountname+"@"+d.dns)
+	}
+
+	switch r := g.rnd.Intn(100); {
+	case r < 4:
+		user.setuac(uacNormalAccount | uacAccountDisable)
+	case r < 20:
+		user.setuac(uacNormalAccount | uacDontExpirePassword)
+	case r < 24:
+		// Never logged on
+		delete(user.attributes, "lastLogonTimestamp")
+	case r < 30:
+		user.set("lastLogonTimestamp", filetime(g.daysAgo(90, 700)))
+	}
+	return user
+}
+
+func (g *generator) addGroup(d *domain, container, name, grouptype string, rid uint32) *object {
+	group := g.addPrincipal(d, "CN="+name+","+container, "group", name, rid)
+	group.set("groupType", grouptype)
+	group.set("sAMAccountType", "268435456")
+	if grouptype == groupDomainLocal {
+		group.set("sAMAccountType", "536870912")
+	}
+	return group
+}
+
+func (g *generator) addBuiltinGroup(d *domain, name string, rid uint32) *object {
+	group := g.add(d, "CN="+name+",CN=Builtin,"+d.dn, "group")
+	group.sid = windowssecurity.MustParseStringSID("S-1-5-32-" + strconv.Itoa(int(rid)))
+	group.set("objectSid", string(group.sid.Bytes()))
+	group.set("sAMAccountName", name)
+	group.set("groupType", groupBuiltinLocal)
+	group.set("sAMAccountType", "536870912")
+	group.set("isCriticalSystemObject", "TRUE")
+	d.byname[strings.ToLower(name)] = group
+	return group
+}
+
+func (g *generator) addComputer(d *domain, container, name, os string) *object {
+	computer := g.addPrincipal(d, "CN="+name+","+container, "computer", name+"$", 0)
+	hostname := strings.ToLower(name) + "." + d.dns
+	computer.set("dNSHostName", hostname)
+	computer.set("sAMAccountType", "805306369")
+	computer.set("primaryGroupID", "515")
+	computer.set("operatingSystem", os)
+	computer.set("servicePrincipalName", "HOST/"+name, "HOST/"+hostname, "RestrictedKrbHost/"+name, "RestrictedKrbHost/"+hostname)
+	computer.set("pwdLastSet", filetime(g.daysAgo(0, 30)))
+	computer.set("lastLogonTimestamp", filetime(g.daysAgo(0, 14)))
+	computer.setuac(uacWorkstationTrustAccount)
+	return computer
+}
+
+// Trusts between parent and child domains, and an external one from the forest root
+func (g *generator) addTrusts() {
+	root := g.root()
+	for _, d := range g.domains[1:] {
+		g.addTrust(root, d.dns, d.netbios, d.sid, 0x20)
+		g.addTrust(d, root.dns, root.netbios, root.sid, 0x20)
+	}
+	external := g.addTrust(root, "fabrikam.com", "FABRIKAM", g.domainSID(), 0x4)
+	external.set("trustDirection", "2") // Outbound only, we trust Fabrikam so their users can use our resources
+}
+
+func (g *generator) addTrust(d *domain, dns, netbios string, sid windowssecurity.SID, attributes int) *object {
+	trust := g.add(d, "CN="+dns+",CN=System,"+d.dn, "trustedDomain")
+	trust.set("trustPartner", dns)
+	trust.set("flatName", netbios)
+	trust.set("securityIdentifier", string(sid.Bytes()))
+	trust.set("trustDirection", "3")
+	trust.set("trustType", "2")
+	trust.set("trustAttributes", strconv.Itoa(attributes))
+	d.trusts = append(d.trusts, trust)
+	return trust
+}
@@ -0,0 +1,576 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/basedata"
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/util"
+	"github.com/lkarlslund/adalanche/modules/version"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	"github.com/pierrec/lz4/v4"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Options controls the size and content of the generated forest. The same options always give the same data
+type Options struct {
+	Seed    int64
+	Domain  string    // DNS name of the forest root domain
+	Domains int       // Number of domains in the forest, the extra ones are children of the root
+	Date    time.Time // Time of the fake collection, all timestamps are relative to this. Zero means DefaultDate
+
+	// Per domain
+	OUs           int // Department OUs with users in them
+	Users         int
+	Groups        int
+	Computers     int
+	LocalMachines int // How many of the computers also get local machine data
+
+	Misconfigurations []string // Names from the catalogue
+}
+
+// DefaultDate is a fixed date rather than today, so a seed gives the same forest no matter when it's generated
+var DefaultDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func DefaultOptions() Options {
+	return Options{
+		Seed:              1,
+		Domain:            "contoso.local",
+		Domains:           1,
+		Date:              DefaultDate,
+		OUs:               5,
+		Users:             200,
+		Groups:            30,
+		Computers:         60,
+		LocalMachines:     20,
+		Misconfigurations: MisconfigurationNames(),
+	}
+}
+
+var (
+	ErrOptions = errors.New("invalid generator options")
+)
+
+type generator struct {
+	options Options
+	rnd     *rand.Rand
+
+	rootdn, configdn string
+	config           []*object // Configuration and schema partitions, saved with every domain
+
+	domains []*domain
+}
+
+type domain struct {
+	dns, netbios, dn string
+	sid              windowssecurity.SID
+	parent           *domain
+
+	objects []*object
+	bydn    map[string]*object
+	byname  map[string]*object // Lowercase sAMAccountName
+	nextrid uint32
+
+	users, workstations, servers []*object
+	departmentgroups, projects   []*object
+	controllers                  []*object
+	admins                       []*object // Named administrator accounts in Tier 0
+
+	gpos          []*gpo
+	trusts        []*object
+	localmachines []*machine
+	sessions      []session
+}
+
+// One object in a naming context, the security descriptor is assembled when the object is saved
+type object struct {
+	dn         string
+	class      string
+	attributes map[string][]string
+	sid        windowssecurity.SID
+	owner      windowssecurity.SID
+	aces       []engine.ACE
+	protected  bool // adminCount objects, the DACL comes from AdminSDHolder and doesn't inherit
+	members    []*object
+}
+
+func (o *object) set(name string, values ...string) {
+	o.attributes[name] = values
+}
+
+func (o *object) add(name string, values ...string) {
+	o.attributes[name] = append(o.attributes[name], values...)
+}
+
+func (o *object) get(name string) string {
+	if values := o.attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (o *object) uac() int {
+	uac, _ := strconv.Atoi(o.get("userAccountControl"))
+	return uac
+}
+
+func (o *object) setuac(uac int) {
+	o.set("userAccountControl", strconv.Itoa(uac))
+}
+
+func (o *object) name() string {
+	return o.get("sAMAccountName")
+}
+
+// allow adds an explicit ACE to the object
+func (o *object) allow(sid windowssecurity.SID, mask engine.Mask, objecttype uuid.UUID) {
+	o.aces = append(o.aces, newACE(sid, mask, objecttype, uuid.Nil, 0))
+}
+
+// delegate adds an ACE that children of the given class inherit, like the delegation wizard does on an OU
+func (o *object) delegate(sid windowssecurity.SID, mask engine.Mask, objecttype uuid.UUID, childclass string) {
+	var inheritedtype uuid.UUID
+	flags := engine.ACEFLAG_INHERIT_ACE
+	if childclass != "" {
+		inheritedtype = lookupClass(childclass).guid
+		flags |= engine.ACEFLAG_INHERIT_ONLY_ACE
+	}
+	o.aces = append(o.aces, newACE(sid, mask, objecttype, inheritedtype, flags))
+}
+
+func newACE(sid windowssecurity.SID, mask engine.Mask, objecttype, inheritedtype uuid.UUID, aceflags engine.ACEFlags) engine.ACE {
+	ace := engine.ACE{
+		SID:                 sid,
+		Type:                engine.ACETYPE_ACCESS_ALLOWED,
+		ACEFlags:            aceflags,
+		Mask:                mask,
+		ObjectType:          objecttype,
+		InheritedObjectType: inheritedtype,
+	}
+	if !objecttype.IsNil() {
+		ace.Flags |= engine.OBJECT_TYPE_PRESENT
+	}
+	if !inheritedtype.IsNil() {
+		ace.Flags |= engine.INHERITED_OBJECT_TYPE_PRESENT
+	}
+	if ace.Flags != 0 {
+		ace.Type = engine.ACETYPE_ACCESS_ALLOWED_OBJECT
+	}
+	return ace
+}
+
+// Generate writes a synthetic forest to path, one subfolder per domain just like separate collections would be
+func Generate(path string, options Options) error {
+	if options.Domain == "" || !strings.Contains(options.Domain, ".") {
+		return fmt.Errorf("%w: domain %q needs to be a DNS name", ErrOptions, options.Domain)
+	}
+	if options.Domains < 1 {
+		return fmt.Errorf("%w: at least one domain is needed", ErrOptions)
+	}
+	if options.Users < 10 || options.Computers < 10 || options.OUs < 1 {
+		return fmt.Errorf("%w: at least 10 users, 10 computers and 1 OU per domain are needed", ErrOptions)
+	}
+	if options.LocalMachines > options.Computers {
+		options.LocalMachines = options.Computers
+	}
+	for _, name := range options.Misconfigurations {
+		if findMisconfiguration(name) == nil {
+			return fmt.Errorf("%w: unknown misconfiguration %q", ErrOptions, name)
+		}
+	}
+	if options.Date.IsZero() {
+		options.Date = DefaultDate
+	}
+
+	g := &generator{
+		options: options,
+		rnd:     rand.New(rand.NewSource(options.Seed)),
+	}
+	g.rootdn = dnsToDN(options.Domain)
+	g.configdn = "CN=Configuration," + g.rootdn
+
+	for i := 0; i < options.Domains; i++ {
+		g.newDomain(i)
+	}
+	g.addConfiguration()
+	g.addSchema()
+	for _, d := range g.domains {
+		g.populate(d)
+	}
+	g.addTrusts()
+
+	// Misconfigurations go into the root domain, in catalogue order so seeds stay stable
+	for _, m := range misconfigurations {
+		for _, name := range options.Misconfigurations {
+			if strings.EqualFold(name, m.Name) {
+				ui.Debug().Msgf("Adding misconfiguration %v", m.Name)
+				m.apply(g, g.domains[0])
+				break
+			}
+		}
+	}
+
+	for _, d := range g.domains {
+		g.addLocalMachines(d)
+		if err := g.save(path, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dnsToDN(dns string) string {
+	return "DC=" + strings.Join(strings.Split(dns, "."), ",DC=")
+}
+
+func (g *generator) newDomain(index int) *domain {
+	d := &domain{
+		dns:     g.options.Domain,
+		bydn:    make(map[string]*object),
+		byname:  make(map[string]*object),
+		nextrid: 1103,
+	}
+	if index > 0 {
+		d.parent = g.domains[0]
+		prefix := childDomains[(index-1)%len(childDomains)]
+		if index > len(childDomains) {
+			prefix += strconv.Itoa((index-1)/len(childDomains) + 1)
+		}
+		d.dns = prefix + "." + g.options.Domain
+	}
+	d.dn = dnsToDN(d.dns)
+	d.netbios = strings.ToUpper(strings.Split(d.dns, ".")[0])
+	d.sid = g.domainSID()
+	g.domains = append(g.domains, d)
+	return d
+}
+
+func (g *generator) domainSID() windowssecurity.SID {
+	return windowssecurity.MustParseStringSID(fmt.Sprintf("S-1-5-21-%d-%d-%d", 1000000000+g.rnd.Int31n(1000000000), 1000000000+g.rnd.Int31n(1000000000), 1000000000+g.rnd.Int31n(1000000000)))
+}
+
+func (g *generator) guid() uuid.UUID {
+	var u uuid.UUID
+	g.rnd.Read(u[:])
+	u.SetVersion(uuid.V4)
+	u.SetVariant(uuid.VariantRFC4122)
+	return u
+}
+
+// Random time between the given number of days before the collection and the collection
+func (g *generator) daysAgo(mindays, maxdays int) time.Time {
+	seconds := int64(mindays)*86400 + g.rnd.Int63n(int64(maxdays-mindays+1)*86400)
+	return g.options.Date.Add(-time.Duration(seconds) * time.Second)
+}
+
+func (g *generator) pick(values []string) string {
+	return values[g.rnd.Intn(len(values))]
+}
+
+// Collector information like GetCommonData, but at the time of the fake collection
+func (g *generator) common() basedata.Common {
+	return basedata.Common{
+		Collector: version.Program,
+		Version:   version.Version,
+		Commit:    version.Commit,
+		Collected: g.options.Date,
+	}
+}
+
+func guidBytes(u uuid.UUID) string {
+	return string(util.SwapUUIDEndianess(u).Bytes())
+}
+
+func filetime(t time.Time) string {
+	return strconv.FormatUint(util.TimeToFiletime(t), 10)
+}
+
+func generalizedTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.0Z")
+}
+
+func rdnValue(dn string) string {
+	rdn, _, _ := strings.Cut(dn, ",")
+	_, value, _ := strings.Cut(rdn, "=")
+	return value
+}
+
+func parentDN(dn string) string {
+	_, parent, _ := strings.Cut(dn, ",")
+	return parent
+}
+
+// newObject sets up the attributes every object has
+func (g *generator) newObject(dn, class string) *object {
+	c := lookupClass(class)
+	category := c
+	if c.category != "" {
+		category = lookupClass(c.category)
+	}
+	created := g.daysAgo(30, 3000)
+	o := &object{
+		dn:         dn,
+		class:      class,
+		attributes: make(map[string][]string),
+	}
+	o.set("objectClass", append(append([]string{}, c.parents...), class)...)
+	o.set("objectCategory", "CN="+category.name+",CN=Schema,"+g.configdn)
+	o.set("cn", rdnValue(dn))
+	o.set("name", rdnValue(dn))
+	o.set("objectGUID", string(g.guid().Bytes()))
+	o.set("whenCreated", generalizedTime(created))
+	o.set("whenChanged", generalizedTime(g.daysAgo(0, int(g.options.Date.Sub(created).Hours()/24))))
+	return o
+}
+
+// Objects in the configuration or schema partition
+func (g *generator) addConfig(dn, class string) *object {
+	o := g.newObject(dn, class)
+	g.config = append(g.config, o)
+	return o
+}
+
+// Objects in the domain partition
+func (g *generator) add(d *domain, dn, class string) *object {
+	o := g.newObject(dn, class)
+	d.objects = append(d.objects, o)
+	d.bydn[strings.ToLower(dn)] = o
+	return o
+}
+
+// Adds a security principal, well known RIDs are given explicitly and everything else gets the next free one
+func (g *generator) addPrincipal(d *domain, dn, class, samaccountname string, rid uint32) *object {
+	o := g.add(d, dn, class)
+	if rid == 0 {
+		rid = d.nextrid
+		d.nextrid++
+	}
+	o.sid = d.sid.AddComponent(rid)
+	o.set("objectSid", string(o.sid.Bytes()))
+	o.set("sAMAccountName", samaccountname)
+	d.byname[strings.ToLower(samaccountname)] = o
+	return o
+}
+
+func (d *domain) rid(rid uint32) windowssecurity.SID {
+	return d.sid.AddComponent(rid)
+}
+
+func (d *domain) find(samaccountname string) *object {
+	return d.byname[strings.ToLower(samaccountname)]
+}
+
+func (g *generator) root() *domain {
+	return g.domains[0]
+}
+
+// Security descriptors are built last, so delegations added by misconfigurations are inherited too
+func (g *generator) securityDescriptor(d *domain, o *object, lookup map[string]*object) []byte {
+	sd := engine.SecurityDescriptor{
+		Owner:   o.owner,
+		Group:   d.rid(513),
+		Control: engine.CONTROLFLAG_DACL_PRESENT | engine.CONTROLFLAG_DACL_AUTO_INHERITED,
+		DACL: engine.ACL{
+			Revision: 4,
+		},
+	}
+	if sd.Owner.IsNull() {
+		sd.Owner = d.rid(512)
+	}
+
+	sd.DACL.Entries = append(sd.DACL.Entries, o.aces...)
+
+	system := windowssecurity.SystemSID
+	sd.DACL.Entries = append(sd.DACL.Entries,
+		newACE(system, engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0),
+		newACE(d.rid(512), engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0),
+		newACE(windowssecurity.AuthenticatedUsersSID, engine.RIGHT_GENERIC_READ, uuid.Nil, uuid.Nil, 0),
+	)
+
+	if o.protected {
+		// What SDProp copies from AdminSDHolder
+		sd.Control |= engine.CONTROLFLAG_DACL_PROTECTED
+		sd.DACL.Entries = append(sd.DACL.Entries,
+			newACE(windowssecurity.AdministratorsSID, engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0),
+			newACE(g.root().rid(519), engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0),
+		)
+		return sd.Bytes()
+	}
+
+	switch o.class {
+	case "user", "group", "computer":
+		sd.DACL.Entries = append(sd.DACL.Entries, newACE(windowssecurity.AccountOperatorsSID, engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0))
+	}
+
+	// Inherited ACEs from the parents, nearest first
+	classguid := lookupClass(o.class).guid
+	for parent := parentDN(o.dn); parent != ""; parent = parentDN(parent) {
+		p := lookup[strings.ToLower(parent)]
+		if p == nil {
+			continue
+		}
+		for _, ace := range p.aces {
+			if ace.ACEFlags&engine.ACEFLAG_INHERIT_ACE == 0 {
+				continue
+			}
+			if !ace.InheritedObjectType.IsNil() && ace.InheritedObjectType != classguid {
+				continue
+			}
+			ace.ACEFlags = engine.ACEFLAG_INHERITED_ACE | ace.ACEFlags&engine.ACEFLAG_INHERIT_ACE
+			sd.DACL.Entries = append(sd.DACL.Entries, ace)
+		}
+	}
+
+	return sd.Bytes()
+}
+
+// Converts objects to what the collector would have saved
+func (g *generator) rawObjects(d *domain, objects []*object, lookup map[string]*object) []activedirectory.RawObject {
+	memberof := make(map[*object][]string)
+	for _, o := range objects {
+		for _, member := range o.members {
+			memberof[member] = append(memberof[member], o.dn)
+		}
+	}
+
+	result := make([]activedirectory.RawObject, len(objects))
+	for i, o := range objects {
+		attributes := make(map[string][]string, len(o.attributes)+4)
+		for name, values := range o.attributes {
+			attributes[name] = values
+		}
+		attributes["distinguishedName"] = []string{o.dn}
+		if len(o.members) > 0 {
+			members := make([]string, len(o.members))
+			for j, member := range o.members {
+				members[j] = member.dn
+			}
+			attributes["member"] = members
+		}
+		if groups := memberof[o]; len(groups) > 0 {
+			attributes["memberOf"] = groups
+		}
+		if o.protected {
+			attributes["adminCount"] = []string{"1"}
+		}
+		attributes["nTSecurityDescriptor"] = []string{string(g.securityDescriptor(d, o, lookup))}
+		result[i] = activedirectory.RawObject{
+			DistinguishedName: o.dn,
+			Attributes:        attributes,
+		}
+	}
+	return result
+}
+
+func (g *generator) save(path string, d *domain) error {
+	folder := filepath.Join(path, d.dn)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return err
+	}
+
+	configlookup := make(map[string]*object)
+	for _, o := range g.config {
+		configlookup[strings.ToLower(o.dn)] = o
+	}
+
+	var configuration, schema []*object
+	for _, o := range g.config {
+		if strings.HasSuffix(o.dn, "CN=Schema,"+g.configdn) {
+			schema = append(schema, o)
+		} else {
+			configuration = append(configuration, o)
+		}
+	}
+
+	rootdse := activedirectory.RawObject{
+		Attributes: map[string][]string{
+			"defaultNamingContext":          {d.dn},
+			"rootDomainNamingContext":       {g.rootdn},
+			"configurationNamingContext":    {g.configdn},
+			"schemaNamingContext":           {"CN=Schema," + g.configdn},
+			"dnsHostName":                   {d.controllers[0].get("dNSHostName")},
+			"domainFunctionality":           {"7"},
+			"forestFunctionality":           {"7"},
+			"domainControllerFunctionality": {"7"},
+			"currentTime":                   {generalizedTime(g.options.Date)},
+		},
+	}
+
+	for filename, objects := range map[string][]activedirectory.RawObject{
+		d.dn:                      g.rawObjects(d, d.objects, d.bydn),
+		g.configdn:                g.rawObjects(g.root(), configuration, configlookup),
+		"CN=Schema," + g.configdn: g.rawObjects(g.root(), schema, configlookup),
+		d.dn + ".RootDSE":         {rootdse},
+	} {
+		if err := writeRawObjects(filepath.Join(folder, filename+".objects.msgp.lz4"), objects); err != nil {
+			return err
+		}
+	}
+
+	for _, gpo := range d.gpos {
+		if err := writeJSON(filepath.Join(folder, "{"+strings.ToUpper(gpo.guid.String())+"}.gpodata.json"), g.gpoDump(d, gpo)); err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(d.localmachines, func(i, j int) bool {
+		return d.localmachines[i].info.Machine.Name < d.localmachines[j].info.Machine.Name
+	})
+	for _, m := range d.localmachines {
+		if err := writeJSON(filepath.Join(folder, m.info.Machine.Name+"$"+d.netbios+".localmachine.json"), m.info); err != nil {
+			return err
+		}
+	}
+
+	ui.Info().Msgf("Generated %v with %v objects, %v GPOs and %v machines with local data in %v", d.dns, len(d.objects), len(d.gpos), len(d.localmachines), folder)
+	return nil
+}
+
+func writeRawObjects(path string, objects []activedirectory.RawObject) error {
+	outfile, err := encryption.Create(path)
+	if err != nil {
+		return fmt.Errorf("problem opening %v: %w", path, err)
+	}
+
+	boutfile := lz4.NewWriter(outfile)
+	boutfile.Apply(
+		lz4.BlockChecksumOption(true),
+		lz4.ChecksumOption(true),
+		lz4.CompressionLevelOption(lz4.Level9),
+		lz4.ConcurrencyOption(-1),
+	)
+	e := msgp.NewWriter(boutfile)
+	for _, ro := range objects {
+		if err = ro.EncodeMsg(e); err != nil {
+			return fmt.Errorf("problem encoding object %v: %w", ro.DistinguishedName, err)
+		}
+	}
+	if err = e.Flush(); err != nil {
+		return err
+	}
+	if err = boutfile.Close(); err != nil {
+		return err
+	}
+	return outfile.Close()
+}
+
+func writeJSON(path string, data any) error {
+	output, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("problem marshalling %v: %w", path, err)
+	}
+	return encryption.WriteFile(path, output, 0644)
+}
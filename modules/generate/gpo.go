@@ -0,0 +1,206 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Microsoft published the key for Group Policy Preferences passwords, so anyone who can read the file can decrypt them
+const gppKey = "4e9906e8fcb66cc9faf49310620ffee8f496e806cc057990209b09a433b66c1b"
+
+type gpo struct {
+	guid   uuid.UUID
+	object *object
+
+	memberships []gpoMembership // Restricted groups in GptTmpl.inf
+	groupsxml   string          // Group Policy Preferences for local users and groups
+}
+
+type gpoMembership struct {
+	group  string // Builtin group SID
+	member windowssecurity.SID
+}
+
+// Well known GUIDs for the default policies
+var (
+	defaultDomainPolicy            = uuid.FromStringOrNil("31B2F340-016D-11D2-945F-00C04FB984F9")
+	defaultDomainControllersPolicy = uuid.FromStringOrNil("6AC1786C-016F-11D2-945F-00C04FB984F9")
+)
+
+func (g *generator) addBaselineGPOs(d *domain, workstations, servers, domaincontrollers, workstationadmins, serveradmins *object) {
+	domainobject := d.bydn[strings.ToLower(d.dn)]
+	g.addGPO(d, "Default Domain Policy", defaultDomainPolicy, domainobject)
+	g.addGPO(d, "Default Domain Controllers Policy", defaultDomainControllersPolicy, domaincontrollers)
+
+	wsgpo := g.addGPO(d, "Workstation Local Administrators", g.guid(), workstations)
+	wsgpo.addMember(windowssecurity.AdministratorsSID, workstationadmins.sid)
+	srvgpo := g.addGPO(d, "Server Local Administrators", g.guid(), servers)
+	srvgpo.addMember(windowssecurity.AdministratorsSID, serveradmins.sid)
+	rdpgpo := g.addGPO(d, "Server Remote Desktop Access", g.guid(), servers)
+	rdpgpo.addMember(windowssecurity.RemoteDesktopUsersSID, serveradmins.sid)
+}
+
+// Adds a group policy container and links it to the containers
+func (g *generator) addGPO(d *domain, name string, guid uuid.UUID, linkedto ...*object) *gpo {
+	dn := "CN={" + strings.ToUpper(guid.String()) + "},CN=Policies,CN=System," + d.dn
+	o := g.add(d, dn, "groupPolicyContainer")
+	o.set("displayName", name)
+	o.set("gPCFileSysPath", g.gpoPath(d, guid))
+	o.set("gPCFunctionalityVersion", "2")
+	o.set("versionNumber", fmt.Sprint(1+g.rnd.Intn(40)))
+	o.set("flags", "0")
+	g.add(d, "CN=Machine,"+dn, "container")
+	g.add(d, "CN=User,"+dn, "container")
+
+	for _, container := range linkedto {
+		container.set("gPLink", container.get("gPLink")+"[LDAP://"+dn+";0]")
+	}
+
+	result := &gpo{
+		guid:   guid,
+		object: o,
+	}
+	d.gpos = append(d.gpos, result)
+	return result
+}
+
+func (g *generator) gpoPath(d *domain, guid uuid.UUID) string {
+	return `\\` + d.dns + `\SysVol\` + d.dns + `\Policies\{` + strings.ToUpper(guid.String()) + `}`
+}
+
+func (gp *gpo) addMember(group, member windowssecurity.SID) {
+	gp.memberships = append(gp.memberships, gpoMembership{group: group.String(), member: member})
+}
+
+func (d *domain) findGPO(name string) *gpo {
+	for _, gp := range d.gpos {
+		if gp.object.get("displayName") == name {
+			return gp
+		}
+	}
+	return nil
+}
+
+// What the collector would have saved from SYSVOL
+func (g *generator) gpoDump(d *domain, gp *gpo) activedirectory.GPOdump {
+	// Everyone can read policies, only admins can change them
+	dacl := engine.ACL{
+		Revision: 2,
+		Entries: []engine.ACE{
+			newACE(windowssecurity.SystemSID, 0x1f01ff, uuid.Nil, uuid.Nil, 0),
+			newACE(d.rid(512), 0x1f01ff, uuid.Nil, uuid.Nil, 0),
+			newACE(g.root().rid(519), 0x1f01ff, uuid.Nil, uuid.Nil, 0),
+			newACE(windowssecurity.AuthenticatedUsersSID, 0x1200a9, uuid.Nil, uuid.Nil, 0),
+		},
+	}.Bytes()
+
+	timestamp := g.daysAgo(1, 1000)
+	dump := activedirectory.GPOdump{
+		Common: g.common(),
+	}
+	dump.GUID = gp.guid
+	dump.DomainDN = d.dn
+	dump.DomainNetbios = d.netbios
+	dump.Path = g.gpoPath(d, gp.guid)
+
+	dir := func(path string) {
+		dump.Files = append(dump.Files, activedirectory.GPOfileinfo{
+			RelativePath: path,
+			IsDir:        true,
+			Timestamp:    timestamp,
+			OwnerSID:     d.rid(512),
+			DACL:         dacl,
+		})
+	}
+	file := func(path string, contents []byte) {
+		dump.Files = append(dump.Files, activedirectory.GPOfileinfo{
+			RelativePath: path,
+			Size:         int64(len(contents)),
+			Timestamp:    timestamp,
+			OwnerSID:     d.rid(512),
+			DACL:         dacl,
+			Contents:     contents,
+		})
+	}
+
+	dir("")
+	file(`\GPT.INI`, []byte(fmt.Sprintf("[General]\r\nVersion=%v\r\ndisplayName=%v\r\n", gp.object.get("versionNumber"), gp.object.get("displayName"))))
+	dir(`\Machine`)
+	dir(`\User`)
+
+	var gpttmpl []string
+	if gp.guid == defaultDomainPolicy {
+		gpttmpl = append(gpttmpl,
+			"[System Access]",
+			"MinimumPasswordAge = 1",
+			"MaximumPasswordAge = 42",
+			"MinimumPasswordLength = 7",
+			"PasswordComplexity = 1",
+			"LockoutBadCount = 0",
+		)
+	}
+	if len(gp.memberships) > 0 {
+		gpttmpl = append(gpttmpl, "[Group Membership]")
+		var groups []string
+		members := make(map[string][]string)
+		for _, membership := range gp.memberships {
+			if _, found := members[membership.group]; !found {
+				groups = append(groups, membership.group)
+			}
+			members[membership.group] = append(members[membership.group], "*"+membership.member.String())
+		}
+		for _, group := range groups {
+			gpttmpl = append(gpttmpl,
+				"*"+group+"__Memberof =",
+				"*"+group+"__Members = "+strings.Join(members[group], ","),
+			)
+		}
+	}
+	if len(gpttmpl) > 0 {
+		header := []string{"[Unicode]", "Unicode=yes", "[Version]", `signature="$CHICAGO$"`, "Revision=1"}
+		contents, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(strings.Join(append(header, gpttmpl...), "\r\n") + "\r\n"))
+		dir(`\Machine\Microsoft`)
+		dir(`\Machine\Microsoft\Windows NT`)
+		dir(`\Machine\Microsoft\Windows NT\SecEdit`)
+		file(`\Machine\Microsoft\Windows NT\SecEdit\GptTmpl.inf`, contents)
+	}
+
+	if gp.groupsxml != "" {
+		dir(`\Machine\Preferences`)
+		dir(`\Machine\Preferences\Groups`)
+		file(`\Machine\Preferences\Groups\Groups.xml`, []byte(gp.groupsxml))
+	}
+
+	return dump
+}
+
+// Groups.xml that sets the password for an account, like admins did before MS14-025
+func (g *generator) gppUser(username, password string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<Groups clsid="{3125E937-EB16-4b4c-9934-544FC6D24D26}"><User clsid="{DF5F1855-51E5-4d24-8B1A-D9BDE98BA1D1}" name="%v" image="2" changed="%v" uid="{%v}"><Properties action="U" newName="" fullName="" description="" cpassword="%v" changeLogon="0" noChange="1" neverExpires="1" acctDisabled="0" userName="%v"/></User>
+</Groups>
+`, username, g.daysAgo(365, 2000).Format("2006-01-02 15:04:05"), strings.ToUpper(g.guid().String()), gppEncrypt(password), username)
+}
+
+// AES-256-CBC with a zero IV over the UTF-16 password, base64 encoded without padding
+func gppEncrypt(password string) string {
+	key, _ := hex.DecodeString(gppKey)
+	block, _ := aes.NewCipher(key)
+	plaintext, _ := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().Bytes([]byte(password))
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(ciphertext, plaintext)
+	return base64.RawStdEncoding.EncodeToString(ciphertext)
+}
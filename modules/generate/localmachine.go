@@ -0,0 +1,173 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+type machine struct {
+	computer *object
+	info     localmachine.Info
+}
+
+// Users that log on to a machine on top of the regular ones, misconfigurations add to this
+type session struct {
+	computer, user *object
+}
+
+// Adds local machine data for a sample of the member computers, and for every computer with planted sessions
+func (g *generator) addLocalMachines(d *domain) {
+	index := 0
+	for i, di := range g.domains {
+		if di == d {
+			index = i
+		}
+	}
+
+	members := append(append([]*object{}, d.workstations...), d.servers...)
+	selected := make(map[*object]bool)
+	for _, computer := range g.sample(members, g.options.LocalMachines) {
+		selected[computer] = true
+	}
+	for _, s := range d.sessions {
+		selected[s.computer] = true
+	}
+
+	sids := make(map[string]*object)
+	for _, o := range d.objects {
+		if !o.sid.IsNull() {
+			sids[o.sid.String()] = o
+		}
+	}
+	member := func(sid windowssecurity.SID) localmachine.Member {
+		if o := sids[sid.String()]; o != nil {
+			return localmachine.Member{Name: d.netbios + `\` + o.name(), SID: sid.String()}
+		}
+		return localmachine.Member{SID: sid.String()}
+	}
+
+	for _, computer := range members {
+		if !selected[computer] {
+			continue
+		}
+		server := parentDN(computer.dn) == "OU=Servers,"+d.dn
+
+		name := strings.TrimSuffix(computer.name(), "$")
+		localsid := g.domainSID()
+		enablelua := uint64(1)
+		m := &machine{
+			computer: computer,
+			info: localmachine.Info{
+				Common: g.common(),
+				Machine: localmachine.Machine{
+					Name:                          name,
+					LocalSID:                      localsid.String(),
+					Domain:                        d.netbios,
+					ComputerDomainSID:             computer.sid.String(),
+					IsDomainJoined:                true,
+					Architecture:                  "AMD64",
+					NumberOfProcessors:            2 + 2*g.rnd.Intn(4),
+					ProductName:                   computer.get("operatingSystem"),
+					ProductType:                   "WinNT",
+					MajorVersionNumber:            10,
+					BuildNumber:                   g.pick([]string{"19045", "22621", "22631"}),
+					UACEnableLUA:                  &enablelua,
+					UACConsentPromptBehaviorAdmin: 5,
+				},
+				Users: localmachine.Users{
+					{Name: "Administrator", SID: localsid.AddComponent(500).String(), IsEnabled: true, IsAdmin: true, PasswordLastSet: g.daysAgo(30, 1500)},
+					{Name: "Guest", SID: localsid.AddComponent(501).String()},
+				},
+			},
+		}
+		if server {
+			m.info.Machine.ProductType = "ServerNT"
+			m.info.Machine.BuildNumber = g.pick([]string{"14393", "17763", "20348"})
+		}
+
+		// Network addresses from the position in the domain, servers and workstations have separate subnets
+		var position int
+		for i, o := range members {
+			if o == computer {
+				position = i
+			}
+		}
+		address := fmt.Sprintf("10.%d.%d.%d/16", index, 100+position/250, 10+position%250)
+		if server {
+			address = fmt.Sprintf("10.%d.10.%d/16", index, 10+position%240)
+		}
+		m.info.Network.NetworkInterfaces = []localmachine.NetworkInterfaceInfo{{
+			Name:       "Ethernet0",
+			MACAddress: fmt.Sprintf("00:15:5d:%02x:%02x:%02x", index, position/256, position%256),
+			Addresses:  []string{address},
+		}}
+
+		// Local groups reflect what the linked GPOs put in them
+		groups := map[string][]windowssecurity.SID{
+			windowssecurity.AdministratorsSID.String(): {localsid.AddComponent(500), d.rid(512)},
+			"S-1-5-32-545": {d.rid(513)},
+			windowssecurity.RemoteDesktopUsersSID.String(): nil,
+			windowssecurity.DCOMUsersSID.String():          nil,
+		}
+		for _, gp := range d.gpos {
+			if !g.appliesTo(d, gp, computer) {
+				continue
+			}
+			for _, membership := range gp.memberships {
+				groups[membership.group] = append(groups[membership.group], membership.member)
+			}
+		}
+		for _, group := range []struct{ name, sid string }{
+			{"Administrators", windowssecurity.AdministratorsSID.String()},
+			{"Users", "S-1-5-32-545"},
+			{"Remote Desktop Users", windowssecurity.RemoteDesktopUsersSID.String()},
+			{"Distributed COM Users", windowssecurity.DCOMUsersSID.String()},
+		} {
+			lg := localmachine.Group{Name: group.name, SID: group.sid}
+			for _, sid := range groups[group.sid] {
+				lg.Members = append(lg.Members, member(sid))
+			}
+			m.info.Groups = append(m.info.Groups, lg)
+		}
+
+		// Someone uses every workstation daily, servers see their admins now and then
+		var regulars []*object
+		if server {
+			if serveradmins := d.find("Server Admins"); serveradmins != nil && len(serveradmins.members) > 0 {
+				regulars = append(regulars, serveradmins.members[g.rnd.Intn(len(serveradmins.members))])
+			}
+		} else {
+			regulars = append(regulars, d.users[g.rnd.Intn(len(d.users))])
+		}
+		for _, s := range d.sessions {
+			if s.computer == computer {
+				regulars = append(regulars, s.user)
+			}
+		}
+		for _, user := range regulars {
+			count := func(n int) localmachine.LoginCount {
+				return localmachine.LoginCount{Name: d.netbios + `\` + user.name(), SID: user.sid.String(), Count: uint64(n)}
+			}
+			days := 1 + g.rnd.Intn(3)
+			m.info.LoginPopularity.Day = append(m.info.LoginPopularity.Day, count(days))
+			m.info.LoginPopularity.Week = append(m.info.LoginPopularity.Week, count(days*5))
+			m.info.LoginPopularity.Month = append(m.info.LoginPopularity.Month, count(days*20))
+		}
+
+		d.localmachines = append(d.localmachines, m)
+	}
+}
+
+// Checks if the GPO is linked to the computers OU or the domain
+func (g *generator) appliesTo(d *domain, gp *gpo, computer *object) bool {
+	link := "[LDAP://" + gp.object.dn + ";0]"
+	for container := parentDN(computer.dn); container != ""; container = parentDN(container) {
+		if o := d.bydn[strings.ToLower(container)]; o != nil && strings.Contains(o.get("gPLink"), link) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package generate
+
+import (
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+// Misconfiguration is something that can be planted in the generated forest, the descriptions tell which accounts it affects so tests know what to look for
+type Misconfiguration struct {
+	Name        string
+	Description string
+	apply       func(g *generator, d *domain)
+}
+
+var misconfigurations = []Misconfiguration{
+	{
+		Name:        "kerberoastable-admin",
+		Description: "Service account svc_sql has an SPN and is a member of Domain Admins",
+		apply: func(g *generator, d *domain) {
+			svc := g.addServiceAccount(d, "svc_sql", "SQL Server service")
+			svc.set("servicePrincipalName", "MSSQLSvc/sql01."+d.dns+":1433", "MSSQLSvc/sql01."+d.dns)
+			svc.protected = true
+			d.find("Domain Admins").members = append(d.find("Domain Admins").members, svc)
+		},
+	},
+	{
+		Name:        "asreproast",
+		Description: "Service account svc_legacy doesn't require Kerberos preauthentication and is a member of Backup Operators",
+		apply: func(g *generator, d *domain) {
+			svc := g.addServiceAccount(d, "svc_legacy", "Old backup agent")
+			svc.setuac(svc.uac() | uacDontRequirePreauth)
+			svc.protected = true
+			d.find("Backup Operators").members = append(d.find("Backup Operators").members, svc)
+		},
+	},
+	{
+		Name:        "helpdesk-reset-admin",
+		Description: "A user in the Staff OU is a member of Server Admins, so Helpdesk can reset the password of a server administrator",
+		apply: func(g *generator, d *domain) {
+			helpdesk := d.find("Helpdesk")
+			for _, user := range d.users {
+				if !contains(helpdesk.members, user) {
+					d.find("Server Admins").members = append(d.find("Server Admins").members, user)
+					return
+				}
+			}
+		},
+	},
+	{
+		Name:        "writedacl-domain",
+		Description: "The Exchange Windows Permissions group can change the DACL of the domain object, and an Exchange server is a member",
+		apply: func(g *generator, d *domain) {
+			group := g.addGroup(d, "OU=Groups,"+d.dn, "Exchange Windows Permissions", groupUniversalSecurity, 0)
+			group.members = append(group.members, d.server("EXCH"))
+			d.bydn[strings.ToLower(d.dn)].allow(group.sid, engine.RIGHT_WRITE_DACL, uuid.Nil)
+		},
+	},
+	{
+		Name:        "dcsync",
+		Description: "Sync account svc_aadsync has the replication rights needed for DCsync",
+		apply: func(g *generator, d *domain) {
+			svc := g.addServiceAccount(d, "svc_aadsync", "Directory synchronization")
+			domainobject := d.bydn[strings.ToLower(d.dn)]
+			domainobject.allow(svc.sid, engine.RIGHT_DS_CONTROL_ACCESS, extendedRightGetChanges)
+			domainobject.allow(svc.sid, engine.RIGHT_DS_CONTROL_ACCESS, extendedRightGetChangesAll)
+		},
+	},
+	{
+		Name:        "unconstrained-delegation",
+		Description: "The first APP server is trusted for unconstrained delegation",
+		apply: func(g *generator, d *domain) {
+			server := d.server("APP")
+			server.setuac(server.uac() | uacTrustedForDelegation)
+		},
+	},
+	{
+		Name:        "rbcd",
+		Description: "The first WEB server allows the first workstation to impersonate users to it with resource based constrained delegation",
+		apply: func(g *generator, d *domain) {
+			sd := engine.SecurityDescriptor{
+				Owner:   windowssecurity.AdministratorsSID,
+				Control: engine.CONTROLFLAG_DACL_PRESENT,
+				DACL: engine.ACL{
+					Revision: 4,
+					Entries:  []engine.ACE{newACE(d.workstations[0].sid, engine.RIGHT_GENERIC_ALL, uuid.Nil, uuid.Nil, 0)},
+				},
+			}
+			d.server("WEB").set("msDS-AllowedToActOnBehalfOfOtherIdentity", string(sd.Bytes()))
+		},
+	},
+	{
+		Name:        "shadow-credentials",
+		Description: "Helpdesk can write msDS-KeyCredentialLink on the first FS server",
+		apply: func(g *generator, d *domain) {
+			d.server("FS").allow(d.find("Helpdesk").sid, engine.RIGHT_DS_WRITE_PROPERTY, lookupAttribute("msDS-KeyCredentialLink").guid)
+		},
+	},
+	{
+		Name:        "esc1-template",
+		Description: "Published certificate template CorpUserAuth allows client authentication with a subject of the enrollees choice, and Domain Users can enroll",
+		apply: func(g *generator, d *domain) {
+			template := g.addTemplate("CorpUserAuth", "1", "1.3.6.1.5.5.7.3.2")
+			template.allow(d.rid(513), engine.RIGHT_DS_CONTROL_ACCESS, extendedRightCertificateEnroll)
+		},
+	},
+	{
+		Name:        "gpo-local-admin",
+		Description: "The Workstation Local Administrators GPO makes Domain Users local administrators",
+		apply: func(g *generator, d *domain) {
+			d.findGPO("Workstation Local Administrators").addMember(windowssecurity.AdministratorsSID, d.rid(513))
+		},
+	},
+	{
+		Name:        "gpo-edit",
+		Description: "The first department group can modify the Server Local Administrators GPO",
+		apply: func(g *generator, d *domain) {
+			d.findGPO("Server Local Administrators").object.allow(d.departmentgroups[0].sid, engine.RIGHT_GENERIC_WRITE, uuid.Nil)
+		},
+	},
+	{
+		Name:        "gpp-password",
+		Description: "A GPO linked to servers has a Group Policy Preferences password for svc_deploy, which is a member of Server Admins",
+		apply: func(g *generator, d *domain) {
+			svc := g.addServiceAccount(d, "svc_deploy", "Software deployment")
+			d.find("Server Admins").members = append(d.find("Server Admins").members, svc)
+			gp := g.addGPO(d, "Deploy Service Account", g.guid(), d.bydn[strings.ToLower("OU=Servers,"+d.dn)])
+			gp.groupsxml = g.gppUser("svc_deploy", "Deploy2014!")
+		},
+	},
+	{
+		Name:        "admin-session",
+		Description: "The first named Domain Admin logs on to the first workstation every day",
+		apply: func(g *generator, d *domain) {
+			d.sessions = append(d.sessions, session{computer: d.workstations[0], user: d.admins[0]})
+		},
+	},
+	{
+		Name:        "trust-no-sid-filtering",
+		Description: "The external trust to fabrikam.com doesn't filter SIDs",
+		apply: func(g *generator, d *domain) {
+			for _, trust := range d.trusts {
+				if trust.get("trustPartner") == "fabrikam.com" {
+					trust.set("trustAttributes", "0")
+				}
+			}
+		},
+	},
+	{
+		Name:        "pre2000-computer",
+		Description: "Computer LEGACY01 was precreated as pre-Windows 2000 compatible and has never been used, so the password is the lowercase name",
+		apply: func(g *generator, d *domain) {
+			computer := g.addComputer(d, "CN=Computers,"+d.dn, "LEGACY01", "Windows XP Professional")
+			computer.setuac(uacWorkstationTrustAccount | uacPasswordNotRequired)
+			computer.set("pwdLastSet", filetime(g.daysAgo(2000, 3000)))
+			delete(computer.attributes, "lastLogonTimestamp")
+			delete(computer.attributes, "dNSHostName")
+		},
+	},
+}
+
+// MisconfigurationNames returns the names of all the misconfigurations in the catalogue
+func MisconfigurationNames() []string {
+	result := make([]string, len(misconfigurations))
+	for i, m := range misconfigurations {
+		result[i] = m.Name
+	}
+	return result
+}
+
+// Misconfigurations returns the catalogue
+func Misconfigurations() []Misconfiguration {
+	return misconfigurations
+}
+
+func findMisconfiguration(name string) *Misconfiguration {
+	for i := range misconfigurations {
+		if strings.EqualFold(misconfigurations[i].Name, name) {
+			return &misconfigurations[i]
+		}
+	}
+	return nil
+}
+
+// Service accounts live with the other Tier 0 stuff, and never get their passwords changed
+func (g *generator) addServiceAccount(d *domain, name, description string) *object {
+	svc := g.addUser(d, "OU=Service Accounts,OU=Tier 0,"+d.dn, name, "", 0)
+	svc.set("description", description)
+	svc.set("pwdLastSet", filetime(g.daysAgo(1000, 2500)))
+	svc.setuac(uacNormalAccount | uacDontExpirePassword)
+	return svc
+}
+
+// First server with the role, or the last server if there are none
+func (d *domain) server(role string) *object {
+	for _, server := range d.servers {
+		if strings.HasPrefix(server.name(), role) {
+			return server
+		}
+	}
+	return d.servers[len(d.servers)-1]
+}
+
+func contains(objects []*object, o *object) bool {
+	for _, candidate := range objects {
+		if candidate == o {
+			return true
+		}
+	}
+	return false
+}
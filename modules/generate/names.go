@@ -0,0 +1,38 @@
+package generate
+
+var givenNames = []string{
+	"Aaron", "Abigail", "Adam", "Aisha", "Alexander", "Alice", "Amelia", "Andrew", "Anna", "Anthony",
+	"Benjamin", "Bianca", "Carlos", "Caroline", "Charlotte", "Christian", "Claire", "Daniel", "David", "Diana",
+	"Edward", "Elena", "Elizabeth", "Emil", "Emma", "Erik", "Fatima", "Felix", "Fiona", "Frederik",
+	"Gabriel", "Grace", "Hannah", "Henrik", "Ida", "Isabella", "Jack", "James", "Jasmine", "Johan",
+	"Julia", "Kevin", "Laura", "Lucas", "Maja", "Maria", "Martin", "Mia", "Michael", "Mohammed",
+	"Nadia", "Nicolas", "Noah", "Olivia", "Oscar", "Patrick", "Paula", "Peter", "Rachel", "Rasmus",
+	"Robert", "Sara", "Sebastian", "Sofia", "Sophie", "Stefan", "Thomas", "Victoria", "William", "Zoe",
+}
+
+var surnames = []string{
+	"Andersen", "Baker", "Becker", "Bennett", "Brown", "Carter", "Christensen", "Clark", "Collins", "Cooper",
+	"Davies", "Dubois", "Evans", "Fischer", "Garcia", "Green", "Hall", "Hansen", "Harris", "Hoffmann",
+	"Hughes", "Jensen", "Johnson", "Jones", "Kim", "King", "Larsen", "Lee", "Lopez", "Martin",
+	"Meyer", "Miller", "Moore", "Morris", "Nguyen", "Nielsen", "Novak", "Olsen", "Parker", "Pedersen",
+	"Petersen", "Rasmussen", "Roberts", "Rossi", "Schmidt", "Schneider", "Scott", "Silva", "Smith", "Taylor",
+	"Thomas", "Thompson", "Turner", "Walker", "Weber", "White", "Williams", "Wilson", "Wright", "Young",
+}
+
+var departments = []string{
+	"Sales", "Marketing", "Finance", "Human Resources", "Engineering", "Support", "Legal", "Operations",
+	"Research", "Purchasing", "Logistics", "Production", "Communications", "Quality", "Facilities",
+}
+
+var projects = []string{
+	"Apollo", "Atlas", "Aurora", "Borealis", "Cascade", "Comet", "Delta", "Eclipse", "Falcon", "Gemini",
+	"Horizon", "Jupiter", "Keystone", "Lighthouse", "Meridian", "Nebula", "Orion", "Pegasus", "Phoenix", "Polaris",
+	"Quasar", "Saturn", "Sentinel", "Sirius", "Summit", "Titan", "Vega", "Voyager", "Zenith", "Zephyr",
+}
+
+// Prefixes for child domains, after that they're numbered
+var childDomains = []string{"emea", "amer", "apac", "lab", "dev", "prod"}
+
+var workstationProducts = []string{"Windows 10 Enterprise", "Windows 11 Enterprise", "Windows 11 Pro"}
+
+var serverProducts = []string{"Windows Server 2016 Standard", "Windows Server 2019 Standard", "Windows Server 2022 Standard", "Windows Server 2022 Datacenter"}
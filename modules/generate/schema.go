@@ -0,0 +1,104 @@
+package generate
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// Classes used by the generated objects, the type of an object is derived from the name of its object category
+type schemaClass struct {
+	ldapname string
+	name     string
+	guid     uuid.UUID
+	parents  []string // Object classes before this one in objectClass, most generic first
+	category string   // lDAPDisplayName of the object category, if it's not the class itself
+}
+
+var schemaClasses = []schemaClass{
+	{ldapname: "top", name: "Top", guid: uuid.FromStringOrNil("bf967ab7-0de6-11d0-a285-00aa003049e2")},
+	{ldapname: "person", name: "Person", guid: uuid.FromStringOrNil("bf967aa7-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "organizationalPerson", name: "Organizational-Person", guid: uuid.FromStringOrNil("bf967aa4-0de6-11d0-a285-00aa003049e2"), parents: []string{"top", "person"}},
+	{ldapname: "user", name: "User", guid: uuid.FromStringOrNil("bf967aba-0de6-11d0-a285-00aa003049e2"), parents: []string{"top", "person", "organizationalPerson"}, category: "person"},
+	{ldapname: "computer", name: "Computer", guid: uuid.FromStringOrNil("bf967a86-0de6-11d0-a285-00aa003049e2"), parents: []string{"top", "person", "organizationalPerson", "user"}},
+	{ldapname: "group", name: "Group", guid: uuid.FromStringOrNil("bf967a9c-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "organizationalUnit", name: "Organizational-Unit", guid: uuid.FromStringOrNil("bf967aa5-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "container", name: "Container", guid: uuid.FromStringOrNil("bf967a8b-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "domain", name: "Domain", guid: uuid.FromStringOrNil("19195a5a-6da0-11d0-afd3-00c04fd930c9"), parents: []string{"top"}},
+	{ldapname: "domainDNS", name: "Domain-DNS", guid: uuid.FromStringOrNil("19195a5b-6da0-11d0-afd3-00c04fd930c9"), parents: []string{"top", "domain"}},
+	{ldapname: "builtinDomain", name: "Builtin-Domain", guid: uuid.FromStringOrNil("bf967a81-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "groupPolicyContainer", name: "Group-Policy-Container", guid: uuid.FromStringOrNil("f30e3bc2-9ff0-11d1-b603-0000f80367c1"), parents: []string{"top", "container"}},
+	{ldapname: "leaf", name: "Leaf", guid: uuid.FromStringOrNil("bf967a9e-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "trustedDomain", name: "Trusted-Domain", guid: uuid.FromStringOrNil("bf967ab8-0de6-11d0-a285-00aa003049e2"), parents: []string{"top", "leaf"}},
+	{ldapname: "configuration", name: "Configuration", guid: uuid.FromStringOrNil("bf967a87-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "crossRefContainer", name: "Cross-Ref-Container", guid: uuid.FromStringOrNil("ef9e60e0-56f7-11d1-a9c6-0000f80367c1"), parents: []string{"top"}},
+	{ldapname: "crossRef", name: "Cross-Ref", guid: uuid.FromStringOrNil("bf967a8d-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "dMD", name: "DMD", guid: uuid.FromStringOrNil("bf967a8f-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "classSchema", name: "Class-Schema", guid: uuid.FromStringOrNil("bf967a83-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "attributeSchema", name: "Attribute-Schema", guid: uuid.FromStringOrNil("bf967a80-0de6-11d0-a285-00aa003049e2"), parents: []string{"top"}},
+	{ldapname: "pKICertificateTemplate", name: "PKI-Certificate-Template", guid: uuid.FromStringOrNil("e5209ca2-3bba-11d2-90cc-00c04fd91ab1"), parents: []string{"top"}},
+	{ldapname: "pKIEnrollmentService", name: "PKI-Enrollment-Service", guid: uuid.FromStringOrNil("ee4aa692-3bba-11d2-90cc-00c04fd91ab1"), parents: []string{"top"}},
+}
+
+// Attributes that ACEs refer to, property sets are needed to resolve ACEs granting a whole set
+type schemaAttribute struct {
+	ldapname    string
+	name        string
+	guid        uuid.UUID
+	propertyset uuid.UUID
+}
+
+var (
+	propertySetMembership           = uuid.FromStringOrNil("bc0ac240-79a9-11d0-9020-00c04fc2d4cf")
+	propertySetUserAccountRestricts = uuid.FromStringOrNil("4c164200-20c0-11d0-a768-00aa003049e2")
+	propertySetUserLogon            = uuid.FromStringOrNil("5f202010-79a5-11d0-9020-00c04fc2d4cf")
+)
+
+var schemaAttributes = []schemaAttribute{
+	{ldapname: "member", name: "Member", guid: uuid.FromStringOrNil("bf9679c0-0de6-11d0-a285-00aa003049e2"), propertyset: propertySetMembership},
+	{ldapname: "servicePrincipalName", name: "Service-Principal-Name", guid: uuid.FromStringOrNil("f3a64788-5306-11d1-a9c5-0000f80367c1")},
+	{ldapname: "userAccountControl", name: "User-Account-Control", guid: uuid.FromStringOrNil("bf967a68-0de6-11d0-a285-00aa003049e2"), propertyset: propertySetUserAccountRestricts},
+	{ldapname: "pwdLastSet", name: "Pwd-Last-Set", guid: uuid.FromStringOrNil("bf967a0a-0de6-11d0-a285-00aa003049e2"), propertyset: propertySetUserAccountRestricts},
+	{ldapname: "scriptPath", name: "Script-Path", guid: uuid.FromStringOrNil("bf9679a8-0de6-11d0-a285-00aa003049e2"), propertyset: propertySetUserLogon},
+	{ldapname: "profilePath", name: "Profile-Path", guid: uuid.FromStringOrNil("bf967a05-0de6-11d0-a285-00aa003049e2"), propertyset: propertySetUserLogon},
+	{ldapname: "altSecurityIdentities", name: "Alt-Security-Identities", guid: uuid.FromStringOrNil("00fbf30c-91fe-11d1-aebc-0000f80367c1")},
+	{ldapname: "msDS-KeyCredentialLink", name: "ms-DS-Key-Credential-Link", guid: uuid.FromStringOrNil("5b47d60f-6090-40b2-9f37-2a4de88f3063")},
+	{ldapname: "msDS-AllowedToActOnBehalfOfOtherIdentity", name: "ms-DS-Allowed-To-Act-On-Behalf-Of-Other-Identity", guid: uuid.FromStringOrNil("3f78c3e5-f79a-46bd-a0b8-9d18116ddc79")},
+	{ldapname: "gPLink", name: "GP-Link", guid: uuid.FromStringOrNil("f30e3bbe-9ff0-11d1-b603-0000f80367c1")},
+	{ldapname: "sIDHistory", name: "SID-History", guid: uuid.FromStringOrNil("17eb4278-d167-11d0-b002-0000f80367c1")},
+}
+
+func lookupClass(ldapname string) schemaClass {
+	for _, class := range schemaClasses {
+		if class.ldapname == ldapname {
+			return class
+		}
+	}
+	panic("generator uses unknown schema class " + ldapname)
+}
+
+func lookupAttribute(ldapname string) schemaAttribute {
+	for _, attribute := range schemaAttributes {
+		if attribute.ldapname == ldapname {
+			return attribute
+		}
+	}
+	panic("generator uses unknown schema attribute " + ldapname)
+}
+
+// Adds the schema partition to the forest
+func (g *generator) addSchema() {
+	schemadn := "CN=Schema," + g.configdn
+	g.addConfig(schemadn, "dMD")
+	for _, class := range schemaClasses {
+		o := g.addConfig("CN="+class.name+","+schemadn, "classSchema")
+		o.set("lDAPDisplayName", class.ldapname)
+		o.set("schemaIDGUID", guidBytes(class.guid))
+	}
+	for _, attribute := range schemaAttributes {
+		o := g.addConfig("CN="+attribute.name+","+schemadn, "attributeSchema")
+		o.set("lDAPDisplayName", attribute.ldapname)
+		o.set("schemaIDGUID", guidBytes(attribute.guid))
+		if !attribute.propertyset.IsNil() {
+			o.set("attributeSecurityGUID", guidBytes(attribute.propertyset))
+		}
+	}
+}
@@ -1,11 +1,68 @@
 package activedirectory
 
-import "github.com/lkarlslund/adalanche/modules/engine"
+import (
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
 
 func NotAChance(source, target *engine.Object) engine.Probability {
 	return 0
 }
 
+// RoastProbability estimates the chance of cracking a roasted ticket for the account, based on
+// the password age when collected, the effective password policy and the Kerberos encryption types supported
+func RoastProbability(target *engine.Object) engine.Probability {
+	if target.Type() == engine.ObjectTypeGroupManagedServiceAccount || target.Type() == engine.ObjectTypeManagedServiceAccount {
+		// Long random passwords rotated automatically
+		return 1
+	}
+
+	probability := 50
+
+	if pwdlastset, ok := target.AttrTime(PwdLastSet); ok {
+		age := CollectionTime(target).Sub(pwdlastset)
+		switch {
+		case age > 3*365*24*time.Hour:
+			probability += 20
+		case age > 365*24*time.Hour:
+			probability += 10
+		case age < 30*24*time.Hour:
+			probability -= 10
+		}
+	}
+
+	if minlength, ok := target.AttrInt(EffectiveMinPwdLength); ok {
+		switch {
+		case minlength < 8:
+			probability += 15
+		case minlength >= 20:
+			probability -= 25
+		case minlength >= 14:
+			probability -= 15
+		}
+	}
+	if complexity, ok := target.AttrBool(EffectivePasswordComplexity); ok && !complexity {
+		probability += 5
+	}
+
+	// Accounts without msDS-SupportedEncryptionTypes get RC4 tickets, which are much faster to crack
+	enctypes, _ := target.AttrInt(MSDSSupportedEncryptionTypes)
+	if enctypes == 0 || enctypes&(engine.ENCTYPE_AES128_CTS_HMAC_SHA1_96|engine.ENCTYPE_AES256_CTS_HMAC_SHA1_96) == 0 {
+		probability += 10
+	} else if enctypes&engine.ENCTYPE_RC4_HMAC == 0 {
+		probability -= 10
+	}
+
+	if probability < 1 {
+		probability = 1
+	}
+	if probability > 100 {
+		probability = 100
+	}
+	return engine.Probability(probability)
+}
+
 var (
 	EdgeACLContainsDeny = engine.NewEdge("ACLContainsDeny").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 0 }).Tag("Informative")
 	EdgeResetPassword   = engine.NewEdge("ResetPassword").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
@@ -70,8 +127,15 @@ var (
 		}
 		return 100
 	}).Tag("Pivot")
-	EdgeWriteAttributeSecurityGUID           = engine.NewEdge("WriteAttrSecurityGUID").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 0 }) // Only if you patch the DC, so this will actually never work
-	EdgeSIDHistoryEquality                   = engine.NewEdge("SIDHistoryEquality").Tag("Pivot")
+	EdgeWriteAttributeSecurityGUID = engine.NewEdge("WriteAttrSecurityGUID").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 0 }) // Only if you patch the DC, so this will actually never work
+	EdgeSIDHistoryEquality         = engine.NewEdge("SIDHistoryEquality").Tag("Pivot")
+	EdgeTrustAuthentication        = engine.NewEdge("TrustAuthentication").Describe("Principals from the trusted domain can authenticate to the trusting domain").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+		return -1 // Just informative
+	}).Tag("Informative")
+	EdgeTrustSIDHistory    = engine.NewEdge("TrustSIDHistory").Describe("Trust does not do SID filtering, so the trusted domain can inject SIDs from the trusting domain using SID history or extra SIDs").Tag("Pivot")
+	EdgeTrustTGTDelegation = engine.NewEdge("TrustTGTDelegation").Describe("Trust allows TGT delegation, so unconstrained delegation hosts in the trusting domain can capture TGTs from the trusted domain").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+		return 75
+	}).Tag("Pivot")
 	EdgeAllExtendedRights                    = engine.NewEdge("AllExtendedRights").Tag("Informative").RegisterProbabilityCalculator(NotAChance)
 	EdgeDSReplicationSyncronize              = engine.NewEdge("DSReplSync").Tag("Granted").SetDefault(false, false, false).Tag("Granted").RegisterProbabilityCalculator(NotAChance)
 	EdgeDSReplicationGetChanges              = engine.NewEdge("DSReplGetChngs").SetDefault(false, false, false).Tag("Granted").Tag("Granted").RegisterProbabilityCalculator(NotAChance)
@@ -84,7 +148,7 @@ var (
 	EdgeMemberOfGroupIndirect                = engine.NewEdge("MemberOfGroupIndirect").SetDefault(false, false, false).Tag("Granted")
 	EdgeHasSPN                               = engine.NewEdge("HasSPN").Describe("Kerberoastable by requesting Kerberos service ticket against SPN and then bruteforcing the ticket").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
 		if target.HasTag("account_active") {
-			return 50
+			return RoastProbability(target)
 		}
 		// Account is disabled
 		return 0
@@ -94,14 +158,14 @@ var (
 			// Account is disabled
 			return 0
 		}
-		return 50
+		return RoastProbability(target)
 	}).Tag("Pivot")
 	EdgeOverwritesACL              = engine.NewEdge("OverwritesACL")
 	EdgeAffectedByGPO              = engine.NewEdge("AffectedByGPO").Tag("Granted").Tag("Pivot")
 	PartOfGPO                      = engine.NewEdge("PartOfGPO").Tag("Granted").Tag("Pivot")
-	EdgeLocalAdminRights           = engine.NewEdge("AdminRights").Tag("Granted").Tag("Pivot")
-	EdgeLocalRDPRights             = engine.NewEdge("RDPRights").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 30 }).Tag("Pivot")
-	EdgeLocalDCOMRights            = engine.NewEdge("DCOMRights").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Pivot")
+	EdgeLocalAdminRights           = engine.NewEdge("AdminRights").RequiresPorts(22, 135, 445, 5985, 5986).Tag("Granted").Tag("Pivot")
+	EdgeLocalRDPRights             = engine.NewEdge("RDPRights").RequiresPorts(3389).RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 30 }).Tag("Pivot")
+	EdgeLocalDCOMRights            = engine.NewEdge("DCOMRights").RequiresPorts(135).RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Pivot")
 	EdgeScheduledTaskOnUNCPath     = engine.NewEdge("SchedTaskOnUNCPath").Tag("Pivot")
 	EdgeMachineScript              = engine.NewEdge("MachineScript").Tag("Pivot")
 	EdgeWriteAltSecurityIdentities = engine.NewEdge("WriteAltSecIdent").Tag("Pivot")
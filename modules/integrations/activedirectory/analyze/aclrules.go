@@ -0,0 +1,305 @@
+package analyze
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/cli"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/query"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ACLRule maps access granted by an ACE on an object to an edge from the trustee to the object
+type ACLRule struct {
+	Description string `yaml:"description"`
+
+	Edge        string   `yaml:"edge"`
+	Probability *int     `yaml:"probability,omitempty"` // Fixed probability for the edge, otherwise the edge default
+	Tags        []string `yaml:"tags,omitempty"`        // Tags added to the edge (Pivot, Granted, Informative ...)
+
+	Types  []string `yaml:"types,omitempty"`  // Object type names the rule applies to, all types if empty
+	Filter string   `yaml:"filter,omitempty"` // LDAP filter the object must match
+
+	Access []string `yaml:"access"` // Rights that must all be granted, names from engine (DS_CONTROL_ACCESS, WRITE_DACL ...) or numbers
+
+	// At most one of these, nothing means the ACE must not be restricted to a specific property or right
+	GUID      string `yaml:"guid,omitempty"`      // Object type GUID in the ACE
+	Right     string `yaml:"right,omitempty"`     // Name of an extended right, validated write or property set (controlAccessRight objects)
+	Attribute string `yaml:"attribute,omitempty"` // lDAPDisplayName or cn of an attribute in the schema
+}
+
+type ACLRuleFile struct {
+	Rules []ACLRule `yaml:"rules"`
+}
+
+//go:embed aclrules.yaml
+var defaultACLRules []byte
+
+var aclRules []ACLRule
+
+var accessRights = map[string]engine.Mask{
+	"GENERIC_READ":               engine.RIGHT_GENERIC_READ,
+	"GENERIC_WRITE":              engine.RIGHT_GENERIC_WRITE,
+	"GENERIC_EXECUTE":            engine.RIGHT_GENERIC_EXECUTE,
+	"GENERIC_ALL":                engine.RIGHT_GENERIC_ALL,
+	"WRITE_OWNER":                engine.RIGHT_WRITE_OWNER,
+	"WRITE_DACL":                 engine.RIGHT_WRITE_DACL,
+	"READ_CONTROL":               engine.RIGHT_READ_CONTROL,
+	"DELETE":                     engine.RIGHT_DELETE,
+	"DS_VOODOO_BIT":              engine.RIGHT_DS_VOODOO_BIT,
+	"DS_CONTROL_ACCESS":          engine.RIGHT_DS_CONTROL_ACCESS,
+	"DS_LIST_OBJECT":             engine.RIGHT_DS_LIST_OBJECT,
+	"DS_DELETE_TREE":             engine.RIGHT_DS_DELETE_TREE,
+	"DS_WRITE_PROPERTY":          engine.RIGHT_DS_WRITE_PROPERTY,
+	"DS_READ_PROPERTY":           engine.RIGHT_DS_READ_PROPERTY,
+	"DS_WRITE_PROPERTY_EXTENDED": engine.RIGHT_DS_WRITE_PROPERTY_EXTENDED,
+	"DS_LIST_CONTENTS":           engine.RIGHT_DS_LIST_CONTENTS,
+	"DS_DELETE_CHILD":            engine.RIGHT_DS_DELETE_CHILD,
+	"DS_CREATE_CHILD":            engine.RIGHT_DS_CREATE_CHILD,
+}
+
+func init() {
+	// Edges are defined before any processing starts
+	cobra.OnInitialize(LoadACLRules)
+
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
+		return ACLRulesAnalyzer(ao, aclRules)
+	}, "ACE to edge mappings from ACL rules", engine.BeforeMergeFinal)
+}
+
+// LoadACLRules loads the built in ACL rules and the ones from --rules, it's done when the command line has been parsed
+func LoadACLRules() {
+	rules, err := ParseACLRules(defaultACLRules)
+	if err != nil {
+		ui.Fatal().Msgf("Problem parsing built in ACL rules: %v", err)
+	}
+	if *cli.RulesPath != "" {
+		files, _ := filepath.Glob(filepath.Join(*cli.RulesPath, "*.yaml"))
+		sort.Strings(files)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				ui.Fatal().Msgf("Problem reading ACL rules from %v: %v", file, err)
+			}
+			morerules, err := ParseACLRules(data)
+			if err != nil {
+				ui.Fatal().Msgf("Problem parsing ACL rules from %v: %v", file, err)
+			}
+			ui.Info().Msgf("Loaded %v ACL rules from %v", len(morerules), file)
+			rules = append(rules, morerules...)
+		}
+	}
+	aclRules = rules
+}
+
+// ParseACLRules reads rules from YAML, checks them and registers the edges they use
+func ParseACLRules(data []byte) ([]ACLRule, error) {
+	var rf ACLRuleFile
+	if err := yaml.Unmarshal(data, &rf); err != nil {
+		return nil, err
+	}
+	created := make(map[engine.Edge]*int) // Edges this file defines, with the fixed probability if it has one
+	for i, rule := range rf.Rules {
+		if rule.Edge == "" {
+			return nil, fmt.Errorf("rule %v (%v) has no edge", i+1, rule.Description)
+		}
+		if _, err := parseAccess(rule.Access); err != nil {
+			return nil, fmt.Errorf("rule %v (%v): %v", i+1, rule.Description, err)
+		}
+		var targets int
+		for _, target := range []string{rule.GUID, rule.Right, rule.Attribute} {
+			if target != "" {
+				targets++
+			}
+		}
+		if targets > 1 {
+			return nil, fmt.Errorf("rule %v (%v) can only have one of guid, right and attribute", i+1, rule.Description)
+		}
+		if rule.GUID != "" {
+			if _, err := uuid.FromString(rule.GUID); err != nil {
+				return nil, fmt.Errorf("rule %v (%v): %v", i+1, rule.Description, err)
+			}
+		}
+		for _, typename := range rule.Types {
+			if _, found := lookupObjectType(typename); !found {
+				return nil, fmt.Errorf("rule %v (%v): unknown object type %v", i+1, rule.Description, typename)
+			}
+		}
+
+		edge := engine.LookupEdge(rule.Edge)
+		if edge == engine.NonExistingEdge {
+			edge = engine.NewEdge(rule.Edge)
+			if rule.Description != "" {
+				edge.Describe(rule.Description)
+			}
+			created[edge] = nil
+		}
+		if rule.Probability != nil {
+			// Replacing the calculator of an existing edge would change it everywhere, not just for this rule
+			fixed, ours := created[edge]
+			if !ours {
+				return nil, fmt.Errorf("rule %v (%v): probability can only be set for edges defined in the same rule file, %v already exists", i+1, rule.Description, rule.Edge)
+			}
+			if fixed == nil {
+				probability := engine.Probability(*rule.Probability)
+				edge.RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+					return probability
+				})
+				created[edge] = rule.Probability
+			} else if *fixed != *rule.Probability {
+				return nil, fmt.Errorf("rule %v (%v): probability %v conflicts with %v set by an earlier rule for %v", i+1, rule.Description, *rule.Probability, *fixed, rule.Edge)
+			}
+		}
+		for _, tag := range rule.Tags {
+			edge.Tag(tag)
+		}
+	}
+	return rf.Rules, nil
+}
+
+func parseAccess(access []string) (engine.Mask, error) {
+	if len(access) == 0 {
+		return 0, fmt.Errorf("no access rights given")
+	}
+	var mask engine.Mask
+	for _, right := range access {
+		if value, found := accessRights[strings.TrimPrefix(strings.ToUpper(right), "RIGHT_")]; found {
+			mask |= value
+			continue
+		}
+		value, err := strconv.ParseUint(right, 0, 32)
+		if err != nil {
+			return 0, fmt.Errorf("unknown access right %v", right)
+		}
+		mask |= engine.Mask(value)
+	}
+	return mask, nil
+}
+
+func lookupObjectType(name string) (engine.ObjectType, bool) {
+	for i, oti := range engine.ObjectTypes() {
+		if strings.EqualFold(oti.Name, name) {
+			return engine.ObjectType(i + 1), true
+		}
+	}
+	return engine.NonExistingObjectType, false
+}
+
+type compiledACLRule struct {
+	edge    engine.Edge
+	mask    engine.Mask
+	guid    uuid.UUID
+	matches map[*engine.Object]struct{} // Objects matching the filter, nil if there is no filter
+}
+
+// Resolves names of rights and attributes using the schema and extended rights in the objects
+func compileACLRules(ao *engine.Objects, rules []ACLRule) (all []*compiledACLRule, bytype map[engine.ObjectType][]*compiledACLRule) {
+	attributes := make(map[string]uuid.UUID)
+	rights := make(map[string]uuid.UUID)
+	var resolve bool
+	for _, rule := range rules {
+		resolve = resolve || rule.Right != "" || rule.Attribute != ""
+	}
+	if resolve {
+		ao.Iterate(func(o *engine.Object) bool {
+			switch o.Type() {
+			case engine.ObjectTypeAttributeSchema:
+				if guid, ok := o.OneAttrRaw(activedirectory.SchemaIDGUID).(uuid.UUID); ok {
+					attributes[strings.ToLower(o.OneAttrString(activedirectory.LDAPDisplayName))] = guid
+					attributes[strings.ToLower(o.OneAttrString(engine.Name))] = guid
+				}
+			case engine.ObjectTypeControlAccessRight:
+				if guid, ok := o.OneAttrRaw(activedirectory.RightsGUID).(uuid.UUID); ok {
+					rights[strings.ToLower(o.OneAttrString(engine.Name))] = guid
+					rights[strings.ToLower(o.OneAttrString(engine.DisplayName))] = guid
+				}
+			}
+			return true
+		})
+	}
+
+	bytype = make(map[engine.ObjectType][]*compiledACLRule)
+	for _, rule := range rules {
+		cr := &compiledACLRule{
+			edge: engine.LookupEdge(rule.Edge),
+		}
+		cr.mask, _ = parseAccess(rule.Access)
+
+		var found bool
+		switch {
+		case rule.GUID != "":
+			cr.guid, _ = uuid.FromString(rule.GUID)
+		case rule.Right != "":
+			if cr.guid, found = rights[strings.ToLower(rule.Right)]; !found {
+				ui.Warn().Msgf("Extended right %v used in ACL rule %v not found, skipping rule", rule.Right, rule.Description)
+				continue
+			}
+		case rule.Attribute != "":
+			if cr.guid, found = attributes[strings.ToLower(rule.Attribute)]; !found {
+				ui.Debug().Msgf("Attribute %v used in ACL rule %v not found in schema, skipping rule", rule.Attribute, rule.Description)
+				continue
+			}
+		}
+
+		if rule.Filter != "" {
+			filter, err := query.ParseLDAPQueryStrict(rule.Filter, ao)
+			if err != nil {
+				ui.Error().Msgf("Problem parsing filter %v in ACL rule %v, skipping rule: %v", rule.Filter, rule.Description, err)
+				continue
+			}
+			cr.matches = make(map[*engine.Object]struct{})
+			query.Execute(filter, ao).Iterate(func(o *engine.Object) bool {
+				cr.matches[o] = struct{}{}
+				return true
+			})
+		}
+
+		if len(rule.Types) == 0 {
+			all = append(all, cr)
+			continue
+		}
+		for _, typename := range rule.Types {
+			ot, _ := lookupObjectType(typename)
+			bytype[ot] = append(bytype[ot], cr)
+		}
+	}
+	return all, bytype
+}
+
+// ACLRulesAnalyzer checks all rules against each ACE in the shared ACL evaluation
+func ACLRulesAnalyzer(ao *engine.Objects, rules []ACLRule) *engine.ACLAnalyzer {
+	all, bytype := compileACLRules(ao, rules)
+	if len(all) == 0 && len(bytype) == 0 {
+		return nil
+	}
+
+	return &engine.ACLAnalyzer{
+		Object: func(ctx *engine.ACLContext) bool {
+			return len(all) > 0 || len(bytype[ctx.Object.Type()]) > 0
+		},
+		ACE: func(ctx *engine.ACLContext, index int) {
+			for _, rulelist := range [2][]*compiledACLRule{all, bytype[ctx.Object.Type()]} {
+				for _, rule := range rulelist {
+					if rule.matches != nil {
+						if _, found := rule.matches[ctx.Object]; !found {
+							continue
+						}
+					}
+					if ctx.Allowed(index, rule.mask, rule.guid) {
+						ctx.Trustee(index).EdgeTo(ctx.Object, rule.edge)
+					}
+				}
+			}
+		},
+	}
+}
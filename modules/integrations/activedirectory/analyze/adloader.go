@@ -6,11 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
 	gsync "github.com/SaveTheRbtz/generic-sync-map-go"
+	"github.com/gofrs/uuid"
 	"github.com/lkarlslund/adalanche/modules/analyze"
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/engine"
 	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
 	"github.com/lkarlslund/adalanche/modules/ui"
@@ -48,10 +51,16 @@ type ADLoader struct {
 	shardobjects gsync.MapOf[string, *engine.Objects]
 
 	objectstoconvert chan convertqueueitem
-	importcnf        bool // Import CNF (conflict) objects (experimental)
-	importdel        bool // Import deleted objects (experimental)
-	warnhardened     bool // Warn about hardened objects
-	importhardened   bool // Import hardened objects
+	deltas           []deltafile // Incremental collections, applied when closing
+	importcnf        bool        // Import CNF (conflict) objects (experimental)
+	importdel        bool        // Import deleted objects (experimental)
+	warnhardened     bool        // Warn about hardened objects
+	importhardened   bool        // Import hardened objects
+}
+
+type deltafile struct {
+	path    string
+	objects []*activedirectory.RawObject
 }
 
 type domaininfo struct {
@@ -72,6 +81,10 @@ func (ld *ADLoader) Init() error {
 
 	ld.importeddns = make(map[string]struct{})
 
+	// Trusts and collection times are gathered from each domain while preprocessing
+	resetTrustMap()
+	activedirectory.ResetCollectionTimes()
+
 	ld.objectstoconvert = make(chan convertqueueitem, 8192)
 
 	// AD Objects
@@ -80,53 +93,60 @@ func (ld *ADLoader) Init() error {
 		go func() {
 			// chunk := make(engine.ObjectSlice, 0, 64)
 			for item := range ld.objectstoconvert {
-				if item.object.DistinguishedName == "" {
-					if dnc, found := item.object.Attributes["defaultNamingContext"]; found {
-						// There's a special place for people who do this
-						item.object.DistinguishedName = "cn=RootDSE," + dnc[0]
-						item.object.Attributes["type"] = []string{"rootdse"}
-					} else {
-						// We want the RootDSE KTHX, but ignore everything else
-						ui.Warn().Msg("Empty DN, ignoring!")
-						continue
-					}
+				if o := ld.convert(item.object); o != nil {
+					item.ao.Add(o)
 				}
+			}
+			ld.done.Done()
+		}()
+	}
 
-				// Convert
-				o := item.object.ToObject(*limitattributes)
+	return nil
+}
 
-				if !ld.importcnf && strings.Contains(o.DN(), "\\0ACNF:") {
-					continue // skip conflict object
-				}
+// Converts a raw object into an engine object, returns nil if the object should be skipped
+func (ld *ADLoader) convert(ro *activedirectory.RawObject) *engine.Object {
+	if ro.DistinguishedName == "" {
+		if dnc, found := ro.Attributes["defaultNamingContext"]; found {
+			// There's a special place for people who do this
+			ro.DistinguishedName = "cn=RootDSE," + dnc[0]
+			ro.Attributes["type"] = []string{"rootdse"}
+		} else {
+			// We want the RootDSE KTHX, but ignore everything else
+			ui.Warn().Msg("Empty DN, ignoring!")
+			return nil
+		}
+	}
 
-				if !ld.importdel && strings.Contains(o.DN(), "\\0ADEL:") {
-					continue // skip deleted object
-				}
+	// Convert
+	o := ro.ToObject(*limitattributes)
 
-				if strings.Contains(o.DN(), ",CN=ForeignSecurityPrincipals,DC=") {
-					continue // skip all foreign security principals
-				}
+	if !ld.importcnf && strings.Contains(o.DN(), "\\0ACNF:") {
+		return nil // skip conflict object
+	}
 
-				if !o.HasAttr(engine.ObjectClass) {
-					if ld.warnhardened {
-						if strings.Contains(o.DN(), ",CN=MicrosoftDNS,") {
-							ui.Debug().Msgf("Hardened DNS object without objectclass detected: %v", o.DN())
-						} else {
-							ui.Warn().Msgf("Hardened object without objectclass detected: %v. This *might* affect your analysis, depending on object.", o.DN())
-						}
-					}
-					if !ld.importhardened {
-						continue
-					}
-				}
+	if !ld.importdel && strings.Contains(o.DN(), "\\0ADEL:") {
+		return nil // skip deleted object
+	}
+
+	if strings.Contains(o.DN(), ",CN=ForeignSecurityPrincipals,DC=") {
+		return nil // skip all foreign security principals
+	}
 
-				item.ao.Add(o)
+	if !o.HasAttr(engine.ObjectClass) {
+		if ld.warnhardened {
+			if strings.Contains(o.DN(), ",CN=MicrosoftDNS,") {
+				ui.Debug().Msgf("Hardened DNS object without objectclass detected: %v", o.DN())
+			} else {
+				ui.Warn().Msgf("Hardened object without objectclass detected: %v. This *might* affect your analysis, depending on object.", o.DN())
 			}
-			ld.done.Done()
-		}()
+		}
+		if !ld.importhardened {
+			return nil
+		}
 	}
 
-	return nil
+	return o
 }
 
 func (ld *ADLoader) getShard(path string) *engine.Objects {
@@ -138,59 +158,176 @@ func (ld *ADLoader) getShard(path string) *engine.Objects {
 }
 
 func (ld *ADLoader) Load(path string, cb engine.ProgressCallbackFunc) error {
+	if strings.HasSuffix(path, ".checkpoint.json") {
+		// Partitioned collection leaves this behind until all partitions are done
+		ui.Warn().Msgf("Collection of %v was interrupted, analysis will be missing objects - rerun the collector to resume it", strings.TrimSuffix(filepath.Base(path), ".checkpoint.json"))
+		return nil
+	}
+	if strings.HasSuffix(path, activedirectory.DeltaSuffix) {
+		// Deltas can only be applied once all the full dumps are loaded, so just keep them for now
+		var changes []*activedirectory.RawObject
+		err := readRawObjects(path, cb, func(ro *activedirectory.RawObject) {
+			changes = append(changes, ro)
+		})
+		if err != nil {
+			return err
+		}
+		ld.importmutex.Lock()
+		ld.deltas = append(ld.deltas, deltafile{
+			path:    path,
+			objects: changes,
+		})
+		ld.importmutex.Unlock()
+		return nil
+	}
 	if strings.HasSuffix(path, ".objects.msgp.lz4") {
 		ao := ld.getShard(path)
+		return readRawObjects(path, cb, func(ro *activedirectory.RawObject) {
+			ld.objectstoconvert <- convertqueueitem{ro, ao}
+		})
+	}
+	return engine.ErrUninterested
+}
 
-		cachefile, err := os.Open(path)
-		if err != nil {
-			return fmt.Errorf("Problem opening domain cache file: %v", err)
-		}
-		defer cachefile.Close()
+func readRawObjects(path string, cb engine.ProgressCallbackFunc, each func(ro *activedirectory.RawObject)) error {
+	cachefile, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Problem opening domain cache file: %v", err)
+	}
+	defer cachefile.Close()
 
-		bcachefile := lz4.NewReader(cachefile)
+	decrypted, err := encryption.NewReader(cachefile)
+	if err != nil {
+		return fmt.Errorf("Problem reading domain cache file %v: %v", path, err)
+	}
 
-		lz4options := []lz4.Option{lz4.ConcurrencyOption(-1)}
-		bcachefile.Apply(lz4options...)
+	bcachefile := lz4.NewReader(decrypted)
 
-		d := msgp.NewReaderSize(bcachefile, 4*1024*1024)
+	lz4options := []lz4.Option{lz4.ConcurrencyOption(-1)}
+	bcachefile.Apply(lz4options...)
 
-		cachestat, _ := cachefile.Stat()
+	d := msgp.NewReaderSize(bcachefile, 4*1024*1024)
 
-		divestimator := int64(1024) // 1kb ~ one object to load
+	cachestat, _ := cachefile.Stat()
 
-		// We're approximating object count, by adding some stuff to max and then reporting on that
-		cb(0, int(-cachestat.Size()/divestimator))
+	divestimator := int64(1024) // 1kb ~ one object to load
 
-		// Load all the stuff
-		var lastpos int64
-		// justread := make([]byte, 4*1024*1024)
-		var iteration uint32
-		for {
-			iteration++
-			if iteration%1000 == 0 {
-				pos, _ := cachefile.Seek(0, io.SeekCurrent)
-				cb(int(-(pos-lastpos)/divestimator), 0) // Rounding errors, FIXME
-				lastpos = pos
-			}
+	// We're approximating object count, by adding some stuff to max and then reporting on that
+	cb(0, int(-cachestat.Size()/divestimator))
+
+	// Load all the stuff
+	var lastpos int64
+	// justread := make([]byte, 4*1024*1024)
+	var iteration uint32
+	for {
+		iteration++
+		if iteration%1000 == 0 {
+			pos, _ := cachefile.Seek(0, io.SeekCurrent)
+			cb(int(-(pos-lastpos)/divestimator), 0) // Rounding errors, FIXME
+			lastpos = pos
+		}
+
+		var rawObject activedirectory.RawObject
+		err = rawObject.DecodeMsg(d)
+		if err == nil {
+			each(&rawObject)
+		} else if msgp.Cause(err) == io.EOF {
+			return nil
+		} else {
+			return fmt.Errorf("Problem decoding object: %v", err)
+		}
+	}
+}
+
+// Identity of an object across collections, objectGUID survives renames and deletion
+func rawObjectKey(ro *activedirectory.RawObject) string {
+	if guid, found := ro.Attributes["objectGUID"]; found && len(guid) == 1 && len(guid[0]) == 16 {
+		return guid[0]
+	}
+	return strings.ToLower(ro.DistinguishedName)
+}
+
+func objectKey(o *engine.Object) string {
+	if guid, ok := o.OneAttrRaw(engine.ObjectGUID).(uuid.UUID); ok {
+		return string(guid.Bytes())
+	}
+	return strings.ToLower(o.DN())
+}
 
-			var rawObject activedirectory.RawObject
-			err = rawObject.DecodeMsg(d)
-			if err == nil {
-				ld.objectstoconvert <- convertqueueitem{&rawObject, ao}
-			} else if msgp.Cause(err) == io.EOF {
-				return nil
+// Applies incremental collections on top of the full dump for a shard, in the order they were collected
+func (ld *ADLoader) applyDeltas(ao *engine.Objects, deltas []deltafile) *engine.Objects {
+	// Every changed object is delivered in full, so the last one seen wins
+	changes := make(map[string]*activedirectory.RawObject)
+	for _, delta := range deltas {
+		ui.Info().Msgf("Applying %v changed objects from %v", len(delta.objects), filepath.Base(delta.path))
+		for _, ro := range delta.objects {
+			changes[rawObjectKey(ro)] = ro
+		}
+	}
+
+	result := engine.NewLoaderObjects(ld)
+	existing := make(map[string]struct{})
+	var updated, deleted, added int
+	ao.Iterate(func(o *engine.Object) bool {
+		if o == ao.Root() {
+			return true
+		}
+		key := objectKey(o)
+		if ro, found := changes[key]; found {
+			existing[key] = struct{}{}
+			if isDeleted(ro) {
+				deleted++
 			} else {
-				return fmt.Errorf("Problem decoding object: %v", err)
+				updated++
+			}
+			return true
+		}
+		result.Add(o)
+		return true
+	})
+	for key, ro := range changes {
+		if isDeleted(ro) {
+			continue
+		}
+		if o := ld.convert(ro); o != nil {
+			result.Add(o)
+			if _, found := existing[key]; !found {
+				added++
 			}
 		}
 	}
-	return engine.ErrUninterested
+	ui.Info().Msgf("Incremental changes resulted in %v updated, %v deleted and %v new objects", updated, deleted, added)
+	return result
+}
+
+func isDeleted(ro *activedirectory.RawObject) bool {
+	deleted, found := ro.Attributes["isDeleted"]
+	return found && len(deleted) == 1 && strings.EqualFold(deleted[0], "TRUE")
 }
 
 func (ld *ADLoader) Close() ([]*engine.Objects, error) {
 	close(ld.objectstoconvert)
 	ld.done.Wait()
 
+	if len(ld.deltas) > 0 {
+		sort.Slice(ld.deltas, func(i, j int) bool {
+			return ld.deltas[i].path < ld.deltas[j].path
+		})
+		shards := make(map[string][]deltafile)
+		for _, delta := range ld.deltas {
+			shard := filepath.Dir(delta.path)
+			shards[shard] = append(shards[shard], delta)
+		}
+		for shard, deltas := range shards {
+			ao, found := ld.shardobjects.Load(shard)
+			if !found {
+				ui.Warn().Msgf("Found incremental collections in %v without a full collection, ignoring them", shard)
+				continue
+			}
+			ld.shardobjects.Store(shard, ld.applyDeltas(ao, deltas))
+		}
+	}
+
 	var aos []*engine.Objects
 	ld.shardobjects.Range(func(path string, ao *engine.Objects) bool {
 		_, netbiosname, _, _, err := FindDomain(ao)
@@ -101,7 +101,7 @@ func init() {
 		return nil, nil
 	})
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
 		// Find LAPS or return
 		var lapsGUID uuid.UUID
 		if lapsobjects, found := ao.FindMulti(engine.Name, engine.AttributeValueString("ms-Mcs-AdmPwd")); found {
@@ -121,45 +121,35 @@ func init() {
 
 		if lapsGUID.IsNil() {
 			ui.Debug().Msg("Microsoft LAPS not detected, skipping tests for this")
-			return
+			return nil
 		}
 
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for computers
-			if o.Type() != engine.ObjectTypeComputer {
-				return true
-			}
-
-			// ... that has LAPS installed
-			if !o.HasAttr(activedirectory.MSmcsAdmPwdExpirationTime) {
-				return true
-			}
-
-			// Analyze ACL
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
+		return &engine.ACLAnalyzer{
+			Object: func(ctx *engine.ACLContext) bool {
+				// Only for computers that has LAPS installed
+				if !ctx.Object.HasAttr(activedirectory.MSmcsAdmPwdExpirationTime) {
+					return false
+				}
 
-			// Link to the machine object
-			machinesid := o.SID()
-			if machinesid.IsBlank() {
-				ui.Fatal().Msgf("Computer account %v has no objectSID", o.DN())
-			}
-			machine, found := ao.Find(DomainJoinedSID, engine.AttributeValueSID(machinesid))
-			if !found {
-				ui.Error().Msgf("Could not locate machine for domain SID %v", machinesid)
+				// Link to the machine object
+				machinesid := ctx.Object.SID()
+				if machinesid.IsBlank() {
+					ui.Fatal().Msgf("Computer account %v has no objectSID", ctx.Object.DN())
+				}
+				if _, found := ao.Find(DomainJoinedSID, engine.AttributeValueSID(machinesid)); !found {
+					ui.Error().Msgf("Could not locate machine for domain SID %v", machinesid)
+					return false
+				}
 				return true
-			}
-
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_CONTROL_ACCESS, lapsGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(machine, activedirectory.EdgeReadLAPSPassword)
+			},
+			ACE: func(ctx *engine.ACLContext, index int) {
+				if ctx.Allowed(index, engine.RIGHT_DS_CONTROL_ACCESS, lapsGUID) {
+					machine, _ := ao.Find(DomainJoinedSID, engine.AttributeValueSID(ctx.Object.SID()))
+					ctx.Trustee(index).EdgeTo(machine, activedirectory.EdgeReadLAPSPassword)
 				}
-			}
-			return true
-		})
-	}, "Reading local admin passwords via LAPS", engine.BeforeMergeFinal)
+			},
+		}
+	}, "Reading local admin passwords via LAPS", engine.BeforeMergeFinal, engine.ObjectTypeComputer)
 
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
@@ -205,20 +195,15 @@ func init() {
 		})
 	}, "User configurations that are part of a GPO", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// It's a group
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for _, acl := range sd.DACL.Entries {
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
+		return &engine.ACLAnalyzer{
+			ACE: func(ctx *engine.ACLContext, index int) {
+				acl := ctx.SD.DACL.Entries[index]
 				if acl.Type == engine.ACETYPE_ACCESS_DENIED || acl.Type == engine.ACETYPE_ACCESS_DENIED_OBJECT {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeACLContainsDeny) // Not a probability of success, this is just an indicator
+					ctx.Trustee(index).EdgeTo(ctx.Object, activedirectory.EdgeACLContainsDeny) // Not a probability of success, this is just an indicator
 				}
-			}
-			return true
-		})
+			},
+		}
 	}, "Indicator for possible false positives, as the ACL contains DENY entries", engine.BeforeMergeFinal)
 
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
@@ -242,157 +227,6 @@ func init() {
 		})
 	}, "Indicator that someone owns an object", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_GENERIC_ALL, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeGenericAll)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone has full permissions on an object", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_GENERIC_WRITE, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteAll)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone can write to all attributes and do all validated writes on an object", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWritePropertyAll)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone can write to all attributes of an object", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY_EXTENDED, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteExtendedAll)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone do all validated writes on an object", engine.BeforeMergeFinal)
-
-	// https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-dtyp/c79a383c-2b3f-4655-abe7-dcbb7ce0cfbe IMPORTANT
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_WRITE_OWNER, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeTakeOwnership)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone is allowed to take ownership of an object", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_WRITE_DACL, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteDACL)
-				}
-			}
-			return true
-		})
-	}, "Indicator that someone can change permissions on an object", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if o.Type() != engine.ObjectTypeAttributeSchema {
-				return true
-			}
-			// FIXME - check for SYSTEM ATTRIBUTES - these can NEVER be changed
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeSecurityGUIDGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteAttributeSecurityGUID) // Experimental, I've never run into this misconfiguration
-				}
-			}
-			return true
-		})
-	}, `Allows an attacker to modify the attribute security set of an attribute, promoting it to a weaker attribute set (experimental/wrong)`, engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only users, computers and service accounts
-			if o.Type() != engine.ObjectTypeUser && o.Type() != engine.ObjectTypeComputer {
-				return true
-			}
-			// Check who can reset the password
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_CONTROL_ACCESS, ResetPwd, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeResetPassword)
-				}
-			}
-			return true
-		})
-	}, "Indicator that a group or user can reset the password of an account", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only group managed service accounts
-			if o.Type() != engine.ObjectTypeGroupManagedServiceAccount {
-				return true
-			}
-
-			// Check who can reset the password
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_READ_PROPERTY, AttributeMSDSManagedPasswordId, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeReadPasswordId)
-				}
-			}
-			return true
-		})
-	}, "Indicator that a group or user can read the msDS-ManagedPasswordId for use in MGSA Golden attack", engine.BeforeMergeFinal)
-
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		kerberoast := engine.AttributeValueString("kerberoast")
 		authusers := FindWellKnown(ao, windowssecurity.AuthenticatedUsersSID)
@@ -436,65 +270,6 @@ func init() {
 		})
 	}, "Indicator that a user has \"don't require preauth\" and can be ASREPRoasted", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only users
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, ValidateWriteSPN, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteSPN)
-				}
-			}
-			return true
-		})
-	}, "Indicator that a user can change the ServicePrincipalName attribute, and then Kerberoast the account", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only computers and users
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY_EXTENDED, ValidateWriteSPN, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteValidatedSPN)
-				}
-			}
-			return true
-		})
-	}, "Indicator that a user can change the ServicePrincipalName attribute (validate write), and then Kerberoast the account", engine.BeforeMergeFinal)
-
-	// https://blog.harmj0y.net/activedirectory/the-most-dangerous-user-right-you-probably-have-never-heard-of/
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only computers
-			if o.Type() != engine.ObjectTypeComputer && o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeAllowedToActOnBehalfOfOtherIdentity, ao) {
-					// This does NOT requires the SeEnableDelegationPrivilege set on the DC for the user doing it!!
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteAllowedToAct)
-				}
-			}
-			return true
-		})
-	}, `Modify the msDS-AllowedToActOnBehalfOfOtherIdentity (Resource Based Constrained Delegation) on an account to enable any SPN enabled user to impersonate it`, engine.BeforeMergeFinal)
-
 	EdgeRBCD := engine.NewEdge("RBConstrainedDeleg")
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
@@ -586,66 +361,6 @@ func init() {
 			})
 		}, `Modify the msDS-AllowedToDelegateTo (Constrained Delegation) on a computer to enable any SPN enabled user to impersonate anyone else`, engine.BeforeMergeFinal)
 	*/
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for groups
-			if o.Type() != engine.ObjectTypeGroup {
-				return true
-			}
-			// It's a group
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeMember, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeAddMember)
-				}
-			}
-			return true
-		})
-	}, "Permission to add a member to a group", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for groups
-			if o.Type() != engine.ObjectTypeGroup {
-				return true
-			}
-			// It's a group
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeSetGroupMembership, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeAddMemberGroupAttr)
-				}
-			}
-			return true
-		})
-	}, "Permission to add a member to a group (via attribute set)", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for groups
-			if o.Type() != engine.ObjectTypeGroup {
-				return true
-			}
-			// It's a group
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY_EXTENDED, ValidateWriteSelfMembership, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeAddSelfMember)
-				}
-			}
-			return true
-		})
-	}, "Permission to add yourself to a group", engine.BeforeMergeFinal)
-
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
 			o.Attr(activedirectory.MSDSGroupMSAMembership).Iterate(func(msads engine.AttributeValue) bool {
@@ -662,62 +377,6 @@ func init() {
 		})
 	}, "Allows someone to read a password of a managed service account", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for users
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeAltSecurityIdentitiesGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteAltSecurityIdentities)
-				}
-			}
-			return true
-		})
-	}, "Allows an attacker to define a certificate that can be used to authenticate as the user", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for users
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeProfilePathGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteProfilePath)
-				}
-			}
-			return true
-		})
-	}, "Change user profile path (allows an attacker to trigger a user auth against an attacker controlled UNC path)", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for users
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeScriptPathGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteScriptPath)
-				}
-			}
-			return true
-		})
-	}, "Change user script path (allows an attacker to trigger a user auth against an attacker controlled UNC path)", engine.BeforeMergeFinal)
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
 			o.Attr(activedirectory.MSDSHostServiceAccount).Iterate(func(dn engine.AttributeValue) bool {
@@ -730,26 +389,6 @@ func init() {
 		})
 	}, "Indicates that the object has a service account in use", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for groups
-			if o.Type() != engine.ObjectTypeUser && o.Type() != engine.ObjectTypeComputer {
-				return true
-			}
-			// It's a group
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeMSDSKeyCredentialLink, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteKeyCredentialLink)
-				}
-			}
-			return true
-		})
-	}, "Allows you to write your own cert to keyCredentialLink, and then auth as that user (no password reset needed)", engine.BeforeMergeFinal)
-
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
 			o.Attr(activedirectory.SIDHistory).Iterate(func(sidval engine.AttributeValue) bool {
@@ -763,76 +402,6 @@ func init() {
 		})
 	}, "Indicates that object has a SID History attribute pointing to the other object, making them the 'same' permission wise", engine.BeforeMergeFinal)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_CONTROL_ACCESS, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeAllExtendedRights)
-				}
-			}
-			return true
-		})
-	}, "Indicates that you have all extended rights", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			if o.Type() != engine.ObjectTypeCertificateTemplate {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-crtd/211ab1e3-bad6-416d-9d56-8480b42617a4
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_CONTROL_ACCESS, ExtendedRightCertificateEnroll, ao) ||
-					sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_VOODOO_BIT, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeCertificateEnroll)
-				}
-			}
-			return true
-		})
-	}, "Permission to enroll into a certificate template", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			if o.Type() != engine.ObjectTypeCertificateTemplate {
-				return true
-			}
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-crtd/211ab1e3-bad6-416d-9d56-8480b42617a4
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_CONTROL_ACCESS, ExtendedRightCertificateAutoEnroll, ao) ||
-					sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_VOODOO_BIT, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeCertificateAutoEnroll)
-				}
-			}
-			return true
-		})
-	}, "Permission to auto-enroll into a certificate template", engine.BeforeMergeFinal)
-
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_VOODOO_BIT, uuid.Nil, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeVoodooBit)
-				}
-			}
-			return true
-		})
-	}, "Has the Voodoo Bit set", engine.BeforeMergeFinal)
-
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		ao.Iterate(func(o *engine.Object) bool {
 			if o.Type() != engine.ObjectTypeDomainDNS {
@@ -1199,6 +768,11 @@ func init() {
 		}, TrustInfo{})
 
 		ao.Iterate(func(object *engine.Object) bool {
+			if collected, ok := object.AttrTime(activedirectory.CurrentTime); ok {
+				// Only on the RootDSE
+				activedirectory.SetCollectionTime(ncname, collected)
+			}
+
 			if rid, ok := object.AttrInt(activedirectory.PrimaryGroupID); ok {
 				sid := object.SID()
 				if len(sid) > 8 {
@@ -1345,16 +919,31 @@ func init() {
 
 				ui.Info().Msgf("Domain %v has a %v trust with %v", dnsroot, direction, partner)
 
-				if dir&2 != 0 && attr&0x08 != 0 && attr&0x40 != 0 {
-					ui.Info().Msgf("SID filtering is not enabled, so pwn %v and pwn this AD too", object.OneAttr(activedirectory.TrustPartner))
+				trustinfo := TrustInfo{
+					Direction:  TrustDirection(dir),
+					Attributes: int(attr),
+				}
+
+				if trustinfo.Transitive() {
+					object.Tag("trust_transitive")
+				}
+				if trustinfo.SelectiveAuthentication() {
+					object.Tag("trust_selective_authentication")
+				}
+				if trustinfo.TGTDelegation() {
+					object.Tag("trust_tgt_delegation")
+				}
+				if !trustinfo.SIDFiltering() {
+					object.Tag("trust_sid_filtering_disabled")
+					if dir&2 != 0 && !trustinfo.WithinForest() {
+						ui.Info().Msgf("SID filtering is not enabled, so pwn %v and pwn this AD too", object.OneAttr(activedirectory.TrustPartner))
+					}
 				}
 
 				TrustMap.Store(TrustPair{
 					SourceDNSRoot: dnsroot,
-					TargetDNSRoot: partner,
-				}, TrustInfo{
-					Direction: TrustDirection(dir),
-				})
+					TargetDNSRoot: strings.ToLower(partner),
+				}, trustinfo)
 			}
 
 			/* else if object.HasAttrValue(engine.ObjectClass, "classSchema") {
@@ -1717,25 +1306,15 @@ func init() {
 		engine.AfterMergeLow,
 	)
 
-	LoaderID.AddProcessor(func(ao *engine.Objects) {
-		ao.Iterate(func(o *engine.Object) bool {
-			// Only for containers and org units
-			if o.Type() != engine.ObjectTypeUser {
-				return true
-			}
-
-			sd, err := o.SecurityDescriptor()
-			if err != nil {
-				return true
-			}
-			for index, acl := range sd.DACL.Entries {
-				if sd.DACL.IsObjectClassAccessAllowed(index, o, engine.RIGHT_DS_WRITE_PROPERTY, AttributeUserAccountControlGUID, ao) {
-					ao.FindOrAddAdjacentSID(acl.SID, o).EdgeTo(o, activedirectory.EdgeWriteUserAccountControl)
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
+		return &engine.ACLAnalyzer{
+			ACE: func(ctx *engine.ACLContext, index int) {
+				if ctx.Allowed(index, engine.RIGHT_DS_WRITE_PROPERTY, AttributeUserAccountControlGUID) {
+					ctx.Trustee(index).EdgeTo(ctx.Object, activedirectory.EdgeWriteUserAccountControl)
 				}
-			}
-			return true
-		})
-	}, "Permissions that lets someone modify userAccountControl", engine.BeforeMergeFinal)
+			},
+		}
+	}, "Permissions that lets someone modify userAccountControl", engine.BeforeMergeFinal, engine.ObjectTypeUser)
 
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
 		edgematch := engine.EdgeBitmap{}.Set(activedirectory.EdgeMemberOfGroup).Set(activedirectory.EdgeForeignIdentity)
@@ -1888,6 +1467,8 @@ func init() {
 	}
 
 	LoaderID.AddProcessor(func(ao *engine.Objects) {
+		trustpaths := ResolveTrustPaths()
+
 		// Find all domains, save info so we can see if an object is "local" or not
 		sidmap := make(map[windowssecurity.SID]sidinfo)
 		ao.Filter(func(o *engine.Object) bool {
@@ -1909,10 +1490,19 @@ func init() {
 				domainContext := object.OneAttrString(engine.DomainContext)
 				domaininfo, found := sidmap[sid.StripRID()]
 				if found && domaininfo.domainContext != domainContext {
-					// it's foreign, find the local one
+					// it's foreign, but only usable if the domain it lives in trusts the native domain
+					path, trusted := trustpaths[util.DomainContextToDomainSuffix(domainContext)][util.DomainContextToDomainSuffix(domaininfo.domainContext)]
+					if !trusted {
+						return true
+					}
+					if path.SelectiveAuthentication {
+						object.Tag("trust_selective_authentication")
+					}
+
+					// find the native one
 					nativeObjects, found := ao.FindTwoMulti(
 						engine.ObjectSid, engine.AttributeValueSID(sid),
-						engine.DomainContext, engine.AttributeValueString(domainContext),
+						engine.DomainContext, engine.AttributeValueString(domaininfo.domainContext),
 					)
 					if found {
 						nativeobject := nativeObjects.First()
@@ -2,12 +2,12 @@ package analyze
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
 
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/engine"
 	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
 	"github.com/lkarlslund/adalanche/modules/ui"
@@ -39,7 +39,7 @@ func (ld *GPOLoader) Init() error {
 		ld.done.Add(1)
 		go func() {
 			for path := range ld.gpofiletoprocess {
-				raw, err := ioutil.ReadFile(path)
+				raw, err := encryption.ReadFile(path)
 				if err != nil {
 					ui.Warn().Msgf("Problem reading data from GPO JSON file %v: %v", path, err)
 					continue
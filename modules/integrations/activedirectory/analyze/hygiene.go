@@ -0,0 +1,138 @@
+package analyze
+
+import (
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/analyze"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+var (
+	hygienepasswordage = analyze.Command.Flags().Int("hygienepasswordage", 365, "Number of days before a password is reported as old by the hygiene analyzer")
+	hygienedormantdays = analyze.Command.Flags().Int("hygienedormantdays", 90, "Number of days without logon before an enabled account is reported as dormant by the hygiene analyzer")
+)
+
+// Hygiene findings, these are set as both tags and values in the hygieneFinding attribute
+const (
+	FindingNeverLoggedOn           = "never_logged_on"
+	FindingDormantAccount          = "dormant_account"
+	FindingOldPassword             = "old_password"
+	FindingComputerPasswdNotReqd   = "computer_passwd_notreqd"
+	FindingPre2000ComputerPassword = "pre2000_computer_password"
+	FindingAdminCountOrphan        = "admincount_orphan"
+	FindingDisabledPrivileged      = "disabled_privileged"
+)
+
+// Groups that are protected by AdminSDHolder and considered privileged
+func privilegedGroupRID(sid windowssecurity.SID) bool {
+	switch sid.Component(2) {
+	case 21:
+		switch sid.RID() {
+		case DOMAIN_GROUP_RID_ADMINS, DOMAIN_GROUP_RID_CONTROLLERS, DOMAIN_GROUP_RID_SCHEMA_ADMINS,
+			DOMAIN_GROUP_RID_ENTERPRISE_ADMINS, DOMAIN_GROUP_RID_READONLY_CONTROLLERS:
+			return true
+		}
+	case 32:
+		switch sid.RID() {
+		case DOMAIN_ALIAS_RID_ADMINS, DOMAIN_ALIAS_RID_ACCOUNT_OPS, DOMAIN_ALIAS_RID_SYSTEM_OPS,
+			DOMAIN_ALIAS_RID_PRINT_OPS, DOMAIN_ALIAS_RID_BACKUP_OPS, DOMAIN_ALIAS_RID_REPLICATOR:
+			return true
+		}
+	}
+	return false
+}
+
+func lastLogon(o *engine.Object) (time.Time, bool) {
+	lastlogon, found := o.AttrTime(activedirectory.LastLogonTimestamp)
+	if ll, ok := o.AttrTime(activedirectory.LastLogon); ok && (!found || ll.After(lastlogon)) {
+		lastlogon, found = ll, true
+	}
+	return lastlogon, found
+}
+
+func init() {
+	LoaderID.AddProcessor(func(ao *engine.Objects) {
+		hygieneFindings(ao, time.Duration(*hygienepasswordage)*24*time.Hour, time.Duration(*hygienedormantdays)*24*time.Hour)
+	},
+		"Hygiene findings",
+		engine.AfterMergeHigh,
+	)
+}
+
+// Tags accounts with hygiene problems, ages are measured from when the domain was collected
+func hygieneFindings(ao *engine.Objects, passwordage, dormantage time.Duration) {
+	// Everyone who is a member of a privileged group, directly or indirectly
+	privileged := make(map[*engine.Object]struct{})
+	ao.Filter(func(o *engine.Object) bool {
+		return o.Type() == engine.ObjectTypeGroup && privilegedGroupRID(o.SID())
+	}).Iterate(func(group *engine.Object) bool {
+		group.EdgeIteratorRecursive(engine.In, engine.EdgeBitmap{}.Set(activedirectory.EdgeMemberOfGroup), true, func(source, member *engine.Object, edge engine.EdgeBitmap, depth int) bool {
+			privileged[member] = struct{}{}
+			return true
+		})
+		return true
+	})
+
+	ao.Iterate(func(o *engine.Object) bool {
+		switch o.Type() {
+		case engine.ObjectTypeUser, engine.ObjectTypeComputer, engine.ObjectTypeManagedServiceAccount, engine.ObjectTypeGroupManagedServiceAccount:
+		default:
+			return true
+		}
+
+		uac, ok := o.AttrInt(activedirectory.UserAccountControl)
+		if !ok {
+			return true
+		}
+
+		var findings []engine.AttributeValue
+		addFinding := func(finding string) {
+			o.Tag(engine.AttributeValueString(finding))
+			findings = append(findings, engine.AttributeValueString(finding))
+		}
+
+		_, isprivileged := privileged[o]
+		enabled := uac&engine.UAC_ACCOUNTDISABLE == 0
+		lastlogon, hasloggedon := lastLogon(o)
+		collected := activedirectory.CollectionTime(o)
+
+		if enabled {
+			if !hasloggedon {
+				addFinding(FindingNeverLoggedOn)
+			} else if collected.Sub(lastlogon) > dormantage {
+				addFinding(FindingDormantAccount)
+			}
+
+			if pwdlastset, ok := o.AttrTime(activedirectory.PwdLastSet); ok && collected.Sub(pwdlastset) > passwordage {
+				addFinding(FindingOldPassword)
+			}
+		} else if isprivileged {
+			addFinding(FindingDisabledPrivileged)
+		}
+
+		if o.Type() == engine.ObjectTypeComputer && uac&engine.UAC_PASSWD_NOTREQD != 0 {
+			addFinding(FindingComputerPasswdNotReqd)
+
+			// Pre-created with "Assign this computer account as a pre-Windows 2000 computer", password is the lowercase name
+			if uac&engine.UAC_WORKSTATION_TRUST_ACCOUNT != 0 && !hasloggedon {
+				addFinding(FindingPre2000ComputerPassword)
+			}
+		}
+
+		if admincount, ok := o.AttrInt(activedirectory.AdminCount); ok && admincount == 1 && !isprivileged {
+			switch o.SID().RID() {
+			case DOMAIN_USER_RID_ADMIN, DOMAIN_USER_RID_KRBTGT:
+				// Always protected
+			default:
+				addFinding(FindingAdminCountOrphan)
+			}
+		}
+
+		if len(findings) > 0 {
+			o.SetValues(activedirectory.HygieneFinding, findings...)
+		}
+		return true
+	})
+}
@@ -0,0 +1,99 @@
+package analyze
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+func TestHygieneFindings(t *testing.T) {
+	// Analysis happens long after collection, so ages must be measured from the collection
+	collected := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	activedirectory.ResetCollectionTimes()
+	activedirectory.SetCollectionTime("DC=contoso,DC=local", collected)
+	defer activedirectory.ResetCollectionTimes()
+	daysago := func(days int) engine.AttributeValueTime {
+		return engine.AttributeValueTime(collected.Add(-time.Duration(days) * 24 * time.Hour))
+	}
+
+	ao := engine.NewObjects()
+	account := func(objecttype engine.ObjectType, rid int, uac int64, flexinit ...any) *engine.Object {
+		sid := windowssecurity.MustParseStringSID("S-1-5-21-1-2-3").AddComponent(uint32(rid))
+		o := engine.NewObject(append([]any{
+			engine.Type, objecttype.ValueString(),
+			engine.DomainContext, "DC=contoso,DC=local",
+			activedirectory.ObjectSid, engine.AttributeValueSID(sid),
+			activedirectory.UserAccountControl, engine.AttributeValueInt(uac),
+		}, flexinit...)...)
+		ao.Add(o)
+		return o
+	}
+	const enabled, disabled = engine.UAC_NORMAL_ACCOUNT, engine.UAC_NORMAL_ACCOUNT | engine.UAC_ACCOUNTDISABLE
+
+	domainadmins := engine.NewObject(
+		engine.Type, engine.ObjectTypeGroup.ValueString(),
+		activedirectory.ObjectSid, engine.AttributeValueSID(windowssecurity.MustParseStringSID("S-1-5-21-1-2-3-512")),
+	)
+	ao.Add(domainadmins)
+	disabledadmin := account(engine.ObjectTypeUser, 1110, disabled, activedirectory.AdminCount, engine.AttributeValueInt(1))
+	disabledadmin.EdgeTo(domainadmins, activedirectory.EdgeMemberOfGroup)
+
+	// No domain context, so the latest collection is used
+	foreign := engine.NewObject(
+		engine.Type, engine.ObjectTypeUser.ValueString(),
+		activedirectory.UserAccountControl, engine.AttributeValueInt(enabled),
+		activedirectory.LastLogonTimestamp, daysago(30),
+	)
+	ao.Add(foreign)
+
+	tests := []struct {
+		name     string
+		account  *engine.Object
+		findings []string
+	}{
+		{"never logged on", account(engine.ObjectTypeUser, 1101, enabled), []string{FindingNeverLoggedOn}},
+		{"recent logon", account(engine.ObjectTypeUser, 1102, enabled,
+			activedirectory.LastLogonTimestamp, daysago(30),
+			activedirectory.PwdLastSet, daysago(200)), nil},
+		{"dormant", account(engine.ObjectTypeUser, 1103, enabled,
+			activedirectory.LastLogonTimestamp, daysago(120)), []string{FindingDormantAccount}},
+		{"dormant but newer last logon on a DC", account(engine.ObjectTypeUser, 1104, enabled,
+			activedirectory.LastLogonTimestamp, daysago(120),
+			activedirectory.LastLogon, daysago(2)), nil},
+		{"old password", account(engine.ObjectTypeUser, 1105, enabled,
+			activedirectory.LastLogonTimestamp, daysago(1),
+			activedirectory.PwdLastSet, daysago(400)), []string{FindingOldPassword}},
+		{"disabled with old password", account(engine.ObjectTypeUser, 1106, disabled,
+			activedirectory.PwdLastSet, daysago(400)), nil},
+		{"admincount orphan", account(engine.ObjectTypeUser, 1107, disabled,
+			activedirectory.AdminCount, engine.AttributeValueInt(1)), []string{FindingAdminCountOrphan}},
+		{"built in administrator", account(engine.ObjectTypeUser, DOMAIN_USER_RID_ADMIN, disabled,
+			activedirectory.AdminCount, engine.AttributeValueInt(1)), nil},
+		{"pre-Windows 2000 computer", account(engine.ObjectTypeComputer, 1108, engine.UAC_WORKSTATION_TRUST_ACCOUNT|engine.UAC_PASSWD_NOTREQD),
+			[]string{FindingNeverLoggedOn, FindingComputerPasswdNotReqd, FindingPre2000ComputerPassword}},
+		{"computer without password required", account(engine.ObjectTypeComputer, 1109, engine.UAC_WORKSTATION_TRUST_ACCOUNT|engine.UAC_PASSWD_NOTREQD,
+			activedirectory.LastLogonTimestamp, daysago(1)), []string{FindingComputerPasswdNotReqd}},
+		{"disabled privileged", disabledadmin, []string{FindingDisabledPrivileged}},
+		{"unknown domain", foreign, nil},
+	}
+
+	hygieneFindings(ao, 365*24*time.Hour, 90*24*time.Hour)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := tt.account.AttrString(activedirectory.HygieneFinding)
+			if !slices.Equal(findings, tt.findings) {
+				t.Errorf("findings %v, expected %v", findings, tt.findings)
+			}
+			for _, finding := range tt.findings {
+				if !tt.account.HasTag(engine.AttributeValueString(finding)) {
+					t.Errorf("not tagged with %v", finding)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package analyze
+
+import (
+	"math"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+type passwordPolicy struct {
+	source           *engine.Object
+	precedence       int64
+	direct           bool // PSO linked directly to the account, wins over group linked PSOs
+	minLength        int64
+	maxAgeDays       int64
+	lockoutThreshold int64
+	complexity       bool
+}
+
+// AD stores password ages as negative 100ns intervals, with 0 or MinInt64 meaning "never"
+func intervalToDays(interval int64) int64 {
+	if interval == 0 || interval == math.MinInt64 {
+		return 0
+	}
+	if interval < 0 {
+		interval = -interval
+	}
+	return interval / (24 * 60 * 60 * 10000000)
+}
+
+func domainPasswordPolicy(domain *engine.Object) passwordPolicy {
+	policy := passwordPolicy{
+		source:     domain,
+		precedence: math.MaxInt64,
+	}
+	policy.minLength, _ = domain.AttrInt(activedirectory.MinPwdLength)
+	policy.lockoutThreshold, _ = domain.AttrInt(activedirectory.LockoutThreshold)
+	maxage, _ := domain.AttrInt(activedirectory.MaxPwdAge)
+	policy.maxAgeDays = intervalToDays(maxage)
+	properties, _ := domain.AttrInt(activedirectory.PwdProperties)
+	policy.complexity = properties&0x01 /* DOMAIN_PASSWORD_COMPLEX */ != 0
+	return policy
+}
+
+func fineGrainedPasswordPolicy(pso *engine.Object) passwordPolicy {
+	policy := passwordPolicy{
+		source:     pso,
+		precedence: math.MaxInt64,
+	}
+	if precedence, ok := pso.AttrInt(activedirectory.MSDSPasswordSettingsPrecedence); ok {
+		policy.precedence = precedence
+	}
+	policy.minLength, _ = pso.AttrInt(activedirectory.MSDSMinimumPasswordLength)
+	policy.lockoutThreshold, _ = pso.AttrInt(activedirectory.MSDSLockoutThreshold)
+	maxage, _ := pso.AttrInt(activedirectory.MSDSMaximumPasswordAge)
+	policy.maxAgeDays = intervalToDays(maxage)
+	policy.complexity, _ = pso.AttrBool(activedirectory.MSDSPasswordComplexityEnabled)
+	return policy
+}
+
+func init() {
+	LoaderID.AddProcessor(evaluatePasswordPolicies,
+		"Password policy evaluation (domain and fine grained password policies)",
+		engine.AfterMerge,
+	)
+}
+
+// Sets the effective password policy on accounts, from the PSOs that apply to them or the domain
+func evaluatePasswordPolicies(ao *engine.Objects) {
+	domainpolicies := make(map[string]passwordPolicy)
+	ao.Filter(func(o *engine.Object) bool {
+		return o.Type() == engine.ObjectTypeDomainDNS && o.HasAttr(activedirectory.ObjectSid)
+	}).Iterate(func(domain *engine.Object) bool {
+		domainpolicies[domain.OneAttrString(engine.DomainContext)] = domainPasswordPolicy(domain)
+		return true
+	})
+
+	// Resolve msDS-PSOAppliesTo links - a directly linked PSO always wins over group linked ones,
+	// otherwise the lowest precedence value wins
+	psopolicies := make(map[*engine.Object]passwordPolicy)
+	applypolicy := func(o *engine.Object, policy passwordPolicy) {
+		if current, found := psopolicies[o]; found {
+			if current.direct && !policy.direct {
+				return
+			}
+			if current.direct == policy.direct && current.precedence <= policy.precedence {
+				return
+			}
+		}
+		psopolicies[o] = policy
+	}
+
+	ao.Filter(func(o *engine.Object) bool {
+		return o.Type() == engine.ObjectTypePasswordSettings
+	}).Iterate(func(pso *engine.Object) bool {
+		policy := fineGrainedPasswordPolicy(pso)
+		pso.Attr(activedirectory.MSDSPSOAppliesTo).Iterate(func(dn engine.AttributeValue) bool {
+			target, found := ao.Find(engine.DistinguishedName, dn)
+			if !found {
+				ui.Warn().Msgf("Fine grained password policy %v applies to %v, which is not found", pso.DN(), dn.String())
+				return true
+			}
+			if target.Type() == engine.ObjectTypeGroup {
+				target.EdgeIteratorRecursive(engine.In, engine.EdgeBitmap{}.Set(activedirectory.EdgeMemberOfGroup), true, func(source, member *engine.Object, edge engine.EdgeBitmap, depth int) bool {
+					applypolicy(member, policy)
+					return true
+				})
+			} else {
+				directpolicy := policy
+				directpolicy.direct = true
+				applypolicy(target, directpolicy)
+			}
+			return true
+		})
+		return true
+	})
+
+	var evaluated, finegrained int
+	ao.Iterate(func(o *engine.Object) bool {
+		switch o.Type() {
+		case engine.ObjectTypeUser, engine.ObjectTypeComputer, engine.ObjectTypeManagedServiceAccount, engine.ObjectTypeGroupManagedServiceAccount:
+		default:
+			return true
+		}
+
+		if enctypes, ok := o.AttrInt(activedirectory.MSDSSupportedEncryptionTypes); ok && enctypes != 0 {
+			if enctypes&engine.ENCTYPE_RC4_HMAC != 0 && enctypes&(engine.ENCTYPE_AES128_CTS_HMAC_SHA1_96|engine.ENCTYPE_AES256_CTS_HMAC_SHA1_96) == 0 {
+				o.Tag("rc4_only")
+			}
+			if enctypes&(engine.ENCTYPE_DES_CBC_CRC|engine.ENCTYPE_DES_CBC_MD5) != 0 {
+				o.Tag("des_enabled")
+			}
+		}
+
+		policy, found := psopolicies[o]
+		if found {
+			finegrained++
+		} else {
+			policy, found = domainpolicies[o.OneAttrString(engine.DomainContext)]
+			if !found {
+				return true
+			}
+		}
+		evaluated++
+
+		o.SetValues(activedirectory.EffectivePasswordPolicy, engine.AttributeValueString(policy.source.DN()))
+		o.SetValues(activedirectory.EffectiveMinPwdLength, engine.AttributeValueInt(policy.minLength))
+		o.SetValues(activedirectory.EffectiveMaxPwdAge, engine.AttributeValueInt(policy.maxAgeDays))
+		o.SetValues(activedirectory.EffectiveLockoutThreshold, engine.AttributeValueInt(policy.lockoutThreshold))
+		o.SetValues(activedirectory.EffectivePasswordComplexity, engine.AttributeValueBool(policy.complexity))
+
+		if policy.minLength < 8 || !policy.complexity {
+			o.Tag("weak_password_policy")
+		}
+		if policy.lockoutThreshold == 0 {
+			o.Tag("no_lockout")
+		}
+		return true
+	})
+	ui.Debug().Msgf("Evaluated password policy for %v accounts, %v of them covered by fine grained password policies", evaluated, finegrained)
+}
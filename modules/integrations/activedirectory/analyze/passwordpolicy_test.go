@@ -0,0 +1,160 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+const testDomainContext = "DC=contoso,DC=local"
+
+func passwordPolicyObjects() (ao *engine.Objects, accounts map[string]*engine.Object) {
+	ao = engine.NewObjects()
+	accounts = make(map[string]*engine.Object)
+	add := func(name string, objecttype engine.ObjectType, flexinit ...any) *engine.Object {
+		o := engine.NewObject(append([]any{
+			engine.DistinguishedName, "CN=" + name + "," + testDomainContext,
+			engine.Type, objecttype.ValueString(),
+			engine.DomainContext, testDomainContext,
+		}, flexinit...)...)
+		ao.Add(o)
+		accounts[name] = o
+		return o
+	}
+
+	add("contoso", engine.ObjectTypeDomainDNS,
+		activedirectory.ObjectSid, engine.AttributeValueSID(windowssecurity.MustParseStringSID("S-1-5-21-1-2-3")),
+		activedirectory.MinPwdLength, engine.AttributeValueInt(7),
+		activedirectory.LockoutThreshold, engine.AttributeValueInt(0),
+		activedirectory.MaxPwdAge, engine.AttributeValueInt(-42*24*60*60*10000000),
+		activedirectory.PwdProperties, engine.AttributeValueInt(1),
+	)
+
+	admins := add("Admins", engine.ObjectTypeGroup)
+	tier0 := add("Tier0", engine.ObjectTypeGroup)
+	nested := add("Nested Admins", engine.ObjectTypeGroup)
+	nested.EdgeTo(admins, activedirectory.EdgeMemberOfGroup)
+
+	pso := func(name string, precedence, minlength, lockout int64, appliesto ...string) {
+		var targets []string
+		for _, target := range appliesto {
+			targets = append(targets, "CN="+target+","+testDomainContext)
+		}
+		add(name, engine.ObjectTypePasswordSettings,
+			activedirectory.MSDSPasswordSettingsPrecedence, engine.AttributeValueInt(precedence),
+			activedirectory.MSDSMinimumPasswordLength, engine.AttributeValueInt(minlength),
+			activedirectory.MSDSLockoutThreshold, engine.AttributeValueInt(lockout),
+			activedirectory.MSDSMaximumPasswordAge, engine.AttributeValueInt(0),
+			activedirectory.MSDSPasswordComplexityEnabled, engine.AttributeValueBool(true),
+			activedirectory.MSDSPSOAppliesTo, targets,
+		)
+	}
+	pso("Admins PSO", 10, 14, 5, "Admins")
+	pso("Tier0 PSO", 5, 12, 5, "Tier0")
+	pso("Carol PSO", 20, 20, 10, "Carol")
+
+	add("Alice", engine.ObjectTypeUser)
+	add("Bob", engine.ObjectTypeUser).EdgeTo(admins, activedirectory.EdgeMemberOfGroup)
+	accounts["Bob"].EdgeTo(tier0, activedirectory.EdgeMemberOfGroup)
+	add("Carol", engine.ObjectTypeUser).EdgeTo(tier0, activedirectory.EdgeMemberOfGroup)
+	add("Dave", engine.ObjectTypeUser).EdgeTo(nested, activedirectory.EdgeMemberOfGroup)
+	add("Eve", engine.ObjectTypeUser,
+		activedirectory.MSDSSupportedEncryptionTypes, engine.AttributeValueInt(engine.ENCTYPE_RC4_HMAC|engine.ENCTYPE_DES_CBC_MD5))
+	return ao, accounts
+}
+
+func TestEvaluatePasswordPolicies(t *testing.T) {
+	ao, accounts := passwordPolicyObjects()
+	evaluatePasswordPolicies(ao)
+
+	tests := []struct {
+		account    string
+		policy     string
+		minlength  int64
+		maxage     int64
+		lockout    int64
+		complexity bool
+		tags       []string
+	}{
+		{"Alice", "contoso", 7, 42, 0, true, []string{"weak_password_policy", "no_lockout"}},
+		{"Bob", "Tier0 PSO", 12, 0, 5, true, nil},    // Lowest precedence wins
+		{"Carol", "Carol PSO", 20, 0, 10, true, nil}, // Directly linked wins over groups
+		{"Dave", "Admins PSO", 14, 0, 5, true, nil},  // Through nested group membership
+		{"Eve", "contoso", 7, 42, 0, true, []string{"weak_password_policy", "no_lockout", "rc4_only", "des_enabled"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.account, func(t *testing.T) {
+			o := accounts[tt.account]
+			if policy := o.OneAttrString(activedirectory.EffectivePasswordPolicy); policy != accounts[tt.policy].DN() {
+				t.Errorf("effective policy is %v, expected %v", policy, accounts[tt.policy].DN())
+			}
+			if minlength, _ := o.AttrInt(activedirectory.EffectiveMinPwdLength); minlength != tt.minlength {
+				t.Errorf("minimum length is %v, expected %v", minlength, tt.minlength)
+			}
+			if maxage, _ := o.AttrInt(activedirectory.EffectiveMaxPwdAge); maxage != tt.maxage {
+				t.Errorf("maximum age is %v, expected %v", maxage, tt.maxage)
+			}
+			if lockout, _ := o.AttrInt(activedirectory.EffectiveLockoutThreshold); lockout != tt.lockout {
+				t.Errorf("lockout threshold is %v, expected %v", lockout, tt.lockout)
+			}
+			if complexity, _ := o.AttrBool(activedirectory.EffectivePasswordComplexity); complexity != tt.complexity {
+				t.Errorf("complexity is %v, expected %v", complexity, tt.complexity)
+			}
+			for _, tag := range []string{"weak_password_policy", "no_lockout", "rc4_only", "des_enabled"} {
+				expected := false
+				for _, expectedtag := range tt.tags {
+					expected = expected || expectedtag == tag
+				}
+				if o.HasTag(engine.AttributeValueString(tag)) != expected {
+					t.Errorf("tag %v is %v, expected %v", tag, !expected, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRoastProbability(t *testing.T) {
+	// Password ages are from the collection, not from when the analysis runs
+	collected := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	activedirectory.ResetCollectionTimes()
+	activedirectory.SetCollectionTime(testDomainContext, collected)
+	defer activedirectory.ResetCollectionTimes()
+	daysago := func(days int) engine.AttributeValueTime {
+		return engine.AttributeValueTime(collected.Add(-time.Duration(days) * 24 * time.Hour))
+	}
+
+	ao, accounts := passwordPolicyObjects()
+	evaluatePasswordPolicies(ao)
+
+	const aes = engine.ENCTYPE_AES128_CTS_HMAC_SHA1_96 | engine.ENCTYPE_AES256_CTS_HMAC_SHA1_96
+	tests := []struct {
+		name        string
+		account     string
+		pwdlastset  engine.AttributeValueTime
+		enctypes    int64
+		probability engine.Probability
+	}{
+		{"old password, weak policy, RC4", "Alice", daysago(4 * 365), 0, 95},
+		{"recent password, AES only", "Bob", daysago(10), aes, 30},
+		{"long minimum length, AES and RC4", "Carol", daysago(2 * 365), aes | engine.ENCTYPE_RC4_HMAC, 35},
+		{"password set when collected", "Dave", daysago(0), aes, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := accounts[tt.account]
+			o.SetValues(activedirectory.PwdLastSet, tt.pwdlastset)
+			o.SetValues(activedirectory.MSDSSupportedEncryptionTypes, engine.AttributeValueInt(tt.enctypes))
+			if probability := activedirectory.RoastProbability(o); probability != tt.probability {
+				t.Errorf("probability is %v, expected %v", probability, tt.probability)
+			}
+		})
+	}
+
+	gmsa := engine.NewObject(engine.Type, engine.ObjectTypeGroupManagedServiceAccount.ValueString(), activedirectory.PwdLastSet, daysago(4*365))
+	if probability := activedirectory.RoastProbability(gmsa); probability != 1 {
+		t.Errorf("gMSA probability is %v, expected 1", probability)
+	}
+}
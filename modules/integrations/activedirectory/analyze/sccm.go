@@ -0,0 +1,111 @@
+package analyze
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+var (
+	MSSMSMPName   = engine.NewAttribute("mSSMSMPName")
+	MSSMSSiteCode = engine.NewAttribute("mSSMSSiteCode")
+
+	// On machines, values are "site role" like "PS1 ManagementPoint" - the site code is blank if it's not known
+	SCCMSiteSystemRoles = engine.NewAttribute("sccmSiteSystemRoles")
+	SCCMAssignedSite    = engine.NewAttribute("sccmAssignedSite")
+)
+
+const (
+	SCCMRoleSiteServer        = "SiteServer"
+	SCCMRoleManagementPoint   = "ManagementPoint"
+	SCCMRoleDistributionPoint = "DistributionPoint"
+)
+
+// AddSCCMRole records that the machine has a role in the SCCM site, keeping any roles it already has
+func AddSCCMRole(machine *engine.Object, site, role string) {
+	value := strings.ToUpper(site) + " " + role
+	roles := machine.AttrString(SCCMSiteSystemRoles)
+	for _, existing := range roles {
+		if existing == value {
+			return
+		}
+	}
+	machine.SetFlex(SCCMSiteSystemRoles, append(roles, value))
+}
+
+func init() {
+	LoaderID.AddProcessor(func(ao *engine.Objects) {
+		ao.Iterate(func(o *engine.Object) bool {
+			if !o.HasAttrValue(engine.ObjectClass, engine.AttributeValueString("mSSMSManagementPoint")) {
+				return true
+			}
+			site := o.OneAttrString(MSSMSSiteCode)
+			if machines, found := ao.FindMulti(DnsHostName, engine.AttributeValueString(o.OneAttrString(MSSMSMPName))); found {
+				machines.Iterate(func(machine *engine.Object) bool {
+					if machine.Type() == engine.ObjectTypeMachine {
+						AddSCCMRole(machine, site, SCCMRoleManagementPoint)
+					}
+					return true
+				})
+			}
+			return true
+		})
+	}, "SCCM management points", engine.BeforeMergeHigh)
+
+	// Site servers are given full control of the System Management container when the schema is extended,
+	// and each domain with a site has its own container
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
+		sites := make(map[string]struct{})
+		ao.Iterate(func(o *engine.Object) bool {
+			if site := o.OneAttrString(MSSMSSiteCode); site != "" {
+				sites[strings.ToUpper(site)] = struct{}{}
+			}
+			return true
+		})
+
+		// Only assign a site if there's no doubt about which one it is
+		var onlysite string
+		if len(sites) == 1 {
+			for site := range sites {
+				onlysite = site
+			}
+		}
+
+		var rolelock sync.Mutex
+		return &engine.ACLAnalyzer{
+			Object: func(ctx *engine.ACLContext) bool {
+				return strings.HasPrefix(strings.ToLower(ctx.Object.DN()), "cn=system management,cn=system,")
+			},
+			ACE: func(ctx *engine.ACLContext, index int) {
+				if !ctx.Allowed(index, engine.RIGHT_GENERIC_ALL, uuid.Nil) {
+					return
+				}
+				sid := ctx.SD.DACL.Entries[index].SID
+				computer, found := ao.Find(engine.ObjectSid, engine.AttributeValueSID(sid))
+				if !found || computer.Type() != engine.ObjectTypeComputer {
+					return
+				}
+				machine, found := ao.Find(DomainJoinedSID, engine.AttributeValueSID(sid))
+				if !found {
+					return
+				}
+
+				rolelock.Lock()
+				defer rolelock.Unlock()
+				site := onlysite
+				for _, role := range machine.AttrString(SCCMSiteSystemRoles) {
+					// Site servers are usually also the management point
+					if code, _, _ := strings.Cut(role, " "); code != "" {
+						site = code
+						break
+					}
+				}
+				ui.Debug().Msgf("Found SCCM site server %v for site %v in %v", machine.Label(), site, ctx.Object.DN())
+				AddSCCMRole(machine, site, SCCMRoleSiteServer)
+			},
+		}
+	}, "SCCM site servers from the System Management containers", engine.BeforeMergeFinal, engine.ObjectTypeContainer)
+}
@@ -0,0 +1,122 @@
+package analyze
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+var nonACLEdges = engine.EdgeBitmap{}.
+	Set(activedirectory.EdgeMemberOfGroup).
+	Set(activedirectory.EdgeMemberOfGroupIndirect).
+	Set(activedirectory.EdgeForeignIdentity)
+
+// Last change of an attribute from the replication metadata of the object
+func attributeChanged(o *engine.Object, attribute string, warned *atomic.Int32) time.Time {
+	var changed time.Time
+	o.Attr(activedirectory.MSDSReplAttributeMetaData).Iterate(func(value engine.AttributeValue) bool {
+		md, err := activedirectory.ParseReplAttributeMetaData(value.String())
+		if err != nil {
+			if warned.Add(1) <= 10 {
+				ui.Warn().Msgf("Problem parsing attribute replication metadata for %v: %v", o.DN(), err)
+			}
+			return true
+		}
+		if strings.EqualFold(md.AttributeName, attribute) {
+			changed = md.LastOriginatingTime
+			return false
+		}
+		return true
+	})
+	return changed
+}
+
+// Marks the edges from objects with the SID to o as present at least since the security descriptor was changed
+func stampACLEdges(ao *engine.Objects, o *engine.Object, sid windowssecurity.SID, changed time.Time) {
+	sources, _ := ao.FindMulti(engine.ObjectSid, engine.AttributeValueSID(sid))
+	sources.Iterate(func(source *engine.Object) bool {
+		if edges, found := o.Edges(engine.In).Get(source); found {
+			for _, edge := range edges.Edges() {
+				if !nonACLEdges.IsSet(edge) {
+					ao.SetEdgePresentSince(source, o, edge, changed)
+				}
+			}
+		}
+		return true
+	})
+}
+
+func init() {
+	var warned atomic.Int32
+
+	LoaderID.AddProcessor(func(ao *engine.Objects) {
+		ao.Filter(func(o *engine.Object) bool {
+			return o.HasAttr(activedirectory.MSDSReplAttributeMetaData) || o.HasAttr(activedirectory.MSDSReplValueMetaData)
+		}).Iterate(func(o *engine.Object) bool {
+			// Linked value replication gives us exact creation time for each group member
+			valuemetadata := make(map[string]struct{})
+			o.Attr(activedirectory.MSDSReplValueMetaData).Iterate(func(value engine.AttributeValue) bool {
+				md, err := activedirectory.ParseReplValueMetaData(value.String())
+				if err != nil {
+					if warned.Add(1) <= 10 {
+						ui.Warn().Msgf("Problem parsing value replication metadata for %v: %v", o.DN(), err)
+					}
+					return true
+				}
+				if !strings.EqualFold(md.AttributeName, "member") || md.IsDeleted() {
+					return true
+				}
+				valuemetadata[strings.ToLower(md.ObjectDN)] = struct{}{}
+				if member, found := ao.Find(engine.DistinguishedName, engine.AttributeValueString(md.ObjectDN)); found {
+					ao.SetEdgeFirstSeen(member, o, activedirectory.EdgeMemberOfGroup, md.Created)
+				}
+				return true
+			})
+
+			// Legacy (pre LVR) members were there when the member attribute was last changed, but could be older
+			if memberchanged := attributeChanged(o, "member", &warned); !memberchanged.IsZero() {
+				o.Attr(activedirectory.Member).Iterate(func(memberdn engine.AttributeValue) bool {
+					if _, found := valuemetadata[strings.ToLower(memberdn.String())]; found {
+						return true
+					}
+					if member, found := ao.Find(engine.DistinguishedName, memberdn); found {
+						ao.SetEdgePresentSince(member, o, activedirectory.EdgeMemberOfGroup, memberchanged)
+					}
+					return true
+				})
+			}
+			return true
+		})
+		if n := warned.Load(); n > 0 {
+			ui.Warn().Msgf("%v replication metadata values could not be parsed", n)
+		}
+	},
+		"Group membership timestamps from replication metadata",
+		engine.AfterMergeHigh,
+	)
+
+	// Edges granted by the ACL were there when the security descriptor was last changed, but could be older
+	LoaderID.AddACLAnalyzer(func(ao *engine.Objects) *engine.ACLAnalyzer {
+		return &engine.ACLAnalyzer{
+			Object: func(ctx *engine.ACLContext) bool {
+				sdchanged := attributeChanged(ctx.Object, "nTSecurityDescriptor", &warned)
+				if sdchanged.IsZero() {
+					return false
+				}
+				ctx.Object.SetValues(activedirectory.SecurityDescriptorChanged, engine.AttributeValueTime(sdchanged))
+				stampACLEdges(ctx.Objects, ctx.Object, ctx.SD.Owner, sdchanged)
+				return true
+			},
+			ACE: func(ctx *engine.ACLContext, index int) {
+				if sdchanged, ok := ctx.Object.OneAttrRaw(activedirectory.SecurityDescriptorChanged).(time.Time); ok {
+					stampACLEdges(ctx.Objects, ctx.Object, ctx.SD.DACL.Entries[index].SID, sdchanged)
+				}
+			},
+		}
+	}, "Edge timestamps from security descriptor replication metadata", engine.AfterMergeHigh)
+}
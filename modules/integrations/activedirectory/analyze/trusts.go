@@ -1,6 +1,15 @@
 package analyze
 
-import gsync "github.com/SaveTheRbtz/generic-sync-map-go"
+import (
+	"strings"
+
+	gsync "github.com/SaveTheRbtz/generic-sync-map-go"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/util"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
 
 type TrustDirection byte
 
@@ -11,6 +20,21 @@ const (
 	Bidirectional
 )
 
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-adts/e9a2d23c-c31e-4a6f-88a0-6646fdb51a3c
+const (
+	TRUST_ATTRIBUTE_NON_TRANSITIVE                           = 0x00000001
+	TRUST_ATTRIBUTE_UPLEVEL_ONLY                             = 0x00000002
+	TRUST_ATTRIBUTE_QUARANTINED_DOMAIN                       = 0x00000004
+	TRUST_ATTRIBUTE_FOREST_TRANSITIVE                        = 0x00000008
+	TRUST_ATTRIBUTE_CROSS_ORGANIZATION                       = 0x00000010
+	TRUST_ATTRIBUTE_WITHIN_FOREST                            = 0x00000020
+	TRUST_ATTRIBUTE_TREAT_AS_EXTERNAL                        = 0x00000040
+	TRUST_ATTRIBUTE_USES_RC4_ENCRYPTION                      = 0x00000080
+	TRUST_ATTRIBUTE_CROSS_ORGANIZATION_NO_TGT_DELEGATION     = 0x00000200
+	TRUST_ATTRIBUTE_PIM_TRUST                                = 0x00000400
+	TRUST_ATTRIBUTE_CROSS_ORGANIZATION_ENABLE_TGT_DELEGATION = 0x00000800
+)
+
 type TrustPair struct {
 	SourceNCName  string // Naming Context (dc=contoso,dc=com)
 	SourceDNSRoot string // DNS root (contoso.com)
@@ -25,3 +49,240 @@ type TrustInfo struct {
 }
 
 var TrustMap gsync.MapOf[TrustPair, TrustInfo]
+
+// Forget the trusts from an earlier analysis, so they don't leak into the next one done by the same process
+func resetTrustMap() {
+	TrustMap.Range(func(pair TrustPair, _ TrustInfo) bool {
+		TrustMap.Delete(pair)
+		return true
+	})
+}
+
+// Transitive trusts are the ones inside a forest and forest trusts
+func (ti TrustInfo) Transitive() bool {
+	return ti.Attributes&TRUST_ATTRIBUTE_NON_TRANSITIVE == 0 &&
+		ti.Attributes&(TRUST_ATTRIBUTE_WITHIN_FOREST|TRUST_ATTRIBUTE_FOREST_TRANSITIVE) != 0
+}
+
+func (ti TrustInfo) WithinForest() bool {
+	return ti.Attributes&TRUST_ATTRIBUTE_WITHIN_FOREST != 0
+}
+
+// SIDFiltering returns true if SIDs from outside the trusted domain / forest are stripped when crossing the trust
+func (ti TrustInfo) SIDFiltering() bool {
+	if ti.Attributes&TRUST_ATTRIBUTE_QUARANTINED_DOMAIN != 0 {
+		return true
+	}
+	if ti.Attributes&TRUST_ATTRIBUTE_WITHIN_FOREST != 0 {
+		return false
+	}
+	if ti.Attributes&TRUST_ATTRIBUTE_FOREST_TRANSITIVE != 0 {
+		// SID history enabled on forest trust (netdom /enablesidhistory)
+		return ti.Attributes&TRUST_ATTRIBUTE_TREAT_AS_EXTERNAL == 0
+	}
+	// External trust without quarantine
+	return false
+}
+
+func (ti TrustInfo) SelectiveAuthentication() bool {
+	return ti.Attributes&TRUST_ATTRIBUTE_CROSS_ORGANIZATION != 0
+}
+
+// TGTDelegation returns true if TGTs are forwarded to unconstrained delegation hosts across the trust
+func (ti TrustInfo) TGTDelegation() bool {
+	if ti.Attributes&TRUST_ATTRIBUTE_WITHIN_FOREST != 0 {
+		return true
+	}
+	if ti.Attributes&TRUST_ATTRIBUTE_CROSS_ORGANIZATION_NO_TGT_DELEGATION != 0 {
+		return false
+	}
+	return ti.Attributes&TRUST_ATTRIBUTE_CROSS_ORGANIZATION_ENABLE_TGT_DELEGATION != 0
+}
+
+// TrustPath is the combined effect of a chain of trusts, where principals of the trusted domain can
+// authenticate to the trusting domain
+type TrustPath struct {
+	SIDFiltering            bool
+	SelectiveAuthentication bool
+	TGTDelegation           bool
+}
+
+type trustLink struct {
+	trusted string
+	info    TrustInfo
+}
+
+// ResolveTrustPaths walks the trusts in TrustMap honoring direction and transitivity, and returns a map
+// of trusting DNS root -> trusted DNS root -> effective trust path. All DNS roots are lowercase.
+func ResolveTrustPaths() map[string]map[string]TrustPath {
+	// trusting domain -> the domains it trusts
+	links := make(map[string][]trustLink)
+	addlink := func(trusting, trusted string, info TrustInfo) {
+		for _, existing := range links[trusting] {
+			if existing.trusted == trusted {
+				return
+			}
+		}
+		links[trusting] = append(links[trusting], trustLink{
+			trusted: trusted,
+			info:    info,
+		})
+	}
+
+	TrustMap.Range(func(pair TrustPair, info TrustInfo) bool {
+		if pair.TargetDNSRoot == "" {
+			// Domain registration, not a trust
+			return true
+		}
+		source := strings.ToLower(pair.SourceDNSRoot)
+		target := strings.ToLower(pair.TargetDNSRoot)
+		if info.Direction&Outgoing != 0 {
+			// Source trusts target, so target principals can authenticate to source
+			addlink(source, target, info)
+		}
+		if info.Direction&Incoming != 0 {
+			addlink(target, source, info)
+		}
+		return true
+	})
+
+	type pathstep struct {
+		domain        string
+		crossedforest bool
+		path          TrustPath
+	}
+
+	result := make(map[string]map[string]TrustPath)
+	for trusting := range links {
+		paths := make(map[string]TrustPath)
+		visited := map[string]struct{}{trusting: {}}
+		queue := []pathstep{{domain: trusting, path: TrustPath{TGTDelegation: true}}}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, link := range links[current.domain] {
+				if current.domain != trusting {
+					// We're extending a chain, so the next link must be transitive, and we can only cross into another forest once
+					if !link.info.Transitive() || (current.crossedforest && !link.info.WithinForest()) {
+						continue
+					}
+				}
+				if _, found := visited[link.trusted]; found {
+					continue
+				}
+				visited[link.trusted] = struct{}{}
+
+				path := TrustPath{
+					SIDFiltering:            current.path.SIDFiltering || link.info.SIDFiltering(),
+					SelectiveAuthentication: current.path.SelectiveAuthentication || link.info.SelectiveAuthentication(),
+					TGTDelegation:           current.path.TGTDelegation && link.info.TGTDelegation(),
+				}
+				paths[link.trusted] = path
+
+				if link.info.Transitive() {
+					queue = append(queue, pathstep{
+						domain:        link.trusted,
+						crossedforest: current.crossedforest || !link.info.WithinForest(),
+						path:          path,
+					})
+				}
+			}
+		}
+		result[trusting] = paths
+	}
+	return result
+}
+
+// Maps domain SIDs to lowercase DNS roots, using both the loaded domains and what trust objects tell us about partners
+func domainSIDsToDNSRoots(ao *engine.Objects) map[windowssecurity.SID]string {
+	result := make(map[windowssecurity.SID]string)
+	ao.Iterate(func(o *engine.Object) bool {
+		switch o.Type() {
+		case engine.ObjectTypeDomainDNS:
+			if o.HasAttr(activedirectory.ObjectSid) {
+				result[o.SID()] = util.DomainContextToDomainSuffix(o.OneAttrString(engine.DomainContext))
+			}
+		case engine.ObjectTypeTrust:
+			if sid, ok := o.OneAttrRaw(activedirectory.SecurityIdentifier).(windowssecurity.SID); ok {
+				if _, found := result[sid]; !found {
+					result[sid] = strings.ToLower(o.OneAttrString(activedirectory.TrustPartner))
+				}
+			}
+		}
+		return true
+	})
+	return result
+}
+
+func init() {
+	LoaderID.AddProcessor(func(ao *engine.Objects) {
+		trustpaths := ResolveTrustPaths()
+		if len(trustpaths) == 0 {
+			return
+		}
+
+		domains := make(map[string]*engine.Object)
+		ao.Filter(func(o *engine.Object) bool {
+			return o.Type() == engine.ObjectTypeDomainDNS && o.HasAttr(activedirectory.ObjectSid)
+		}).Iterate(func(domain *engine.Object) bool {
+			domains[util.DomainContextToDomainSuffix(domain.OneAttrString(engine.DomainContext))] = domain
+			return true
+		})
+
+		for trusting, paths := range trustpaths {
+			trustingdomain, found := domains[trusting]
+			if !found {
+				continue
+			}
+			for trusted, path := range paths {
+				trusteddomain, found := domains[trusted]
+				if !found {
+					continue
+				}
+				trusteddomain.EdgeTo(trustingdomain, activedirectory.EdgeTrustAuthentication)
+				if !path.SIDFiltering {
+					trusteddomain.EdgeTo(trustingdomain, activedirectory.EdgeTrustSIDHistory)
+				}
+				if path.TGTDelegation {
+					trustingdomain.EdgeTo(trusteddomain, activedirectory.EdgeTrustTGTDelegation)
+				}
+			}
+		}
+
+		// SID history pointing into another domain only works if that domain trusts us and does not filter SIDs
+		sidtodomain := domainSIDsToDNSRoots(ao)
+		var removed int
+		ao.Filter(func(o *engine.Object) bool {
+			return o.HasAttr(activedirectory.SIDHistory)
+		}).Iterate(func(o *engine.Object) bool {
+			sourcedomain := util.DomainContextToDomainSuffix(o.OneAttrString(engine.DomainContext))
+			var blocked []*engine.Object
+			o.Edges(engine.Out).Range(func(target *engine.Object, edge engine.EdgeBitmap) bool {
+				if !edge.IsSet(activedirectory.EdgeSIDHistoryEquality) {
+					return true
+				}
+				targetdomain, found := sidtodomain[target.SID().StripRID()]
+				if !found || sourcedomain == "" || targetdomain == sourcedomain {
+					// Unknown or same domain, we can't say anything
+					return true
+				}
+				if path, found := trustpaths[targetdomain][sourcedomain]; !found || path.SIDFiltering {
+					blocked = append(blocked, target)
+				}
+				return true
+			})
+			for _, target := range blocked {
+				o.EdgeClear(target, activedirectory.EdgeSIDHistoryEquality)
+				removed++
+			}
+			return true
+		})
+		if removed > 0 {
+			ui.Info().Msgf("Removed %v SID history edges that are blocked by SID filtering or missing trusts", removed)
+		}
+	},
+		"Trust relationships between domains",
+		engine.AfterMergeLow,
+	)
+}
@@ -0,0 +1,107 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveTrustPaths(t *testing.T) {
+	type trust struct {
+		source, target string
+		direction      TrustDirection
+		attributes     int
+	}
+
+	tests := []struct {
+		name   string
+		trusts []trust
+		paths  map[string]map[string]TrustPath // Trusting domain -> trusted domain
+	}{
+		{
+			name: "one-way outgoing",
+			trusts: []trust{
+				{"a.local", "", Disabled, 0}, // Domain registration
+				{"a.local", "b.local", Outgoing, 0},
+			},
+			paths: map[string]map[string]TrustPath{
+				"a.local": {"b.local": {}},
+				"b.local": nil,
+			},
+		},
+		{
+			name: "one-way incoming",
+			trusts: []trust{
+				{"a.local", "b.local", Incoming, 0},
+			},
+			paths: map[string]map[string]TrustPath{
+				"a.local": nil,
+				"b.local": {"a.local": {}},
+			},
+		},
+		{
+			name: "non-transitive external trust",
+			trusts: []trust{
+				{"a.local", "b.local", Bidirectional, TRUST_ATTRIBUTE_QUARANTINED_DOMAIN},
+				{"b.local", "child.b.local", Bidirectional, TRUST_ATTRIBUTE_WITHIN_FOREST},
+			},
+			paths: map[string]map[string]TrustPath{
+				"a.local":       {"b.local": {SIDFiltering: true}},
+				"b.local":       {"a.local": {SIDFiltering: true}, "child.b.local": {TGTDelegation: true}},
+				"child.b.local": {"b.local": {TGTDelegation: true}},
+			},
+		},
+		{
+			name: "forest transitive chain",
+			trusts: []trust{
+				{"child.a.local", "a.local", Bidirectional, TRUST_ATTRIBUTE_WITHIN_FOREST},
+				{"a.local", "b.local", Bidirectional, TRUST_ATTRIBUTE_FOREST_TRANSITIVE},
+				{"b.local", "child.b.local", Bidirectional, TRUST_ATTRIBUTE_WITHIN_FOREST},
+				{"b.local", "c.local", Bidirectional, TRUST_ATTRIBUTE_FOREST_TRANSITIVE | TRUST_ATTRIBUTE_CROSS_ORGANIZATION},
+			},
+			paths: map[string]map[string]TrustPath{
+				// Only one forest trust can be crossed, so c.local is out of reach
+				"child.a.local": {
+					"a.local":       {TGTDelegation: true},
+					"b.local":       {SIDFiltering: true},
+					"child.b.local": {SIDFiltering: true},
+				},
+				"c.local": {
+					"b.local":       {SIDFiltering: true, SelectiveAuthentication: true},
+					"child.b.local": {SIDFiltering: true, SelectiveAuthentication: true},
+				},
+			},
+		},
+		{
+			name: "forest trust with SID history",
+			trusts: []trust{
+				{"a.local", "b.local", Outgoing, TRUST_ATTRIBUTE_FOREST_TRANSITIVE | TRUST_ATTRIBUTE_TREAT_AS_EXTERNAL | TRUST_ATTRIBUTE_CROSS_ORGANIZATION_ENABLE_TGT_DELEGATION},
+			},
+			paths: map[string]map[string]TrustPath{
+				"a.local": {"b.local": {TGTDelegation: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTrustMap()
+			defer resetTrustMap()
+			for _, trust := range tt.trusts {
+				TrustMap.Store(TrustPair{SourceDNSRoot: trust.source, TargetDNSRoot: trust.target}, TrustInfo{
+					Direction:  trust.direction,
+					Attributes: trust.attributes,
+				})
+			}
+
+			result := ResolveTrustPaths()
+			for trusting, expected := range tt.paths {
+				paths := result[trusting]
+				if len(paths) == 0 && len(expected) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual(paths, expected) {
+					t.Errorf("%v trusts %+v, expected %+v", trusting, paths, expected)
+				}
+			}
+		})
+	}
+}
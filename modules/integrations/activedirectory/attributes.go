@@ -5,7 +5,13 @@ import (
 )
 
 var (
-	EdgeForeignIdentity = engine.NewEdge("ForeignIdentity")
+	EdgeForeignIdentity = engine.NewEdge("ForeignIdentity").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+		if target.HasTag("trust_selective_authentication") {
+			// Needs Allowed-To-Authenticate on the resources in the trusting domain too
+			return 50
+		}
+		return 100
+	})
 
 	DistinguishedName                       = engine.NewAttribute("distinguishedName").Tag("AD").Unique().Single()
 	ObjectClass                             = engine.NewAttribute("objectClass").Tag("AD")
@@ -29,6 +35,8 @@ var (
 	PwdProperties                           = engine.NewAttribute("pwdProperties").Tag("AD")
 	LockOutDuration                         = engine.NewAttribute("lockoutDuration").Tag("AD")
 	PwdHistoryLength                        = engine.NewAttribute("pwdHistoryLength").Tag("AD").Type(engine.AttributeTypeInt)
+	LockoutThreshold                        = engine.NewAttribute("lockoutThreshold").Tag("AD").Type(engine.AttributeTypeInt)
+	MaxPwdAge                               = engine.NewAttribute("maxPwdAge").Tag("AD").Type(engine.AttributeTypeInt)
 	IsCriticalSystemObject                  = engine.NewAttribute("isCriticalSystemObject").Tag("AD")
 	FSMORoleOwner                           = engine.NewAttribute("fSMORoleOwner").Tag("AD")
 	NTMixedDomain                           = engine.NewAttribute("nTMixedDomain").Tag("AD")
@@ -70,6 +78,7 @@ var (
 	PwdLastSet                              = engine.NewAttribute("pwdLastSet").Tag("AD").Type(engine.AttributeTypeTime)
 	WhenCreated                             = engine.NewAttribute("whenCreated").Type(engine.AttributeTypeTime)
 	WhenChanged                             = engine.NewAttribute("whenChanged").Type(engine.AttributeTypeTime)
+	CurrentTime                             = engine.NewAttribute("currentTime").Type(engine.AttributeTypeTime) // On the RootDSE, when the DC was asked
 	DsCorePropagationData                   = engine.NewAttribute("dsCorePropagationData").Type(engine.AttributeTypeTime)
 	MsExchLastUpdateTime                    = engine.NewAttribute("msExchLastUpdateTime").Type(engine.AttributeTypeTime)
 	GWARTLastModified                       = engine.NewAttribute("gWARTLastModified").Type(engine.AttributeTypeTime)
@@ -82,6 +91,15 @@ var (
 	MSDSGroupMSAMembership                  = engine.NewAttribute("msDS-GroupMSAMembership").Tag("AD").Type(engine.AttributeTypeSecurityDescriptor)
 	MSDSHostServiceAccount                  = engine.NewAttribute("msDS-HostServiceAccount").Tag("AD")
 	MSDSHostServiceAccountBL                = engine.NewAttribute("msDS-HostServiceAccountBL").Tag("AD")
+	MSDSSupportedEncryptionTypes            = engine.NewAttribute("msDS-SupportedEncryptionTypes").Tag("AD").Type(engine.AttributeTypeInt)
+	MSDSPasswordSettingsPrecedence          = engine.NewAttribute("msDS-PasswordSettingsPrecedence").Tag("AD").Type(engine.AttributeTypeInt)
+	MSDSMinimumPasswordLength               = engine.NewAttribute("msDS-MinimumPasswordLength").Tag("AD").Type(engine.AttributeTypeInt)
+	MSDSMaximumPasswordAge                  = engine.NewAttribute("msDS-MaximumPasswordAge").Tag("AD").Type(engine.AttributeTypeInt)
+	MSDSLockoutThreshold                    = engine.NewAttribute("msDS-LockoutThreshold").Tag("AD").Type(engine.AttributeTypeInt)
+	MSDSPasswordComplexityEnabled           = engine.NewAttribute("msDS-PasswordComplexityEnabled").Tag("AD").Type(engine.AttributeTypeBool)
+	MSDSPSOAppliesTo                        = engine.NewAttribute("msDS-PSOAppliesTo").Tag("AD")
+	MSDSReplAttributeMetaData               = engine.NewAttribute("msDS-ReplAttributeMetaData").Tag("AD")
+	MSDSReplValueMetaData                   = engine.NewAttribute("msDS-ReplValueMetaData").Tag("AD")
 	MSmcsAdmPwdExpirationTime               = engine.NewAttribute("ms-mcs-AdmPwdExpirationTime").Tag("AD").Type(engine.AttributeTypeTime) // LAPS password timeout
 	SecurityIdentifier                      = engine.NewAttribute("securityIdentifier").Type(engine.AttributeTypeSID)
 	TrustDirection                          = engine.NewAttribute("trustDirection").Type(engine.AttributeTypeInt)
@@ -102,3 +120,18 @@ var (
 	MsDSBehaviourVersion                    = engine.NewAttribute("msDS-Behavior-Version").Type(engine.AttributeTypeInt)
 	DNSHostName                             = engine.NewAttribute("dnsHostName").Tag("AD")
 )
+
+// Derived from password policy evaluation (domain policy and fine-grained password policies)
+var (
+	EffectivePasswordPolicy     = engine.NewAttribute("effectivePasswordPolicy").Single()
+	EffectiveMinPwdLength       = engine.NewAttribute("effectiveMinPwdLength").Single().Type(engine.AttributeTypeInt)
+	EffectiveMaxPwdAge          = engine.NewAttribute("effectiveMaxPwdAge").Single().Type(engine.AttributeTypeInt) // days, 0 = never expires
+	EffectiveLockoutThreshold   = engine.NewAttribute("effectiveLockoutThreshold").Single().Type(engine.AttributeTypeInt)
+	EffectivePasswordComplexity = engine.NewAttribute("effectivePasswordComplexity").Single().Type(engine.AttributeTypeBool)
+)
+
+// Named hygiene findings, set by the AD analyzer
+var HygieneFinding = engine.NewAttribute("hygieneFinding")
+
+// Derived from replication metadata
+var SecurityDescriptorChanged = engine.NewAttribute("securityDescriptorChanged").Single().Type(engine.AttributeTypeTime)
@@ -19,7 +19,6 @@ import (
 	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
 	"github.com/lkarlslund/adalanche/modules/ui"
 	"github.com/lkarlslund/binstruct"
-	"github.com/pierrec/lz4/v4"
 	"github.com/tinylib/msgp/msgp"
 )
 
@@ -489,23 +488,13 @@ func (adex *ADExplorerDumper) Dump(do DumpOptions) ([]activedirectory.RawObject,
 		if err != nil {
 			return nil, fmt.Errorf("problem creating directory: %v", err)
 		}
-		outfile, err := os.Create(do.WriteToFile)
+		outfile, boutfile, writer, err := createObjectFile(do.WriteToFile)
 		if err != nil {
-			return nil, fmt.Errorf("problem opening domain cache file: %v", err)
+			return nil, err
 		}
 		defer outfile.Close()
-
-		boutfile := lz4.NewWriter(outfile)
-		lz4options := []lz4.Option{
-			lz4.BlockChecksumOption(true),
-			// lz4.BlockSizeOption(lz4.BlockSize(51 * 1024)),
-			lz4.ChecksumOption(true),
-			lz4.CompressionLevelOption(lz4.Level9),
-			lz4.ConcurrencyOption(-1),
-		}
-		boutfile.Apply(lz4options...)
 		defer boutfile.Close()
-		e = msgp.NewWriter(boutfile)
+		e = writer
 	}
 
 	bar := ui.ProgressBar("Converting objects from AD Explorer snapshot", int(header.ObjectCount))
@@ -9,8 +9,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/lkarlslund/adalanche/modules/ui"
 	"github.com/pkg/errors"
@@ -19,6 +21,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/lkarlslund/adalanche/modules/basedata"
 	clicollect "github.com/lkarlslund/adalanche/modules/cli/collect"
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
 	"github.com/lkarlslund/adalanche/modules/util"
 	"github.com/lkarlslund/adalanche/modules/windowssecurity"
@@ -40,6 +43,8 @@ var (
 
 	ntdsfile = Command.Flags().String("ntdsfile", "", "Import AD objects from NTDS.DIT file")
 
+	ldiffile = Command.Flags().String("ldiffile", "", "Import AD objects from LDIF file (ldapsearch -LLL, ldifde or similar)")
+
 	servers = Command.Flags().StringArray("server", nil, "DC to connect to, use IP or full hostname, random DC is auto-detected if not supplied")
 	port    = Command.Flags().Int("port", -1, "LDAP port to connect to (389 or 636 typical, -1 for auto based on tlsmode)")
 	domain  = Command.Flags().String("domain", "", "domain suffix to analyze (auto-detected if not supplied)")
@@ -55,6 +60,8 @@ var (
 	authdomain      = Command.Flags().String("authdomain", "", "domain for authentication, if using ntlm auth")
 	attributesparam = Command.Flags().String("attributes", "*", "Comma seperated list of attributes to get, * = all, or a comma seperated list of attribute names (expert)")
 
+	replmetadata = Command.Flags().Bool("replmetadata", false, "Also collect replication metadata (msDS-ReplAttributeMetaData and msDS-ReplValueMetaData) for object timelines, when collecting all attributes")
+
 	nosacl   = Command.Flags().Bool("nosacl", true, "Request data with NO SACL flag, allows normal users to dump ntSecurityDescriptor field")
 	pagesize = Command.Flags().Int("pagesize", 1000, "Number of objects per request to collect (increase for performance, but some DCs have limits)")
 
@@ -67,6 +74,12 @@ var (
 	AuthmodeString       = Command.Flags().String("authmode", "ntlm", "Bind mode: unauth/anonymous, basic/simple, digest/md5, kerberoscache, ntlm, ntlmpth (password is hash)")
 
 	purgeolddata = Command.Flags().Bool("purgeolddata", false, "Purge existing data from the datapath if connection to DC is successfull")
+	partitioned  = Command.Flags().Bool("partitioned", false, "Split collection into partitions (one per top level container) with checkpoints, so an interrupted collection can be resumed")
+	parallel     = Command.Flags().Int("parallel", 1, "Number of concurrent LDAP connections to use for partitioned collection")
+	spreaddcs    = Command.Flags().Bool("spreaddcs", false, "Spread parallel connections across all supplied or detected DCs instead of only the first one that works")
+	resume       = Command.Flags().Bool("resume", true, "Resume interrupted partitioned collection from checkpoints in the datapath")
+
+	incremental = Command.Flags().Bool("incremental", false, "Only collect objects changed or deleted since the last collection from the same DC (using uSNChanged), saved as delta files on top of the last full collection")
 
 	authmode AuthMode
 	tlsmode  TLSmode
@@ -81,7 +94,7 @@ func init() {
 
 // Checks that we have enough data to proceed with the real run
 func PreRun(cmd *cobra.Command, args []string) error {
-	if *adexplorerfile != "" || *ntdsfile != "" {
+	if *adexplorerfile != "" || *ntdsfile != "" || *ldiffile != "" {
 		// That's all we need for this run to work
 		return nil
 	}
@@ -241,6 +254,45 @@ func Execute(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("problem collecting Active Directory objects: %v", err)
 		}
 
+		err = ad.Disconnect()
+		if err != nil {
+			return err
+		}
+	} else if *ldiffile != "" {
+		// LDIF export from some other tool
+		ui.Info().Msgf("Collecting objects from LDIF file %v ...", *ldiffile)
+
+		ad := LDIFDumper{
+			path: *ldiffile,
+		}
+
+		err := ad.Connect()
+		if err != nil {
+			return err
+		}
+
+		do := DumpOptions{
+			WriteToFile: filepath.Join(datapath, filepath.Base(*ldiffile)+".objects.msgp.lz4"),
+		}
+
+		if *collectgpos == "auto" || cp {
+			do.OnObject = func(ro *activedirectory.RawObject) error {
+				if _, found := ro.Attributes["gPCFileSysPath"]; found {
+					gpostocollect = append(gpostocollect, ro)
+				}
+				if nbn, found := ro.Attributes["nETBIOSName"]; found {
+					netbiosname = nbn[0]
+				}
+				return nil
+			}
+		}
+
+		_, err = ad.Dump(do)
+		if err != nil {
+			os.Remove(do.WriteToFile)
+			return fmt.Errorf("problem collecting Active Directory objects: %v", err)
+		}
+
 		err = ad.Disconnect()
 		if err != nil {
 			return err
@@ -282,7 +334,7 @@ func Execute(cmd *cobra.Command, args []string) error {
 			debugfilename := do.WriteToFile + ".json"
 			ui.Debug().Msgf("Writing %v debug objects to %v", len(objects), debugfilename)
 			jsondata, _ := json.MarshalIndent(objects, "", "  ")
-			os.WriteFile(debugfilename, jsondata, 0644)
+			encryption.WriteFile(debugfilename, jsondata, 0644)
 		}
 
 		if err != nil {
@@ -328,10 +380,38 @@ func Execute(cmd *cobra.Command, args []string) error {
 			return errors.New("All DCs failed login attempts")
 		}
 
+		// Extra connections for parallel collection
+		dumpers := []LDAPDumper{ad}
+		usedservers := map[string]struct{}{chosenserver: {}}
+		if *partitioned && *parallel > 1 {
+			candidates := []string{chosenserver}
+			if *spreaddcs {
+				for _, server := range *servers {
+					if server != chosenserver {
+						candidates = append(candidates, server)
+					}
+				}
+			}
+			for i := 1; i < *parallel; i++ {
+				options.Server = candidates[i%len(candidates)]
+				extra := CreateDumper(options)
+				if err := extra.Connect(); err != nil {
+					ui.Warn().Msgf("Problem opening extra connection to DC %v: %v", options.Server, err)
+					continue
+				}
+				dumpers = append(dumpers, extra)
+				usedservers[options.Server] = struct{}{}
+			}
+			ui.Info().Msgf("Collecting using %v connections to %v DCs", len(dumpers), len(usedservers))
+		}
+
 		var attributes []string
 		switch *attributesparam {
 		case "*":
-			// don't do anything
+			// Constructed attributes are not included in *, so ask for them explicitly
+			if *replmetadata {
+				attributes = []string{"*", "msDS-ReplAttributeMetaData", "msDS-ReplValueMetaData"}
+			}
 		default:
 			attributes = strings.Split(*attributesparam, ",")
 		}
@@ -394,8 +474,39 @@ func Execute(cmd *cobra.Command, args []string) error {
 			datapath = filepath.Join("data", domainContext)
 		}
 
+		// USNs are local to each DC, so we can only ask for changes if we're talking to the same DC as last time
+		collectionstart := time.Now()
+		state := activedirectory.IncrementalState{
+			Server:    chosenserver,
+			Collected: collectionstart,
+		}
+		if len(rd.Attributes["dsServiceName"]) > 0 {
+			state.DSAName = rd.Attributes["dsServiceName"][0]
+		}
+		if len(rd.Attributes["highestCommittedUSN"]) > 0 {
+			state.HighestUSN, _ = strconv.ParseInt(rd.Attributes["highestCommittedUSN"][0], 10, 64)
+		}
+
+		var previous activedirectory.IncrementalState
+		if *incremental {
+			previous, err = activedirectory.LoadIncrementalState(datapath, domainContext)
+			switch {
+			case err != nil:
+				ui.Warn().Msgf("No usable state from previous collection (%v), doing full collection", err)
+			case state.HighestUSN == 0 || state.DSAName == "":
+				ui.Warn().Msg("Could not read highestCommittedUSN from RootDSE, doing full collection")
+			case !strings.EqualFold(previous.DSAName, state.DSAName):
+				ui.Warn().Msgf("Previous collection was from DC %v, but we're connected to %v - doing full collection (use --server %v to collect incrementally)", previous.Server, chosenserver, previous.Server)
+			default:
+				state.Incremental = true
+				ui.Info().Msgf("Collecting changes since USN %v (%v)", previous.HighestUSN, previous.Collected.Format(time.RFC3339))
+			}
+		}
+
 		// Clean up old data if requested
-		if _, err := os.Stat(datapath); err == nil && *purgeolddata {
+		if state.Incremental && *purgeolddata {
+			ui.Warn().Msg("Not purging old data, as it is needed for incremental collection")
+		} else if _, err := os.Stat(datapath); err == nil && *purgeolddata {
 			ui.Info().Msgf("Removing old data from %v", datapath)
 			os.RemoveAll(datapath)
 		}
@@ -431,14 +542,43 @@ func Execute(cmd *cobra.Command, args []string) error {
 			ReturnObjects: false,
 		}
 
+		outputfile := func(context string) string {
+			return filepath.Join(datapath, context+".objects.msgp.lz4")
+		}
+		if state.Incremental {
+			// Tombstones are included, so the loader can remove deleted objects
+			do.Query = fmt.Sprintf("(uSNChanged>=%v)", previous.HighestUSN+1)
+			do.ShowDeleted = true
+			outputfile = func(context string) string {
+				return activedirectory.DeltaFilename(datapath, context, collectionstart)
+			}
+		} else {
+			// A full collection replaces everything, so deltas from earlier runs no longer apply
+			oldDeltas, _ := filepath.Glob(filepath.Join(datapath, "*"+activedirectory.DeltaSuffix))
+			for _, oldDelta := range oldDeltas {
+				ui.Debug().Msgf("Removing old incremental collection %v", oldDelta)
+				os.Remove(oldDelta)
+			}
+		}
+
+		dump := func(context string) error {
+			if *partitioned && !state.Incremental {
+				return collectPartitioned(dumpers, datapath, context, do)
+			}
+			do.SearchBase = context
+			do.WriteToFile = outputfile(context)
+			_, err := ad.Dump(do)
+			if err != nil {
+				os.Remove(do.WriteToFile)
+			}
+			return err
+		}
+
 		cs, _ := util.ParseBool(*collectschema)
 		if (*collectschema == "auto" && schemaContext != "") || cs {
 			ui.Info().Msgf("Collecting schema objects from %v ...", schemaContext)
-			do.SearchBase = schemaContext
-			do.WriteToFile = filepath.Join(datapath, do.SearchBase+".objects.msgp.lz4")
-			_, err = ad.Dump(do)
+			err = dump(schemaContext)
 			if err != nil {
-				os.Remove(do.WriteToFile)
 				return fmt.Errorf("problem collecting Active Directory schema objects: %v", err)
 			}
 		}
@@ -446,8 +586,6 @@ func Execute(cmd *cobra.Command, args []string) error {
 		cs, _ = util.ParseBool(*collectconfiguration)
 		if (*collectconfiguration == "auto" && configContext != "") || cs {
 			ui.Info().Msgf("Collecting configuration objects from %v ...", configContext)
-			do.SearchBase = configContext
-			do.WriteToFile = filepath.Join(datapath, do.SearchBase+".objects.msgp.lz4")
 
 			if *collectgpos == "auto" || cp {
 				do.OnObject = func(ro *activedirectory.RawObject) error {
@@ -458,9 +596,8 @@ func Execute(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			_, err = ad.Dump(do)
+			err = dump(configContext)
 			if err != nil {
-				os.Remove(do.WriteToFile)
 				return fmt.Errorf("problem collecting Active Directory configuration objects: %v", err)
 			}
 		}
@@ -470,11 +607,8 @@ func Execute(cmd *cobra.Command, args []string) error {
 			ui.Info().Msgf("Collecting %v other objects ...", len(otherContexts))
 			for _, context := range otherContexts {
 				ui.Info().Msgf("Collecting from base DN %v ...", context)
-				do.SearchBase = context
-				do.WriteToFile = filepath.Join(datapath, do.SearchBase+".objects.msgp.lz4")
-				_, err = ad.Dump(do)
+				err = dump(context)
 				if err != nil {
-					os.Remove(do.WriteToFile)
 					return fmt.Errorf("problem collecting Active Directory Forest DNS objects: %v", err)
 				}
 			}
@@ -483,8 +617,6 @@ func Execute(cmd *cobra.Command, args []string) error {
 		cs, _ = util.ParseBool(*collectobjects)
 		if (*collectobjects == "auto" && domainContext != "") || cs {
 			ui.Info().Msgf("Collecting main AD objects from %v ...", domainContext)
-			do.SearchBase = domainContext
-			do.WriteToFile = filepath.Join(datapath, do.SearchBase+".objects.msgp.lz4")
 
 			if *collectgpos == "auto" || cp {
 				do.OnObject = func(ro *activedirectory.RawObject) error {
@@ -495,16 +627,27 @@ func Execute(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			_, err = ad.Dump(do)
+			err = dump(domainContext)
 			if err != nil {
-				os.Remove(do.WriteToFile)
 				return fmt.Errorf("problem collecting Active Directory objects: %v", err)
 			}
 		}
 
-		err = ad.Disconnect()
-		if err != nil {
-			return fmt.Errorf("problem disconnecting from AD: %v", err)
+		for _, dumper := range dumpers {
+			err = dumper.Disconnect()
+			if err != nil {
+				return fmt.Errorf("problem disconnecting from AD: %v", err)
+			}
+		}
+
+		if len(usedservers) > 1 {
+			// Objects came from several DCs, so the USN from one of them doesn't cover everything
+			ui.Warn().Msg("Not saving state for incremental collection, as data was collected from more than one DC")
+		} else if state.HighestUSN != 0 {
+			err = state.Save(datapath, domainContext)
+			if err != nil {
+				ui.Warn().Msgf("Problem saving state for incremental collection: %v", err)
+			}
 		}
 	}
 
@@ -596,11 +739,11 @@ func Execute(cmd *cobra.Command, args []string) error {
 					}
 
 					gpodatafile := filepath.Join(datapath, gpoguid[0]+".gpodata.json")
-					f, err := os.Create(gpodatafile)
+					f, err := encryption.Create(gpodatafile)
 					if err != nil {
-						ui.Error().Msgf("Problem writing GPO information to %v: %v")
+						ui.Error().Msgf("Problem writing GPO information to %v: %v", gpodatafile, err)
+						continue
 					}
-					defer f.Close()
 
 					encoder := json.NewEncoder(f)
 					encoder.SetIndent("", "  ")
@@ -608,6 +751,9 @@ func Execute(cmd *cobra.Command, args []string) error {
 					if err != nil {
 						ui.Error().Msgf("Problem marshalling GPO information to %v: %v", gpodatafile, err)
 					}
+					if err = f.Close(); err != nil {
+						ui.Error().Msgf("Problem writing GPO information to %v: %v", gpodatafile, err)
+					}
 				}
 			} else {
 				ui.Warn().Msgf("Skipping %v, not a GPO", object.Attributes["displayName"])
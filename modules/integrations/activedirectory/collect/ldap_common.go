@@ -154,6 +154,8 @@ type LDAPOptions struct {
 
 type objectCallbackFunc func(ro *activedirectory.RawObject) error
 
+type pageCallbackFunc func(cookie []byte) error
+
 type DumpOptions struct {
 	SearchBase string
 	Scope      int
@@ -162,6 +164,11 @@ type DumpOptions struct {
 	NoSACL     bool
 	ChunkSize  int
 
+	ShowDeleted bool // Include tombstones, needed to pick up deletions in incremental collections
+
+	Cookie []byte           // Continue a paged search from this cookie
+	OnPage pageCallbackFunc // Called after each page, with the cookie for the next page (nil when done)
+
 	OnObject      objectCallbackFunc
 	WriteToFile   string
 	ReturnObjects bool
@@ -18,7 +18,6 @@ import (
 	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
 	"github.com/lkarlslund/adalanche/modules/ui"
 	ldap "github.com/lkarlslund/ldap/v3"
-	"github.com/pierrec/lz4/v4"
 	"github.com/schollz/progressbar/v3"
 	"github.com/tinylib/msgp/msgp"
 )
@@ -195,23 +194,13 @@ func (ad *AD) RootDn() string {
 func (ad *AD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 	var e *msgp.Writer
 	if do.WriteToFile != "" {
-		outfile, err := os.Create(do.WriteToFile)
+		outfile, boutfile, writer, err := createObjectFile(do.WriteToFile)
 		if err != nil {
-			return nil, fmt.Errorf("problem opening domain cache file: %v", err)
+			return nil, err
 		}
 		defer outfile.Close()
-
-		boutfile := lz4.NewWriter(outfile)
-		lz4options := []lz4.Option{
-			lz4.BlockChecksumOption(true),
-			// lz4.BlockSizeOption(lz4.BlockSize(51 * 1024)),
-			lz4.ChecksumOption(true),
-			lz4.CompressionLevelOption(lz4.Level9),
-			lz4.ConcurrencyOption(-1),
-		}
-		boutfile.Apply(lz4options...)
 		defer boutfile.Close()
-		e = msgp.NewWriter(boutfile)
+		e = writer
 	}
 
 	bar := progressbar.NewOptions(-1,
@@ -234,8 +223,15 @@ func (ad *AD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 		controls = append(controls, sdcontrol)
 	}
 
+	if do.ShowDeleted {
+		controls = append(controls, ldap.NewControlMicrosoftShowDeleted())
+	}
+
 	if do.ChunkSize > 0 {
 		paging := ldap.NewControlPaging(uint32(do.ChunkSize))
+		if len(do.Cookie) > 0 {
+			paging.SetCookie(do.Cookie)
+		}
 		controls = append(controls, paging)
 	}
 
@@ -262,6 +258,7 @@ func (ad *AD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 
 		// For a page of results, iterate through the reponse and pull the individual entries
 		for _, entry := range response.Entries {
+			ad.fetchRanges(entry, controls)
 			newObject := activedirectory.RawObject{}
 			err = newObject.IngestLDAP(entry)
 			if err == nil {
@@ -290,11 +287,26 @@ func (ad *AD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 			}
 		}
 
+		var cookie []byte
 		responseControl := ldap.FindControl(response.Controls, ldap.ControlTypePaging)
 		if rctrl, ok := responseControl.(*ldap.ControlPaging); rctrl != nil && ok && len(rctrl.Cookie) != 0 {
+			cookie = rctrl.Cookie
+		}
+
+		if do.OnPage != nil {
+			if e != nil {
+				e.Flush()
+			}
+			err = do.OnPage(cookie)
+			if err != nil {
+				return objects, err
+			}
+		}
+
+		if cookie != nil {
 			pagingControl := ldap.FindControl(controls, ldap.ControlTypePaging)
 			if sctrl, ok := pagingControl.(*ldap.ControlPaging); sctrl != nil && ok {
-				sctrl.SetCookie(rctrl.Cookie)
+				sctrl.SetCookie(cookie)
 				continue
 			}
 		}
@@ -310,6 +322,46 @@ func (ad *AD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 	return objects, nil
 }
 
+// Large multivalued attributes (member, msDS-ReplValueMetaData) are returned in ranges, so fetch the rest
+// of them from the object before it's ingested
+func (ad *AD) fetchRanges(entry *ldap.Entry, controls []ldap.Control) {
+	var basecontrols []ldap.Control
+	for _, control := range controls {
+		if control.GetControlType() != ldap.ControlTypePaging {
+			basecontrols = append(basecontrols, control)
+		}
+	}
+	for i := 0; i < len(entry.Attributes); i++ {
+		name, next, last := activedirectory.ParseRange(entry.Attributes[i].Name)
+		for !last {
+			response, err := ad.conn.Search(ldap.NewSearchRequest(
+				entry.DN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+				"(objectClass=*)",
+				[]string{fmt.Sprintf("%v;range=%v-*", name, next)},
+				basecontrols,
+			))
+			if err != nil || len(response.Entries) != 1 {
+				ui.Warn().Msgf("Could not fetch %v values from %v onwards for %v, they will be missing: %v", name, next, entry.DN, err)
+				break
+			}
+			var found bool
+			for _, attr := range response.Entries[0].Attributes {
+				attrname, attrnext, attrlast := activedirectory.ParseRange(attr.Name)
+				if !strings.EqualFold(attrname, name) || (!attrlast && attrnext <= next) {
+					continue
+				}
+				// Stored under the plain name, so the outer loop doesn't fetch it again
+				entry.Attributes = append(entry.Attributes, &ldap.EntryAttribute{Name: name, Values: attr.Values, ByteValues: attr.ByteValues})
+				next, last, found = attrnext, attrlast, true
+			}
+			if !found {
+				ui.Warn().Msgf("Server returned no %v values from %v onwards for %v, they will be missing", name, next, entry.DN)
+				break
+			}
+		}
+	}
+}
+
 type ControlInteger struct {
 	ControlType  string
 	Criticality  bool
@@ -6,7 +6,6 @@ package collect
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"runtime"
 	"syscall"
 	"time"
@@ -18,7 +17,6 @@ import (
 	"github.com/lkarlslund/adalanche/modules/ui"
 	ldap "github.com/lkarlslund/ldap/v3"
 	"github.com/lkarlslund/ldap/v3/gssapi"
-	"github.com/pierrec/lz4/v4"
 	"github.com/pkg/errors"
 	"github.com/schollz/progressbar/v3"
 	"github.com/tinylib/msgp/msgp"
@@ -244,23 +242,13 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 
 	var e *msgp.Writer
 	if do.WriteToFile != "" {
-		outfile, err := os.Create(do.WriteToFile)
+		outfile, boutfile, writer, err := createObjectFile(do.WriteToFile)
 		if err != nil {
-			return nil, fmt.Errorf("problem opening domain cache file: %v", err)
+			return nil, err
 		}
 		defer outfile.Close()
-
-		boutfile := lz4.NewWriter(outfile)
-		lz4options := []lz4.Option{
-			lz4.BlockChecksumOption(true),
-			// lz4.BlockSizeOption(lz4.BlockSize(51 * 1024)),
-			lz4.ChecksumOption(true),
-			lz4.CompressionLevelOption(lz4.Level9),
-			lz4.ConcurrencyOption(-1),
-		}
-		boutfile.Apply(lz4options...)
 		defer boutfile.Close()
-		e = msgp.NewWriter(boutfile)
+		e = writer
 	}
 
 	bar := progressbar.NewOptions(-1,
@@ -275,9 +263,16 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 	var objects []activedirectory.RawObject
 	var err error
 
-	var scarray []*LDAPControl // 0 = paging, 1 = NoSACL, 2 = nil
+	var scarray []*LDAPControl // 0 = paging, 1 = NoSACL, 2 = ShowDeleted, 3 = nil
 	if do.ChunkSize > 0 {
-		paging, err := a.conn.CreatePageControl(nil, uint32(do.ChunkSize))
+		var startcookie *LDAPBerval
+		if len(do.Cookie) > 0 {
+			startcookie = &LDAPBerval{
+				val: &do.Cookie[0],
+				len: uint64(len(do.Cookie)),
+			}
+		}
+		paging, err := a.conn.CreatePageControl(startcookie, uint32(do.ChunkSize))
 		if err != nil {
 			return nil, err
 		}
@@ -302,6 +297,14 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 		scarray = append(scarray, &nosaclcontrol)
 	}
 
+	if do.ShowDeleted {
+		showdeletedcontrol := LDAPControl{
+			oid:        MakeCString("1.2.840.113556.1.4.417"),
+			iscritical: true,
+		}
+		scarray = append(scarray, &showdeletedcontrol)
+	}
+
 	if do.Query == "" {
 		do.Query = "(objectClass=*)"
 	}
@@ -375,7 +378,15 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 
 				mem_free(uintptr(unsafe.Pointer(attr)))
 
-				item.Attributes[attrName] = values
+				// Ranged values are not fetched when using the native API
+				if base, _, last := activedirectory.ParseRange(attrName); base != attrName {
+					if !last {
+						ui.Warn().Msgf("Object %v attribute %v is returned in ranges, only the first %v values are collected", item.DistinguishedName, base, len(values))
+					}
+					attrName = base
+				}
+
+				item.Attributes[attrName] = append(item.Attributes[attrName], values...)
 
 				attr = entry.next_attribute(ber)
 			}
@@ -416,6 +427,20 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 				ui.Debug().Msgf("Error parsing page controls: %v", err)
 			}
 
+			if do.OnPage != nil {
+				if e != nil {
+					e.Flush()
+				}
+				var nextcookie []byte
+				if cookie != nil && cookie.len != 0 {
+					nextcookie = cookie.Data()
+				}
+				err = do.OnPage(nextcookie)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			if cookie == nil || cookie.len == 0 {
 				ui.Trace().Msgf("No more results")
 				break
@@ -434,11 +459,18 @@ func (a *WAD) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
 			oldcontrol.free()
 		} else {
 			// No paging requested
+			if do.OnPage != nil {
+				err = do.OnPage(nil)
+				if err != nil {
+					return nil, err
+				}
+			}
 			break
 		}
 	}
 
 	runtime.KeepAlive(scarray)
+	runtime.KeepAlive(do.Cookie)
 
 	bar.Finish()
 	if e != nil {
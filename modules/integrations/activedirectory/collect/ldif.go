@@ -0,0 +1,203 @@
+package collect
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Reads LDIF from ldapsearch, ldifde or similar tools (RFC 2849)
+type LDIFDumper struct {
+	path string
+
+	file *os.File
+}
+
+func (ld *LDIFDumper) Connect() error {
+	var err error
+	ld.file, err = os.Open(ld.path)
+	return err
+}
+
+func (ld *LDIFDumper) Disconnect() error {
+	return ld.file.Close()
+}
+
+func (ld *LDIFDumper) Dump(do DumpOptions) ([]activedirectory.RawObject, error) {
+	var e *msgp.Writer
+	if do.WriteToFile != "" {
+		outfile, boutfile, writer, err := createObjectFile(do.WriteToFile)
+		if err != nil {
+			return nil, err
+		}
+		defer outfile.Close()
+		defer boutfile.Close()
+		e = writer
+	}
+
+	stat, _ := ld.file.Stat()
+	bar := ui.ProgressBar("Converting objects from LDIF file", int(stat.Size()))
+
+	var objects []activedirectory.RawObject
+	err := parseLDIF(&progressReader{ld.file, bar.Add}, func(item *activedirectory.RawObject) error {
+		if e != nil {
+			if err := item.EncodeMsg(e); err != nil {
+				return fmt.Errorf("problem encoding LDAP object %v: %v", item.DistinguishedName, err)
+			}
+		}
+		if do.OnObject != nil {
+			do.OnObject(item)
+		}
+		if do.ReturnObjects {
+			objects = append(objects, *item)
+		}
+		return nil
+	})
+
+	bar.Finish()
+	if e != nil {
+		e.Flush()
+	}
+
+	return objects, err
+}
+
+type progressReader struct {
+	r   io.Reader
+	add func(int)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.add(n)
+	return n, err
+}
+
+// parseLDIF calls each for every entry with a DN, unfolding continued lines and decoding base64 (::) and file URL (:<) values
+func parseLDIF(r io.Reader, each func(item *activedirectory.RawObject) error) error {
+	br := bufio.NewReaderSize(r, 1024*1024)
+
+	// Callers can keep the pointers they get, so every entry gets its own object
+	newitem := func() *activedirectory.RawObject {
+		var item activedirectory.RawObject
+		item.Init()
+		return &item
+	}
+	item := newitem()
+	var lines []string
+	var linenumber int
+	var incomment bool
+
+	flushline := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+		line := strings.Join(lines, "")
+		lines = lines[:0]
+
+		name, value, err := parseLDIFLine(line)
+		if err != nil {
+			return fmt.Errorf("line %v: %w", linenumber, err)
+		}
+
+		// Attribute options (;binary, ;range=0-1499) are not part of the name
+		name, _, _ = strings.Cut(name, ";")
+
+		switch strings.ToLower(name) {
+		case "dn":
+			item.DistinguishedName = value
+		case "version", "changetype", "search", "result", "ref", "control":
+			// LDIF and ldapsearch bookkeeping, not attributes
+		default:
+			item.Attributes[name] = append(item.Attributes[name], value)
+		}
+		return nil
+	}
+
+	flushitem := func() error {
+		if err := flushline(); err != nil {
+			return err
+		}
+		if item.DistinguishedName != "" {
+			if err := each(item); err != nil {
+				return err
+			}
+		}
+		item = newitem()
+		return nil
+	}
+
+	for {
+		line, readerr := br.ReadString('\n')
+		if readerr != nil && readerr != io.EOF {
+			return readerr
+		}
+		line = strings.TrimRight(line, "\r\n")
+		linenumber++
+
+		switch {
+		case strings.HasPrefix(line, " "):
+			// Folded line continues the previous one
+			if !incomment && len(lines) > 0 {
+				lines = append(lines, line[1:])
+			}
+		case strings.HasPrefix(line, "#"):
+			// Comments can be folded too
+			if err := flushline(); err != nil {
+				return err
+			}
+			incomment = true
+		case line == "":
+			incomment = false
+			if err := flushitem(); err != nil {
+				return err
+			}
+		default:
+			incomment = false
+			if err := flushline(); err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+
+		if readerr == io.EOF {
+			return flushitem()
+		}
+	}
+}
+
+func parseLDIFLine(line string) (string, string, error) {
+	name, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", "", fmt.Errorf("missing separator in %q", line)
+	}
+	switch {
+	case strings.HasPrefix(value, ":"):
+		// Base64 encoded, this is how binary attributes like objectSid and nTSecurityDescriptor are represented
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+		if err != nil {
+			return "", "", fmt.Errorf("problem decoding base64 value for %v: %w", name, err)
+		}
+		return name, string(decoded), nil
+	case strings.HasPrefix(value, "<"):
+		// Value is in an external file (ldapsearch -t)
+		location, err := url.Parse(strings.TrimSpace(value[1:]))
+		if err != nil || location.Scheme != "file" {
+			return "", "", fmt.Errorf("unsupported URL value for %v: %v", name, value[1:])
+		}
+		data, err := os.ReadFile(location.Path)
+		if err != nil {
+			return "", "", fmt.Errorf("problem reading value for %v: %w", name, err)
+		}
+		return name, string(data), nil
+	}
+	return name, strings.TrimLeft(value, " "), nil
+}
@@ -0,0 +1,104 @@
+package collect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+)
+
+func TestParseLDIF(t *testing.T) {
+	tests := []struct {
+		name    string
+		ldif    string
+		want    []activedirectory.RawObject
+		wanterr bool
+	}{
+		{
+			name: "folded lines",
+			ldif: "version: 1\n" +
+				"# A comment that is\n" +
+				"  folded: too\n" +
+				"dn: CN=A very long name,OU=Some\n" +
+				" where,DC=contoso,DC=local\n" +
+				"description: Split in the mid\n" +
+				" dle of a word\n" +
+				"\n",
+			want: []activedirectory.RawObject{{
+				DistinguishedName: "CN=A very long name,OU=Somewhere,DC=contoso,DC=local",
+				Attributes:        map[string][]string{"description": {"Split in the middle of a word"}},
+			}},
+		},
+		{
+			name: "base64 values",
+			ldif: "dn:: Q049QmrDuHJuLERDPWNvbnRvc28sREM9bG9jYWw=\n" +
+				"displayName:: QmrDuHJu\n" +
+				"memberOf: CN=Group,DC=contoso,DC=local\n" +
+				"memberOf: CN=Other,DC=contoso,DC=local\n",
+			want: []activedirectory.RawObject{{
+				DistinguishedName: "CN=Bjørn,DC=contoso,DC=local",
+				Attributes: map[string][]string{
+					"displayName": {"Bjørn"},
+					"memberOf":    {"CN=Group,DC=contoso,DC=local", "CN=Other,DC=contoso,DC=local"},
+				},
+			}},
+		},
+		{
+			name: "binary attributes",
+			ldif: "dn: CN=Bin,DC=contoso,DC=local\n" +
+				"objectSid;binary:: AQEAAAAAAAUSAAAA\n" +
+				"objectGUID:: AAEC/w==\n" +
+				"\n" +
+				"dn: CN=Second,DC=contoso,DC=local\n" +
+				"name: Second\n",
+			want: []activedirectory.RawObject{
+				{
+					DistinguishedName: "CN=Bin,DC=contoso,DC=local",
+					Attributes: map[string][]string{
+						"objectSid":  {"\x01\x01\x00\x00\x00\x00\x00\x05\x12\x00\x00\x00"},
+						"objectGUID": {"\x00\x01\x02\xff"},
+					},
+				},
+				{
+					DistinguishedName: "CN=Second,DC=contoso,DC=local",
+					Attributes:        map[string][]string{"name": {"Second"}},
+				},
+			},
+		},
+		{
+			name:    "broken base64",
+			ldif:    "dn: CN=Broken,DC=contoso,DC=local\nobjectSid:: !!!\n",
+			wanterr: true,
+		},
+		{
+			name:    "missing separator",
+			ldif:    "dn: CN=Broken,DC=contoso,DC=local\nthis is not ldif\n",
+			wanterr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep the pointers like the GPO collection does, every entry must be its own object
+			var got []*activedirectory.RawObject
+			err := parseLDIF(strings.NewReader(tt.ldif), func(item *activedirectory.RawObject) error {
+				got = append(got, item)
+				return nil
+			})
+			if (err != nil) != tt.wanterr {
+				t.Fatalf("parseLDIF() error = %v, wanterr %v", err, tt.wanterr)
+			}
+			if tt.wanterr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseLDIF() gave %v objects, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !reflect.DeepEqual(*got[i], tt.want[i]) {
+					t.Errorf("object %v = %+v, want %+v", i, *got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
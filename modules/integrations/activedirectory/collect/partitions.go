@@ -0,0 +1,339 @@
+package collect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	ldap "github.com/lkarlslund/ldap/v3"
+	"github.com/pierrec/lz4/v4"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// A naming context is collected as the context object itself plus one subtree per top level container,
+// and each of these partitions checkpoints the paging cookie after every page, so an interrupted
+// collection can be resumed
+type partition struct {
+	Base    string `json:"base"`
+	Scope   int    `json:"scope"`
+	Objects int    `json:"objects"`          // Objects flushed to the partial file
+	Cookie  []byte `json:"cookie,omitempty"` // Paging cookie to continue from
+	Done    bool   `json:"done"`
+}
+
+type partitionedCollection struct {
+	Context    string       `json:"context"`
+	Query      string       `json:"query"`
+	Attributes []string     `json:"attributes"`
+	Partitions []*partition `json:"partitions"`
+
+	datapath string
+	lock     sync.Mutex
+}
+
+func checkpointFilename(datapath, context string) string {
+	return filepath.Join(datapath, context+".checkpoint.json")
+}
+
+func (pc *partitionedCollection) filename(n int) string {
+	return filepath.Join(pc.datapath, fmt.Sprintf("%v.part%04d.objects.msgp.lz4", pc.Context, n))
+}
+
+func (pc *partitionedCollection) save() error {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	data, err := json.MarshalIndent(pc, "", "  ")
+	if err != nil {
+		return err
+	}
+	checkpointfile := checkpointFilename(pc.datapath, pc.Context)
+	err = os.WriteFile(checkpointfile+".tmp", data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(checkpointfile+".tmp", checkpointfile)
+}
+
+// Loads the checkpoint from an interrupted run, or splits the naming context into new partitions
+func planPartitions(ad LDAPDumper, datapath, context string, do DumpOptions) (*partitionedCollection, error) {
+	if *resume {
+		if data, err := os.ReadFile(checkpointFilename(datapath, context)); err == nil {
+			var pc partitionedCollection
+			if err = json.Unmarshal(data, &pc); err != nil {
+				ui.Warn().Msgf("Problem reading checkpoint for %v, starting over: %v", context, err)
+			} else if pc.Query != do.Query || !slices.Equal(pc.Attributes, do.Attributes) {
+				ui.Warn().Msgf("Checkpoint for %v was made with different query or attributes, starting over", context)
+			} else {
+				pc.datapath = datapath
+				var done int
+				for _, p := range pc.Partitions {
+					if p.Done {
+						done++
+					}
+				}
+				ui.Info().Msgf("Resuming collection of %v, %v of %v partitions already done", context, done, len(pc.Partitions))
+				return &pc, nil
+			}
+		}
+	}
+
+	children, err := ad.Dump(DumpOptions{
+		SearchBase:    context,
+		Scope:         ldap.ScopeSingleLevel,
+		Query:         "(objectClass=*)",
+		Attributes:    []string{"1.1"}, // No attributes, we only need the DN
+		ChunkSize:     do.ChunkSize,
+		ShowDeleted:   do.ShowDeleted,
+		ReturnObjects: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("problem listing top level containers in %v: %w", context, err)
+	}
+
+	pc := partitionedCollection{
+		Context:    context,
+		Query:      do.Query,
+		Attributes: do.Attributes,
+		datapath:   datapath,
+	}
+	pc.Partitions = append(pc.Partitions, &partition{
+		Base:  context,
+		Scope: ldap.ScopeBaseObject,
+	})
+	for _, child := range children {
+		pc.Partitions = append(pc.Partitions, &partition{
+			Base:  child.DistinguishedName,
+			Scope: ldap.ScopeWholeSubtree,
+		})
+	}
+
+	// Results from earlier runs would duplicate what we're collecting now
+	oldfiles, _ := filepath.Glob(filepath.Join(datapath, context+".part*.objects.msgp.lz4*"))
+	oldfiles = append(oldfiles, filepath.Join(datapath, context+".objects.msgp.lz4"))
+	for _, oldfile := range oldfiles {
+		os.Remove(oldfile)
+	}
+
+	ui.Debug().Msgf("Split %v into %v partitions", context, len(pc.Partitions))
+	return &pc, pc.save()
+}
+
+// Collects all unfinished partitions of a naming context, running one worker per connection
+func collectPartitioned(dumpers []LDAPDumper, datapath, context string, do DumpOptions) error {
+	pc, err := planPartitions(dumpers[0], datapath, context, do)
+	if err != nil {
+		return err
+	}
+
+	// Callbacks are not expecting to be called concurrently
+	if do.OnObject != nil {
+		var onobjectlock sync.Mutex
+		onobject := do.OnObject
+		do.OnObject = func(ro *activedirectory.RawObject) error {
+			onobjectlock.Lock()
+			defer onobjectlock.Unlock()
+			return onobject(ro)
+		}
+	}
+
+	queue := make(chan int, len(pc.Partitions))
+	for n, p := range pc.Partitions {
+		if !p.Done {
+			queue <- n
+		}
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	var errlock sync.Mutex
+	var errs []error
+	for _, ad := range dumpers {
+		wg.Add(1)
+		go func(ad LDAPDumper) {
+			for n := range queue {
+				if err := pc.collect(ad, n, do, true); err != nil {
+					errlock.Lock()
+					errs = append(errs, fmt.Errorf("partition %v: %w", pc.Partitions[n].Base, err))
+					errlock.Unlock()
+				}
+			}
+			wg.Done()
+		}(ad)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (rerun to resume from checkpoint)", errors.Join(errs...))
+	}
+	return os.Remove(checkpointFilename(datapath, context))
+}
+
+func (pc *partitionedCollection) collect(ad LDAPDumper, n int, do DumpOptions, retry bool) error {
+	p := pc.Partitions[n]
+	finalfile := pc.filename(n)
+	partialfile := finalfile + ".partial"
+
+	if p.Cookie == nil {
+		// Nothing to continue from
+		p.Objects = 0
+	}
+
+	// Keep the objects we already have up to the checkpoint, anything after that will be collected again
+	var oldfile string
+	if p.Objects > 0 {
+		oldfile = partialfile + ".old"
+		if _, err := os.Stat(oldfile); err != nil {
+			if err = os.Rename(partialfile, oldfile); err != nil {
+				return err
+			}
+		}
+	}
+
+	outfile, boutfile, e, err := createObjectFile(partialfile)
+	if err != nil {
+		return err
+	}
+	defer outfile.Close()
+
+	if oldfile != "" {
+		err = copyObjects(oldfile, e, p.Objects, do.OnObject)
+		os.Remove(oldfile)
+		if err != nil {
+			ui.Warn().Msgf("Problem resuming %v from %v, collecting it again: %v", p.Base, oldfile, err)
+			outfile.Close()
+			os.Remove(partialfile)
+			pc.lock.Lock()
+			p.Cookie = nil
+			p.Objects = 0
+			pc.lock.Unlock()
+			return pc.collect(ad, n, do, false)
+		}
+	}
+
+	var pending, pages int
+	pdo := do
+	pdo.SearchBase = p.Base
+	pdo.Scope = p.Scope
+	pdo.WriteToFile = ""
+	pdo.ReturnObjects = false
+	pdo.Cookie = p.Cookie
+	pdo.OnObject = func(ro *activedirectory.RawObject) error {
+		if err := ro.EncodeMsg(e); err != nil {
+			return fmt.Errorf("problem encoding LDAP object %v: %v", ro.DistinguishedName, err)
+		}
+		pending++
+		if do.OnObject != nil {
+			return do.OnObject(ro)
+		}
+		return nil
+	}
+	pdo.OnPage = func(cookie []byte) error {
+		if err := e.Flush(); err != nil {
+			return err
+		}
+		if err := boutfile.Flush(); err != nil {
+			return err
+		}
+		if err := outfile.Flush(); err != nil {
+			return err
+		}
+		pages++
+		pc.lock.Lock()
+		p.Objects += pending
+		p.Cookie = cookie
+		pc.lock.Unlock()
+		pending = 0
+		return pc.save()
+	}
+
+	resumed := p.Cookie != nil
+	_, err = ad.Dump(pdo)
+	if err != nil {
+		if resumed && pages == 0 && retry {
+			// The DC does not know the cookie anymore (restarted, or it expired), so do this one again from scratch
+			ui.Warn().Msgf("Could not resume %v from checkpoint, collecting it again: %v", p.Base, err)
+			outfile.Close()
+			os.Remove(partialfile)
+			pc.lock.Lock()
+			p.Cookie = nil
+			p.Objects = 0
+			pc.lock.Unlock()
+			return pc.collect(ad, n, do, false)
+		}
+		return err
+	}
+
+	if err = boutfile.Close(); err != nil {
+		return err
+	}
+	if err = outfile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(partialfile, finalfile); err != nil {
+		return err
+	}
+
+	pc.lock.Lock()
+	p.Done = true
+	p.Cookie = nil
+	pc.lock.Unlock()
+	return pc.save()
+}
+
+func createObjectFile(path string) (*encryption.File, *lz4.Writer, *msgp.Writer, error) {
+	outfile, err := encryption.Create(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("problem opening domain cache file: %v", err)
+	}
+
+	boutfile := lz4.NewWriter(outfile)
+	lz4options := []lz4.Option{
+		lz4.BlockChecksumOption(true),
+		lz4.ChecksumOption(true),
+		lz4.CompressionLevelOption(lz4.Level9),
+		lz4.ConcurrencyOption(-1),
+	}
+	boutfile.Apply(lz4options...)
+	return outfile, boutfile, msgp.NewWriter(boutfile), nil
+}
+
+// Copies the first count objects from a (possibly truncated) object file
+func copyObjects(path string, e *msgp.Writer, count int, onobject objectCallbackFunc) error {
+	infile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer infile.Close()
+
+	decrypted, err := encryption.NewReader(infile)
+	if err != nil {
+		return err
+	}
+	d := msgp.NewReader(lz4.NewReader(decrypted))
+	for i := 0; i < count; i++ {
+		var ro activedirectory.RawObject
+		if err = ro.DecodeMsg(d); err != nil {
+			if msgp.Cause(err) == io.EOF || msgp.Cause(err) == io.ErrUnexpectedEOF {
+				return fmt.Errorf("only %v of %v checkpointed objects found", i, count)
+			}
+			return err
+		}
+		if err = ro.EncodeMsg(e); err != nil {
+			return err
+		}
+		if onobject != nil {
+			if err = onobject(&ro); err != nil {
+				return err
+			}
+		}
+	}
+	return e.Flush()
+}
@@ -0,0 +1,49 @@
+package activedirectory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+var (
+	collectionTimesLock sync.RWMutex
+	collectionTimes     = make(map[string]time.Time) // Lowercase domain context -> currentTime from the RootDSE
+)
+
+// SetCollectionTime records when the domain was collected
+func SetCollectionTime(domaincontext string, collected time.Time) {
+	collectionTimesLock.Lock()
+	collectionTimes[strings.ToLower(domaincontext)] = collected
+	collectionTimesLock.Unlock()
+}
+
+// ResetCollectionTimes forgets the domains from an earlier analysis
+func ResetCollectionTimes() {
+	collectionTimesLock.Lock()
+	clear(collectionTimes)
+	collectionTimesLock.Unlock()
+}
+
+// CollectionTime returns when the domain of the object was collected, so ages are measured from the time of the
+// snapshot and not from when it is analyzed. Objects from unknown domains use the latest collection, and if no
+// collection times are known it's now
+func CollectionTime(o *engine.Object) time.Time {
+	collectionTimesLock.RLock()
+	defer collectionTimesLock.RUnlock()
+	if collected, found := collectionTimes[strings.ToLower(o.OneAttrString(engine.DomainContext))]; found {
+		return collected
+	}
+	var latest time.Time
+	for _, collected := range collectionTimes {
+		if collected.After(latest) {
+			latest = collected
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
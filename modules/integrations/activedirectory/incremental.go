@@ -0,0 +1,47 @@
+package activedirectory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Incremental collections are saved with this suffix, and the filenames sort in collection order
+const DeltaSuffix = ".delta.msgp.lz4"
+
+// IncrementalState is saved after each LDAP collection, so the next run can ask for changes only
+type IncrementalState struct {
+	Server      string    `json:"server"`      // DC that was collected from
+	DSAName     string    `json:"dsaname"`     // dsServiceName from RootDSE, USNs are only valid for this DC
+	HighestUSN  int64     `json:"highestusn"`  // highestCommittedUSN before collection started
+	Collected   time.Time `json:"collected"`   // When collection started
+	Incremental bool      `json:"incremental"` // Last run was incremental
+}
+
+func IncrementalStateFile(datapath, domainContext string) string {
+	return filepath.Join(datapath, domainContext+".incremental.json")
+}
+
+func LoadIncrementalState(datapath, domainContext string) (IncrementalState, error) {
+	var state IncrementalState
+	data, err := os.ReadFile(IncrementalStateFile(datapath, domainContext))
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+func (state IncrementalState) Save(datapath, domainContext string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(IncrementalStateFile(datapath, domainContext), data, 0644)
+}
+
+// DeltaFilename returns the filename for changes in a naming context collected at a given time
+func DeltaFilename(datapath, context string, collected time.Time) string {
+	return filepath.Join(datapath, context+"."+collected.UTC().Format("20060102T150405Z")+DeltaSuffix)
+}
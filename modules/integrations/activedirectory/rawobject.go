@@ -69,11 +69,32 @@ func (item *RawObject) IngestLDAP(source *ldap.Entry) error {
 		if len(attr.Values) == 0 && attr.Name != "member" {
 			ui.Warn().Msgf("Object %v attribute %v has no values", item.DistinguishedName, attr.Name)
 		}
-		item.Attributes[attr.Name] = attr.Values
+		// Ranged retrieval (large groups, replication metadata), the collector fetches the remaining ranges and
+		// they're all kept under the real name
+		name, _, _ := ParseRange(attr.Name)
+		item.Attributes[name] = append(item.Attributes[name], attr.Values...)
 	}
 	return nil
 }
 
+// ParseRange splits a ranged attribute like member;range=0-1499 into the attribute name and where the next range
+// starts. Last is true for the final range (member;range=1500-*) and for attributes without a range
+func ParseRange(attribute string) (name string, next int, last bool) {
+	name, option, found := strings.Cut(attribute, ";range=")
+	if !found {
+		return attribute, 0, true
+	}
+	_, end, _ := strings.Cut(option, "-")
+	if end == "*" {
+		return name, 0, true
+	}
+	n, err := strconv.Atoi(end)
+	if err != nil {
+		return name, 0, true
+	}
+	return name, n + 1, false
+}
+
 // Performance hack
 var avsPool = sync.Pool{
 	New: func() any {
@@ -109,7 +130,7 @@ func EncodeAttributeData(attribute engine.Attribute, values []string) engine.Att
 			} else {
 				ui.Warn().Msgf("Failed to convert attribute %v value %2x to timestamp: %v", attribute.String(), value, err)
 			}
-		case WhenChanged, WhenCreated, DsCorePropagationData,
+		case WhenChanged, WhenCreated, DsCorePropagationData, CurrentTime,
 			MsExchLastUpdateTime, MsExchPolicyLastAppliedTime, MsExchWhenMailboxCreated,
 			GWARTLastModified, SpaceLastComputed:
 
@@ -194,7 +215,7 @@ func EncodeAttributeData(attribute engine.Attribute, values []string) engine.Att
 				attributevalue = engine.AttributeValueBool(true)
 				break
 			} else if value == "false" || value == "FALSE" {
-				attributevalue = engine.AttributeValueBool(true)
+				attributevalue = engine.AttributeValueBool(false)
 				break
 			}
 
@@ -0,0 +1,57 @@
+package activedirectory
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// Parsed msDS-ReplAttributeMetaData entry
+// https://learn.microsoft.com/en-us/windows/win32/api/ntdsapi/ns-ntdsapi-ds_repl_attr_meta_data_2
+type ReplAttributeMetaData struct {
+	AttributeName         string    `xml:"pszAttributeName" json:"attribute"`
+	Version               int       `xml:"dwVersion" json:"version"`
+	LastOriginatingTime   time.Time `xml:"ftimeLastOriginatingChange" json:"changed"`
+	OriginatingDSA        string    `xml:"pszLastOriginatingDsaDN" json:"originatingdsa"`
+	OriginatingInvocation string    `xml:"uuidLastOriginatingDsaInvocationID" json:"-"`
+	OriginatingUSN        int64     `xml:"usnOriginatingChange" json:"-"`
+	LocalUSN              int64     `xml:"usnLocalChange" json:"-"`
+}
+
+// Parsed msDS-ReplValueMetaData entry, only linked attributes (member etc) have these
+// https://learn.microsoft.com/en-us/windows/win32/api/ntdsapi/ns-ntdsapi-ds_repl_value_meta_data_2
+type ReplValueMetaData struct {
+	AttributeName       string    `xml:"pszAttributeName" json:"attribute"`
+	ObjectDN            string    `xml:"pszObjectDn" json:"value"`
+	Created             time.Time `xml:"ftimeCreated" json:"created"`
+	Deleted             time.Time `xml:"ftimeDeleted" json:"deleted,omitempty"`
+	Version             int       `xml:"dwVersion" json:"version"`
+	LastOriginatingTime time.Time `xml:"ftimeLastOriginatingChange" json:"changed"`
+	OriginatingDSA      string    `xml:"pszLastOriginatingDsaDN" json:"originatingdsa"`
+}
+
+// IsDeleted returns true if the linked value is absent (removed), AD uses 1601-01-01 for "not deleted"
+func (rvmd ReplValueMetaData) IsDeleted() bool {
+	return rvmd.Deleted.Year() > 1601
+}
+
+func ParseReplAttributeMetaData(value string) (ReplAttributeMetaData, error) {
+	var result ReplAttributeMetaData
+	err := xml.Unmarshal([]byte(strings.TrimRight(value, "\x00")), &result)
+	return result, err
+}
+
+func ParseReplValueMetaData(value string) (ReplValueMetaData, error) {
+	var result ReplValueMetaData
+	err := xml.Unmarshal([]byte(strings.TrimRight(value, "\x00")), &result)
+	return result, err
+}
+
+// OriginatingDC extracts the DC name from the NTDS Settings DN of the originating DSA
+func OriginatingDC(dsadn string) string {
+	parts := strings.Split(dsadn, ",")
+	if len(parts) > 1 && strings.EqualFold(parts[0], "CN=NTDS Settings") {
+		return strings.TrimPrefix(strings.TrimPrefix(parts[1], "CN="), "cn=")
+	}
+	return dsadn
+}
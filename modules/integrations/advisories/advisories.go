@@ -0,0 +1,166 @@
+package advisories
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownFeed = errors.New("not a known advisory feed format")
+
+	localPrivEscDescription = regexp.MustCompile(`(?i)(elevation of privilege|privilege escalation|escalate privileges|elevate privileges|gain (elevated|higher|root|system|administrator|administrative) privileges)`)
+)
+
+// Advisory is one CVE with the software versions it affects
+type Advisory struct {
+	ID             string
+	Severity       string // CRITICAL, HIGH, MEDIUM, LOW
+	LocalPrivEsc   bool
+	KnownExploited bool
+	Affects        []Affected
+}
+
+// Affected is a vulnerable application from a CPE match, versions are inclusive or exclusive bounds
+type Affected struct {
+	Vendor, Product              string
+	Version                      string // Exact version, blank if ranges are used
+	StartIncluding, StartExclude string
+	EndIncluding, EndExclude     string
+}
+
+type nvdFeed struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string `json:"id"`
+			Descriptions []struct {
+				Lang  string `json:"lang"`
+				Value string `json:"value"`
+			} `json:"descriptions"`
+			Metrics map[string][]struct {
+				CVSSData struct {
+					BaseSeverity string `json:"baseSeverity"`
+					AttackVector string `json:"attackVector"`
+					AccessVector string `json:"accessVector"`
+				} `json:"cvssData"`
+				BaseSeverity string `json:"baseSeverity"` // CVSS v2 has it here
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					Negate   bool `json:"negate"`
+					CPEMatch []struct {
+						Vulnerable            bool   `json:"vulnerable"`
+						Criteria              string `json:"criteria"`
+						VersionStartIncluding string `json:"versionStartIncluding"`
+						VersionStartExcluding string `json:"versionStartExcluding"`
+						VersionEndIncluding   string `json:"versionEndIncluding"`
+						VersionEndExcluding   string `json:"versionEndExcluding"`
+					} `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type kevFeed struct {
+	CatalogVersion  string `json:"catalogVersion"`
+	Vulnerabilities []struct {
+		CVEID string `json:"cveID"`
+	} `json:"vulnerabilities"`
+}
+
+// ParseNVD reads an NVD CVE API 2.0 JSON dump or feed file. Only applications (cpe:2.3:a:...) are kept
+func ParseNVD(data []byte) ([]Advisory, error) {
+	var feed nvdFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+
+	var result []Advisory
+	for _, vulnerability := range feed.Vulnerabilities {
+		cve := vulnerability.CVE
+		if cve.ID == "" {
+			return nil, ErrUnknownFeed
+		}
+		advisory := Advisory{
+			ID: cve.ID,
+		}
+
+		// Newest CVSS version available wins
+		var attackvector string
+		for _, version := range []string{"cvssMetricV31", "cvssMetricV30", "cvssMetricV2"} {
+			if metrics := cve.Metrics[version]; len(metrics) > 0 {
+				advisory.Severity = metrics[0].CVSSData.BaseSeverity
+				if advisory.Severity == "" {
+					advisory.Severity = metrics[0].BaseSeverity
+				}
+				attackvector = metrics[0].CVSSData.AttackVector + metrics[0].CVSSData.AccessVector
+				break
+			}
+		}
+		if attackvector == "LOCAL" {
+			for _, description := range cve.Descriptions {
+				if description.Lang == "en" && localPrivEscDescription.MatchString(description.Value) {
+					advisory.LocalPrivEsc = true
+				}
+			}
+		}
+
+		for _, configuration := range cve.Configurations {
+			for _, node := range configuration.Nodes {
+				if node.Negate {
+					continue
+				}
+				for _, match := range node.CPEMatch {
+					// cpe:2.3:part:vendor:product:version:...
+					fields := strings.Split(match.Criteria, ":")
+					if !match.Vulnerable || len(fields) < 6 || fields[2] != "a" {
+						continue
+					}
+					affected := Affected{
+						Vendor:         fields[3],
+						Product:        fields[4],
+						StartIncluding: match.VersionStartIncluding,
+						StartExclude:   match.VersionStartExcluding,
+						EndIncluding:   match.VersionEndIncluding,
+						EndExclude:     match.VersionEndExcluding,
+					}
+					if fields[5] != "*" && fields[5] != "-" {
+						affected.Version = fields[5]
+					} else if affected.StartIncluding+affected.StartExclude+affected.EndIncluding+affected.EndExclude == "" {
+						// Every version ever, too broad to be useful with fuzzy name matching
+						continue
+					}
+					advisory.Affects = append(advisory.Affects, affected)
+				}
+			}
+		}
+		if len(advisory.Affects) > 0 {
+			result = append(result, advisory)
+		}
+	}
+	return result, nil
+}
+
+// ParseKEV reads the CISA Known Exploited Vulnerabilities catalog and returns the CVE IDs in it
+func ParseKEV(data []byte) ([]string, error) {
+	var feed kevFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+	if feed.CatalogVersion == "" {
+		return nil, ErrUnknownFeed
+	}
+	result := make([]string, len(feed.Vulnerabilities))
+	for i, vulnerability := range feed.Vulnerabilities {
+		result[i] = vulnerability.CVEID
+	}
+	return result, nil
+}
+
+// Feed file names as downloaded from CISA and NVD
+const (
+	KEVPrefix = "known_exploited_vulnerabilities"
+	NVDPrefix = "nvdcve-"
+)
@@ -0,0 +1,137 @@
+package advisories
+
+import (
+	"reflect"
+	"testing"
+)
+
+const nvdSample = `{
+  "resultsPerPage": 3,
+  "format": "NVD_CVE",
+  "version": "2.0",
+  "vulnerabilities": [
+    {
+      "cve": {
+        "id": "CVE-2023-0001",
+        "descriptions": [
+          {"lang": "en", "value": "A flaw in the updater service allows a local user to gain elevated privileges."},
+          {"lang": "es", "value": "Una falla en el servicio."}
+        ],
+        "metrics": {
+          "cvssMetricV31": [{"cvssData": {"version": "3.1", "attackVector": "LOCAL", "baseSeverity": "HIGH"}}],
+          "cvssMetricV2": [{"cvssData": {"version": "2.0", "accessVector": "LOCAL"}, "baseSeverity": "MEDIUM"}]
+        },
+        "configurations": [
+          {
+            "nodes": [
+              {
+                "operator": "OR",
+                "negate": false,
+                "cpeMatch": [
+                  {"vulnerable": true, "criteria": "cpe:2.3:a:7-zip:7-zip:*:*:*:*:*:*:*:*", "versionEndExcluding": "23.01"},
+                  {"vulnerable": true, "criteria": "cpe:2.3:a:7-zip:7-zip:9.20:*:*:*:*:*:*:*"},
+                  {"vulnerable": true, "criteria": "cpe:2.3:a:7-zip:7-zip_plugin:*:*:*:*:*:*:*:*"},
+                  {"vulnerable": true, "criteria": "cpe:2.3:o:microsoft:windows_10:-:*:*:*:*:*:*:*"},
+                  {"vulnerable": false, "criteria": "cpe:2.3:a:microsoft:.net_framework:4.8:*:*:*:*:*:*:*"}
+                ]
+              },
+              {
+                "operator": "OR",
+                "negate": true,
+                "cpeMatch": [
+                  {"vulnerable": true, "criteria": "cpe:2.3:a:7-zip:7-zip:22.00:*:*:*:*:*:*:*"}
+                ]
+              }
+            ]
+          }
+        ]
+      }
+    },
+    {
+      "cve": {
+        "id": "CVE-2023-0002",
+        "descriptions": [{"lang": "en", "value": "Remote code execution in the web interface."}],
+        "metrics": {
+          "cvssMetricV2": [{"cvssData": {"version": "2.0", "accessVector": "NETWORK"}, "baseSeverity": "MEDIUM"}]
+        },
+        "configurations": [
+          {
+            "nodes": [
+              {
+                "cpeMatch": [
+                  {"vulnerable": true, "criteria": "cpe:2.3:a:videolan:vlc_media_player:*:*:*:*:*:*:*:*", "versionStartIncluding": "3.0.0", "versionEndIncluding": "3.0.18"}
+                ]
+              }
+            ]
+          }
+        ]
+      }
+    },
+    {
+      "cve": {
+        "id": "CVE-2023-0003",
+        "descriptions": [{"lang": "en", "value": "Only affects the operating system and hardware."}],
+        "configurations": [
+          {
+            "nodes": [
+              {
+                "cpeMatch": [
+                  {"vulnerable": true, "criteria": "cpe:2.3:o:cisco:ios:15.0:*:*:*:*:*:*:*"},
+                  {"vulnerable": false, "criteria": "cpe:2.3:a:cisco:webex:*:*:*:*:*:*:*:*", "versionEndExcluding": "43.1"}
+                ]
+              }
+            ]
+          }
+        ]
+      }
+    }
+  ]
+}`
+
+func TestParseNVD(t *testing.T) {
+	advisories, err := ParseNVD([]byte(nvdSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Operating systems, non vulnerable CPEs, negated nodes and products without any version bounds are skipped,
+	// and so are CVEs with nothing left
+	expected := []Advisory{
+		{
+			ID:           "CVE-2023-0001",
+			Severity:     "HIGH",
+			LocalPrivEsc: true,
+			Affects: []Affected{
+				{Vendor: "7-zip", Product: "7-zip", EndExclude: "23.01"},
+				{Vendor: "7-zip", Product: "7-zip", Version: "9.20"},
+			},
+		},
+		{
+			ID:       "CVE-2023-0002",
+			Severity: "MEDIUM",
+			Affects: []Affected{
+				{Vendor: "videolan", Product: "vlc_media_player", StartIncluding: "3.0.0", EndIncluding: "3.0.18"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(advisories, expected) {
+		t.Errorf("parsed %+v\nexpected %+v", advisories, expected)
+	}
+}
+
+func TestParseNVDUnknownFeed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"KEV catalog", `{"catalogVersion": "2024.01.01", "vulnerabilities": [{"cveID": "CVE-2023-0001"}]}`},
+		{"not JSON", `<nvd/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseNVD([]byte(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package analyze
+
+import (
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+var (
+	Vulnerabilities       = engine.NewAttribute("vulnerabilities")
+	VulnerabilitySeverity = engine.NewAttribute("vulnerabilitySeverity")
+	KnownExploitedVulns   = engine.NewAttribute("knownExploitedVulnerabilities")
+	LocalPrivEscVulns     = engine.NewAttribute("localPrivEscVulnerabilities")
+	EdgeLocalPrivEsc      = engine.NewEdge("LocalPrivEsc").Describe("Machine has software with a local privilege escalation vulnerability, so users logging on to it can become SYSTEM").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+		if target.HasAttr(KnownExploitedVulns) {
+			var exploited bool
+			target.Attr(LocalPrivEscVulns).Iterate(func(value engine.AttributeValue) bool {
+				exploited = target.HasAttrValue(KnownExploitedVulns, value)
+				return !exploited
+			})
+			if exploited {
+				return 70
+			}
+		}
+		return 40
+	}).Tag("Pivot")
+)
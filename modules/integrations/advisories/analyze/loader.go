@@ -0,0 +1,105 @@
+package analyze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/advisories"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+const loadername = "Vulnerability advisory feeds"
+
+var (
+	loader = engine.AddLoader(func() engine.Loader { return &AdvisoryLoader{} })
+
+	// Feeds are matched against installed software after merge, when all machines are known
+	pendingLock           sync.Mutex
+	pendingAdvisories     []advisories.Advisory
+	pendingKnownExploited []string
+)
+
+// AdvisoryLoader reads offline CISA KEV and NVD CVE feeds, it doesn't create any objects
+type AdvisoryLoader struct {
+	ao             *engine.Objects
+	lock           sync.Mutex
+	advisories     []advisories.Advisory
+	knownexploited []string
+}
+
+func (ld *AdvisoryLoader) Name() string {
+	return loadername
+}
+
+func (ld *AdvisoryLoader) Init() error {
+	ld.ao = engine.NewLoaderObjects(ld)
+	return nil
+}
+
+func (ld *AdvisoryLoader) Close() ([]*engine.Objects, error) {
+	pendingLock.Lock()
+	pendingAdvisories = append(pendingAdvisories, ld.advisories...)
+	pendingKnownExploited = append(pendingKnownExploited, ld.knownexploited...)
+	pendingLock.Unlock()
+
+	result := []*engine.Objects{ld.ao}
+	ld.ao = nil
+	ld.advisories = nil
+	ld.knownexploited = nil
+	return result, nil
+}
+
+func (ld *AdvisoryLoader) Load(path string, cb engine.ProgressCallbackFunc) error {
+	name := strings.ToLower(filepath.Base(path))
+	compressed := strings.HasSuffix(name, ".json.gz")
+	if !compressed && !strings.HasSuffix(name, ".json") {
+		return engine.ErrUninterested
+	}
+	iskev := strings.HasPrefix(name, advisories.KEVPrefix)
+	if !iskev && !strings.HasPrefix(name, advisories.NVDPrefix) {
+		return engine.ErrUninterested
+	}
+
+	raw, err := encryption.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if compressed {
+		gz, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		raw, err = io.ReadAll(gz)
+		if err != nil {
+			return err
+		}
+	}
+
+	if iskev {
+		ids, err := advisories.ParseKEV(raw)
+		if err != nil {
+			return err
+		}
+		ui.Debug().Msgf("Loaded %v known exploited vulnerabilities from %v", len(ids), path)
+		ld.lock.Lock()
+		ld.knownexploited = append(ld.knownexploited, ids...)
+		ld.lock.Unlock()
+		return nil
+	}
+
+	feed, err := advisories.ParseNVD(raw)
+	if err != nil {
+		return err
+	}
+	ui.Debug().Msgf("Loaded %v advisories affecting applications from %v", len(feed), path)
+	ld.lock.Lock()
+	ld.advisories = append(ld.advisories, feed...)
+	ld.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,103 @@
+package analyze
+
+import (
+	"sort"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/advisories"
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+func init() {
+	loader.AddProcessor(func(ao *engine.Objects) {
+		pendingLock.Lock()
+		feed := pendingAdvisories
+		knownexploited := pendingKnownExploited
+		pendingAdvisories = nil
+		pendingKnownExploited = nil
+		pendingLock.Unlock()
+
+		if len(feed) == 0 {
+			if len(knownexploited) > 0 {
+				ui.Warn().Msgf("Known exploited vulnerabilities catalog loaded without any NVD feed, can't match it against installed software")
+			}
+			return
+		}
+
+		matcher := advisories.NewMatcher(feed, knownexploited)
+
+		var vulnerablemachines, privescmachines int
+		ao.Iterate(func(machine *engine.Object) bool {
+			if machine.Type() != engine.ObjectTypeMachine || !machine.HasAttr(localmachine.InstalledSoftware) {
+				return true
+			}
+
+			found := make(map[string]*advisories.Advisory)
+			machine.Attr(localmachine.InstalledSoftware).Iterate(func(software engine.AttributeValue) bool {
+				for _, advisory := range matcher.Match(software.String()) {
+					found[advisory.ID] = advisory
+				}
+				return true
+			})
+			if len(found) == 0 {
+				return true
+			}
+			vulnerablemachines++
+
+			var ids, exploited, privesc []string
+			var severity string
+			for id, advisory := range found {
+				ids = append(ids, id)
+				severity = advisories.HigherSeverity(severity, advisory.Severity)
+				if advisory.KnownExploited {
+					exploited = append(exploited, id)
+				}
+				if advisory.LocalPrivEsc {
+					privesc = append(privesc, id)
+				}
+			}
+			sort.Strings(ids)
+			sort.Strings(exploited)
+			sort.Strings(privesc)
+
+			machine.SetFlex(
+				engine.IgnoreBlanks,
+				Vulnerabilities, ids,
+				VulnerabilitySeverity, severity,
+				KnownExploitedVulns, exploited,
+				LocalPrivEscVulns, privesc,
+			)
+			machine.Tag("vulnerable")
+			if len(exploited) > 0 {
+				machine.Tag("known_exploited")
+			}
+
+			if len(privesc) == 0 {
+				return true
+			}
+			privescmachines++
+
+			// Anyone who has or can get an interactive session on the machine
+			machine.Edges(engine.Out).Range(func(user *engine.Object, edges engine.EdgeBitmap) bool {
+				if edges.IsSet(lmanalyze.EdgeLocalSessionLastDay) || edges.IsSet(lmanalyze.EdgeLocalSessionLastWeek) || edges.IsSet(lmanalyze.EdgeLocalSessionLastMonth) {
+					user.EdgeTo(machine, EdgeLocalPrivEsc)
+				}
+				return true
+			})
+			machine.Edges(engine.In).Range(func(principal *engine.Object, edges engine.EdgeBitmap) bool {
+				if edges.IsSet(lmanalyze.EdgeLocalRDPRights) {
+					principal.EdgeTo(machine, EdgeLocalPrivEsc)
+				}
+				return true
+			})
+			return true
+		})
+
+		ui.Info().Msgf("Found vulnerable software on %v machines, %v of them with local privilege escalation vulnerabilities", vulnerablemachines, privescmachines)
+	},
+		"Vulnerable software from advisory feeds",
+		engine.AfterMerge,
+	)
+}
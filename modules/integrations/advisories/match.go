@@ -0,0 +1,174 @@
+package advisories
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Matcher finds advisories for installed software by CPE vendor and product names
+type Matcher struct {
+	byToken map[string][]match
+}
+
+type match struct {
+	advisory *Advisory
+	affected *Affected
+	tokens   []string // Vendor and product tokens that must all be in the software name
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NewMatcher indexes the affected products of the advisories, KEV IDs flag known exploited ones
+func NewMatcher(advisories []Advisory, knownexploited []string) *Matcher {
+	kev := make(map[string]struct{}, len(knownexploited))
+	for _, id := range knownexploited {
+		kev[id] = struct{}{}
+	}
+
+	m := &Matcher{
+		byToken: make(map[string][]match),
+	}
+	for i := range advisories {
+		advisory := &advisories[i]
+		if _, found := kev[advisory.ID]; found {
+			advisory.KnownExploited = true
+		}
+		for j := range advisory.Affects {
+			affected := &advisory.Affects[j]
+			producttokens := tokenize(affected.Product)
+			if len(producttokens) == 0 {
+				continue
+			}
+			// Index on the longest product token, as that's the most selective
+			longest := producttokens[0]
+			for _, token := range producttokens[1:] {
+				if len(token) > len(longest) {
+					longest = token
+				}
+			}
+			m.byToken[longest] = append(m.byToken[longest], match{
+				advisory: advisory,
+				affected: affected,
+				tokens:   append(tokenize(affected.Vendor), producttokens...),
+			})
+		}
+	}
+	return m
+}
+
+// Match returns the advisories affecting the software, which is formatted as "publisher name version" like the
+// localmachine importer does it
+func (m *Matcher) Match(software string) []*Advisory {
+	var version string
+	if space := strings.LastIndexByte(software, ' '); space != -1 {
+		version = software[space+1:]
+	}
+	if version == "" || !unicode.IsDigit(rune(version[0])) {
+		// No version, so nothing to compare against
+		return nil
+	}
+
+	tokens := tokenize(software)
+	present := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		present[token] = struct{}{}
+	}
+
+	var result []*Advisory
+	seen := make(map[*Advisory]struct{})
+	for _, token := range tokens {
+		for _, candidate := range m.byToken[token] {
+			if _, found := seen[candidate.advisory]; found {
+				continue
+			}
+			if !allPresent(present, candidate.tokens) || !candidate.affected.Contains(version) {
+				continue
+			}
+			seen[candidate.advisory] = struct{}{}
+			result = append(result, candidate.advisory)
+		}
+	}
+	return result
+}
+
+func allPresent(present map[string]struct{}, tokens []string) bool {
+	for _, token := range tokens {
+		if _, found := present[token]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains checks if the version is within the affected versions
+func (a Affected) Contains(version string) bool {
+	if a.Version != "" {
+		return CompareVersions(version, a.Version) == 0
+	}
+	if a.StartIncluding != "" && CompareVersions(version, a.StartIncluding) < 0 {
+		return false
+	}
+	if a.StartExclude != "" && CompareVersions(version, a.StartExclude) <= 0 {
+		return false
+	}
+	if a.EndIncluding != "" && CompareVersions(version, a.EndIncluding) > 0 {
+		return false
+	}
+	if a.EndExclude != "" && CompareVersions(version, a.EndExclude) >= 0 {
+		return false
+	}
+	return true
+}
+
+// CompareVersions compares dotted versions segment by segment, numerically where both segments are numbers. Missing
+// segments count as zero, so 1.2 equals 1.2.0
+func CompareVersions(a, b string) int {
+	as := tokenize(a)
+	bs := tokenize(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		asegment, bsegment := "0", "0"
+		if i < len(as) {
+			asegment = as[i]
+		}
+		if i < len(bs) {
+			bsegment = bs[i]
+		}
+		anum, aerr := strconv.ParseUint(asegment, 10, 64)
+		bnum, berr := strconv.ParseUint(bsegment, 10, 64)
+		switch {
+		case aerr == nil && berr == nil:
+			if anum != bnum {
+				if anum < bnum {
+					return -1
+				}
+				return 1
+			}
+		case asegment != bsegment:
+			if asegment < bsegment {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+var severityRank = map[string]int{
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// HigherSeverity returns the most severe of the two CVSS severities
+func HigherSeverity(a, b string) string {
+	if severityRank[b] > severityRank[a] {
+		return b
+	}
+	return a
+}
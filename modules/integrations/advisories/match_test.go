@@ -0,0 +1,100 @@
+package advisories
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		result int
+	}{
+		{"1.2", "1.2.0", 0},
+		{"1.2.0", "1.2", 0},
+		{"1.2", "1.2.0.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.2.3", "1.2.10", -1},
+		{"2.0", "10.0", -1},
+		{"22.01", "23.01", -1},
+		{"1.0a", "1.0b", -1},
+		{"1.0-2", "1.0.2", 0},
+	}
+	for _, tt := range tests {
+		if result := CompareVersions(tt.a, tt.b); result != tt.result {
+			t.Errorf("CompareVersions(%v, %v) is %v, expected %v", tt.a, tt.b, result, tt.result)
+		}
+	}
+}
+
+func TestAffectedContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected Affected
+		version  string
+		contains bool
+	}{
+		{"exact", Affected{Version: "1.2"}, "1.2.0", true},
+		{"exact other", Affected{Version: "1.2"}, "1.2.1", false},
+		{"no bounds", Affected{}, "1.2", true},
+		{"start excluding at start", Affected{StartExclude: "1.0", EndExclude: "2.0"}, "1.0", false},
+		{"start excluding after start", Affected{StartExclude: "1.0", EndExclude: "2.0"}, "1.0.1", true},
+		{"end excluding before end", Affected{StartExclude: "1.0", EndExclude: "2.0"}, "1.9.9", true},
+		{"end excluding at end", Affected{StartExclude: "1.0", EndExclude: "2.0"}, "2.0.0", false},
+		{"start including at start", Affected{StartIncluding: "1.0", EndIncluding: "2.0"}, "1.0", true},
+		{"start including before start", Affected{StartIncluding: "1.0", EndIncluding: "2.0"}, "0.9", false},
+		{"end including at end", Affected{StartIncluding: "1.0", EndIncluding: "2.0"}, "2.0", true},
+		{"end including after end", Affected{StartIncluding: "1.0", EndIncluding: "2.0"}, "2.0.1", false},
+		{"only end", Affected{EndExclude: "23.01"}, "9.20", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if contains := tt.affected.Contains(tt.version); contains != tt.contains {
+				t.Errorf("%+v contains %v is %v, expected %v", tt.affected, tt.version, contains, tt.contains)
+			}
+		})
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	advisories := []Advisory{
+		{ID: "CVE-2022-0001", Affects: []Affected{{Vendor: "7-zip", Product: "7-zip", EndExclude: "23.01"}}},
+		{ID: "CVE-2022-0002", Affects: []Affected{{Vendor: "mozilla", Product: "firefox", StartIncluding: "115.0", EndExclude: "115.4"}}},
+		{ID: "CVE-2022-0003", Affects: []Affected{{Vendor: "mozilla", Product: "firefox", Version: "115.2"}}},
+		{ID: "CVE-2022-0004", Affects: []Affected{{Vendor: "videolan", Product: "vlc_media_player", EndIncluding: "3.0.18"}}},
+	}
+	m := NewMatcher(advisories, []string{"CVE-2022-0002"})
+
+	tests := []struct {
+		software string
+		ids      []string
+	}{
+		{"Igor Pavlov 7-Zip 22.01 (x64) 22.01", []string{"CVE-2022-0001"}},
+		{"Igor Pavlov 7-Zip 23.01 (x64) 23.01", nil},
+		{"Mozilla Corporation Mozilla Firefox (x64 en-US) 115.2", []string{"CVE-2022-0002", "CVE-2022-0003"}},
+		{"Mozilla Corporation Mozilla Firefox (x64 en-US) 115.2.0", []string{"CVE-2022-0002", "CVE-2022-0003"}},
+		{"Mozilla Corporation Mozilla Firefox (x64 en-US) 116.0", nil},
+		{"Mozilla Corporation Mozilla Firefox (x64 en-US)", nil}, // No version
+		{"Firefox 115.2", nil}, // Vendor token missing from the name
+		{"VideoLAN VLC media player 3.0.18", []string{"CVE-2022-0004"}},
+		{"VideoLAN VLC 3.0.18", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.software, func(t *testing.T) {
+			found := m.Match(tt.software)
+			var ids []string
+			for _, advisory := range found {
+				ids = append(ids, advisory.ID)
+			}
+			if len(ids) != len(tt.ids) {
+				t.Fatalf("matched %v, expected %v", ids, tt.ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.ids[i] {
+					t.Errorf("matched %v, expected %v", ids, tt.ids)
+				}
+			}
+		})
+	}
+
+	if !advisories[1].KnownExploited || advisories[0].KnownExploited {
+		t.Error("known exploited flag not set from the KEV list")
+	}
+}
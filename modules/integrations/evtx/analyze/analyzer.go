@@ -0,0 +1,14 @@
+package analyze
+
+import (
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+var (
+	EventLogFiles           = engine.NewAttribute("eventLogFiles")
+	EventLogNewest          = engine.NewAttribute("eventLogNewest")
+	FailedLogons            = engine.NewAttribute("failedLogonsLastMonth")
+	EdgeExplicitCredentials = engine.NewEdge("ExplicitCredentials").Describe("Credentials for the account were entered on the machine with runas or similar, and may still be in memory").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Pivot")
+	EdgeUsedCredentialsOf   = engine.NewEdge("UsedCredentialsOf").Describe("Account logged on with explicit credentials for another account, so it knows the password").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 60 }).Tag("Pivot")
+	EdgeAdminLogonObserved  = engine.NewEdge("AdminLogonObserved").Describe("Account was seen logging on with administrative privileges in the event log").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 90 }).Tag("Granted")
+)
@@ -0,0 +1,195 @@
+package analyze
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/evtx"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+// Logon types that leave reusable credentials on the machine
+var interactiveLogonTypes = map[string]bool{
+	"2":  true, // Interactive
+	"7":  true, // Unlock
+	"10": true, // RemoteInteractive
+	"11": true, // CachedInteractive
+}
+
+// Privileges that make the holder admin equivalent on the machine
+var adminPrivileges = []string{"SeDebugPrivilege", "SeTcbPrivilege", "SeTakeOwnershipPrivilege", "SeLoadDriverPrivilege"}
+
+type account struct {
+	SID    windowssecurity.SID
+	Domain string
+	Name   string
+}
+
+func (a account) DownLevelLogonName() string {
+	if a.Domain == "" || a.Name == "" {
+		return ""
+	}
+	return a.Domain + "\\" + a.Name
+}
+
+func (a account) IsMachineAccount() bool {
+	return strings.HasSuffix(a.Name, "$")
+}
+
+type credentialUse struct {
+	Subject account
+	Target  account
+}
+
+// Everything learned about one machine from its event logs
+type hostLog struct {
+	Computer string // As written in the events, usually the DNS name
+	Files    []string
+	Newest   time.Time
+
+	Domain      string // NetBIOS name of the domain the machine is joined to
+	LocalSID    windowssecurity.SID
+	ComputerSID windowssecurity.SID
+
+	Sessions          map[account]time.Time // Last interactive logon
+	AdminLogons       map[account]time.Time
+	ExplicitLogons    map[credentialUse]time.Time
+	FailedLogonTimes  []time.Time
+	candidateLocalSID map[windowssecurity.SID]int
+}
+
+func newHostLog(computer string) *hostLog {
+	return &hostLog{
+		Computer:          computer,
+		Sessions:          make(map[account]time.Time),
+		AdminLogons:       make(map[account]time.Time),
+		ExplicitLogons:    make(map[credentialUse]time.Time),
+		candidateLocalSID: make(map[windowssecurity.SID]int),
+	}
+}
+
+// Short uppercase name of the machine
+func (hl *hostLog) Name() string {
+	name, _, _ := strings.Cut(hl.Computer, ".")
+	return strings.ToUpper(name)
+}
+
+func (hl *hostLog) DNSHostName() string {
+	if !strings.Contains(hl.Computer, ".") {
+		return ""
+	}
+	return strings.ToLower(hl.Computer)
+}
+
+func eventAccount(event evtx.Event, prefix string) account {
+	sid, _ := windowssecurity.ParseStringSID(event.Data[prefix+"UserSid"])
+	return account{
+		SID:    sid,
+		Domain: strings.ToUpper(event.Data[prefix+"DomainName"]),
+		Name:   event.Data[prefix+"UserName"],
+	}
+}
+
+func later(m map[account]time.Time, a account, t time.Time) {
+	if t.After(m[a]) {
+		m[a] = t
+	}
+}
+
+func (hl *hostLog) Add(event evtx.Event) {
+	if event.Written.After(hl.Newest) {
+		hl.Newest = event.Written
+	}
+
+	switch event.EventID {
+	case 4624: // Successful logon
+		target := eventAccount(event, "Target")
+		hl.learn(target)
+		if interactiveLogonTypes[event.Data["LogonType"]] {
+			later(hl.Sessions, target, event.Written)
+		}
+	case 4625: // Failed logon
+		hl.FailedLogonTimes = append(hl.FailedLogonTimes, event.Written)
+	case 4648: // Logon with explicit credentials
+		subject := eventAccount(event, "Subject")
+		hl.learn(subject)
+		target := account{
+			Domain: strings.ToUpper(event.Data["TargetDomainName"]),
+			Name:   event.Data["TargetUserName"],
+		}
+		if target.Name == "" || strings.EqualFold(subject.DownLevelLogonName(), target.DownLevelLogonName()) {
+			break
+		}
+		use := credentialUse{Subject: subject, Target: target}
+		if event.Written.After(hl.ExplicitLogons[use]) {
+			hl.ExplicitLogons[use] = event.Written
+		}
+	case 4672: // Special privileges assigned to new logon
+		subject := eventAccount(event, "Subject")
+		hl.learn(subject)
+		privileges := event.Data["PrivilegeList"]
+		for _, privilege := range adminPrivileges {
+			if strings.Contains(privileges, privilege) {
+				later(hl.AdminLogons, subject, event.Written)
+				break
+			}
+		}
+	}
+}
+
+// Picks up the machine SIDs and domain name from the accounts in the events
+func (hl *hostLog) learn(a account) {
+	if !strings.EqualFold(a.Name, hl.Name()+"$") {
+		if a.SID.Component(2) == 21 && strings.EqualFold(a.Domain, hl.Name()) {
+			hl.candidateLocalSID[a.SID.StripRID()]++
+		}
+		return
+	}
+	// The machine account, SYSTEM is also reported like this
+	if a.Domain != "" && a.Domain != "WORKGROUP" && !strings.EqualFold(a.Domain, hl.Name()) && !strings.Contains(a.Domain, ".") {
+		hl.Domain = a.Domain
+	}
+	if a.SID.Component(2) == 21 {
+		hl.ComputerSID = a.SID
+	}
+}
+
+// Combines logs from several files from the same machine
+func (hl *hostLog) Merge(other *hostLog) {
+	hl.Files = append(hl.Files, other.Files...)
+	if other.Newest.After(hl.Newest) {
+		hl.Newest = other.Newest
+	}
+	if other.Domain != "" {
+		hl.Domain = other.Domain
+	}
+	if !other.ComputerSID.IsBlank() {
+		hl.ComputerSID = other.ComputerSID
+	}
+	for a, t := range other.Sessions {
+		later(hl.Sessions, a, t)
+	}
+	for a, t := range other.AdminLogons {
+		later(hl.AdminLogons, a, t)
+	}
+	for use, t := range other.ExplicitLogons {
+		if t.After(hl.ExplicitLogons[use]) {
+			hl.ExplicitLogons[use] = t
+		}
+	}
+	hl.FailedLogonTimes = append(hl.FailedLogonTimes, other.FailedLogonTimes...)
+	for sid, count := range other.candidateLocalSID {
+		hl.candidateLocalSID[sid] += count
+	}
+}
+
+// Settles on the local machine SID when all the data is in
+func (hl *hostLog) Finish() {
+	var best int
+	for sid, count := range hl.candidateLocalSID {
+		if count > best {
+			hl.LocalSID = sid
+			best = count
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package analyze
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	adanalyze "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+)
+
+// ImportHostLog adds what was learned from the event logs of one machine, and returns the machine object
+func ImportHostLog(ao *engine.Objects, hl *hostLog) *engine.Object {
+	hl.Finish()
+
+	machine := ao.AddNew(
+		engine.IgnoreBlanks,
+		engine.Type, engine.AttributeValueString("Machine"),
+		engine.DisplayName, hl.Name(),
+		lmanalyze.DNSHostname, hl.DNSHostName(),
+		EventLogFiles, hl.Files,
+		EventLogNewest, hl.Newest,
+		// Don't set UniqueSource on the machine object, it needs to merge with the AD object!
+		engine.DataSource, hl.Name(),
+	)
+
+	// The local SID merges with localmachine data, the computer account SID with the machine from Active Directory
+	if !hl.LocalSID.IsBlank() {
+		machine.SetFlex(engine.ObjectSid, hl.LocalSID)
+	}
+
+	var computer *engine.Object
+	if !hl.ComputerSID.IsBlank() {
+		machine.SetFlex(adanalyze.DomainJoinedSID, engine.AttributeValueSID(hl.ComputerSID))
+		computer, _ = ao.FindOrAdd(
+			activedirectory.ObjectSid, engine.AttributeValueSID(hl.ComputerSID),
+		)
+	}
+	if hl.Domain != "" {
+		downlevelmachinename := engine.AttributeValueString(hl.Domain + "\\" + hl.Name() + "$")
+		if computer == nil {
+			computer, _ = ao.FindOrAdd(
+				engine.DownLevelLogonName, downlevelmachinename,
+			)
+		}
+		computer.SetFlex(
+			activedirectory.SAMAccountName, engine.AttributeValueString(hl.Name()+"$"),
+			engine.DownLevelLogonName, downlevelmachinename,
+		)
+	}
+	if computer != nil {
+		machine.EdgeTo(computer, adanalyze.EdgeAuthenticatesAs)
+		machine.EdgeTo(computer, adanalyze.EdgeMachineAccount)
+		machine.ChildOf(computer)
+	}
+
+	// Recency is relative to the newest event, the logs are probably not from today
+	lastday := hl.Newest.Add(-24 * time.Hour)
+	lastweek := hl.Newest.Add(-7 * 24 * time.Hour)
+	lastmonth := hl.Newest.Add(-30 * 24 * time.Hour)
+
+	accounts := make(map[account]*engine.Object)
+	accountObject := func(a account) *engine.Object {
+		// Only real accounts, not SYSTEM and friends. Machine accounts log on everywhere all the time
+		if a.SID.Component(2) != 21 || a.IsMachineAccount() {
+			return nil
+		}
+		if o, found := accounts[a]; found {
+			return o
+		}
+		o := ao.AddNew(
+			activedirectory.ObjectSid, engine.AttributeValueSID(a.SID),
+			engine.Type, "Person",
+		)
+		if a.SID.StripRID() == hl.LocalSID {
+			o.SetFlex(
+				engine.DataSource, hl.Name(),
+			)
+		}
+		if name := a.DownLevelLogonName(); name != "" {
+			o.SetValues(engine.DownLevelLogonName, engine.AttributeValueString(name))
+		}
+		accounts[a] = o
+		return o
+	}
+
+	for a, last := range hl.Sessions {
+		if last.Before(lastmonth) {
+			continue
+		}
+		user := accountObject(a)
+		if user == nil {
+			continue
+		}
+		if last.After(lastday) {
+			machine.EdgeTo(user, lmanalyze.EdgeLocalSessionLastDay)
+		}
+		if last.After(lastweek) {
+			machine.EdgeTo(user, lmanalyze.EdgeLocalSessionLastWeek)
+		}
+		machine.EdgeTo(user, lmanalyze.EdgeLocalSessionLastMonth)
+	}
+
+	for a := range hl.AdminLogons {
+		if admin := accountObject(a); admin != nil {
+			admin.EdgeTo(machine, EdgeAdminLogonObserved)
+		}
+	}
+
+	for use, last := range hl.ExplicitLogons {
+		if last.Before(lastmonth) || use.Target.IsMachineAccount() {
+			continue
+		}
+		target := hl.namedAccount(ao, use.Target)
+		if target == nil {
+			continue
+		}
+		machine.EdgeTo(target, EdgeExplicitCredentials)
+		if subject := accountObject(use.Subject); subject != nil {
+			subject.EdgeTo(target, EdgeUsedCredentialsOf)
+		}
+	}
+
+	var failed int
+	for _, t := range hl.FailedLogonTimes {
+		if t.After(lastmonth) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		machine.SetFlex(FailedLogons, failed)
+	}
+
+	return machine
+}
+
+// Finds accounts that are only known by name, like the targets of explicit credential logons
+func (hl *hostLog) namedAccount(ao *engine.Objects, a account) *engine.Object {
+	if strings.Contains(a.Name, "@") {
+		o, _ := ao.FindOrAdd(
+			engine.UserPrincipalName, engine.AttributeValueString(a.Name),
+		)
+		return o
+	}
+	// DNS domain names can't be mapped to the NetBIOS name without the domain data
+	if a.Domain == "" || strings.Contains(a.Domain, ".") {
+		return nil
+	}
+	o, _ := ao.FindOrAdd(
+		engine.DownLevelLogonName, engine.AttributeValueString(a.DownLevelLogonName()),
+	)
+	if strings.EqualFold(a.Domain, hl.Name()) {
+		o.SetFlex(engine.DataSource, hl.Name())
+	}
+	return o
+}
@@ -0,0 +1,129 @@
+package analyze
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/evtx"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+const loadername = "Windows event log file"
+
+var (
+	loader = engine.AddLoader(func() engine.Loader { return &EventLogLoader{} })
+)
+
+type loaderQueueItem struct {
+	path string
+	cb   engine.ProgressCallbackFunc
+}
+
+// EventLogLoader reads exported Security event logs. Logs from the same machine are combined before import, as
+// they're often split over several files
+type EventLogLoader struct {
+	ao         *engine.Objects
+	done       sync.WaitGroup
+	infostoadd chan loaderQueueItem
+
+	lock  sync.Mutex
+	hosts map[string]*hostLog
+}
+
+func (ld *EventLogLoader) Name() string {
+	return loadername
+}
+
+func (ld *EventLogLoader) Init() error {
+	ld.ao = engine.NewLoaderObjects(ld)
+	ld.infostoadd = make(chan loaderQueueItem, 128)
+	ld.hosts = make(map[string]*hostLog)
+
+	for i := 0; i < runtime.NumCPU(); i++ {
+		ld.done.Add(1)
+		go func() {
+			for queueItem := range ld.infostoadd {
+				raw, err := encryption.ReadFile(queueItem.path)
+				if err != nil {
+					ui.Warn().Msgf("Problem reading data from event log file %v: %v", queueItem.path, err)
+					continue
+				}
+
+				hosts := make(map[string]*hostLog)
+				err = evtx.ReadEvents(raw, func(event evtx.Event) bool {
+					if event.Computer == "" {
+						return true
+					}
+					key := strings.ToLower(event.Computer)
+					hl, found := hosts[key]
+					if !found {
+						hl = newHostLog(event.Computer)
+						hl.Files = []string{queueItem.path}
+						hosts[key] = hl
+					}
+					hl.Add(event)
+					return true
+				})
+				if err != nil {
+					ui.Warn().Msgf("Problem parsing event log file %v: %v", queueItem.path, err)
+					continue
+				}
+
+				ld.lock.Lock()
+				for key, hl := range hosts {
+					if existing, found := ld.hosts[key]; found {
+						existing.Merge(hl)
+					} else {
+						ld.hosts[key] = hl
+					}
+				}
+				ld.lock.Unlock()
+
+				// Add progress
+				queueItem.cb(-100, 0)
+			}
+			ld.done.Done()
+		}()
+	}
+
+	return nil
+}
+
+func (ld *EventLogLoader) Close() ([]*engine.Objects, error) {
+	close(ld.infostoadd)
+	ld.done.Wait()
+
+	for _, hl := range ld.hosts {
+		ImportHostLog(ld.ao, hl)
+	}
+
+	result := []*engine.Objects{ld.ao}
+	ld.ao = nil
+	ld.hosts = nil
+	return result, nil
+}
+
+func (ld *EventLogLoader) Estimate(path string, cb engine.ProgressCallbackFunc) error {
+	if !strings.HasSuffix(strings.ToLower(path), evtx.Suffix) {
+		return engine.ErrUninterested
+	}
+
+	// Estimate progress
+	cb(0, -100)
+	return nil
+}
+
+func (ld *EventLogLoader) Load(path string, cb engine.ProgressCallbackFunc) error {
+	if !strings.HasSuffix(strings.ToLower(path), evtx.Suffix) {
+		return engine.ErrUninterested
+	}
+
+	ld.infostoadd <- loaderQueueItem{
+		path: path,
+		cb:   cb,
+	}
+	return nil
+}
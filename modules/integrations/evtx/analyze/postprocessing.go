@@ -0,0 +1,92 @@
+package analyze
+
+import (
+	"github.com/lkarlslund/adalanche/modules/engine"
+	adanalyze "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+// Finds the machine from AD or localmachine data for event log machines that did not merge on SIDs
+func realMachine(ao *engine.Objects, logmachine *engine.Object) *engine.Object {
+	isOther := func(o *engine.Object) bool {
+		return o != logmachine && o.Type() == engine.ObjectTypeMachine && o.HasAttr(adanalyze.DomainJoinedSID)
+	}
+
+	if computer := logmachine.Parent(); computer != nil {
+		if sid := computer.SID(); !sid.IsBlank() {
+			if machine, found := ao.Find(adanalyze.DomainJoinedSID, engine.AttributeValueSID(sid)); found && isOther(machine) {
+				return machine
+			}
+		}
+	}
+
+	if dnshostname := logmachine.OneAttr(lmanalyze.DNSHostname); dnshostname != nil {
+		if machines, found := ao.FindMulti(lmanalyze.DNSHostname, dnshostname); found {
+			var result *engine.Object
+			machines.Iterate(func(o *engine.Object) bool {
+				if isOther(o) {
+					result = o
+					return false
+				}
+				return true
+			})
+			return result
+		}
+	}
+	return nil
+}
+
+func init() {
+	loader.AddProcessor(func(ao *engine.Objects) {
+		type move struct {
+			from, to *engine.Object
+		}
+		var moves []move
+		ao.Iterate(func(o *engine.Object) bool {
+			if !o.HasAttr(EventLogFiles) || o.HasAttr(adanalyze.DomainJoinedSID) {
+				return true
+			}
+			if machine := realMachine(ao, o); machine != nil {
+				moves = append(moves, move{o, machine})
+			}
+			return true
+		})
+
+		for _, m := range moves {
+			ui.Debug().Msgf("Moving event log findings from %v to machine %v by host name", m.from.Label(), m.to.Label())
+			for _, attr := range []engine.Attribute{EventLogFiles, EventLogNewest, FailedLogons} {
+				if values, found := m.from.Get(attr); found {
+					m.to.Set(attr, values)
+				}
+			}
+
+			type edge struct {
+				source, target *engine.Object
+				edges          engine.EdgeBitmap
+			}
+			var edges []edge
+			m.from.Edges(engine.Out).Range(func(target *engine.Object, edgebitmap engine.EdgeBitmap) bool {
+				edges = append(edges, edge{m.from, target, edgebitmap})
+				return true
+			})
+			m.from.Edges(engine.In).Range(func(source *engine.Object, edgebitmap engine.EdgeBitmap) bool {
+				edges = append(edges, edge{source, m.from, edgebitmap})
+				return true
+			})
+			for _, e := range edges {
+				for _, edgetype := range e.edges.Edges() {
+					e.source.EdgeClear(e.target, edgetype)
+					if e.source == m.from {
+						m.to.EdgeTo(e.target, edgetype)
+					} else {
+						e.source.EdgeTo(m.to, edgetype)
+					}
+				}
+			}
+		}
+	},
+		"Move event log findings to machines that only match on host name",
+		engine.AfterMerge,
+	)
+}
@@ -0,0 +1,533 @@
+package evtx
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+const (
+	Suffix = ".evtx"
+
+	fileSignature   = "ElfFile\x00"
+	chunkSignature  = "ElfChnk\x00"
+	fileHeaderSize  = 0x1000
+	chunkSize       = 0x10000
+	chunkRecords    = 0x200 // Records start after the header, string and template tables
+	recordSignature = 0x00002a2a
+
+	maxNesting = 32
+)
+
+var (
+	ErrNotEVTX     = errors.New("not an EVTX file")
+	errTruncated   = errors.New("BinXML data is truncated")
+	errTooDeep     = errors.New("BinXML nesting is too deep")
+	errBadToken    = errors.New("unknown BinXML token")
+	errNoSuchValue = errors.New("substitution refers to missing value")
+)
+
+// Event is the parts of an event record needed for analysis
+type Event struct {
+	RecordID uint64
+	Written  time.Time
+	EventID  uint16
+	Channel  string
+	Computer string
+	Data     map[string]string // EventData values by name
+}
+
+// ReadEvents parses the records of an exported event log and calls f for each of them until it returns false.
+// Records that can't be parsed are skipped.
+func ReadEvents(data []byte, f func(Event) bool) error {
+	if len(data) < fileHeaderSize || string(data[:len(fileSignature)]) != fileSignature {
+		return ErrNotEVTX
+	}
+
+	var bad int
+	for offset := fileHeaderSize; offset+chunkSize <= len(data); offset += chunkSize {
+		chunk := data[offset : offset+chunkSize]
+		if string(chunk[:len(chunkSignature)]) != chunkSignature {
+			continue // Unused chunk
+		}
+
+		freespace := min(int(binary.LittleEndian.Uint32(chunk[48:])), chunkSize)
+		for pos := chunkRecords; pos+28 <= freespace; {
+			if binary.LittleEndian.Uint32(chunk[pos:]) != recordSignature {
+				break
+			}
+			size := int(binary.LittleEndian.Uint32(chunk[pos+4:]))
+			if size < 28 || pos+size > chunkSize {
+				break
+			}
+
+			event, err := func() (event Event, err error) {
+				// Bounds are checked while parsing, but one broken record must not take down the whole import
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("parser failure: %v", r)
+					}
+				}()
+				return parseRecord(chunk, pos, size)
+			}()
+			if err != nil {
+				bad++
+				ui.Debug().Msgf("Skipping event record at offset %v: %v", offset+pos, err)
+			} else if !f(event) {
+				return nil
+			}
+			pos += size
+		}
+	}
+	if bad > 0 {
+		ui.Warn().Msgf("Skipped %v event records that could not be parsed", bad)
+	}
+	return nil
+}
+
+func parseRecord(chunk []byte, pos, size int) (Event, error) {
+	event := Event{
+		RecordID: binary.LittleEndian.Uint64(chunk[pos+8:]),
+		Written:  filetime(binary.LittleEndian.Uint64(chunk[pos+16:])),
+	}
+
+	p := binxmlParser{chunk: chunk}
+	nodes, _, err := p.parse(pos+24, pos+size-4, nil)
+	if err != nil {
+		return event, err
+	}
+	root := (&node{children: nodes}).child("Event")
+	if root == nil {
+		return event, errors.New("record contains no Event element")
+	}
+
+	system := root.child("System")
+	if system == nil {
+		return event, errors.New("event contains no System element")
+	}
+	if eventid := system.child("EventID"); eventid != nil {
+		id, _ := strconv.ParseUint(eventid.text.String(), 10, 16)
+		event.EventID = uint16(id)
+	}
+	if channel := system.child("Channel"); channel != nil {
+		event.Channel = channel.text.String()
+	}
+	if computer := system.child("Computer"); computer != nil {
+		event.Computer = computer.text.String()
+	}
+
+	if eventdata := root.child("EventData"); eventdata != nil {
+		event.Data = make(map[string]string)
+		for _, data := range eventdata.children {
+			if name := data.attributes["Name"]; name != "" {
+				event.Data[name] = data.text.String()
+			}
+		}
+	}
+	return event, nil
+}
+
+func filetime(ft uint64) time.Time {
+	if ft == 0 {
+		return time.Time{}
+	}
+	// 100ns intervals since 1601-01-01
+	return time.Unix(0, 0).UTC().Add(time.Duration(ft-116444736000000000) * 100)
+}
+
+// Minimal XML tree, the rendered event
+type node struct {
+	name       string
+	attributes map[string]string
+	text       strings.Builder
+	children   []*node
+}
+
+func (n *node) child(name string) *node {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+type substitution struct {
+	valuetype byte
+	start     int
+	end       int
+}
+
+// Binary XML parser, positions are offsets in the chunk as names and templates are referenced that way
+type binxmlParser struct {
+	chunk []byte
+	depth int
+}
+
+func (p *binxmlParser) need(pos, n int) error {
+	if pos < 0 || pos+n > len(p.chunk) {
+		return errTruncated
+	}
+	return nil
+}
+
+// Parses BinXML from pos to end (or the end of fragment token), returning the top level nodes and the position after them
+func (p *binxmlParser) parse(pos, end int, subs []substitution) ([]*node, int, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxNesting {
+		return nil, pos, errTooDeep
+	}
+
+	root := &node{}
+	stack := []*node{root}
+	current := func() *node {
+		return stack[len(stack)-1]
+	}
+
+	for pos < end {
+		token := p.chunk[pos]
+		switch token &^ 0x40 {
+		case 0x00: // End of fragment
+			return root.children, pos + 1, nil
+		case 0x0f: // Fragment header
+			pos += 4
+		case 0x0c: // Template instance
+			nodes, next, err := p.templateInstance(pos)
+			if err != nil {
+				return nil, pos, err
+			}
+			current().children = append(current().children, nodes...)
+			pos = next
+		case 0x01: // Open start element
+			if err := p.need(pos, 11); err != nil {
+				return nil, pos, err
+			}
+			name, next, err := p.name(binary.LittleEndian.Uint32(p.chunk[pos+7:]), pos+11)
+			if err != nil {
+				return nil, pos, err
+			}
+			pos = next
+			if token&0x40 != 0 {
+				pos += 4 // Attribute list size
+			}
+			element := &node{name: name}
+			current().children = append(current().children, element)
+			stack = append(stack, element)
+		case 0x02: // Close start element, children follow
+			pos++
+		case 0x03, 0x04: // Close empty element, end element
+			pos++
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case 0x06: // Attribute
+			if err := p.need(pos, 5); err != nil {
+				return nil, pos, err
+			}
+			name, next, err := p.name(binary.LittleEndian.Uint32(p.chunk[pos+1:]), pos+5)
+			if err != nil {
+				return nil, pos, err
+			}
+			pos = next
+			var value strings.Builder
+			for pos < end && isValueToken(p.chunk[pos]) {
+				if pos, err = p.content(pos, subs, &node{}, &value); err != nil {
+					return nil, pos, err
+				}
+			}
+			if current().attributes == nil {
+				current().attributes = make(map[string]string)
+			}
+			current().attributes[name] = value.String()
+		case 0x05, 0x07, 0x08, 0x09, 0x0d, 0x0e:
+			var err error
+			if pos, err = p.content(pos, subs, current(), &current().text); err != nil {
+				return nil, pos, err
+			}
+		case 0x0a: // Processing instruction target
+			if err := p.need(pos, 5); err != nil {
+				return nil, pos, err
+			}
+			_, next, err := p.name(binary.LittleEndian.Uint32(p.chunk[pos+1:]), pos+5)
+			if err != nil {
+				return nil, pos, err
+			}
+			pos = next
+		case 0x0b: // Processing instruction data
+			_, next, err := p.utf16(pos + 1)
+			if err != nil {
+				return nil, pos, err
+			}
+			pos = next
+		default:
+			return nil, pos, fmt.Errorf("%w 0x%02x at offset %v", errBadToken, token, pos)
+		}
+	}
+	return root.children, pos, nil
+}
+
+func isValueToken(token byte) bool {
+	switch token &^ 0x40 {
+	case 0x05, 0x07, 0x08, 0x09, 0x0d, 0x0e:
+		return true
+	}
+	return false
+}
+
+// Text, character and entity references and substitutions. Embedded BinXML values become children of parent
+func (p *binxmlParser) content(pos int, subs []substitution, parent *node, text *strings.Builder) (int, error) {
+	token := p.chunk[pos]
+	switch token &^ 0x40 {
+	case 0x05: // Value
+		if err := p.need(pos, 2); err != nil {
+			return pos, err
+		}
+		s, next, err := p.utf16(pos + 2)
+		if err != nil {
+			return pos, err
+		}
+		text.WriteString(s)
+		return next, nil
+	case 0x07: // CDATA
+		s, next, err := p.utf16(pos + 1)
+		if err != nil {
+			return pos, err
+		}
+		text.WriteString(s)
+		return next, nil
+	case 0x08: // Character reference
+		if err := p.need(pos, 3); err != nil {
+			return pos, err
+		}
+		text.WriteRune(rune(binary.LittleEndian.Uint16(p.chunk[pos+1:])))
+		return pos + 3, nil
+	case 0x09: // Entity reference
+		if err := p.need(pos, 5); err != nil {
+			return pos, err
+		}
+		name, next, err := p.name(binary.LittleEndian.Uint32(p.chunk[pos+1:]), pos+5)
+		if err != nil {
+			return pos, err
+		}
+		text.WriteString(map[string]string{"amp": "&", "lt": "<", "gt": ">", "quot": `"`, "apos": "'"}[name])
+		return next, nil
+	case 0x0d, 0x0e: // Normal and optional substitution
+		if err := p.need(pos, 4); err != nil {
+			return pos, err
+		}
+		id := int(binary.LittleEndian.Uint16(p.chunk[pos+1:]))
+		if id >= len(subs) {
+			return pos, errNoSuchValue
+		}
+		sub := subs[id]
+		if sub.valuetype == 0x21 {
+			// Embedded BinXML, like UserData
+			nodes, _, err := p.parse(sub.start, sub.end, nil)
+			if err != nil {
+				return pos, err
+			}
+			parent.children = append(parent.children, nodes...)
+		} else {
+			text.WriteString(formatValue(sub.valuetype, p.chunk[sub.start:sub.end]))
+		}
+		return pos + 4, nil
+	}
+	return pos, errBadToken
+}
+
+// Names are stored once per chunk and referenced by offset, the first use has the name inline
+func (p *binxmlParser) name(offset uint32, pos int) (string, int, error) {
+	start := int(offset)
+	if err := p.need(start, 8); err != nil {
+		return "", pos, err
+	}
+	chars := int(binary.LittleEndian.Uint16(p.chunk[start+6:]))
+	if err := p.need(start+8, chars*2); err != nil {
+		return "", pos, err
+	}
+	name := decodeUTF16(p.chunk[start+8 : start+8+chars*2])
+	if start == pos {
+		pos += 8 + chars*2 + 2
+	}
+	return name, pos, nil
+}
+
+// Length prefixed UTF-16 string
+func (p *binxmlParser) utf16(pos int) (string, int, error) {
+	if err := p.need(pos, 2); err != nil {
+		return "", pos, err
+	}
+	chars := int(binary.LittleEndian.Uint16(p.chunk[pos:]))
+	if err := p.need(pos+2, chars*2); err != nil {
+		return "", pos, err
+	}
+	return decodeUTF16(p.chunk[pos+2 : pos+2+chars*2]), pos + 2 + chars*2, nil
+}
+
+// Template with its substitution values, templates are stored once per chunk and referenced by offset
+func (p *binxmlParser) templateInstance(pos int) ([]*node, int, error) {
+	if err := p.need(pos, 10); err != nil {
+		return nil, pos, err
+	}
+	definition := int(binary.LittleEndian.Uint32(p.chunk[pos+6:]))
+	pos += 10
+	if err := p.need(definition, 24); err != nil {
+		return nil, pos, err
+	}
+	bodysize := int(binary.LittleEndian.Uint32(p.chunk[definition+20:]))
+	bodystart := definition + 24
+	if err := p.need(bodystart, bodysize); err != nil {
+		return nil, pos, err
+	}
+	if definition == pos {
+		pos = bodystart + bodysize
+	}
+
+	if err := p.need(pos, 4); err != nil {
+		return nil, pos, err
+	}
+	count := int(binary.LittleEndian.Uint32(p.chunk[pos:]))
+	pos += 4
+	if err := p.need(pos, count*4); err != nil {
+		return nil, pos, err
+	}
+	subs := make([]substitution, count)
+	valuepos := pos + count*4
+	for i := range subs {
+		size := int(binary.LittleEndian.Uint16(p.chunk[pos+i*4:]))
+		subs[i] = substitution{
+			valuetype: p.chunk[pos+i*4+2],
+			start:     valuepos,
+			end:       valuepos + size,
+		}
+		valuepos += size
+	}
+	if err := p.need(pos, valuepos-pos); err != nil {
+		return nil, pos, err
+	}
+
+	nodes, _, err := p.parse(bodystart, bodystart+bodysize, subs)
+	return nodes, valuepos, err
+}
+
+func decodeUTF16(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+}
+
+func formatValue(valuetype byte, b []byte) string {
+	if valuetype&0x80 != 0 {
+		// Arrays, strings are NUL separated and the rest fixed size
+		base := valuetype &^ 0x80
+		var items []string
+		switch base {
+		case 0x01:
+			items = strings.Split(decodeUTF16(b), "\x00")
+		case 0x02:
+			items = strings.Split(strings.TrimRight(string(b), "\x00"), "\x00")
+		default:
+			size := map[byte]int{0x03: 1, 0x04: 1, 0x05: 2, 0x06: 2, 0x07: 4, 0x08: 4, 0x09: 8, 0x0a: 8, 0x0b: 4, 0x0c: 8, 0x0d: 4, 0x0f: 16, 0x11: 8, 0x12: 16, 0x14: 4, 0x15: 8}[base]
+			if size == 0 {
+				return strings.ToUpper(hex.EncodeToString(b))
+			}
+			for i := 0; i+size <= len(b); i += size {
+				items = append(items, formatValue(base, b[i:i+size]))
+			}
+		}
+		return strings.Join(items, " ")
+	}
+
+	fixed := func(n int) bool {
+		return len(b) >= n
+	}
+	switch valuetype {
+	case 0x00:
+		return ""
+	case 0x01:
+		return decodeUTF16(b)
+	case 0x02:
+		return strings.TrimRight(string(b), "\x00")
+	case 0x03:
+		if fixed(1) {
+			return strconv.Itoa(int(int8(b[0])))
+		}
+	case 0x04:
+		if fixed(1) {
+			return strconv.Itoa(int(b[0]))
+		}
+	case 0x05:
+		if fixed(2) {
+			return strconv.Itoa(int(int16(binary.LittleEndian.Uint16(b))))
+		}
+	case 0x06:
+		if fixed(2) {
+			return strconv.Itoa(int(binary.LittleEndian.Uint16(b)))
+		}
+	case 0x07:
+		if fixed(4) {
+			return strconv.Itoa(int(int32(binary.LittleEndian.Uint32(b))))
+		}
+	case 0x08:
+		if fixed(4) {
+			return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(b)), 10)
+		}
+	case 0x09:
+		if fixed(8) {
+			return strconv.FormatInt(int64(binary.LittleEndian.Uint64(b)), 10)
+		}
+	case 0x0a:
+		if fixed(8) {
+			return strconv.FormatUint(binary.LittleEndian.Uint64(b), 10)
+		}
+	case 0x0b:
+		if fixed(4) {
+			return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), 'g', -1, 32)
+		}
+	case 0x0c:
+		if fixed(8) {
+			return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)), 'g', -1, 64)
+		}
+	case 0x0d:
+		if fixed(4) {
+			return strconv.FormatBool(binary.LittleEndian.Uint32(b) != 0)
+		}
+	case 0x0f:
+		if fixed(16) {
+			return fmt.Sprintf("{%08X-%04X-%04X-%X-%X}", binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint16(b[4:]), binary.LittleEndian.Uint16(b[6:]), b[8:10], b[10:16])
+		}
+	case 0x10, 0x14, 0x15:
+		switch len(b) {
+		case 4:
+			return fmt.Sprintf("0x%x", binary.LittleEndian.Uint32(b))
+		case 8:
+			return fmt.Sprintf("0x%x", binary.LittleEndian.Uint64(b))
+		}
+	case 0x11:
+		if fixed(8) {
+			return filetime(binary.LittleEndian.Uint64(b)).Format(time.RFC3339Nano)
+		}
+	case 0x12:
+		if fixed(16) {
+			field := func(i int) int { return int(binary.LittleEndian.Uint16(b[i*2:])) }
+			return time.Date(field(0), time.Month(field(1)), field(3), field(4), field(5), field(6), field(7)*int(time.Millisecond), time.UTC).Format(time.RFC3339Nano)
+		}
+	case 0x13:
+		if sid, _, err := windowssecurity.BytesToSID(b); err == nil {
+			return sid.String()
+		}
+	}
+	return strings.ToUpper(hex.EncodeToString(b))
+}
@@ -0,0 +1,193 @@
+package evtx
+
+import (
+	"encoding/binary"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Security log with logon events from a Windows 2012 R2 test box, taken from the Elastic Beats winlogbeat test data (Apache 2.0)
+func sample(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/logon.evtx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func readAll(data []byte) ([]Event, error) {
+	var events []Event
+	err := ReadEvents(data, func(event Event) bool {
+		events = append(events, event)
+		return true
+	})
+	return events, err
+}
+
+func TestReadEvents(t *testing.T) {
+	events, err := readAll(sample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 18 {
+		t.Fatalf("got %v events, want 18", len(events))
+	}
+
+	tests := []struct {
+		index    int
+		recordid uint64
+		written  time.Time
+		eventid  uint16
+		data     map[string]string
+	}{
+		{
+			index:    0,
+			recordid: 1,
+			written:  time.Date(2019, 3, 29, 21, 10, 39, 786832100, time.UTC),
+			eventid:  4624,
+			data: map[string]string{
+				"TargetUserName": "SYSTEM",
+				"TargetUserSid":  "S-1-5-18",
+				"LogonType":      "5",
+				"ProcessId":      "0x1fc",
+				"LogonGuid":      "{00000000-0000-0000-0000-000000000000}",
+			},
+		},
+		{
+			index:    10,
+			recordid: 11,
+			written:  time.Date(2019, 3, 29, 21, 13, 17, 614994600, time.UTC),
+			eventid:  4624,
+			data: map[string]string{
+				"TargetUserName": "vagrant",
+				"TargetUserSid":  "S-1-5-21-3541430928-2051711210-1391384369-1001",
+				"LogonType":      "10",
+				"IpAddress":      "10.0.2.2",
+			},
+		},
+		{
+			// The sample has no timestamp on this one
+			index:    17,
+			recordid: 18,
+			eventid:  4625,
+			data: map[string]string{
+				"TargetUserName": "bosch",
+				"Status":         "0xc000006d",
+				"IpAddress":      "::1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		event := events[tt.index]
+		if event.RecordID != tt.recordid || !event.Written.Equal(tt.written) || event.EventID != tt.eventid {
+			t.Errorf("event %v = record %v written %v id %v, want record %v written %v id %v", tt.index, event.RecordID, event.Written, event.EventID, tt.recordid, tt.written, tt.eventid)
+		}
+		if event.Channel != "Security" || event.Computer != "vagrant-2012-r2" {
+			t.Errorf("event %v from %v/%v, want Security/vagrant-2012-r2", tt.index, event.Channel, event.Computer)
+		}
+		for name, want := range tt.data {
+			if got := event.Data[name]; got != want {
+				t.Errorf("event %v %v = %q, want %q", tt.index, name, got, want)
+			}
+		}
+	}
+
+	var stopped int
+	ReadEvents(sample(t), func(event Event) bool {
+		stopped++
+		return stopped < 3
+	})
+	if stopped != 3 {
+		t.Errorf("ReadEvents() continued after callback returned false, got %v events", stopped)
+	}
+}
+
+func TestReadEventsTruncated(t *testing.T) {
+	data := sample(t)
+
+	if _, err := readAll(data[:fileHeaderSize-1]); err != ErrNotEVTX {
+		t.Errorf("truncated header error = %v, want %v", err, ErrNotEVTX)
+	}
+
+	// Partial chunks are ignored, as Windows writes whole chunks
+	for _, size := range []int{fileHeaderSize, fileHeaderSize + 100, fileHeaderSize + chunkRecords + 1000, len(data) - 1} {
+		events, err := readAll(data[:size])
+		if err != nil || len(events) != 0 {
+			t.Errorf("truncated to %v bytes gave %v events, error %v", size, len(events), err)
+		}
+	}
+
+	// Records cut short inside the chunk, each is skipped on its own
+	chunk := data[fileHeaderSize : fileHeaderSize+chunkSize]
+	for pos := chunkRecords; binary.LittleEndian.Uint32(chunk[pos:]) == recordSignature; {
+		size := int(binary.LittleEndian.Uint32(chunk[pos+4:]))
+		for cut := 28; cut < size; cut += 7 {
+			parseRecord(chunk, pos, cut)
+		}
+		pos += size
+	}
+}
+
+// Overwrites bytes all over the records, the parser must report errors instead of panicking
+func TestReadEventsCorrupted(t *testing.T) {
+	data := sample(t)
+	chunk := data[fileHeaderSize : fileHeaderSize+chunkSize]
+	freespace := int(binary.LittleEndian.Uint32(chunk[48:]))
+
+	var records [][2]int
+	for pos := chunkRecords; binary.LittleEndian.Uint32(chunk[pos:]) == recordSignature; {
+		size := int(binary.LittleEndian.Uint32(chunk[pos+4:]))
+		records = append(records, [2]int{pos, size})
+		pos += size
+	}
+
+	// Templates are shared between records, so besides the damaged record the last one gets parsed as well
+	corrupted := make([]byte, len(chunk))
+	last := records[len(records)-1]
+	for offset := chunkRecords; offset < freespace; offset += 3 {
+		record := records[slices.IndexFunc(records, func(r [2]int) bool { return offset < r[0]+r[1] })]
+		for _, value := range []byte{0x00, 0x0a, 0x41, 0xff} {
+			copy(corrupted, chunk)
+			corrupted[offset] = value
+			parseRecord(corrupted, record[0], record[1])
+			parseRecord(corrupted, last[0], last[1])
+		}
+	}
+
+	// The whole file with the record sizes garbled still reads
+	copy(corrupted, chunk)
+	for _, record := range records {
+		binary.LittleEndian.PutUint32(corrupted[record[0]+4:], uint32(record[1]+1000))
+	}
+	garbled := append(append([]byte{}, data[:fileHeaderSize]...), corrupted...)
+	if _, err := readAll(garbled); err != nil {
+		t.Errorf("garbled record sizes error = %v", err)
+	}
+}
+
+// Tokens at the very end of the data, that used to be read without checking the length
+func TestParseTokenAtEnd(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"processing instruction target", []byte{0x0a, 0x01, 0x02}},
+		{"entity reference", []byte{0x09, 0x01}},
+		{"open start element", []byte{0x01, 0x00, 0x00}},
+		{"template instance", []byte{0x0c, 0x01}},
+		{"substitution", []byte{0x0d, 0x00}},
+		{"character reference", []byte{0x08, 0x41}},
+		{"value", []byte{0x05, 0x01, 0x05, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := binxmlParser{chunk: tt.data}
+			if _, _, err := p.parse(0, len(tt.data), nil); err == nil {
+				t.Errorf("parse() succeeded on truncated data")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package analyze
+
+import (
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+var (
+	KernelVersion            = engine.NewAttribute("kernelVersion")
+	JoinProvider             = engine.NewAttribute("joinProvider")
+	KeytabPrincipals         = engine.NewAttribute("keytabPrincipals")
+	SSSDAccessFilter         = engine.NewAttribute("sssdADAccessFilter")
+	SSHAuthorizedKeysCommand = engine.NewAttribute("sshAuthorizedKeysCommand")
+	SSHAllowGroups           = engine.NewAttribute("sshAllowGroups")
+	SSHAllowUsers            = engine.NewAttribute("sshAllowUsers")
+	UIDNumber                = engine.NewAttribute("uidNumber")
+	GIDNumber                = engine.NewAttribute("gidNumber")
+	LoginShell               = engine.NewAttribute("loginShell")
+	HomeDirectory            = engine.NewAttribute("unixHomeDirectory")
+	EdgeLinuxLogonRights     = engine.NewEdge("LinuxLogonRights").RequiresPorts(22).Describe("Allowed to log on to the Linux machine by SSSD or winbind access control").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 30 }).Tag("Granted").Tag("Pivot")
+	EdgeSudoCommands         = engine.NewEdge("SudoCommands").Describe("Can run specific commands as root with sudo, which is often enough to get a root shell").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 40 }).Tag("Granted")
+	EdgeSudoAsUser           = engine.NewEdge("SudoAsUser").Describe("Can run commands as another account with sudo").Tag("Granted").Tag("Pivot")
+)
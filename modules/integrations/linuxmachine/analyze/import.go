@@ -0,0 +1,422 @@
+package analyze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	adanalyze "github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+)
+
+// Returns the machine object
+func ImportCollectorInfo(ao *engine.Objects, cinfo linuxmachine.Info) (*engine.Object, error) {
+	if cinfo.Machine.Name == "" {
+		return nil, fmt.Errorf("collected linuxmachine information doesn't contain a machine name")
+	}
+
+	if cinfo.UnprivilegedCollection {
+		ui.Info().Msgf("Loading partial information from unprivileged collector on Linux machine %v", cinfo.Machine.Name)
+	}
+
+	machine := ao.AddNew(
+		engine.IgnoreBlanks,
+		engine.Type, engine.AttributeValueString("Machine"),
+		engine.DisplayName, cinfo.Machine.Name,
+		lmanalyze.DNSHostname, cinfo.Machine.DNSHostName,
+		engine.NewAttribute("architecture"), cinfo.Machine.Architecture,
+		engine.NewAttribute("productName"), cinfo.Machine.OSName,
+		engine.NewAttribute("version"), cinfo.Machine.OSVersion,
+		KernelVersion, cinfo.Machine.KernelVersion,
+		JoinProvider, cinfo.Machine.JoinProvider,
+		// Don't set UniqueSource on the machine object, it needs to merge with the AD object!
+		engine.DataSource, cinfo.Machine.Name,
+	)
+	machine.Tag("linux")
+
+	li := linuxInfo{
+		ao:          ao,
+		cinfo:       cinfo,
+		machine:     machine,
+		localusers:  make(map[string]*engine.Object),
+		localgroups: make(map[string]*engine.Object),
+	}
+
+	if cinfo.Machine.IsDomainJoined && cinfo.Machine.Domain != "" {
+		machineaccount := cinfo.Machine.MachineAccount
+		if machineaccount == "" {
+			machineaccount = cinfo.Machine.Name + "$"
+		}
+
+		// Link to the AD account, it merges on the downlevel logon name
+		computer, _ := ao.FindOrAdd(
+			engine.DownLevelLogonName, engine.AttributeValueString(cinfo.Machine.Domain+"\\"+machineaccount),
+			activedirectory.SAMAccountName, engine.AttributeValueString(machineaccount),
+		)
+
+		machine.EdgeTo(computer, adanalyze.EdgeAuthenticatesAs)
+		machine.EdgeTo(computer, adanalyze.EdgeMachineAccount)
+		machine.ChildOf(computer)
+	}
+
+	var macaddrs, ipaddresses []string
+	for _, networkinterface := range cinfo.Network.NetworkInterfaces {
+		if strings.Count(networkinterface.MACAddress, ":") == 5 {
+			macaddrs = append(macaddrs, strings.ReplaceAll(networkinterface.MACAddress, ":", ""))
+		}
+		ipaddresses = append(ipaddresses, networkinterface.Addresses...)
+	}
+	machine.SetFlex(
+		engine.IgnoreBlanks,
+		localmachine.MACAddress, macaddrs,
+		engine.IPAddress, ipaddresses,
+	)
+
+	ao.ReindexObject(machine, false) // We changed stuff after adding it
+
+	// Everyone on the machine, domain accounts are members if they can log on
+	li.everyone = ao.AddNew(
+		activedirectory.ObjectSid, engine.AttributeValueSID(windowssecurity.EveryoneSID),
+		activedirectory.Name, "ALL",
+		engine.Type, "Group",
+		engine.DataSource, cinfo.Machine.Name,
+	)
+	li.everyone.ChildOf(machine)
+
+	// LOCAL USERS AND GROUPS
+	userscontainer := engine.NewObject(activedirectory.Name, "Users")
+	ao.Add(userscontainer)
+	userscontainer.ChildOf(machine)
+
+	gids := make(map[int]*engine.Object)
+	uids := make(map[int]*engine.Object)
+
+	groupscontainer := engine.NewObject(activedirectory.Name, "Groups")
+	ao.Add(groupscontainer)
+	groupscontainer.ChildOf(machine)
+	for _, group := range cinfo.Groups {
+		groupobject := ao.AddNew(
+			activedirectory.Name, group.Name,
+			engine.DownLevelLogonName, cinfo.Machine.Name+"\\"+group.Name,
+			GIDNumber, group.GID,
+			engine.Type, "Group",
+			engine.DataSource, cinfo.Machine.Name,
+		)
+		groupobject.ChildOf(groupscontainer)
+		li.localgroups[group.Name] = groupobject
+		gids[group.GID] = groupobject
+	}
+
+	for _, user := range cinfo.Users {
+		userobject := ao.AddNew(
+			engine.IgnoreBlanks,
+			activedirectory.Name, user.Name,
+			activedirectory.DisplayName, strings.Split(user.GECOS, ",")[0],
+			engine.DownLevelLogonName, cinfo.Machine.Name+"\\"+user.Name,
+			UIDNumber, user.UID,
+			GIDNumber, user.GID,
+			LoginShell, user.Shell,
+			HomeDirectory, user.Home,
+			engine.Type, "Person",
+			engine.DataSource, cinfo.Machine.Name,
+		)
+		userobject.ChildOf(userscontainer)
+		userobject.EdgeTo(li.everyone, activedirectory.EdgeMemberOfGroup)
+		li.localusers[user.Name] = userobject
+		uids[user.UID] = userobject
+
+		if primarygroup, found := gids[user.GID]; found {
+			userobject.EdgeTo(primarygroup, activedirectory.EdgeMemberOfGroup)
+		}
+		if user.UID == 0 {
+			userobject.EdgeTo(machine, lmanalyze.EdgeLocalAdminRights)
+		}
+	}
+
+	for _, group := range cinfo.Groups {
+		groupobject := li.localgroups[group.Name]
+		for _, member := range group.Members {
+			if memberobject := li.Account(member); memberobject != nil {
+				memberobject.EdgeTo(groupobject, activedirectory.EdgeMemberOfGroup)
+			}
+		}
+	}
+
+	// SUDO
+	for _, rule := range cinfo.Sudoers {
+		if !li.sudoRuleApplies(rule) {
+			continue
+		}
+
+		allcommands := false
+		for _, command := range rule.Commands {
+			if command == "ALL" {
+				allcommands = true
+			}
+		}
+
+		var principals []*engine.Object
+		for _, user := range rule.Users {
+			var principal *engine.Object
+			switch {
+			case user == "ALL":
+				principal = li.everyone
+			case strings.HasPrefix(user, "%#"):
+				gid, _ := strconv.Atoi(user[2:])
+				principal = gids[gid]
+			case strings.HasPrefix(user, "%:"):
+				principal = li.Group(user[2:])
+			case strings.HasPrefix(user, "%"):
+				principal = li.Group(user[1:])
+			case strings.HasPrefix(user, "#"):
+				uid, _ := strconv.Atoi(user[1:])
+				principal = uids[uid]
+			case strings.HasPrefix(user, "+"):
+				// Netgroups are not supported
+			default:
+				principal = li.Account(user)
+			}
+			if principal != nil {
+				principals = append(principals, principal)
+			}
+		}
+
+		runas := rule.RunAs
+		if len(runas) == 0 {
+			runas = []string{"root"}
+		}
+		for _, target := range runas {
+			var targetobject *engine.Object
+			edge := EdgeSudoAsUser
+			switch target {
+			case "ALL", "root", "#0":
+				targetobject = machine
+				edge = EdgeSudoCommands
+				if allcommands {
+					edge = lmanalyze.EdgeLocalAdminRights
+				}
+			default:
+				if strings.HasPrefix(target, "%") {
+					continue // Running with another group isn't interesting enough
+				}
+				if strings.HasPrefix(target, "#") {
+					uid, _ := strconv.Atoi(target[1:])
+					targetobject = uids[uid]
+				} else {
+					targetobject = li.Account(target)
+				}
+			}
+			if targetobject == nil {
+				continue
+			}
+			for _, principal := range principals {
+				if principal != targetobject {
+					principal.EdgeTo(targetobject, edge)
+				}
+			}
+		}
+	}
+
+	// LOGON RIGHTS
+	if cinfo.Machine.IsDomainJoined {
+		domaineveryone, _ := ao.FindTwoOrAdd(
+			activedirectory.ObjectSid, engine.AttributeValueSID(windowssecurity.EveryoneSID),
+			engine.DataSource, engine.AttributeValueString(cinfo.Machine.Domain),
+		)
+
+		everyonecanlogon := cinfo.Machine.JoinProvider == "winbind"
+		for _, domain := range cinfo.SSSD {
+			accessprovider := domain.AccessProvider
+			if accessprovider == "" {
+				accessprovider = domain.IDProvider // SSSD default
+			}
+			switch accessprovider {
+			case "permit":
+				everyonecanlogon = true
+			case "simple":
+				if len(domain.SimpleAllowUsers) == 0 && len(domain.SimpleAllowGroups) == 0 {
+					// Only deny lists, we don't do exclusions
+					everyonecanlogon = true
+				}
+				for _, user := range domain.SimpleAllowUsers {
+					if o := li.Account(user); o != nil {
+						o.EdgeTo(machine, EdgeLinuxLogonRights)
+					}
+				}
+				for _, group := range domain.SimpleAllowGroups {
+					if o := li.Group(group); o != nil {
+						o.EdgeTo(machine, EdgeLinuxLogonRights)
+					}
+				}
+			case "ad":
+				if domain.ADAccessFilter == "" {
+					everyonecanlogon = true
+				} else {
+					// Groups in the LDAP filter are resolved after merging with AD
+					machine.SetFlex(SSSDAccessFilter, domain.ADAccessFilter)
+				}
+			}
+		}
+
+		if everyonecanlogon {
+			domaineveryone.EdgeTo(machine, EdgeLinuxLogonRights)
+		}
+		domaineveryone.EdgeTo(li.everyone, activedirectory.EdgeMemberOfGroup)
+	}
+
+	// SSH
+	if cinfo.SSH.AuthorizedKeysCommand != "" {
+		machine.SetFlex(SSHAuthorizedKeysCommand, cinfo.SSH.AuthorizedKeysCommand)
+		if strings.Contains(cinfo.SSH.AuthorizedKeysCommand, "sss_ssh_authorizedkeys") {
+			// Public keys come from the directory, so writing a user's sshPublicKey attribute allows logon as them
+			machine.Tag("ssh_keys_from_directory")
+		}
+	}
+	machine.SetFlex(
+		engine.IgnoreBlanks,
+		SSHAllowGroups, cinfo.SSH.AllowGroups,
+		SSHAllowUsers, cinfo.SSH.AllowUsers,
+	)
+
+	// KEYTAB CREDENTIALS
+	var principals []string
+	for _, principal := range cinfo.Keytab {
+		principals = append(principals, principal.Name+"@"+principal.Realm)
+		if strings.Contains(principal.Name, "/") || strings.EqualFold(principal.Name, cinfo.Machine.MachineAccount) {
+			// Service principal names belong to the machine account
+			continue
+		}
+		if account := li.Account(principal.Name + "@" + principal.Realm); account != nil {
+			machine.EdgeTo(account, lmanalyze.EdgeHasServiceAccountCredentials)
+		}
+	}
+	machine.SetFlex(engine.IgnoreBlanks, KeytabPrincipals, principals)
+
+	// USERS THAT HAVE SESSIONS ON THE MACHINE ONCE IN WHILE
+	for _, sessions := range []struct {
+		logins []linuxmachine.LoginCount
+		edge   engine.Edge
+	}{
+		{cinfo.LoginPopularity.Day, lmanalyze.EdgeLocalSessionLastDay},
+		{cinfo.LoginPopularity.Week, lmanalyze.EdgeLocalSessionLastWeek},
+		{cinfo.LoginPopularity.Month, lmanalyze.EdgeLocalSessionLastMonth},
+	} {
+		for _, login := range sessions.logins {
+			if user := li.DomainAccount(login.Name); user != nil {
+				machine.EdgeTo(user, sessions.edge)
+			}
+		}
+	}
+
+	// Still logged on, so credentials are probably in memory
+	for _, session := range cinfo.ActiveSessions {
+		if user := li.DomainAccount(session.Name); user != nil {
+			machine.EdgeTo(user, lmanalyze.EdgeLocalSessionLastDay)
+		}
+	}
+
+	return machine, nil
+}
+
+type linuxInfo struct {
+	ao          *engine.Objects
+	cinfo       linuxmachine.Info
+	machine     *engine.Object
+	everyone    *engine.Object
+	localusers  map[string]*engine.Object
+	localgroups map[string]*engine.Object
+}
+
+func (li *linuxInfo) sudoRuleApplies(rule linuxmachine.SudoRule) bool {
+	for _, host := range rule.Hosts {
+		if host == "ALL" ||
+			strings.EqualFold(host, li.cinfo.Machine.Name) ||
+			strings.EqualFold(host, li.cinfo.Machine.DNSHostName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits names like DOMAIN\name and name@domain.com into NetBIOS domain and name, domain is blank for unqualified names
+func (li *linuxInfo) splitName(name string) (string, string) {
+	if domain, account, found := strings.Cut(name, "\\"); found {
+		return strings.ToUpper(domain), account
+	}
+	if at := strings.LastIndex(name, "@"); at > 0 {
+		dnsdomain := name[at+1:]
+		domain := li.cinfo.Machine.Domain
+		if !strings.EqualFold(dnsdomain, li.cinfo.Machine.Realm) {
+			domain = strings.ToUpper(dnsdomain)
+			for _, sssddomain := range li.cinfo.SSSD {
+				if strings.EqualFold(dnsdomain, sssddomain.Name) || strings.EqualFold(dnsdomain, sssddomain.ADDomain) {
+					domain = li.cinfo.Machine.Domain
+					break
+				}
+			}
+			if domain != li.cinfo.Machine.Domain {
+				// Trusted domain, assume the NetBIOS name is the first part of the DNS name
+				domain, _, _ = strings.Cut(domain, ".")
+			}
+		}
+		return domain, name[:at]
+	}
+	return "", name
+}
+
+// Account finds a local user or group, or a domain account if the name is qualified or not local on a domain joined machine
+func (li *linuxInfo) Account(name string) *engine.Object {
+	return li.lookup(name, li.localusers)
+}
+
+// Group finds a local or domain group
+func (li *linuxInfo) Group(name string) *engine.Object {
+	return li.lookup(name, li.localgroups)
+}
+
+func (li *linuxInfo) lookup(name string, local map[string]*engine.Object) *engine.Object {
+	if name == "" {
+		return nil
+	}
+	domain, account := li.splitName(name)
+	if account == "" {
+		return nil
+	}
+	if domain == "" || strings.EqualFold(domain, li.cinfo.Machine.Name) {
+		if o, found := local[account]; found {
+			return o
+		}
+		if o, found := li.localusers[account]; found {
+			return o
+		}
+		if o, found := li.localgroups[account]; found {
+			return o
+		}
+		if domain != "" || !li.cinfo.Machine.IsDomainJoined {
+			return nil
+		}
+		domain = li.cinfo.Machine.Domain
+	}
+	if domain == "" {
+		return nil
+	}
+	o, _ := li.ao.FindOrAdd(
+		engine.DownLevelLogonName, engine.AttributeValueString(domain+"\\"+account),
+	)
+	return o
+}
+
+// DomainAccount finds a domain account, local accounts return nil
+func (li *linuxInfo) DomainAccount(name string) *engine.Object {
+	o := li.Account(name)
+	if o == nil || o.HasAttrValue(engine.DataSource, engine.AttributeValueString(li.cinfo.Machine.Name)) {
+		return nil
+	}
+	return o
+}
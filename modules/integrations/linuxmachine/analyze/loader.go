@@ -0,0 +1,102 @@
+package analyze
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/mailru/easyjson"
+)
+
+const loadername = "LinuxMachine JSON file"
+
+var (
+	loader = engine.AddLoader(func() engine.Loader { return &LinuxMachineLoader{} })
+)
+
+type loaderQueueItem struct {
+	path string
+	cb   engine.ProgressCallbackFunc
+}
+
+type LinuxMachineLoader struct {
+	ao         *engine.Objects
+	done       sync.WaitGroup
+	infostoadd chan loaderQueueItem
+}
+
+func (ld *LinuxMachineLoader) Name() string {
+	return loadername
+}
+
+func (ld *LinuxMachineLoader) Init() error {
+	ld.ao = engine.NewLoaderObjects(ld)
+	ld.infostoadd = make(chan loaderQueueItem, 128)
+
+	for i := 0; i < runtime.NumCPU(); i++ {
+		ld.done.Add(1)
+		go func() {
+			for queueItem := range ld.infostoadd {
+				raw, err := encryption.ReadFile(queueItem.path)
+				if err != nil {
+					ui.Warn().Msgf("Problem reading data from JSON file %v: %v", queueItem, err)
+					continue
+				}
+
+				var cinfo linuxmachine.Info
+				err = easyjson.Unmarshal(raw, &cinfo)
+				if err != nil {
+					ui.Warn().Msgf("Problem unmarshalling data from JSON file %v: %v", queueItem, err)
+					continue
+				}
+
+				_, err = ImportCollectorInfo(ld.ao, cinfo)
+				if err != nil {
+					ui.Warn().Msgf("Problem importing collector info: %v", err)
+					continue
+				}
+
+				// Add progress
+				queueItem.cb(-100, 0)
+			}
+			ld.done.Done()
+		}()
+	}
+
+	return nil
+}
+
+func (ld *LinuxMachineLoader) Close() ([]*engine.Objects, error) {
+	close(ld.infostoadd)
+	ld.done.Wait()
+
+	result := []*engine.Objects{ld.ao}
+	ld.ao = nil
+	return result, nil
+}
+
+func (ld *LinuxMachineLoader) Estimate(path string, cb engine.ProgressCallbackFunc) error {
+	if !strings.HasSuffix(path, linuxmachine.Suffix) {
+		return engine.ErrUninterested
+	}
+
+	// Estimate progress
+	cb(0, -100)
+	return nil
+}
+
+func (ld *LinuxMachineLoader) Load(path string, cb engine.ProgressCallbackFunc) error {
+	if !strings.HasSuffix(path, linuxmachine.Suffix) {
+		return engine.ErrUninterested
+	}
+
+	ld.infostoadd <- loaderQueueItem{
+		path: path,
+		cb:   cb,
+	}
+	return nil
+}
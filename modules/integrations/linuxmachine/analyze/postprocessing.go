@@ -0,0 +1,57 @@
+package analyze
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+// (memberOf=CN=...) including the LDAP_MATCHING_RULE_IN_CHAIN variant
+var memberOfFilter = regexp.MustCompile(`(?i)\(memberof(?::[0-9.]+:)?=([^)]+)\)`)
+
+// Reverses the escaping of special characters in LDAP filter values (\28 for parenthesis etc)
+func unescapeFilterValue(value string) string {
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+2 < len(value) {
+			if b, err := hex.DecodeString(value[i+1 : i+3]); err == nil {
+				result.Write(b)
+				i += 2
+				continue
+			}
+		}
+		result.WriteByte(value[i])
+	}
+	return result.String()
+}
+
+func init() {
+	loader.AddProcessor(func(ao *engine.Objects) {
+		ao.Iterate(func(machine *engine.Object) bool {
+			filter := machine.OneAttrString(SSSDAccessFilter)
+			if filter == "" {
+				return true
+			}
+			matches := memberOfFilter.FindAllStringSubmatch(filter, -1)
+			if len(matches) == 0 {
+				ui.Warn().Msgf("SSSD access filter %v on %v doesn't check group membership, can't tell who is allowed to log on", filter, machine.Label())
+			}
+			for _, match := range matches {
+				dn := unescapeFilterValue(match[1])
+				group, found := ao.Find(engine.DistinguishedName, engine.AttributeValueString(dn))
+				if !found {
+					ui.Warn().Msgf("Could not find group %v from SSSD access filter on %v", dn, machine.Label())
+					continue
+				}
+				group.EdgeTo(machine, EdgeLinuxLogonRights)
+			}
+			return true
+		})
+	},
+		"Link groups in SSSD AD access filters to Linux machines",
+		engine.AfterMerge,
+	)
+}
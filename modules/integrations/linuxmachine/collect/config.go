@@ -0,0 +1,243 @@
+package collect
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+func readLines(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func readFirstLine(name string) (string, error) {
+	lines, err := readLines(name)
+	if err != nil || len(lines) == 0 {
+		return "", err
+	}
+	return strings.TrimSpace(lines[0]), nil
+}
+
+func firstLine(name string) string {
+	line, _ := readFirstLine(name)
+	return line
+}
+
+// KEY=value files like os-release
+func readKeyValues(name string) (map[string]string, error) {
+	result := make(map[string]string)
+	lines, err := readLines(name)
+	for _, line := range lines {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		result[key] = value
+	}
+	return result, err
+}
+
+// INI style files (sssd.conf, smb.conf, krb5.conf), section and key names are lowercased
+func readINI(name string) (map[string]map[string]string, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]map[string]string)
+	section := make(map[string]string)
+	result[""] = section
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			sectionname := strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			section = result[sectionname]
+			if section == nil {
+				section = make(map[string]string)
+				result[sectionname] = section
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		section[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func sssdDomains(conf map[string]map[string]string) []linuxmachine.SSSDomain {
+	var domains []linuxmachine.SSSDomain
+	for section, values := range conf {
+		name, found := strings.CutPrefix(section, "domain/")
+		if !found {
+			continue
+		}
+		fullyqualified, _ := strconv.ParseBool(values["use_fully_qualified_names"])
+		domains = append(domains, linuxmachine.SSSDomain{
+			Name:                   name,
+			ADDomain:               values["ad_domain"],
+			IDProvider:             values["id_provider"],
+			AccessProvider:         values["access_provider"],
+			UseFullyQualifiedNames: fullyqualified,
+			SimpleAllowUsers:       splitList(values["simple_allow_users"]),
+			SimpleAllowGroups:      splitList(values["simple_allow_groups"]),
+			SimpleDenyUsers:        splitList(values["simple_deny_users"]),
+			SimpleDenyGroups:       splitList(values["simple_deny_groups"]),
+			ADAccessFilter:         values["ad_access_filter"],
+		})
+	}
+	return domains
+}
+
+func readPasswd(name string) ([]linuxmachine.User, error) {
+	lines, err := readLines(name)
+	var users []linuxmachine.User
+	for _, line := range lines {
+		fields := strings.Split(line, ":")
+		if len(fields) < 7 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		uid, _ := strconv.Atoi(fields[2])
+		gid, _ := strconv.Atoi(fields[3])
+		users = append(users, linuxmachine.User{
+			Name:  fields[0],
+			UID:   uid,
+			GID:   gid,
+			GECOS: fields[4],
+			Home:  fields[5],
+			Shell: fields[6],
+		})
+	}
+	return users, err
+}
+
+func readGroup(name string) ([]linuxmachine.Group, error) {
+	lines, err := readLines(name)
+	var groups []linuxmachine.Group
+	for _, line := range lines {
+		fields := strings.Split(line, ":")
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		gid, _ := strconv.Atoi(fields[2])
+		groups = append(groups, linuxmachine.Group{
+			Name:    fields[0],
+			GID:     gid,
+			Members: splitList(fields[3]),
+		})
+	}
+	return groups, err
+}
+
+var errMatchBlock = errors.New("conditional Match block reached")
+
+// Settings for all connections from sshd_config, conditional Match blocks are ignored
+func readSSHConfig(root, name string) (linuxmachine.SSHConfig, error) {
+	var config linuxmachine.SSHConfig
+	seen := make(map[string]bool)
+
+	var read func(name string) error
+	read = func(name string) error {
+		lines, err := readLines(filepath.Join(root, name))
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			keyword := strings.ToLower(fields[0])
+			if keyword == "match" {
+				return errMatchBlock
+			}
+			if keyword == "include" {
+				for _, pattern := range fields[1:] {
+					if !filepath.IsAbs(pattern) {
+						pattern = filepath.Join("/etc/ssh", pattern)
+					}
+					matches, _ := filepath.Glob(filepath.Join(root, pattern))
+					for _, match := range matches {
+						relative, _ := filepath.Rel(root, match)
+						if err = read("/" + relative); err == errMatchBlock {
+							return err
+						}
+					}
+				}
+				continue
+			}
+
+			// First value wins, except for the lists which add up
+			value := strings.Join(fields[1:], " ")
+			switch keyword {
+			case "allowusers":
+				config.AllowUsers = append(config.AllowUsers, fields[1:]...)
+			case "allowgroups":
+				config.AllowGroups = append(config.AllowGroups, fields[1:]...)
+			case "denyusers":
+				config.DenyUsers = append(config.DenyUsers, fields[1:]...)
+			case "denygroups":
+				config.DenyGroups = append(config.DenyGroups, fields[1:]...)
+			}
+			if seen[keyword] {
+				continue
+			}
+			seen[keyword] = true
+			switch keyword {
+			case "authorizedkeyscommand":
+				config.AuthorizedKeysCommand = value
+			case "authorizedkeyscommanduser":
+				config.AuthorizedKeysCommandUser = value
+			case "permitrootlogin":
+				config.PermitRootLogin = value
+			case "passwordauthentication":
+				config.PasswordAuthentication = value
+			case "gssapiauthentication":
+				config.GSSAPIAuthentication = value
+			}
+		}
+		return nil
+	}
+
+	err := read(name)
+	if err == errMatchBlock {
+		err = nil
+	}
+	return config, err
+}
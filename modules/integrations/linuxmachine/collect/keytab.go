@@ -0,0 +1,152 @@
+package collect
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+var errKeytabTruncated = errors.New("keytab entry is truncated")
+
+// Reads the principals from a MIT keytab file, the keys themselves are skipped
+func readKeytab(name string) ([]linuxmachine.Principal, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != 5 || (data[1] != 1 && data[1] != 2) {
+		return nil, fmt.Errorf("unsupported keytab format in %v", name)
+	}
+
+	// Version 1 uses native byte order, we assume little endian
+	var order binary.ByteOrder = binary.BigEndian
+	version := data[1]
+	if version == 1 {
+		order = binary.LittleEndian
+	}
+
+	var principals []linuxmachine.Principal
+	data = data[2:]
+	for len(data) >= 4 {
+		// Widened so negating math.MinInt32 doesn't overflow
+		size := int64(int32(order.Uint32(data)))
+		data = data[4:]
+		if size < 0 {
+			// Deleted entry
+			size = -size
+			if size > int64(len(data)) {
+				break
+			}
+			data = data[size:]
+			continue
+		}
+		if size == 0 {
+			// Zero length marks the end of the entries
+			break
+		}
+		if size > int64(len(data)) {
+			return principals, errKeytabTruncated
+		}
+		entry := data[:size]
+		data = data[size:]
+
+		principal, err := parseKeytabEntry(entry, order, version)
+		if err != nil {
+			return principals, err
+		}
+
+		// One entry per key, merge them by principal
+		index := slices.IndexFunc(principals, func(p linuxmachine.Principal) bool {
+			return p.Name == principal.Name && p.Realm == principal.Realm
+		})
+		if index == -1 {
+			principals = append(principals, principal)
+			continue
+		}
+		existing := &principals[index]
+		if principal.KVNO > existing.KVNO {
+			existing.KVNO = principal.KVNO
+			existing.Timestamp = principal.Timestamp
+		}
+		for _, enctype := range principal.EncryptionTypes {
+			if !slices.Contains(existing.EncryptionTypes, enctype) {
+				existing.EncryptionTypes = append(existing.EncryptionTypes, enctype)
+			}
+		}
+	}
+	return principals, nil
+}
+
+func parseKeytabEntry(entry []byte, order binary.ByteOrder, version byte) (linuxmachine.Principal, error) {
+	var principal linuxmachine.Principal
+	readData := func() (string, error) {
+		if len(entry) < 2 {
+			return "", errKeytabTruncated
+		}
+		length := int(order.Uint16(entry))
+		if len(entry) < 2+length {
+			return "", errKeytabTruncated
+		}
+		s := string(entry[2 : 2+length])
+		entry = entry[2+length:]
+		return s, nil
+	}
+
+	if len(entry) < 2 {
+		return principal, errKeytabTruncated
+	}
+	components := int(order.Uint16(entry))
+	entry = entry[2:]
+	if version == 1 {
+		components-- // Count includes the realm
+	}
+	if components < 1 {
+		return principal, errors.New("keytab entry has no principal name components")
+	}
+
+	var err error
+	principal.Realm, err = readData()
+	if err != nil {
+		return principal, err
+	}
+	names := make([]string, components)
+	for i := range names {
+		if names[i], err = readData(); err != nil {
+			return principal, err
+		}
+	}
+	principal.Name = strings.Join(names, "/")
+
+	if version == 2 {
+		if len(entry) < 4 {
+			return principal, errKeytabTruncated
+		}
+		entry = entry[4:] // Name type
+	}
+
+	// Timestamp, 8 bit kvno, key type and key
+	if len(entry) < 4+1+2 {
+		return principal, errKeytabTruncated
+	}
+	principal.Timestamp = time.Unix(int64(order.Uint32(entry)), 0).UTC()
+	principal.KVNO = uint32(entry[4])
+	principal.EncryptionTypes = []uint16{order.Uint16(entry[5:])}
+	entry = entry[7:]
+	if _, err = readData(); err != nil {
+		return principal, err
+	}
+
+	// Optional 32 bit kvno replaces the 8 bit one
+	if len(entry) >= 4 {
+		if kvno := order.Uint32(entry); kvno != 0 {
+			principal.KVNO = kvno
+		}
+	}
+	return principal, nil
+}
@@ -0,0 +1,134 @@
+package collect
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+// Builds a keytab entry with its size prefix
+func keytabEntry(order binary.AppendByteOrder, version byte, count uint16, realm string, components []string, kvno byte, enctype uint16) []byte {
+	var entry []byte
+	data := func(s string) {
+		entry = order.AppendUint16(entry, uint16(len(s)))
+		entry = append(entry, s...)
+	}
+	entry = order.AppendUint16(entry, count)
+	data(realm)
+	for _, component := range components {
+		data(component)
+	}
+	if version == 2 {
+		entry = order.AppendUint32(entry, 1) // KRB5_NT_PRINCIPAL
+	}
+	entry = order.AppendUint32(entry, 1700000000)
+	entry = append(entry, kvno)
+	entry = order.AppendUint16(entry, enctype)
+	data("0123456789abcdef")
+	return append(order.AppendUint32(nil, uint32(len(entry))), entry...)
+}
+
+func keytabFile(version byte, entries ...[]byte) []byte {
+	data := []byte{5, version}
+	for _, entry := range entries {
+		data = append(data, entry...)
+	}
+	return data
+}
+
+func TestReadKeytab(t *testing.T) {
+	be := binary.BigEndian
+	le := binary.LittleEndian
+	stamp := time.Unix(1700000000, 0).UTC()
+
+	host := keytabEntry(be, 2, 2, "CONTOSO.LOCAL", []string{"host", "web01.contoso.local"}, 3, 18)
+	deleted := keytabEntry(be, 2, 1, "CONTOSO.LOCAL", []string{"OLD$"}, 1, 23)
+	binary.BigEndian.PutUint32(deleted, uint32(-int32(len(deleted)-4)))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []linuxmachine.Principal
+		wanterr bool
+	}{
+		{
+			name: "keys merged by principal",
+			data: keytabFile(2,
+				keytabEntry(be, 2, 1, "CONTOSO.LOCAL", []string{"WEB01$"}, 2, 17),
+				keytabEntry(be, 2, 1, "CONTOSO.LOCAL", []string{"WEB01$"}, 3, 18),
+				host,
+			),
+			want: []linuxmachine.Principal{
+				{Name: "WEB01$", Realm: "CONTOSO.LOCAL", KVNO: 3, EncryptionTypes: []uint16{17, 18}, Timestamp: stamp},
+				{Name: "host/web01.contoso.local", Realm: "CONTOSO.LOCAL", KVNO: 3, EncryptionTypes: []uint16{18}, Timestamp: stamp},
+			},
+		},
+		{
+			name: "version 1 counts the realm",
+			data: keytabFile(1, keytabEntry(le, 1, 2, "CONTOSO.LOCAL", []string{"WEB01$"}, 5, 23)),
+			want: []linuxmachine.Principal{
+				{Name: "WEB01$", Realm: "CONTOSO.LOCAL", KVNO: 5, EncryptionTypes: []uint16{23}, Timestamp: stamp},
+			},
+		},
+		{
+			name: "deleted entries are skipped",
+			data: keytabFile(2, deleted, host),
+			want: []linuxmachine.Principal{
+				{Name: "host/web01.contoso.local", Realm: "CONTOSO.LOCAL", KVNO: 3, EncryptionTypes: []uint16{18}, Timestamp: stamp},
+			},
+		},
+		{
+			name: "minimum size",
+			data: keytabFile(2, []byte{0x80, 0, 0, 0, 1, 2, 3}),
+		},
+		{
+			name: "deleted entry past the end",
+			data: keytabFile(2, []byte{0xff, 0xff, 0xff, 0x00, 1, 2, 3}),
+		},
+		{
+			name:    "size past the end",
+			data:    keytabFile(2, host[:len(host)-1]),
+			wanterr: true,
+		},
+		{
+			name:    "version 1 without components",
+			data:    keytabFile(1, keytabEntry(le, 1, 0, "CONTOSO.LOCAL", nil, 5, 23)),
+			wanterr: true,
+		},
+		{
+			name:    "version 2 without components",
+			data:    keytabFile(2, keytabEntry(be, 2, 0, "CONTOSO.LOCAL", nil, 5, 23)),
+			wanterr: true,
+		},
+		{
+			name:    "more components than data",
+			data:    keytabFile(2, keytabEntry(be, 2, 40, "CONTOSO.LOCAL", []string{"WEB01$"}, 5, 23)),
+			wanterr: true,
+		},
+		{
+			name:    "unsupported format",
+			data:    []byte{5, 3},
+			wanterr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "krb5.keytab")
+			if err := os.WriteFile(name, tt.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readKeytab(name)
+			if (err != nil) != tt.wanterr {
+				t.Fatalf("readKeytab() error = %v, wanterr %v", err, tt.wanterr)
+			}
+			if !tt.wanterr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readKeytab() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package collect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/basedata"
+	clicollect "github.com/lkarlslund/adalanche/modules/cli/collect"
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cmd = &cobra.Command{
+		Use:   "linuxmachine",
+		Short: "Gathers local information about an AD joined Linux machine (sssd, winbind) - run as root for complete results",
+		RunE:  Execute,
+	}
+
+	root string
+)
+
+func init() {
+	cmd.Flags().StringVar(&root, "root", "/", "Collect from a Linux filesystem mounted here instead of the running system")
+	clicollect.Collect.AddCommand(cmd)
+}
+
+func Execute(cmd *cobra.Command, args []string) error {
+	var outputpath string
+	if op := cmd.InheritedFlags().Lookup("datapath"); op != nil {
+		outputpath = op.Value.String()
+	}
+
+	if outputpath == "" {
+		ui.Warn().Msg("Missing -outputpath parameter - writing file to current directory")
+		outputpath = "."
+	}
+
+	err := os.MkdirAll(outputpath, 0700)
+	if err != nil {
+		return fmt.Errorf("Problem accessing output folder: %v", err)
+	}
+
+	info, err := Collect(root)
+	if err != nil {
+		return err
+	}
+
+	targetname := info.Machine.Name + linuxmachine.Suffix
+	if info.Machine.IsDomainJoined {
+		targetname = info.Machine.Name + "$" + info.Machine.Domain + linuxmachine.Suffix
+	}
+	output, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Problem marshalling JSON: %v", err)
+	}
+
+	outputfile := filepath.Join(outputpath, targetname)
+	err = encryption.WriteFile(outputfile, output, 0600)
+	if err != nil {
+		return fmt.Errorf("Problem writing to file %v: %v", outputfile, err)
+	}
+	ui.Info().Msgf("Information collected to file %v", outputfile)
+	return nil
+}
+
+// Collect gathers information from the Linux filesystem at root
+func Collect(root string) (linuxmachine.Info, error) {
+	path := func(name string) string {
+		return filepath.Join(root, name)
+	}
+
+	info := linuxmachine.Info{
+		Common:                 basedata.GetCommonData(),
+		UnprivilegedCollection: os.Geteuid() != 0,
+	}
+
+	if info.UnprivilegedCollection {
+		ui.Warn().Msg("Collection is being run without root privileges. Sudoers, keytab and SSSD configuration will probably be missing, which will affect analysis results.")
+	}
+
+	// MACHINE
+	hostname, _ := readFirstLine(path("/etc/hostname"))
+	if hostname == "" || root == "/" {
+		hostname, _ = os.Hostname()
+	}
+	name, _, _ := strings.Cut(hostname, ".")
+	info.Machine = linuxmachine.Machine{
+		Name:          strings.ToUpper(name),
+		Architecture:  runtime.GOARCH,
+		KernelVersion: firstLine(path("/proc/sys/kernel/osrelease")),
+	}
+	if strings.Contains(hostname, ".") {
+		info.Machine.DNSHostName = strings.ToLower(hostname)
+	}
+
+	osrelease, err := readKeyValues(path("/etc/os-release"))
+	if err != nil {
+		ui.Warn().Msgf("Problem reading OS release information: %v", err)
+	}
+	info.Machine.OSName = osrelease["PRETTY_NAME"]
+	info.Machine.OSVersion = osrelease["VERSION_ID"]
+
+	// ACCOUNTS
+	info.Users, err = readPasswd(path("/etc/passwd"))
+	if err != nil {
+		ui.Warn().Msgf("Problem reading local users: %v", err)
+	}
+	info.Groups, err = readGroup(path("/etc/group"))
+	if err != nil {
+		ui.Warn().Msgf("Problem reading local groups: %v", err)
+	}
+
+	// SUDO
+	info.Sudoers, err = readSudoers(root, "/etc/sudoers")
+	if err != nil {
+		ui.Warn().Msgf("Problem reading sudoers: %v", err)
+	}
+
+	// DOMAIN MEMBERSHIP
+	sssdconf, err := readINI(path("/etc/sssd/sssd.conf"))
+	if err == nil {
+		info.SSSD = sssdDomains(sssdconf)
+		info.Machine.JoinProvider = "sssd"
+	} else if !os.IsNotExist(err) {
+		ui.Warn().Msgf("Problem reading SSSD configuration: %v", err)
+	}
+
+	smbconf, err := readINI(path("/etc/samba/smb.conf"))
+	if err == nil {
+		global := smbconf["global"]
+		info.Machine.Domain = strings.ToUpper(global["workgroup"])
+		info.Machine.Realm = strings.ToUpper(global["realm"])
+		if strings.EqualFold(global["security"], "ads") && info.Machine.JoinProvider == "" {
+			info.Machine.JoinProvider = "winbind"
+		}
+	}
+
+	if info.Machine.Realm == "" {
+		for _, domain := range info.SSSD {
+			if domain.ADDomain != "" {
+				info.Machine.Realm = strings.ToUpper(domain.ADDomain)
+				break
+			}
+		}
+	}
+	if info.Machine.Realm == "" {
+		if krb5conf, err := readINI(path("/etc/krb5.conf")); err == nil {
+			info.Machine.Realm = strings.ToUpper(krb5conf["libdefaults"]["default_realm"])
+		}
+	}
+
+	info.Keytab, err = readKeytab(path("/etc/krb5.keytab"))
+	if err != nil && !os.IsNotExist(err) {
+		ui.Warn().Msgf("Problem reading system keytab: %v", err)
+	}
+	for _, principal := range info.Keytab {
+		if strings.HasSuffix(principal.Name, "$") && !strings.Contains(principal.Name, "/") {
+			info.Machine.MachineAccount = strings.ToUpper(principal.Name)
+			if info.Machine.Realm == "" {
+				info.Machine.Realm = strings.ToUpper(principal.Realm)
+			}
+			break
+		}
+	}
+
+	info.Machine.IsDomainJoined = info.Machine.MachineAccount != "" || (info.Machine.JoinProvider != "" && info.Machine.Realm != "")
+	if info.Machine.IsDomainJoined {
+		if info.Machine.Domain == "" {
+			// Best guess, the NetBIOS name is usually the first part of the DNS name
+			info.Machine.Domain, _, _ = strings.Cut(info.Machine.Realm, ".")
+		}
+		if info.Machine.DNSHostName == "" && info.Machine.Realm != "" {
+			info.Machine.DNSHostName = strings.ToLower(name + "." + info.Machine.Realm)
+		}
+	}
+
+	// SSH
+	info.SSH, err = readSSHConfig(root, "/etc/ssh/sshd_config")
+	if err != nil && !os.IsNotExist(err) {
+		ui.Warn().Msgf("Problem reading SSH daemon configuration: %v", err)
+	}
+
+	// SESSIONS
+	info.LoginPopularity, info.ActiveSessions, err = readWtmp(time.Now(), path("/var/log/wtmp.1"), path("/var/log/wtmp"))
+	if err != nil {
+		ui.Warn().Msgf("Problem reading login history: %v", err)
+	}
+
+	// NETWORK
+	if root == "/" {
+		interfaces, err := net.Interfaces()
+		if err != nil {
+			ui.Warn().Msgf("Problem getting network adapter information: %v", err)
+		}
+		for _, iface := range interfaces {
+			if iface.Flags&net.FlagLoopback != 0 {
+				continue
+			}
+			addrs, _ := iface.Addrs()
+			var addrstrings []string
+			for _, addr := range addrs {
+				addrstrings = append(addrstrings, addr.String())
+			}
+			info.Network.NetworkInterfaces = append(info.Network.NetworkInterfaces, linuxmachine.NetworkInterfaceInfo{
+				Name:       iface.Name,
+				MACAddress: iface.HardwareAddr.String(),
+				Flags:      uint(iface.Flags),
+				Addresses:  addrstrings,
+			})
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,314 @@
+package collect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+var sudoTags = map[string]bool{
+	"NOPASSWD": true, "PASSWD": true,
+	"NOEXEC": true, "EXEC": true,
+	"SETENV": true, "NOSETENV": true,
+	"LOG_INPUT": true, "NOLOG_INPUT": true,
+	"LOG_OUTPUT": true, "NOLOG_OUTPUT": true,
+	"MAIL": true, "NOMAIL": true,
+	"FOLLOW": true, "NOFOLLOW": true,
+	"INTERCEPT": true, "NOINTERCEPT": true,
+}
+
+type sudoersParser struct {
+	root    string
+	aliases map[string]map[string][]string // User_Alias, Runas_Alias, Host_Alias, Cmnd_Alias -> name -> items
+	rules   []linuxmachine.SudoRule
+	depth   int
+}
+
+// Reads sudoers and everything it includes, returning the user specifications with aliases expanded
+func readSudoers(root, name string) ([]linuxmachine.SudoRule, error) {
+	sp := sudoersParser{
+		root: root,
+		aliases: map[string]map[string][]string{
+			"User_Alias":  {},
+			"Runas_Alias": {},
+			"Host_Alias":  {},
+			"Cmnd_Alias":  {},
+		},
+	}
+	err := sp.parseFile(name)
+	return sp.rules, err
+}
+
+func (sp *sudoersParser) parseFile(name string) error {
+	if sp.depth > 8 {
+		ui.Warn().Msgf("Sudoers include nesting too deep at %v, skipping it", name)
+		return nil
+	}
+	sp.depth++
+	defer func() { sp.depth-- }()
+
+	lines, err := readLines(filepath.Join(sp.root, name))
+	if err != nil {
+		return err
+	}
+
+	var line string
+	for _, l := range lines {
+		// Line continuations
+		if strings.HasSuffix(l, "\\") {
+			line += strings.TrimSuffix(l, "\\")
+			continue
+		}
+		line = strings.TrimSpace(line + l)
+		current := line
+		line = ""
+
+		if include, found := cutDirective(current, "includedir"); found {
+			sp.includeDir(name, include)
+			continue
+		}
+		if include, found := cutDirective(current, "include"); found {
+			if err := sp.parseFile(sp.includePath(name, include)); err != nil {
+				ui.Warn().Msgf("Problem reading included sudoers file %v: %v", include, err)
+			}
+			continue
+		}
+
+		current = strings.TrimSpace(stripSudoersComment(current))
+		if current == "" || strings.HasPrefix(current, "Defaults") {
+			continue
+		}
+
+		keyword, definition, _ := strings.Cut(current, " ")
+		if keyword == "Cmd_Alias" {
+			keyword = "Cmnd_Alias"
+		}
+		if aliases, found := sp.aliases[keyword]; found {
+			// NAME = item, item : NAME2 = item
+			for _, part := range splitSudoers(definition, ':') {
+				aliasname, items, found := strings.Cut(part, "=")
+				if !found {
+					continue
+				}
+				aliases[strings.TrimSpace(aliasname)] = splitSudoers(items, ',')
+			}
+			continue
+		}
+
+		sp.parseUserSpec(name, current)
+	}
+	return nil
+}
+
+// #include, @include, #includedir and @includedir
+func cutDirective(line, directive string) (string, bool) {
+	for _, prefix := range []string{"#", "@"} {
+		if rest, found := strings.CutPrefix(line, prefix+directive+" "); found {
+			return strings.Trim(strings.TrimSpace(rest), `"`), true
+		}
+	}
+	return "", false
+}
+
+func (sp *sudoersParser) includePath(current, include string) string {
+	include = strings.ReplaceAll(include, "%h", firstLine(filepath.Join(sp.root, "/etc/hostname")))
+	if !filepath.IsAbs(include) {
+		include = filepath.Join(filepath.Dir(current), include)
+	}
+	return include
+}
+
+func (sp *sudoersParser) includeDir(current, dir string) {
+	dir = sp.includePath(current, dir)
+	entries, err := os.ReadDir(filepath.Join(sp.root, dir))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			ui.Warn().Msgf("Problem reading included sudoers folder %v: %v", dir, err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		// Same rules as sudo, files ending in ~ or containing a dot are skipped
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), "~") || strings.Contains(entry.Name(), ".") {
+			continue
+		}
+		if err := sp.parseFile(filepath.Join(dir, entry.Name())); err != nil {
+			ui.Warn().Msgf("Problem reading included sudoers file %v: %v", entry.Name(), err)
+		}
+	}
+}
+
+// Removes comments, but not #uid references
+func stripSudoersComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '#':
+			if i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9' {
+				continue
+			}
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Splits on separator outside of parenthesis and quotes, honoring backslash escapes. Items are trimmed and blanks dropped
+func splitSudoers(s string, separator byte) []string {
+	var result []string
+	var depth, start int
+	var quoted bool
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			if quoted && s[i] != '"' {
+				continue
+			}
+			switch s[i] {
+			case '\\':
+				i++
+				continue
+			case '"':
+				quoted = !quoted
+				continue
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case separator:
+				// Colons are also used in tags (NOPASSWD:), runas groups and %:group, so as a separator they need a space before them
+				if depth > 0 || (separator == ':' && (i == 0 || (s[i-1] != ' ' && s[i-1] != '\t'))) {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if item := strings.TrimSpace(s[start:min(i, len(s))]); item != "" {
+			result = append(result, item)
+		}
+		start = i + 1
+	}
+	return result
+}
+
+func unescapeSudoers(s string) string {
+	var result strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			continue
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+		}
+		result.WriteByte(s[i])
+	}
+	return result.String()
+}
+
+// Expands aliases recursively, negated items are dropped as they only make the rule narrower
+func (sp *sudoersParser) expand(kind string, items []string, depth int) []string {
+	var result []string
+	for _, item := range items {
+		if strings.HasPrefix(item, "!") {
+			continue
+		}
+		if members, found := sp.aliases[kind][item]; found && depth < 8 {
+			result = append(result, sp.expand(kind, members, depth+1)...)
+			continue
+		}
+		result = append(result, unescapeSudoers(item))
+	}
+	return result
+}
+
+// user_list host_list = (runas) TAG: cmnd, cmnd : host_list = cmnd
+func (sp *sudoersParser) parseUserSpec(source, spec string) {
+	// User list ends at the first whitespace that isn't part of a comma separated list
+	var userend int
+	var quoted bool
+	for userend < len(spec) {
+		switch spec[userend] {
+		case '\\':
+			userend += 2
+			continue
+		case '"':
+			quoted = !quoted
+		}
+		if !quoted && (spec[userend] == ' ' || spec[userend] == '\t') {
+			before := strings.TrimSpace(spec[:userend])
+			after := strings.TrimSpace(spec[userend:])
+			if !strings.HasSuffix(before, ",") && !strings.HasPrefix(after, ",") {
+				break
+			}
+		}
+		userend++
+	}
+	if userend >= len(spec) {
+		ui.Debug().Msgf("Can't parse sudoers line in %v: %v", source, spec)
+		return
+	}
+	users := sp.expand("User_Alias", splitSudoers(spec[:userend], ','), 0)
+
+	for _, hostspec := range splitSudoers(spec[userend:], ':') {
+		hostlist, cmndspecs, found := strings.Cut(hostspec, "=")
+		if !found {
+			// Probably a colon inside a command, we're not that clever
+			continue
+		}
+		hosts := sp.expand("Host_Alias", splitSudoers(hostlist, ','), 0)
+
+		var runas []string
+		var nopassword bool
+		var rule *linuxmachine.SudoRule
+		for _, cmndspec := range splitSudoers(cmndspecs, ',') {
+			newrule := rule == nil
+			if strings.HasPrefix(cmndspec, "(") {
+				runasspec, rest, found := strings.Cut(cmndspec[1:], ")")
+				if !found {
+					ui.Debug().Msgf("Unterminated runas specification in %v: %v", source, spec)
+					break
+				}
+				runasusers, runasgroups, _ := strings.Cut(runasspec, ":")
+				runas = sp.expand("Runas_Alias", splitSudoers(runasusers, ','), 0)
+				for _, group := range sp.expand("Runas_Alias", splitSudoers(runasgroups, ','), 0) {
+					runas = append(runas, "%"+group)
+				}
+				cmndspec = strings.TrimSpace(rest)
+				newrule = true
+			}
+
+			// Tags and options like ROLE=x before the command
+			for {
+				word, rest, _ := strings.Cut(cmndspec, " ")
+				if tag, afterTag, found := strings.Cut(word, ":"); found && sudoTags[tag] {
+					if tag == "NOPASSWD" || tag == "PASSWD" {
+						newrule = newrule || nopassword != (tag == "NOPASSWD")
+						nopassword = tag == "NOPASSWD"
+					}
+					rest = afterTag + " " + rest
+				} else if option, _, found := strings.Cut(word, "="); !found || option != strings.ToUpper(option) || rest == "" {
+					break
+				}
+				cmndspec = strings.TrimSpace(rest)
+			}
+
+			if newrule {
+				sp.rules = append(sp.rules, linuxmachine.SudoRule{
+					Source:     source,
+					Users:      users,
+					Hosts:      hosts,
+					RunAs:      runas,
+					NoPassword: nopassword,
+				})
+				rule = &sp.rules[len(sp.rules)-1]
+			}
+			rule.Commands = append(rule.Commands, sp.expand("Cmnd_Alias", []string{cmndspec}, 0)...)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package collect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+func TestReadSudoers(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []linuxmachine.SudoRule
+	}{
+		{
+			name: "defaults and comments",
+			files: map[string]string{
+				"/etc/sudoers": "Defaults env_reset\n" +
+					"# root ALL=(ALL) ALL\n" +
+					"root ALL=(ALL:ALL) ALL # trailing comment\n" +
+					"%sudo ALL=(ALL:ALL) ALL\n",
+			},
+			want: []linuxmachine.SudoRule{
+				{Source: "/etc/sudoers", Users: []string{"root"}, Hosts: []string{"ALL"}, RunAs: []string{"ALL", "%ALL"}, Commands: []string{"ALL"}},
+				{Source: "/etc/sudoers", Users: []string{"%sudo"}, Hosts: []string{"ALL"}, RunAs: []string{"ALL", "%ALL"}, Commands: []string{"ALL"}},
+			},
+		},
+		{
+			name: "aliases and tags",
+			files: map[string]string{
+				"/etc/sudoers": "User_Alias ADMINS = alice, bob : OPS = %ops\n" +
+					"Cmnd_Alias RESTART = /usr/bin/systemctl restart *, \\\n" +
+					"    /usr/sbin/reboot\n" +
+					"ADMINS, OPS, !mallory web01 = (www-data) NOPASSWD: RESTART, PASSWD: /bin/ls\n" +
+					"#1001 ALL = /usr/bin/id\n",
+			},
+			want: []linuxmachine.SudoRule{
+				{Source: "/etc/sudoers", Users: []string{"alice", "bob", "%ops"}, Hosts: []string{"web01"}, RunAs: []string{"www-data"}, NoPassword: true, Commands: []string{"/usr/bin/systemctl restart *", "/usr/sbin/reboot"}},
+				{Source: "/etc/sudoers", Users: []string{"alice", "bob", "%ops"}, Hosts: []string{"web01"}, RunAs: []string{"www-data"}, Commands: []string{"/bin/ls"}},
+				{Source: "/etc/sudoers", Users: []string{"#1001"}, Hosts: []string{"ALL"}, Commands: []string{"/usr/bin/id"}},
+			},
+		},
+		{
+			name: "includes",
+			files: map[string]string{
+				"/etc/sudoers":           "@includedir /etc/sudoers.d\n#include extra\n",
+				"/etc/extra":             "carol ALL = ALL\n",
+				"/etc/sudoers.d/admins":  "dave ALL = ALL\n",
+				"/etc/sudoers.d/old~":    "skipped ALL = ALL\n",
+				"/etc/sudoers.d/x.dpkg":  "skipped ALL = ALL\n",
+				"/etc/sudoers.d/missing": "#include /etc/does-not-exist\n",
+			},
+			want: []linuxmachine.SudoRule{
+				{Source: "/etc/sudoers.d/admins", Users: []string{"dave"}, Hosts: []string{"ALL"}, Commands: []string{"ALL"}},
+				{Source: "/etc/extra", Users: []string{"carol"}, Hosts: []string{"ALL"}, Commands: []string{"ALL"}},
+			},
+		},
+		{
+			name: "include loop",
+			files: map[string]string{
+				"/etc/sudoers": "eve ALL = ALL\n#include /etc/sudoers\n",
+			},
+			want: func() []linuxmachine.SudoRule {
+				var rules []linuxmachine.SudoRule
+				for i := 0; i < 9; i++ {
+					rules = append(rules, linuxmachine.SudoRule{Source: "/etc/sudoers", Users: []string{"eve"}, Hosts: []string{"ALL"}, Commands: []string{"ALL"}})
+				}
+				return rules
+			}(),
+		},
+		{
+			name: "malformed lines",
+			files: map[string]string{
+				"/etc/sudoers": "frank\n" +
+					"frank ALL\n" +
+					"User_Alias BROKEN\n" +
+					"grace ALL = (root /bin/sh\n" +
+					"heidi ALL = ALL\n" +
+					"\\\n",
+			},
+			want: []linuxmachine.SudoRule{
+				{Source: "/etc/sudoers", Users: []string{"heidi"}, Hosts: []string{"ALL"}, Commands: []string{"ALL"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				name = filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := readSudoers(root, "/etc/sudoers")
+			if err != nil {
+				t.Fatalf("readSudoers() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readSudoers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := readSudoers(t.TempDir(), "/etc/sudoers"); !os.IsNotExist(err) {
+		t.Errorf("readSudoers() on missing file error = %v, want not exist", err)
+	}
+}
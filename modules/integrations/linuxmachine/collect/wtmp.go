@@ -0,0 +1,127 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+// Linux utmp record layout (same on 32 and 64 bit)
+const (
+	utmpSize = 384
+
+	utmpBootTime    = 2
+	utmpUserProcess = 7
+	utmpDeadProcess = 8
+)
+
+type utmpRecord struct {
+	Type int16
+	Line string
+	User string
+	Host string
+	Time time.Time
+}
+
+func parseUtmp(record []byte) utmpRecord {
+	cstring := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i != -1 {
+			b = b[:i]
+		}
+		return string(b)
+	}
+	return utmpRecord{
+		Type: int16(binary.LittleEndian.Uint16(record[0:])),
+		Line: cstring(record[8:40]),
+		User: cstring(record[44:76]),
+		Host: cstring(record[76:332]),
+		Time: time.Unix(int64(int32(binary.LittleEndian.Uint32(record[340:]))), 0).UTC(),
+	}
+}
+
+// Counts logins per user in the last day, week and month, and finds sessions that are still open. Files are read in order, oldest first
+func readWtmp(now time.Time, names ...string) (linuxmachine.LoginPopularity, []linuxmachine.Session, error) {
+	day := make(map[string]uint64)
+	week := make(map[string]uint64)
+	month := make(map[string]uint64)
+	active := make(map[string]linuxmachine.Session) // by line
+
+	var lasterr error
+	var found bool
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				lasterr = err
+			}
+			continue
+		}
+		found = true
+		for len(data) >= utmpSize {
+			record := parseUtmp(data[:utmpSize])
+			data = data[utmpSize:]
+
+			switch record.Type {
+			case utmpBootTime:
+				clear(active)
+			case utmpDeadProcess:
+				delete(active, record.Line)
+			case utmpUserProcess:
+				if record.User == "" {
+					continue
+				}
+				active[record.Line] = linuxmachine.Session{
+					Name:    record.User,
+					Line:    record.Line,
+					Host:    record.Host,
+					Started: record.Time,
+				}
+				age := now.Sub(record.Time)
+				if age <= 24*time.Hour {
+					day[record.User]++
+				}
+				if age <= 7*24*time.Hour {
+					week[record.User]++
+				}
+				if age <= 30*24*time.Hour {
+					month[record.User]++
+				}
+			}
+		}
+	}
+	if !found && lasterr == nil {
+		lasterr = os.ErrNotExist
+	}
+
+	counts := func(logins map[string]uint64) []linuxmachine.LoginCount {
+		var result []linuxmachine.LoginCount
+		for name, count := range logins {
+			result = append(result, linuxmachine.LoginCount{Name: name, Count: count})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].Count == result[j].Count {
+				return result[i].Name < result[j].Name
+			}
+			return result[i].Count > result[j].Count
+		})
+		return result
+	}
+
+	var sessions []linuxmachine.Session
+	for _, session := range active {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Started.Before(sessions[j].Started)
+	})
+
+	return linuxmachine.LoginPopularity{
+		Day:   counts(day),
+		Week:  counts(week),
+		Month: counts(month),
+	}, sessions, lasterr
+}
@@ -0,0 +1,128 @@
+package collect
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/integrations/linuxmachine"
+)
+
+func utmpBytes(kind int16, line, user, host string, when time.Time) []byte {
+	record := make([]byte, utmpSize)
+	binary.LittleEndian.PutUint16(record[0:], uint16(kind))
+	copy(record[8:40], line)
+	copy(record[44:76], user)
+	copy(record[76:332], host)
+	binary.LittleEndian.PutUint32(record[340:], uint32(when.Unix()))
+	return record
+}
+
+func TestReadWtmp(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	hour := now.Add(-time.Hour)
+	days := now.Add(-3 * 24 * time.Hour)
+	weeks := now.Add(-14 * 24 * time.Hour)
+	months := now.Add(-60 * 24 * time.Hour)
+
+	join := func(records ...[]byte) []byte {
+		var data []byte
+		for _, record := range records {
+			data = append(data, record...)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		files    [][]byte
+		want     linuxmachine.LoginPopularity
+		sessions []linuxmachine.Session
+	}{
+		{
+			name: "logins and logouts",
+			files: [][]byte{join(
+				utmpBytes(utmpUserProcess, "pts/0", "alice", "10.0.0.1", hour),
+				utmpBytes(utmpDeadProcess, "pts/0", "", "", hour),
+				utmpBytes(utmpUserProcess, "pts/1", "alice", "10.0.0.1", days),
+				utmpBytes(utmpUserProcess, "pts/2", "bob", "10.0.0.2", weeks),
+				utmpBytes(utmpUserProcess, "pts/3", "carol", "", months),
+			)},
+			want: linuxmachine.LoginPopularity{
+				Day:   []linuxmachine.LoginCount{{Name: "alice", Count: 1}},
+				Week:  []linuxmachine.LoginCount{{Name: "alice", Count: 2}},
+				Month: []linuxmachine.LoginCount{{Name: "alice", Count: 2}, {Name: "bob", Count: 1}},
+			},
+			sessions: []linuxmachine.Session{
+				{Name: "carol", Line: "pts/3", Started: months},
+				{Name: "bob", Line: "pts/2", Host: "10.0.0.2", Started: weeks},
+				{Name: "alice", Line: "pts/1", Host: "10.0.0.1", Started: days},
+			},
+		},
+		{
+			name: "reboot closes sessions across files",
+			files: [][]byte{
+				utmpBytes(utmpUserProcess, "tty1", "alice", "", weeks),
+				join(
+					utmpBytes(utmpBootTime, "~", "reboot", "", days),
+					utmpBytes(utmpUserProcess, "tty1", "bob", "", hour),
+				),
+			},
+			want: linuxmachine.LoginPopularity{
+				Day:   []linuxmachine.LoginCount{{Name: "bob", Count: 1}},
+				Week:  []linuxmachine.LoginCount{{Name: "bob", Count: 1}},
+				Month: []linuxmachine.LoginCount{{Name: "alice", Count: 1}, {Name: "bob", Count: 1}},
+			},
+			sessions: []linuxmachine.Session{
+				{Name: "bob", Line: "tty1", Started: hour},
+			},
+		},
+		{
+			name: "truncated and garbage records",
+			files: [][]byte{join(
+				utmpBytes(utmpUserProcess, "pts/0", "", "", hour),
+				utmpBytes(12345, "pts/1", "mallory", "", hour),
+				utmpBytes(utmpUserProcess, "pts/2", "alice", "", hour),
+				utmpBytes(utmpUserProcess, "pts/3", "bob", "", hour)[:utmpSize-1],
+			)},
+			want: linuxmachine.LoginPopularity{
+				Day:   []linuxmachine.LoginCount{{Name: "alice", Count: 1}},
+				Week:  []linuxmachine.LoginCount{{Name: "alice", Count: 1}},
+				Month: []linuxmachine.LoginCount{{Name: "alice", Count: 1}},
+			},
+			sessions: []linuxmachine.Session{
+				{Name: "alice", Line: "pts/2", Started: hour},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var names []string
+			for i, data := range tt.files {
+				name := filepath.Join(dir, "wtmp"+string(rune('a'+i)))
+				if err := os.WriteFile(name, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, name)
+			}
+			got, sessions, err := readWtmp(now, names...)
+			if err != nil {
+				t.Fatalf("readWtmp() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readWtmp() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(sessions, tt.sessions) {
+				t.Errorf("readWtmp() sessions = %+v, want %+v", sessions, tt.sessions)
+			}
+		})
+	}
+
+	if _, _, err := readWtmp(now, filepath.Join(t.TempDir(), "wtmp")); !os.IsNotExist(err) {
+		t.Errorf("readWtmp() on missing file error = %v, want not exist", err)
+	}
+}
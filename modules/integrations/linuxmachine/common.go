@@ -0,0 +1,3 @@
+package linuxmachine
+
+const Suffix = ".linuxmachine.json"
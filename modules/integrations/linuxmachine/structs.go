@@ -0,0 +1,131 @@
+package linuxmachine
+
+//go:generate easyjson -all structs.go
+
+import (
+	"time"
+
+	"github.com/lkarlslund/adalanche/modules/basedata"
+)
+
+type Info struct {
+	basedata.Common
+
+	UnprivilegedCollection bool `json:",omitempty"` // True if the collector ran without root, so some data will be missing
+
+	Machine Machine            `json:",omitempty"`
+	Network NetworkInformation `json:",omitempty"`
+
+	LoginPopularity LoginPopularity `json:",omitempty"`
+	ActiveSessions  []Session       `json:",omitempty"` // Logins in wtmp without a matching logout
+
+	Users   []User      `json:",omitempty"`
+	Groups  []Group     `json:",omitempty"`
+	Sudoers []SudoRule  `json:",omitempty"`
+	SSSD    []SSSDomain `json:",omitempty"`
+	SSH     SSHConfig   `json:",omitempty"`
+	Keytab  []Principal `json:",omitempty"`
+}
+
+type Machine struct {
+	Name           string `json:",omitempty"` // Short hostname
+	DNSHostName    string `json:",omitempty"`
+	Domain         string `json:",omitempty"` // NetBIOS name of the AD domain
+	Realm          string `json:",omitempty"` // Kerberos realm / DNS name of the AD domain
+	IsDomainJoined bool   `json:",omitempty"`
+	JoinProvider   string `json:",omitempty"` // sssd or winbind
+	MachineAccount string `json:",omitempty"` // sAMAccountName of the computer account, from the keytab
+
+	OSName        string `json:",omitempty"`
+	OSVersion     string `json:",omitempty"`
+	KernelVersion string `json:",omitempty"`
+	Architecture  string `json:",omitempty"`
+}
+
+type NetworkInformation struct {
+	NetworkInterfaces []NetworkInterfaceInfo `json:",omitempty"`
+}
+
+type NetworkInterfaceInfo struct {
+	Name       string   `json:",omitempty"`
+	MACAddress string   `json:",omitempty"`
+	Flags      uint     `json:",omitempty"`
+	Addresses  []string `json:",omitempty"`
+}
+
+type LoginPopularity struct {
+	Day   []LoginCount
+	Week  []LoginCount
+	Month []LoginCount
+}
+
+type LoginCount struct {
+	Name  string `json:",omitempty"`
+	Count uint64 `json:",omitempty"`
+}
+
+type Session struct {
+	Name    string    `json:",omitempty"`
+	Line    string    `json:",omitempty"`
+	Host    string    `json:",omitempty"`
+	Started time.Time `json:",omitempty"`
+}
+
+type User struct {
+	Name  string `json:",omitempty"`
+	UID   int    `json:",omitempty"`
+	GID   int    `json:",omitempty"`
+	GECOS string `json:",omitempty"`
+	Home  string `json:",omitempty"`
+	Shell string `json:",omitempty"`
+}
+
+type Group struct {
+	Name    string   `json:",omitempty"`
+	GID     int      `json:",omitempty"`
+	Members []string `json:",omitempty"`
+}
+
+// One user specification from sudoers with aliases expanded. Users are names, %group, %:group (non-Unix group), #uid, %#gid or ALL
+type SudoRule struct {
+	Source     string   `json:",omitempty"` // File the rule was read from
+	Users      []string `json:",omitempty"`
+	Hosts      []string `json:",omitempty"`
+	RunAs      []string `json:",omitempty"` // Blank means root
+	Commands   []string `json:",omitempty"`
+	NoPassword bool     `json:",omitempty"`
+}
+
+type SSSDomain struct {
+	Name                   string   `json:",omitempty"`
+	ADDomain               string   `json:",omitempty"`
+	IDProvider             string   `json:",omitempty"`
+	AccessProvider         string   `json:",omitempty"`
+	UseFullyQualifiedNames bool     `json:",omitempty"`
+	SimpleAllowUsers       []string `json:",omitempty"`
+	SimpleAllowGroups      []string `json:",omitempty"`
+	SimpleDenyUsers        []string `json:",omitempty"`
+	SimpleDenyGroups       []string `json:",omitempty"`
+	ADAccessFilter         string   `json:",omitempty"`
+}
+
+type SSHConfig struct {
+	AuthorizedKeysCommand     string   `json:",omitempty"`
+	AuthorizedKeysCommandUser string   `json:",omitempty"`
+	PermitRootLogin           string   `json:",omitempty"`
+	PasswordAuthentication    string   `json:",omitempty"`
+	GSSAPIAuthentication      string   `json:",omitempty"`
+	AllowUsers                []string `json:",omitempty"`
+	AllowGroups               []string `json:",omitempty"`
+	DenyUsers                 []string `json:",omitempty"`
+	DenyGroups                []string `json:",omitempty"`
+}
+
+// Kerberos principal found in the system keytab, keys are never collected
+type Principal struct {
+	Name            string    `json:",omitempty"` // Without realm, i.e. HOST$ or host/fqdn
+	Realm           string    `json:",omitempty"`
+	KVNO            uint32    `json:",omitempty"`
+	EncryptionTypes []uint16  `json:",omitempty"`
+	Timestamp       time.Time `json:",omitempty"`
+}
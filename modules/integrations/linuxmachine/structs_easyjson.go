@@ -0,0 +1,2418 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package linuxmachine
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(in *jlexer.Lexer, out *User) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "UID":
+			out.UID = int(in.Int())
+		case "GID":
+			out.GID = int(in.Int())
+		case "GECOS":
+			out.GECOS = string(in.String())
+		case "Home":
+			out.Home = string(in.String())
+		case "Shell":
+			out.Shell = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(out *jwriter.Writer, in User) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.UID != 0 {
+		const prefix string = ",\"UID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.UID))
+	}
+	if in.GID != 0 {
+		const prefix string = ",\"GID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.GID))
+	}
+	if in.GECOS != "" {
+		const prefix string = ",\"GECOS\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.GECOS))
+	}
+	if in.Home != "" {
+		const prefix string = ",\"Home\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Home))
+	}
+	if in.Shell != "" {
+		const prefix string = ",\"Shell\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Shell))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v User) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *User) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(in *jlexer.Lexer, out *SudoRule) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Source":
+			out.Source = string(in.String())
+		case "Users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]string, 0, 4)
+					} else {
+						out.Users = []string{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Users = append(out.Users, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Hosts":
+			if in.IsNull() {
+				in.Skip()
+				out.Hosts = nil
+			} else {
+				in.Delim('[')
+				if out.Hosts == nil {
+					if !in.IsDelim(']') {
+						out.Hosts = make([]string, 0, 4)
+					} else {
+						out.Hosts = []string{}
+					}
+				} else {
+					out.Hosts = (out.Hosts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 string
+					v2 = string(in.String())
+					out.Hosts = append(out.Hosts, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "RunAs":
+			if in.IsNull() {
+				in.Skip()
+				out.RunAs = nil
+			} else {
+				in.Delim('[')
+				if out.RunAs == nil {
+					if !in.IsDelim(']') {
+						out.RunAs = make([]string, 0, 4)
+					} else {
+						out.RunAs = []string{}
+					}
+				} else {
+					out.RunAs = (out.RunAs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 string
+					v3 = string(in.String())
+					out.RunAs = append(out.RunAs, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Commands":
+			if in.IsNull() {
+				in.Skip()
+				out.Commands = nil
+			} else {
+				in.Delim('[')
+				if out.Commands == nil {
+					if !in.IsDelim(']') {
+						out.Commands = make([]string, 0, 4)
+					} else {
+						out.Commands = []string{}
+					}
+				} else {
+					out.Commands = (out.Commands)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.Commands = append(out.Commands, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "NoPassword":
+			out.NoPassword = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(out *jwriter.Writer, in SudoRule) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Source != "" {
+		const prefix string = ",\"Source\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Source))
+	}
+	if len(in.Users) != 0 {
+		const prefix string = ",\"Users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Users {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Hosts) != 0 {
+		const prefix string = ",\"Hosts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v7, v8 := range in.Hosts {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v8))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.RunAs) != 0 {
+		const prefix string = ",\"RunAs\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v9, v10 := range in.RunAs {
+				if v9 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v10))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Commands) != 0 {
+		const prefix string = ",\"Commands\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v11, v12 := range in.Commands {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NoPassword {
+		const prefix string = ",\"NoPassword\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.NoPassword))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SudoRule) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SudoRule) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SudoRule) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SudoRule) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine1(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(in *jlexer.Lexer, out *Session) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "Line":
+			out.Line = string(in.String())
+		case "Host":
+			out.Host = string(in.String())
+		case "Started":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Started).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(out *jwriter.Writer, in Session) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Line != "" {
+		const prefix string = ",\"Line\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Line))
+	}
+	if in.Host != "" {
+		const prefix string = ",\"Host\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Host))
+	}
+	if true {
+		const prefix string = ",\"Started\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Started).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Session) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Session) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Session) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Session) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine2(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(in *jlexer.Lexer, out *SSSDomain) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "ADDomain":
+			out.ADDomain = string(in.String())
+		case "IDProvider":
+			out.IDProvider = string(in.String())
+		case "AccessProvider":
+			out.AccessProvider = string(in.String())
+		case "UseFullyQualifiedNames":
+			out.UseFullyQualifiedNames = bool(in.Bool())
+		case "SimpleAllowUsers":
+			if in.IsNull() {
+				in.Skip()
+				out.SimpleAllowUsers = nil
+			} else {
+				in.Delim('[')
+				if out.SimpleAllowUsers == nil {
+					if !in.IsDelim(']') {
+						out.SimpleAllowUsers = make([]string, 0, 4)
+					} else {
+						out.SimpleAllowUsers = []string{}
+					}
+				} else {
+					out.SimpleAllowUsers = (out.SimpleAllowUsers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 string
+					v13 = string(in.String())
+					out.SimpleAllowUsers = append(out.SimpleAllowUsers, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "SimpleAllowGroups":
+			if in.IsNull() {
+				in.Skip()
+				out.SimpleAllowGroups = nil
+			} else {
+				in.Delim('[')
+				if out.SimpleAllowGroups == nil {
+					if !in.IsDelim(']') {
+						out.SimpleAllowGroups = make([]string, 0, 4)
+					} else {
+						out.SimpleAllowGroups = []string{}
+					}
+				} else {
+					out.SimpleAllowGroups = (out.SimpleAllowGroups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v14 string
+					v14 = string(in.String())
+					out.SimpleAllowGroups = append(out.SimpleAllowGroups, v14)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "SimpleDenyUsers":
+			if in.IsNull() {
+				in.Skip()
+				out.SimpleDenyUsers = nil
+			} else {
+				in.Delim('[')
+				if out.SimpleDenyUsers == nil {
+					if !in.IsDelim(']') {
+						out.SimpleDenyUsers = make([]string, 0, 4)
+					} else {
+						out.SimpleDenyUsers = []string{}
+					}
+				} else {
+					out.SimpleDenyUsers = (out.SimpleDenyUsers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v15 string
+					v15 = string(in.String())
+					out.SimpleDenyUsers = append(out.SimpleDenyUsers, v15)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "SimpleDenyGroups":
+			if in.IsNull() {
+				in.Skip()
+				out.SimpleDenyGroups = nil
+			} else {
+				in.Delim('[')
+				if out.SimpleDenyGroups == nil {
+					if !in.IsDelim(']') {
+						out.SimpleDenyGroups = make([]string, 0, 4)
+					} else {
+						out.SimpleDenyGroups = []string{}
+					}
+				} else {
+					out.SimpleDenyGroups = (out.SimpleDenyGroups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 string
+					v16 = string(in.String())
+					out.SimpleDenyGroups = append(out.SimpleDenyGroups, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ADAccessFilter":
+			out.ADAccessFilter = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(out *jwriter.Writer, in SSSDomain) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.ADDomain != "" {
+		const prefix string = ",\"ADDomain\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ADDomain))
+	}
+	if in.IDProvider != "" {
+		const prefix string = ",\"IDProvider\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.IDProvider))
+	}
+	if in.AccessProvider != "" {
+		const prefix string = ",\"AccessProvider\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.AccessProvider))
+	}
+	if in.UseFullyQualifiedNames {
+		const prefix string = ",\"UseFullyQualifiedNames\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.UseFullyQualifiedNames))
+	}
+	if len(in.SimpleAllowUsers) != 0 {
+		const prefix string = ",\"SimpleAllowUsers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v17, v18 := range in.SimpleAllowUsers {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SimpleAllowGroups) != 0 {
+		const prefix string = ",\"SimpleAllowGroups\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v19, v20 := range in.SimpleAllowGroups {
+				if v19 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v20))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SimpleDenyUsers) != 0 {
+		const prefix string = ",\"SimpleDenyUsers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v21, v22 := range in.SimpleDenyUsers {
+				if v21 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v22))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SimpleDenyGroups) != 0 {
+		const prefix string = ",\"SimpleDenyGroups\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v23, v24 := range in.SimpleDenyGroups {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ADAccessFilter != "" {
+		const prefix string = ",\"ADAccessFilter\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.ADAccessFilter))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SSSDomain) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SSSDomain) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SSSDomain) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SSSDomain) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine3(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(in *jlexer.Lexer, out *SSHConfig) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "AuthorizedKeysCommand":
+			out.AuthorizedKeysCommand = string(in.String())
+		case "AuthorizedKeysCommandUser":
+			out.AuthorizedKeysCommandUser = string(in.String())
+		case "PermitRootLogin":
+			out.PermitRootLogin = string(in.String())
+		case "PasswordAuthentication":
+			out.PasswordAuthentication = string(in.String())
+		case "GSSAPIAuthentication":
+			out.GSSAPIAuthentication = string(in.String())
+		case "AllowUsers":
+			if in.IsNull() {
+				in.Skip()
+				out.AllowUsers = nil
+			} else {
+				in.Delim('[')
+				if out.AllowUsers == nil {
+					if !in.IsDelim(']') {
+						out.AllowUsers = make([]string, 0, 4)
+					} else {
+						out.AllowUsers = []string{}
+					}
+				} else {
+					out.AllowUsers = (out.AllowUsers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 string
+					v25 = string(in.String())
+					out.AllowUsers = append(out.AllowUsers, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "AllowGroups":
+			if in.IsNull() {
+				in.Skip()
+				out.AllowGroups = nil
+			} else {
+				in.Delim('[')
+				if out.AllowGroups == nil {
+					if !in.IsDelim(']') {
+						out.AllowGroups = make([]string, 0, 4)
+					} else {
+						out.AllowGroups = []string{}
+					}
+				} else {
+					out.AllowGroups = (out.AllowGroups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v26 string
+					v26 = string(in.String())
+					out.AllowGroups = append(out.AllowGroups, v26)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "DenyUsers":
+			if in.IsNull() {
+				in.Skip()
+				out.DenyUsers = nil
+			} else {
+				in.Delim('[')
+				if out.DenyUsers == nil {
+					if !in.IsDelim(']') {
+						out.DenyUsers = make([]string, 0, 4)
+					} else {
+						out.DenyUsers = []string{}
+					}
+				} else {
+					out.DenyUsers = (out.DenyUsers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v27 string
+					v27 = string(in.String())
+					out.DenyUsers = append(out.DenyUsers, v27)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "DenyGroups":
+			if in.IsNull() {
+				in.Skip()
+				out.DenyGroups = nil
+			} else {
+				in.Delim('[')
+				if out.DenyGroups == nil {
+					if !in.IsDelim(']') {
+						out.DenyGroups = make([]string, 0, 4)
+					} else {
+						out.DenyGroups = []string{}
+					}
+				} else {
+					out.DenyGroups = (out.DenyGroups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 string
+					v28 = string(in.String())
+					out.DenyGroups = append(out.DenyGroups, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(out *jwriter.Writer, in SSHConfig) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.AuthorizedKeysCommand != "" {
+		const prefix string = ",\"AuthorizedKeysCommand\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.AuthorizedKeysCommand))
+	}
+	if in.AuthorizedKeysCommandUser != "" {
+		const prefix string = ",\"AuthorizedKeysCommandUser\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.AuthorizedKeysCommandUser))
+	}
+	if in.PermitRootLogin != "" {
+		const prefix string = ",\"PermitRootLogin\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PermitRootLogin))
+	}
+	if in.PasswordAuthentication != "" {
+		const prefix string = ",\"PasswordAuthentication\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.PasswordAuthentication))
+	}
+	if in.GSSAPIAuthentication != "" {
+		const prefix string = ",\"GSSAPIAuthentication\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.GSSAPIAuthentication))
+	}
+	if len(in.AllowUsers) != 0 {
+		const prefix string = ",\"AllowUsers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v29, v30 := range in.AllowUsers {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v30))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.AllowGroups) != 0 {
+		const prefix string = ",\"AllowGroups\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v31, v32 := range in.AllowGroups {
+				if v31 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v32))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.DenyUsers) != 0 {
+		const prefix string = ",\"DenyUsers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v33, v34 := range in.DenyUsers {
+				if v33 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v34))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.DenyGroups) != 0 {
+		const prefix string = ",\"DenyGroups\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v35, v36 := range in.DenyGroups {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v36))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SSHConfig) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SSHConfig) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SSHConfig) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SSHConfig) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine4(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(in *jlexer.Lexer, out *Principal) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "Realm":
+			out.Realm = string(in.String())
+		case "KVNO":
+			out.KVNO = uint32(in.Uint32())
+		case "EncryptionTypes":
+			if in.IsNull() {
+				in.Skip()
+				out.EncryptionTypes = nil
+			} else {
+				in.Delim('[')
+				if out.EncryptionTypes == nil {
+					if !in.IsDelim(']') {
+						out.EncryptionTypes = make([]uint16, 0, 32)
+					} else {
+						out.EncryptionTypes = []uint16{}
+					}
+				} else {
+					out.EncryptionTypes = (out.EncryptionTypes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 uint16
+					v37 = uint16(in.Uint16())
+					out.EncryptionTypes = append(out.EncryptionTypes, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Timestamp":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Timestamp).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(out *jwriter.Writer, in Principal) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Realm != "" {
+		const prefix string = ",\"Realm\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Realm))
+	}
+	if in.KVNO != 0 {
+		const prefix string = ",\"KVNO\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint32(uint32(in.KVNO))
+	}
+	if len(in.EncryptionTypes) != 0 {
+		const prefix string = ",\"EncryptionTypes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v38, v39 := range in.EncryptionTypes {
+				if v38 > 0 {
+					out.RawByte(',')
+				}
+				out.Uint16(uint16(v39))
+			}
+			out.RawByte(']')
+		}
+	}
+	if true {
+		const prefix string = ",\"Timestamp\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Timestamp).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Principal) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Principal) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Principal) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Principal) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine5(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(in *jlexer.Lexer, out *NetworkInterfaceInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "MACAddress":
+			out.MACAddress = string(in.String())
+		case "Flags":
+			out.Flags = uint(in.Uint())
+		case "Addresses":
+			if in.IsNull() {
+				in.Skip()
+				out.Addresses = nil
+			} else {
+				in.Delim('[')
+				if out.Addresses == nil {
+					if !in.IsDelim(']') {
+						out.Addresses = make([]string, 0, 4)
+					} else {
+						out.Addresses = []string{}
+					}
+				} else {
+					out.Addresses = (out.Addresses)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 string
+					v40 = string(in.String())
+					out.Addresses = append(out.Addresses, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(out *jwriter.Writer, in NetworkInterfaceInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.MACAddress != "" {
+		const prefix string = ",\"MACAddress\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MACAddress))
+	}
+	if in.Flags != 0 {
+		const prefix string = ",\"Flags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint(uint(in.Flags))
+	}
+	if len(in.Addresses) != 0 {
+		const prefix string = ",\"Addresses\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v41, v42 := range in.Addresses {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v42))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NetworkInterfaceInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NetworkInterfaceInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NetworkInterfaceInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NetworkInterfaceInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine6(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(in *jlexer.Lexer, out *NetworkInformation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "NetworkInterfaces":
+			if in.IsNull() {
+				in.Skip()
+				out.NetworkInterfaces = nil
+			} else {
+				in.Delim('[')
+				if out.NetworkInterfaces == nil {
+					if !in.IsDelim(']') {
+						out.NetworkInterfaces = make([]NetworkInterfaceInfo, 0, 1)
+					} else {
+						out.NetworkInterfaces = []NetworkInterfaceInfo{}
+					}
+				} else {
+					out.NetworkInterfaces = (out.NetworkInterfaces)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 NetworkInterfaceInfo
+					(v43).UnmarshalEasyJSON(in)
+					out.NetworkInterfaces = append(out.NetworkInterfaces, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(out *jwriter.Writer, in NetworkInformation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.NetworkInterfaces) != 0 {
+		const prefix string = ",\"NetworkInterfaces\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v44, v45 := range in.NetworkInterfaces {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				(v45).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NetworkInformation) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NetworkInformation) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NetworkInformation) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NetworkInformation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine7(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(in *jlexer.Lexer, out *Machine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "DNSHostName":
+			out.DNSHostName = string(in.String())
+		case "Domain":
+			out.Domain = string(in.String())
+		case "Realm":
+			out.Realm = string(in.String())
+		case "IsDomainJoined":
+			out.IsDomainJoined = bool(in.Bool())
+		case "JoinProvider":
+			out.JoinProvider = string(in.String())
+		case "MachineAccount":
+			out.MachineAccount = string(in.String())
+		case "OSName":
+			out.OSName = string(in.String())
+		case "OSVersion":
+			out.OSVersion = string(in.String())
+		case "KernelVersion":
+			out.KernelVersion = string(in.String())
+		case "Architecture":
+			out.Architecture = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(out *jwriter.Writer, in Machine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.DNSHostName != "" {
+		const prefix string = ",\"DNSHostName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.DNSHostName))
+	}
+	if in.Domain != "" {
+		const prefix string = ",\"Domain\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Domain))
+	}
+	if in.Realm != "" {
+		const prefix string = ",\"Realm\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Realm))
+	}
+	if in.IsDomainJoined {
+		const prefix string = ",\"IsDomainJoined\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsDomainJoined))
+	}
+	if in.JoinProvider != "" {
+		const prefix string = ",\"JoinProvider\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.JoinProvider))
+	}
+	if in.MachineAccount != "" {
+		const prefix string = ",\"MachineAccount\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.MachineAccount))
+	}
+	if in.OSName != "" {
+		const prefix string = ",\"OSName\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.OSName))
+	}
+	if in.OSVersion != "" {
+		const prefix string = ",\"OSVersion\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.OSVersion))
+	}
+	if in.KernelVersion != "" {
+		const prefix string = ",\"KernelVersion\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.KernelVersion))
+	}
+	if in.Architecture != "" {
+		const prefix string = ",\"Architecture\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Architecture))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Machine) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Machine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Machine) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Machine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine8(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(in *jlexer.Lexer, out *LoginPopularity) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Day":
+			if in.IsNull() {
+				in.Skip()
+				out.Day = nil
+			} else {
+				in.Delim('[')
+				if out.Day == nil {
+					if !in.IsDelim(']') {
+						out.Day = make([]LoginCount, 0, 2)
+					} else {
+						out.Day = []LoginCount{}
+					}
+				} else {
+					out.Day = (out.Day)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v46 LoginCount
+					(v46).UnmarshalEasyJSON(in)
+					out.Day = append(out.Day, v46)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Week":
+			if in.IsNull() {
+				in.Skip()
+				out.Week = nil
+			} else {
+				in.Delim('[')
+				if out.Week == nil {
+					if !in.IsDelim(']') {
+						out.Week = make([]LoginCount, 0, 2)
+					} else {
+						out.Week = []LoginCount{}
+					}
+				} else {
+					out.Week = (out.Week)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v47 LoginCount
+					(v47).UnmarshalEasyJSON(in)
+					out.Week = append(out.Week, v47)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Month":
+			if in.IsNull() {
+				in.Skip()
+				out.Month = nil
+			} else {
+				in.Delim('[')
+				if out.Month == nil {
+					if !in.IsDelim(']') {
+						out.Month = make([]LoginCount, 0, 2)
+					} else {
+						out.Month = []LoginCount{}
+					}
+				} else {
+					out.Month = (out.Month)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v48 LoginCount
+					(v48).UnmarshalEasyJSON(in)
+					out.Month = append(out.Month, v48)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(out *jwriter.Writer, in LoginPopularity) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Day\":"
+		out.RawString(prefix[1:])
+		if in.Day == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v49, v50 := range in.Day {
+				if v49 > 0 {
+					out.RawByte(',')
+				}
+				(v50).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Week\":"
+		out.RawString(prefix)
+		if in.Week == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v51, v52 := range in.Week {
+				if v51 > 0 {
+					out.RawByte(',')
+				}
+				(v52).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Month\":"
+		out.RawString(prefix)
+		if in.Month == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v53, v54 := range in.Month {
+				if v53 > 0 {
+					out.RawByte(',')
+				}
+				(v54).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginPopularity) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginPopularity) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginPopularity) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginPopularity) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine9(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(in *jlexer.Lexer, out *LoginCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "Count":
+			out.Count = uint64(in.Uint64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(out *jwriter.Writer, in LoginCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Count != 0 {
+		const prefix string = ",\"Count\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LoginCount) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LoginCount) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LoginCount) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LoginCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine10(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(in *jlexer.Lexer, out *Info) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "UnprivilegedCollection":
+			out.UnprivilegedCollection = bool(in.Bool())
+		case "Machine":
+			(out.Machine).UnmarshalEasyJSON(in)
+		case "Network":
+			(out.Network).UnmarshalEasyJSON(in)
+		case "LoginPopularity":
+			(out.LoginPopularity).UnmarshalEasyJSON(in)
+		case "ActiveSessions":
+			if in.IsNull() {
+				in.Skip()
+				out.ActiveSessions = nil
+			} else {
+				in.Delim('[')
+				if out.ActiveSessions == nil {
+					if !in.IsDelim(']') {
+						out.ActiveSessions = make([]Session, 0, 0)
+					} else {
+						out.ActiveSessions = []Session{}
+					}
+				} else {
+					out.ActiveSessions = (out.ActiveSessions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v55 Session
+					(v55).UnmarshalEasyJSON(in)
+					out.ActiveSessions = append(out.ActiveSessions, v55)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]User, 0, 0)
+					} else {
+						out.Users = []User{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v56 User
+					(v56).UnmarshalEasyJSON(in)
+					out.Users = append(out.Users, v56)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Groups":
+			if in.IsNull() {
+				in.Skip()
+				out.Groups = nil
+			} else {
+				in.Delim('[')
+				if out.Groups == nil {
+					if !in.IsDelim(']') {
+						out.Groups = make([]Group, 0, 1)
+					} else {
+						out.Groups = []Group{}
+					}
+				} else {
+					out.Groups = (out.Groups)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v57 Group
+					(v57).UnmarshalEasyJSON(in)
+					out.Groups = append(out.Groups, v57)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Sudoers":
+			if in.IsNull() {
+				in.Skip()
+				out.Sudoers = nil
+			} else {
+				in.Delim('[')
+				if out.Sudoers == nil {
+					if !in.IsDelim(']') {
+						out.Sudoers = make([]SudoRule, 0, 0)
+					} else {
+						out.Sudoers = []SudoRule{}
+					}
+				} else {
+					out.Sudoers = (out.Sudoers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 SudoRule
+					(v58).UnmarshalEasyJSON(in)
+					out.Sudoers = append(out.Sudoers, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "SSSD":
+			if in.IsNull() {
+				in.Skip()
+				out.SSSD = nil
+			} else {
+				in.Delim('[')
+				if out.SSSD == nil {
+					if !in.IsDelim(']') {
+						out.SSSD = make([]SSSDomain, 0, 0)
+					} else {
+						out.SSSD = []SSSDomain{}
+					}
+				} else {
+					out.SSSD = (out.SSSD)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v59 SSSDomain
+					(v59).UnmarshalEasyJSON(in)
+					out.SSSD = append(out.SSSD, v59)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "SSH":
+			(out.SSH).UnmarshalEasyJSON(in)
+		case "Keytab":
+			if in.IsNull() {
+				in.Skip()
+				out.Keytab = nil
+			} else {
+				in.Delim('[')
+				if out.Keytab == nil {
+					if !in.IsDelim(']') {
+						out.Keytab = make([]Principal, 0, 0)
+					} else {
+						out.Keytab = []Principal{}
+					}
+				} else {
+					out.Keytab = (out.Keytab)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v60 Principal
+					(v60).UnmarshalEasyJSON(in)
+					out.Keytab = append(out.Keytab, v60)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Collector":
+			out.Collector = string(in.String())
+		case "Version":
+			out.Version = string(in.String())
+		case "Commit":
+			out.Commit = string(in.String())
+		case "Collected":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Collected).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(out *jwriter.Writer, in Info) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.UnprivilegedCollection {
+		const prefix string = ",\"UnprivilegedCollection\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.UnprivilegedCollection))
+	}
+	if true {
+		const prefix string = ",\"Machine\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.Machine).MarshalEasyJSON(out)
+	}
+	if true {
+		const prefix string = ",\"Network\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.Network).MarshalEasyJSON(out)
+	}
+	if true {
+		const prefix string = ",\"LoginPopularity\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.LoginPopularity).MarshalEasyJSON(out)
+	}
+	if len(in.ActiveSessions) != 0 {
+		const prefix string = ",\"ActiveSessions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v61, v62 := range in.ActiveSessions {
+				if v61 > 0 {
+					out.RawByte(',')
+				}
+				(v62).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Users) != 0 {
+		const prefix string = ",\"Users\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v63, v64 := range in.Users {
+				if v63 > 0 {
+					out.RawByte(',')
+				}
+				(v64).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Groups) != 0 {
+		const prefix string = ",\"Groups\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v65, v66 := range in.Groups {
+				if v65 > 0 {
+					out.RawByte(',')
+				}
+				(v66).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Sudoers) != 0 {
+		const prefix string = ",\"Sudoers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v67, v68 := range in.Sudoers {
+				if v67 > 0 {
+					out.RawByte(',')
+				}
+				(v68).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SSSD) != 0 {
+		const prefix string = ",\"SSSD\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v69, v70 := range in.SSSD {
+				if v69 > 0 {
+					out.RawByte(',')
+				}
+				(v70).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if true {
+		const prefix string = ",\"SSH\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.SSH).MarshalEasyJSON(out)
+	}
+	if len(in.Keytab) != 0 {
+		const prefix string = ",\"Keytab\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v71, v72 := range in.Keytab {
+				if v71 > 0 {
+					out.RawByte(',')
+				}
+				(v72).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Collector\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Collector))
+	}
+	{
+		const prefix string = ",\"Version\":"
+		out.RawString(prefix)
+		out.String(string(in.Version))
+	}
+	{
+		const prefix string = ",\"Commit\":"
+		out.RawString(prefix)
+		out.String(string(in.Commit))
+	}
+	{
+		const prefix string = ",\"Collected\":"
+		out.RawString(prefix)
+		out.Raw((in.Collected).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Info) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Info) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Info) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Info) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine11(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(in *jlexer.Lexer, out *Group) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Name":
+			out.Name = string(in.String())
+		case "GID":
+			out.GID = int(in.Int())
+		case "Members":
+			if in.IsNull() {
+				in.Skip()
+				out.Members = nil
+			} else {
+				in.Delim('[')
+				if out.Members == nil {
+					if !in.IsDelim(']') {
+						out.Members = make([]string, 0, 4)
+					} else {
+						out.Members = []string{}
+					}
+				} else {
+					out.Members = (out.Members)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v73 string
+					v73 = string(in.String())
+					out.Members = append(out.Members, v73)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(out *jwriter.Writer, in Group) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"Name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.GID != 0 {
+		const prefix string = ",\"GID\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.GID))
+	}
+	if len(in.Members) != 0 {
+		const prefix string = ",\"Members\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v74, v75 := range in.Members {
+				if v74 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v75))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Group) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Group) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Group) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Group) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLinuxmachine12(l, v)
+}
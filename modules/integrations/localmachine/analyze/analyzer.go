@@ -12,9 +12,11 @@ var (
 	ShareType               = engine.NewAttribute("shareType")
 	ServiceStart            = engine.NewAttribute("serviceStart")
 	ServiceType             = engine.NewAttribute("serviceType")
+	TaskPath                = engine.NewAttribute("taskPath")
+	TaskRunLevel            = engine.NewAttribute("taskRunLevel")
 
-	EdgeLocalAdminRights = engine.NewEdge("AdminRights").Tag("Granted")
-	EdgeLocalRDPRights   = engine.NewEdge("RDPRights").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
+	EdgeLocalAdminRights = engine.NewEdge("AdminRights").RequiresPorts(22, 135, 445, 5985, 5986).RegisterProbabilityCalculator(adminRightsProbability).Tag("Granted")
+	EdgeLocalRDPRights   = engine.NewEdge("RDPRights").RequiresPorts(3389).RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability {
 		var probability engine.Probability
 		/* ENDLESS LOOPS
 			target.Edges(engine.In).Range(func(potential *engine.Object, edge engine.EdgeBitmap) bool {
@@ -33,11 +35,11 @@ var (
 		}
 		return probability
 	}).Tag("Granted").Tag("Pivot")
-	EdgeLocalDCOMRights              = engine.NewEdge("DCOMRights").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Granted")
+	EdgeLocalDCOMRights              = engine.NewEdge("DCOMRights").RequiresPorts(135).RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Granted")
 	EdgeLocalSMSAdmins               = engine.NewEdge("SMSAdmins").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 }).Tag("Granted")
-	EdgeLocalSessionLastDay          = engine.NewEdge("SessionLastDay").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 80 }).Tag("Pivot")
-	EdgeLocalSessionLastWeek         = engine.NewEdge("SessionLastWeek").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 55 }).Tag("Pivot")
-	EdgeLocalSessionLastMonth        = engine.NewEdge("SessionLastMonth").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 30 }).Tag("Pivot")
+	EdgeLocalSessionLastDay          = engine.NewEdge("SessionLastDay").RegisterProbabilityCalculator(sessionProbability(80, false)).Tag("Pivot")
+	EdgeLocalSessionLastWeek         = engine.NewEdge("SessionLastWeek").RegisterProbabilityCalculator(sessionProbability(55, true)).Tag("Pivot")
+	EdgeLocalSessionLastMonth        = engine.NewEdge("SessionLastMonth").RegisterProbabilityCalculator(sessionProbability(30, true)).Tag("Pivot")
 	EdgeHasServiceAccountCredentials = engine.NewEdge("SvcAccntCreds").Tag("Pivot")
 	EdgeHasAutoAdminLogonCredentials = engine.NewEdge("AutoAdminLogonCreds").Tag("Pivot")
 	EdgeRunsExecutable               = engine.NewEdge("RunsExecutable")
@@ -46,6 +48,13 @@ var (
 	EdgeMemberOfGroup                = engine.NewEdge("MemberOfGroup")
 	EdgeFileWrite                    = engine.NewEdge("FileWrite")
 	EdgeFileRead                     = engine.NewEdge("FileRead")
+	EdgeFileModifyDACL               = engine.NewEdge("FileModifyDACL").Tag("Pivot")
+	EdgeFileTakeOwnership            = engine.NewEdge("FileTakeOwnership").Tag("Pivot")
+	EdgeUnquotedServicePathHijack    = engine.NewEdge("UnquotedServicePathHijack").Describe("Can create an executable that an unquoted service path runs before the real one").Tag("Pivot")
+	EdgeServiceRegistryReconfigure   = engine.NewEdge("ServiceRegistryReconfigure").Describe("Can change the service configuration in the registry, including what it runs").Tag("Pivot")
+	EdgeWritableServiceDirectory     = engine.NewEdge("WritableServiceDirectory").Describe("Can plant DLLs in a folder the service searches").Tag("Pivot")
+	EdgeWritablePathDirectory        = engine.NewEdge("WritablePathDirectory").Describe("Can plant DLLs in a system PATH folder, which only works if the service loads one that isn't found elsewhere").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 20 }).Tag("Pivot")
+	EdgeTaskRunsAsLimited            = engine.NewEdge("TaskRunsAsLimited").Describe("Scheduled task runs as account without elevation").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 50 })
 	EdgeShares                       = engine.NewEdge("Shares").Describe("Machine offers a file share")
 	EdgeRegistryOwns                 = engine.NewEdge("RegistryOwns")
 	EdgeRegistryWrite                = engine.NewEdge("RegistryWrite")
@@ -79,10 +88,24 @@ var (
 	EdgeControlsUpdates = engine.NewEdge("ControlsUpdates").Tag("Affects")
 	WUServer            = engine.NewAttribute("wuServer")
 	SCCMServer          = engine.NewAttribute("sccmServer")
+	SCCMSiteAccounts    = engine.NewAttribute("sccmSiteAccounts")
+
+	// Hardening settings from the collector, these modulate the credential theft and admin edges
+	UACEnableLUA                     = engine.NewAttribute("uacEnableLUA")
+	UACLocalAccountTokenFilterPolicy = engine.NewAttribute("uacLocalAccountTokenFilterPolicy")
+	UACFilterAdministratorToken      = engine.NewAttribute("uacFilterAdministratorToken")
+	LSARunAsPPL                      = engine.NewAttribute("lsaRunAsPPL")
+	CredentialGuard                  = engine.NewAttribute("credentialGuard")
+	WDigestUseLogonCredential        = engine.NewAttribute("wdigestUseLogonCredential")
+	CachedLogonsCount                = engine.NewAttribute("cachedLogonsCount")
+	RestrictedAdmin                  = engine.NewAttribute("restrictedAdmin")
+	SMBSigningRequired               = engine.NewAttribute("smbSigningRequired")
 
 	EdgePublishes = engine.NewEdge("Publishes").Tag("Informative")
 
-	ObjectTypeShare = engine.NewObjectType("Share", "Share")
+	ObjectTypeShare         = engine.NewObjectType("Share", "Share")
+	ObjectTypeScheduledTask = engine.NewObjectType("ScheduledTask", "Scheduled-Task").SetDefault(engine.Last, false)
+	ObjectTypeSCCMSite      = engine.NewObjectType("SCCMSite", "SCCM-Site")
 )
 
 func MapSID(original, new, input windowssecurity.SID) windowssecurity.SID {
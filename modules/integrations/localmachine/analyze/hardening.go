@@ -0,0 +1,59 @@
+package analyze
+
+import (
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+func attrIs(o *engine.Object, attr engine.Attribute, values ...int64) bool {
+	value, found := o.AttrInt(attr)
+	if !found {
+		return false
+	}
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionProbability adjusts the chance of getting credentials from a session on the machine (source) for the
+// hardening it has. Older sessions only leave credentials behind if they're cached or the user is still logged on
+func sessionProbability(base engine.Probability, older bool) engine.ProbabilityCalculatorFunction {
+	return func(source, target *engine.Object) engine.Probability {
+		probability := base
+		if older && source.OneAttrString(CachedLogonsCount) == "0" {
+			probability /= 2
+		}
+		switch {
+		case attrIs(source, CredentialGuard, 1, 2):
+			// Secrets are isolated, but tokens of live sessions can still be used
+			probability /= 3
+		case attrIs(source, LSARunAsPPL, 1, 2):
+			// Needs a driver to get around, but it's commonly done
+			probability = probability * 3 / 4
+		case attrIs(source, WDigestUseLogonCredential, 1):
+			// Cleartext passwords in memory
+			probability = min(probability+10, engine.MAXPROBABILITY)
+		}
+		return probability
+	}
+}
+
+// adminRightsProbability blocks remote use of local accounts when UAC remote restrictions apply. Only the built in
+// Administrator (RID 500) is exempt, unless FilterAdministratorToken is set too. Machines where the UAC settings
+// weren't collected get the default probability
+func adminRightsProbability(source, target *engine.Object) engine.Probability {
+	if !target.HasAttr(UACEnableLUA) || attrIs(target, UACEnableLUA, 0) || attrIs(target, UACLocalAccountTokenFilterPolicy, 1) {
+		return 100
+	}
+	sid := source.SID()
+	if sid.IsBlank() || sid.StripRID() != target.SID() {
+		// Not a local account on this machine
+		return 100
+	}
+	if sid.RID() == 500 && !attrIs(target, UACFilterAdministratorToken, 1) {
+		return 100
+	}
+	return 0
+}
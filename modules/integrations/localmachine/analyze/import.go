@@ -82,6 +82,25 @@ func ImportCollectorInfo(ao *engine.Objects, cinfo localmachine.Info) (*engine.O
 		engine.NewAttribute("connectivity"), cinfo.Network.InternetConnectivity,
 	)
 
+	if cinfo.Machine.UACEnableLUA != nil {
+		// Only when collected, so missing settings don't look like UAC is turned off
+		machine.SetFlex(
+			UACEnableLUA, *cinfo.Machine.UACEnableLUA,
+			UACLocalAccountTokenFilterPolicy, cinfo.Machine.UACLocalAccountTokenFilterPolicy,
+			UACFilterAdministratorToken, cinfo.Machine.UACFilterAdministratorToken,
+		)
+	}
+	machine.SetFlex(
+		LSARunAsPPL, cinfo.Machine.LSARunAsPPL,
+		CredentialGuard, cinfo.Machine.LSACfgFlags,
+		WDigestUseLogonCredential, cinfo.Machine.WDigestUseLogonCredential,
+		RestrictedAdmin, cinfo.Machine.RestrictedAdmin,
+		SMBSigningRequired, cinfo.Machine.SMBRequireSecuritySignature == 1,
+	)
+	if cinfo.Machine.CachedLogonsCount != "" {
+		machine.SetFlex(CachedLogonsCount, cinfo.Machine.CachedLogonsCount)
+	}
+
 	if cinfo.Machine.WUServer != "" {
 		if u, err := url.Parse(cinfo.Machine.WUServer); err == nil {
 			host, _, _ := strings.Cut(u.Host, ":")
@@ -100,6 +119,21 @@ func ImportCollectorInfo(ao *engine.Objects, cinfo localmachine.Info) (*engine.O
 		}
 	}
 
+	if cinfo.Machine.SCCMAssignedSiteCode != "" {
+		machine.SetFlex(analyze.SCCMAssignedSite, strings.ToUpper(cinfo.Machine.SCCMAssignedSiteCode))
+	}
+	if cinfo.Machine.SCCMSiteServerCode != "" {
+		analyze.AddSCCMRole(machine, cinfo.Machine.SCCMSiteServerCode, analyze.SCCMRoleSiteServer)
+	}
+	machine.SetFlex(engine.IgnoreBlanks, SCCMSiteAccounts, cinfo.Machine.SCCMSiteAccounts)
+	for _, share := range cinfo.Shares {
+		if strings.EqualFold(share.Name, "SCCMContentLib$") || strings.EqualFold(share.Name, "SMS_DP$") {
+			// The site isn't in the share, but distribution points are almost always clients of their own site
+			analyze.AddSCCMRole(machine, cinfo.Machine.SCCMAssignedSiteCode, analyze.SCCMRoleDistributionPoint)
+			break
+		}
+	}
+
 	var isdomaincontroller bool
 	if cinfo.Machine.ProductType != "" {
 		// New way of detecting domain controller
@@ -499,6 +533,7 @@ func ImportCollectorInfo(ao *engine.Objects, cinfo localmachine.Info) (*engine.O
 	)
 	localservicesgroup.ChildOf(machine)
 
+	serviceaccounts := make(map[*engine.Object]struct{})
 	for _, service := range cinfo.Services {
 		serviceobject := engine.NewObject(
 			engine.IgnoreBlanks,
@@ -675,6 +710,24 @@ func ImportCollectorInfo(ao *engine.Objects, cinfo localmachine.Info) (*engine.O
 			}
 		}
 
+		// Hijacking the service gives whatever it runs as
+		runsas := svcaccount
+		if runsas == nil {
+			runsas = serviceobject
+		}
+		serviceaccounts[runsas] = struct{}{}
+
+		ri.SecurityEdges(service.RegistryOwner, service.RegistryDACL, engine.KEY_SET_VALUE|engine.RIGHT_WRITE_DACL|engine.RIGHT_WRITE_OWNER, runsas, EdgeServiceRegistryReconfigure)
+
+		if !strings.HasPrefix(service.ImagePath, `"`) && strings.Contains(service.ImageExecutable, " ") {
+			serviceobject.Tag("service_unquoted_path")
+		}
+		for _, candidate := range service.UnquotedCandidates {
+			ri.SecurityEdges(candidate.Owner, candidate.DACL, engine.FILE_ADD_FILE|engine.RIGHT_WRITE_DACL|engine.RIGHT_WRITE_OWNER, runsas, EdgeUnquotedServicePathHijack)
+		}
+
+		ri.SecurityEdges(service.ImageDirectory.Owner, service.ImageDirectory.DACL, engine.FILE_ADD_FILE|engine.RIGHT_WRITE_DACL|engine.RIGHT_WRITE_OWNER, runsas, EdgeWritableServiceDirectory)
+
 		// Change service executable contents
 		serviceimageobject := engine.NewObject(
 			activedirectory.DisplayName, filepath.Base(service.ImageExecutable),
@@ -730,6 +783,99 @@ func ImportCollectorInfo(ao *engine.Objects, cinfo localmachine.Info) (*engine.O
 		}
 	}
 
+	// DLLs not found elsewhere are searched for in the system PATH, so writable folders there might hit any service.
+	// That requires a service that loads a missing DLL, which we can't see, hence the low probability edge
+	for _, directory := range cinfo.Machine.PathDirectories {
+		for runsas := range serviceaccounts {
+			ri.SecurityEdges(directory.Owner, directory.DACL, engine.FILE_ADD_FILE|engine.RIGHT_WRITE_DACL|engine.RIGHT_WRITE_OWNER, runsas, EdgeWritablePathDirectory)
+		}
+	}
+
+	// SCHEDULED TASKS
+	if len(cinfo.Tasks) > 0 {
+		taskscontainer := engine.NewObject(activedirectory.Name, "Scheduled Tasks")
+		ao.Add(taskscontainer)
+		taskscontainer.ChildOf(machine)
+
+		for _, task := range cinfo.Tasks {
+			taskobject := ao.AddNew(
+				engine.IgnoreBlanks,
+				activedirectory.Name, task.Name,
+				activedirectory.DisplayName, task.Name,
+				activedirectory.Description, task.Definition.RegistrationInfo.Description,
+				TaskPath, task.Path,
+				TaskRunLevel, int64(task.Definition.Principal.RunLevel),
+				engine.Type, ObjectTypeScheduledTask.ValueString(),
+			)
+			if !task.Enabled {
+				taskobject.Tag("task_disabled")
+			}
+			if task.Definition.Principal.RunLevel == taskRunLevelHighest {
+				taskobject.Tag("task_highest")
+			}
+			taskobject.ChildOf(taskscontainer)
+			machine.EdgeTo(taskobject, EdgeHosts)
+
+			// The account the task runs as, or the group whose members it runs for
+			account := task.Definition.Principal.UserID
+			if account == "" {
+				account = task.Definition.Principal.GroupID
+			}
+			if runsas := ri.GetAccountObject(account); runsas != nil {
+				if sid := runsas.SID(); task.Definition.Principal.RunLevel == taskRunLevelHighest || (!sid.IsBlank() && sid.Component(2) < 21) {
+					// Elevated, or a builtin account like SYSTEM which always has the full token
+					taskobject.EdgeTo(runsas, analyze.EdgeAuthenticatesAs)
+				} else {
+					taskobject.EdgeTo(runsas, EdgeTaskRunsAsLimited)
+				}
+			} else if account != "" {
+				ui.Debug().Msgf("Could not resolve account %v for scheduled task %v on %v", account, task.Path, cinfo.Machine.Name)
+			}
+
+			for _, action := range task.Definition.Actions {
+				if action.Path == "" {
+					continue
+				}
+				executable := ao.AddNew(
+					activedirectory.DisplayName, filepath.Base(action.Path),
+					AbsolutePath, action.Path,
+					engine.Type, "Executable",
+				)
+				executable.EdgeTo(taskobject, EdgeExecuted)
+				executable.ChildOf(taskobject)
+
+				if ownersid, err := windowssecurity.ParseStringSID(action.PathOwner); err == nil && !ri.IsAlreadyAdmin(ownersid) {
+					owner, _, _ := ri.GetSIDObject(ownersid, Auto)
+					owner.EdgeTo(executable, activedirectory.EdgeOwns)
+				}
+
+				if len(action.PathDACL) == 0 {
+					continue
+				}
+				acl, err := engine.ParseACL(action.PathDACL)
+				if err != nil {
+					ui.Warn().Msgf("Could not parse DACL for %v in scheduled task %v on %v: %v", action.Path, task.Path, cinfo.Machine.Name, err)
+					continue
+				}
+				for _, entry := range acl.Entries {
+					if entry.Type != engine.ACETYPE_ACCESS_ALLOWED || entry.ACEFlags&engine.ACEFLAG_INHERIT_ONLY_ACE != 0 || ri.IsAlreadyAdmin(entry.SID) {
+						continue
+					}
+					trustee, _, _ := ri.GetSIDObject(entry.SID, Auto)
+					if entry.Mask&engine.FILE_WRITE_DATA != 0 {
+						trustee.EdgeTo(executable, EdgeFileWrite)
+					}
+					if entry.Mask&engine.RIGHT_WRITE_DACL != 0 {
+						trustee.EdgeTo(executable, EdgeFileModifyDACL)
+					}
+					if entry.Mask&engine.RIGHT_WRITE_OWNER != 0 {
+						trustee.EdgeTo(executable, EdgeFileTakeOwnership)
+					}
+				}
+			}
+		}
+	}
+
 	// SOFTWARE INVENTORY AS ATTRIBUTES
 	installedsoftware := make([]string, len(cinfo.Software))
 	for i, software := range cinfo.Software {
@@ -885,6 +1031,78 @@ type relativeInfo struct {
 	ao                 *engine.Objects
 }
 
+// Principal run level from Task Scheduler, 0 is least privileges
+const taskRunLevelHighest = 1
+
+// IsAlreadyAdmin is true for accounts that are local admins anyway, so their rights on local things don't matter
+func (ri *relativeInfo) IsAlreadyAdmin(sid windowssecurity.SID) bool {
+	return sid == windowssecurity.AdministratorsSID || sid == windowssecurity.SystemSID || sid.Component(2) == 80 /* Service user */
+}
+
+// SecurityEdges adds the edge to target from the owner and from trustees granted any of the rights in mask by the DACL
+func (ri *relativeInfo) SecurityEdges(owner string, dacl []byte, mask engine.Mask, target *engine.Object, edge engine.Edge) {
+	if ownersid, err := windowssecurity.ParseStringSID(owner); err == nil && !ri.IsAlreadyAdmin(ownersid) {
+		// Owner can always change the DACL
+		o, _, _ := ri.GetSIDObject(ownersid, Auto)
+		o.EdgeTo(target, edge)
+	}
+	if len(dacl) == 0 {
+		return
+	}
+	acl, err := engine.ParseACL(dacl)
+	if err != nil {
+		ui.Warn().Msgf("Problem parsing DACL for %v edges: %v", edge.String(), err)
+		return
+	}
+	for _, entry := range acl.Entries {
+		if entry.Type != engine.ACETYPE_ACCESS_ALLOWED || entry.ACEFlags&engine.ACEFLAG_INHERIT_ONLY_ACE != 0 || entry.Mask&mask == 0 || ri.IsAlreadyAdmin(entry.SID) {
+			continue
+		}
+		o, _, _ := ri.GetSIDObject(entry.SID, Auto)
+		o.EdgeTo(target, edge)
+	}
+}
+
+// GetAccountObject finds the object for an account given as SID, NT AUTHORITY name, DOMAIN\Name, .\Name or Name
+func (ri *relativeInfo) GetAccountObject(account string) *engine.Object {
+	if account == "" {
+		return nil
+	}
+	if sid, err := windowssecurity.ParseStringSID(account); err == nil {
+		o, _, _ := ri.GetSIDObject(sid, Auto)
+		return o
+	}
+
+	domain, name, found := strings.Cut(account, "\\")
+	if !found {
+		name = domain
+		domain = ""
+	}
+	switch strings.ToUpper(name) {
+	case "SYSTEM", "LOCALSYSTEM":
+		o, _, _ := ri.GetSIDObject(windowssecurity.SystemSID, Local)
+		return o
+	case "LOCAL SERVICE", "LOCALSERVICE":
+		o, _, _ := ri.GetSIDObject(windowssecurity.LocalServiceSID, Local)
+		return o
+	case "NETWORK SERVICE", "NETWORKSERVICE":
+		o, _, _ := ri.GetSIDObject(windowssecurity.NetworkServiceSID, Local)
+		return o
+	}
+
+	if domain == "" || domain == "." || strings.EqualFold(domain, ri.LocalName.String()) {
+		o, _ := ri.ao.FindOrAdd(
+			engine.DownLevelLogonName, engine.AttributeValueString(ri.LocalName.String()+"\\"+name),
+		)
+		o.SetFlex(engine.DataSource, ri.LocalName)
+		return o
+	}
+	o, _ := ri.ao.FindOrAdd(
+		engine.DownLevelLogonName, engine.AttributeValueString(domain+"\\"+name),
+	)
+	return o
+}
+
 type RelativeLocation byte
 
 const (
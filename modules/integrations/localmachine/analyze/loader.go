@@ -1,11 +1,11 @@
 package analyze
 
 import (
-	"io/ioutil"
 	"runtime"
 	"strings"
 	"sync"
 
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/engine"
 	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
 	"github.com/lkarlslund/adalanche/modules/ui"
@@ -44,7 +44,7 @@ func (ld *LocalMachineLoader) Init() error {
 		ld.done.Add(1)
 		go func() {
 			for queueItem := range ld.infostoadd {
-				raw, err := ioutil.ReadFile(queueItem.path)
+				raw, err := encryption.ReadFile(queueItem.path)
 				if err != nil {
 					ui.Warn().Msgf("Problem reading data from JSON file %v: %v", queueItem, err)
 					continue
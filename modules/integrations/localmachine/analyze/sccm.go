@@ -0,0 +1,117 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory/analyze"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+var (
+	SCCMSiteCode = engine.NewAttribute("sccmSiteCode")
+
+	EdgeSCCMSiteServer  = engine.NewEdge("SCCMSiteServer").Describe("Machine is the SCCM site server, and controls everything in the site").Tag("Pivot")
+	EdgeSCCMSiteSystem  = engine.NewEdge("SCCMSiteSystem").Describe("The site server computer account is administrator on the SCCM site systems (management and distribution points)").Tag("Granted")
+	EdgeSCCMClient      = engine.NewEdge("SCCMClient").Describe("SCCM administrators can deploy applications and scripts that run as SYSTEM on clients in the site").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 90 }).Tag("Pivot")
+	EdgeSCCMSiteAccount = engine.NewEdge("SCCMSiteAccount").Describe("Credentials for the account (client push, network access or other) are stored in the SCCM site, and can be decrypted on the site server").RegisterProbabilityCalculator(func(source, target *engine.Object) engine.Probability { return 80 }).Tag("Pivot")
+)
+
+func LinkSCCMSites(ao *engine.Objects) {
+	type siteRole struct {
+		machine    *engine.Object
+		site, role string
+	}
+	var roles []siteRole
+	clients := make(map[string][]*engine.Object)
+	codes := make(map[string]struct{})
+
+	ao.Iterate(func(o *engine.Object) bool {
+		if o.Type() != engine.ObjectTypeMachine {
+			return true
+		}
+		for _, value := range o.AttrString(analyze.SCCMSiteSystemRoles) {
+			site, role, _ := strings.Cut(value, " ")
+			roles = append(roles, siteRole{o, site, role})
+			if site != "" {
+				codes[site] = struct{}{}
+			}
+		}
+		if site := o.OneAttrString(analyze.SCCMAssignedSite); site != "" {
+			clients[site] = append(clients[site], o)
+			codes[site] = struct{}{}
+		}
+		return true
+	})
+
+	if len(codes) == 0 {
+		return
+	}
+
+	// Roles where we couldn't tell the site go to the only site there is
+	var onlysite string
+	if len(codes) == 1 {
+		for code := range codes {
+			onlysite = code
+		}
+	}
+
+	sitecodes := make([]string, 0, len(codes))
+	for code := range codes {
+		sitecodes = append(sitecodes, code)
+	}
+	sort.Strings(sitecodes)
+
+	sites := make(map[string]*engine.Object)
+	for _, code := range sitecodes {
+		sites[code] = ao.AddNew(
+			engine.Type, ObjectTypeSCCMSite.ValueString(),
+			engine.Name, "SCCM site "+code,
+			SCCMSiteCode, code,
+		)
+	}
+
+	for _, sr := range roles {
+		if sr.site == "" {
+			sr.site = onlysite
+		}
+		site := sites[sr.site]
+		if site == nil {
+			ui.Debug().Msgf("Can't tell which SCCM site %v is %v for", sr.machine.Label(), sr.role)
+			continue
+		}
+		switch sr.role {
+		case analyze.SCCMRoleSiteServer:
+			sr.machine.EdgeTo(site, EdgeSCCMSiteServer)
+			for _, account := range sr.machine.AttrString(SCCMSiteAccounts) {
+				accountobject, found := ao.Find(engine.DownLevelLogonName, engine.AttributeValueString(account))
+				if !found {
+					ui.Debug().Msgf("Could not find SCCM site account %v", account)
+					continue
+				}
+				site.EdgeTo(accountobject, EdgeSCCMSiteAccount)
+			}
+		case analyze.SCCMRoleManagementPoint, analyze.SCCMRoleDistributionPoint:
+			site.EdgeTo(sr.machine, EdgeSCCMSiteSystem)
+		}
+	}
+
+	var clientcount int
+	for code, machines := range clients {
+		for _, machine := range machines {
+			sites[code].EdgeTo(machine, EdgeSCCMClient)
+			clientcount++
+		}
+	}
+
+	ui.Info().Msgf("Found %v SCCM sites with %v clients", len(sites), clientcount)
+}
+
+func init() {
+	loader.AddProcessor(
+		LinkSCCMSites,
+		"SCCM site hierarchy with site systems, clients and site accounts",
+		engine.AfterMerge,
+	)
+}
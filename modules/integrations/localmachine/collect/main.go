@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
@@ -21,6 +20,7 @@ import (
 	"github.com/gravwell/gravwell/v3/winevent"
 	"github.com/lkarlslund/adalanche/modules/basedata"
 	clicollect "github.com/lkarlslund/adalanche/modules/cli/collect"
+	"github.com/lkarlslund/adalanche/modules/encryption"
 	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
 	"github.com/lkarlslund/adalanche/modules/ui"
 	"github.com/lkarlslund/adalanche/modules/version"
@@ -74,7 +74,7 @@ func Execute(cmd *cobra.Command, args []string) error {
 	}
 
 	outputfile := filepath.Join(outputpath, targetname)
-	err = ioutil.WriteFile(outputfile, output, 0600)
+	err = encryption.WriteFile(outputfile, output, 0600)
 	if err != nil {
 		return fmt.Errorf("Problem writing to file %v: %v", outputfile, err)
 	}
@@ -190,6 +190,7 @@ func Collect() (localmachine.Info, error) {
 			machineinfo.AltDefaultUsername, _, _ = winlogon_key.GetStringValue(`AltDefaultUsername`)
 			machineinfo.AltDefaultDomain, _, _ = winlogon_key.GetStringValue(`AltDefaultDomain`)
 		}
+		machineinfo.CachedLogonsCount, _, _ = winlogon_key.GetStringValue(`CachedLogonsCount`)
 	}
 
 	// APP COMPAT CACHE - LAST 1024 PROGRAM EXECUTIONS
@@ -230,6 +231,7 @@ func Collect() (localmachine.Info, error) {
 		defer ccmsetup_key.Close()
 		machineinfo.SCCMLastValidMP, _, _ = ccmsetup_key.GetStringValue(`LastValidMP`)
 	}
+	collectSCCM(&machineinfo)
 
 	// WSUS SETTINGS
 	wu_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
@@ -248,11 +250,70 @@ func Collect() (localmachine.Info, error) {
 	if err == nil {
 		defer polsys_key.Close()
 		machineinfo.UACConsentPromptBehaviorAdmin, _, _ = polsys_key.GetIntegerValue(`ConsentPromptBehaviorAdmin`)
-		machineinfo.UACEnableLUA, _, _ = polsys_key.GetIntegerValue(`EnableLUA`)
+		enablelua, _, err := polsys_key.GetIntegerValue(`EnableLUA`)
+		if err != nil {
+			enablelua = 1 // Windows default when the value is missing
+		}
+		machineinfo.UACEnableLUA = &enablelua
 		machineinfo.UACLocalAccountTokenFilterPolicy, _, _ = polsys_key.GetIntegerValue(`LocalAccountTokenFilterPolicy`)
 		machineinfo.UACFilterAdministratorToken, _, _ = polsys_key.GetIntegerValue(`FilterAdministratorToken`)
 	}
 
+	// CREDENTIAL PROTECTION SETTINGS
+	lsa_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\Lsa`,
+		registry.READ|registry.WOW64_64KEY)
+	if err == nil {
+		defer lsa_key.Close()
+		machineinfo.LSARunAsPPL, _, _ = lsa_key.GetIntegerValue(`RunAsPPL`)
+		machineinfo.LSACfgFlags, _, _ = lsa_key.GetIntegerValue(`LsaCfgFlags`)
+		// Restricted Admin mode is only enabled if this is present and 0
+		disablerestrictedadmin, _, err := lsa_key.GetIntegerValue(`DisableRestrictedAdmin`)
+		machineinfo.RestrictedAdmin = err == nil && disablerestrictedadmin == 0
+	}
+	if machineinfo.LSACfgFlags == 0 {
+		deviceguard_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+			`SOFTWARE\Policies\Microsoft\Windows\DeviceGuard`,
+			registry.READ|registry.WOW64_64KEY)
+		if err == nil {
+			defer deviceguard_key.Close()
+			machineinfo.LSACfgFlags, _, _ = deviceguard_key.GetIntegerValue(`LsaCfgFlags`)
+		}
+	}
+	wdigest_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\SecurityProviders\WDigest`,
+		registry.READ|registry.WOW64_64KEY)
+	if err == nil {
+		defer wdigest_key.Close()
+		machineinfo.WDigestUseLogonCredential, _, _ = wdigest_key.GetIntegerValue(`UseLogonCredential`)
+	}
+	lanmanserver_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Services\LanmanServer\Parameters`,
+		registry.READ|registry.WOW64_64KEY)
+	if err == nil {
+		defer lanmanserver_key.Close()
+		machineinfo.SMBRequireSecuritySignature, _, _ = lanmanserver_key.GetIntegerValue(`RequireSecuritySignature`)
+	}
+
+	// SYSTEM PATH, services looking for DLLs that aren't anywhere else end up searching these
+	environment_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\Session Manager\Environment`,
+		registry.READ|registry.WOW64_64KEY)
+	if err == nil {
+		defer environment_key.Close()
+		systempath, _, _ := environment_key.GetStringValue(`Path`)
+		for _, directory := range strings.Split(systempath, ";") {
+			if directory == "" {
+				continue
+			}
+			if ps, err := getPathSecurity(directory); err == nil {
+				machineinfo.PathDirectories = append(machineinfo.PathDirectories, ps)
+			} else {
+				ui.Debug().Msgf("Problem getting security info for PATH folder %v: %v", directory, err)
+			}
+		}
+	}
+
 	// SHARES
 	var sharesinfo localmachine.Shares
 
@@ -643,6 +704,8 @@ func Collect() (localmachine.Info, error) {
 						var imagepathowner string
 						var imageexecutable string
 						var imagepathdacl []byte
+						var imagedirectory localmachine.PathSecurity
+						var unquotedcandidates []localmachine.PathSecurity
 
 						if imagepath != "" {
 							// Windows service executable names is a hot effin mess
@@ -684,6 +747,21 @@ func Collect() (localmachine.Info, error) {
 								}
 							}
 							ui.Debug().Msgf("Imagepath %v is mapped to executable %v", imagepath, executable)
+
+							// Windows tries each prefix of an unquoted path ending at a space before getting to the real executable
+							if imagepath[0] != '"' {
+								for i, c := range executable {
+									if c != ' ' {
+										continue
+									}
+									candidate := executable[:i] + ".exe"
+									if ps, err := getPathSecurity(filepath.Dir(candidate)); err == nil {
+										ps.Path = candidate
+										unquotedcandidates = append(unquotedcandidates, ps)
+									}
+								}
+							}
+
 							executable = resolvepath(executable)
 							imageexecutable = executable
 							if executable != "" {
@@ -694,6 +772,9 @@ func Collect() (localmachine.Info, error) {
 								} else {
 									ui.Warn().Msgf("Problem getting security info for %v: %v", executable, err)
 								}
+								if imagedirectory, err = getPathSecurity(filepath.Dir(executable)); err != nil {
+									ui.Warn().Msgf("Problem getting security info for folder with %v: %v", executable, err)
+								}
 							} else {
 								ui.Warn().Msgf("Could not resolve executable %v", imagepath)
 							}
@@ -709,6 +790,8 @@ func Collect() (localmachine.Info, error) {
 							ImageExecutable:      imageexecutable,
 							ImageExecutableOwner: imagepathowner,
 							ImageExecutableDACL:  imagepathdacl,
+							ImageDirectory:       imagedirectory,
+							UnquotedCandidates:   unquotedcandidates,
 							Start:                int(start),
 							Type:                 int(stype),
 							Account:              objectname,
@@ -3,8 +3,11 @@ package collect
 import (
 	"strings"
 
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
 	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
 	"github.com/shirou/gopsutil/v3/host"
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
 
@@ -39,3 +42,16 @@ func resolvepath(input string) string {
 	}
 	return output
 }
+
+// Owner and DACL of a file or folder, the path is stored as given
+func getPathSecurity(path string) (localmachine.PathSecurity, error) {
+	ownersid, dacl, err := windowssecurity.GetOwnerAndDACL(resolvepath(path), windows.SE_FILE_OBJECT)
+	if err != nil {
+		return localmachine.PathSecurity{}, err
+	}
+	return localmachine.PathSecurity{
+		Path:  path,
+		Owner: ownersid.String(),
+		DACL:  dacl,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package collect
+
+import (
+	"github.com/lkarlslund/adalanche/modules/integrations/localmachine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/yusufpapurcu/wmi"
+	"golang.org/x/sys/windows/registry"
+)
+
+type smsProviderLocation struct {
+	SiteCode string
+}
+
+type smsSCIReserved struct {
+	UserName string
+}
+
+// collectSCCM finds the site a client is assigned to, and for site servers the accounts configured in the site
+// (client push, network access and others). Only the user names are read, passwords stay encrypted in the site
+func collectSCCM(machineinfo *localmachine.Machine) {
+	client_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SOFTWARE\Microsoft\SMS\Mobile Client`,
+		registry.READ|registry.WOW64_64KEY)
+	if err == nil {
+		defer client_key.Close()
+		machineinfo.SCCMAssignedSiteCode, _, _ = client_key.GetStringValue(`AssignedSiteCode`)
+	}
+
+	identification_key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SOFTWARE\Microsoft\SMS\Identification`,
+		registry.READ|registry.WOW64_64KEY)
+	if err != nil {
+		return
+	}
+	defer identification_key.Close()
+	machineinfo.SCCMSiteServerCode, _, _ = identification_key.GetStringValue(`Site Code`)
+	if machineinfo.SCCMSiteServerCode == "" {
+		return
+	}
+
+	var providers []smsProviderLocation
+	err = wmi.QueryNamespace("SELECT SiteCode FROM SMS_ProviderLocation WHERE ProviderForLocalSite = TRUE", &providers, `root\SMS`)
+	if err != nil || len(providers) == 0 {
+		ui.Debug().Msgf("No local SMS Provider on site server: %v", err)
+		return
+	}
+	var accounts []smsSCIReserved
+	err = wmi.QueryNamespace("SELECT UserName FROM SMS_SCI_Reserved", &accounts, `root\SMS\site_`+providers[0].SiteCode)
+	if err != nil {
+		ui.Warn().Msgf("Problem reading SCCM site accounts: %v", err)
+		return
+	}
+	for _, account := range accounts {
+		if account.UserName != "" {
+			machineinfo.SCCMSiteAccounts = append(machineinfo.SCCMSiteAccounts, account.UserName)
+		}
+	}
+}
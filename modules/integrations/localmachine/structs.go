@@ -59,15 +59,34 @@ type Machine struct {
 
 	AppCache [][]byte `json:",omitempty"`
 
-	SCCMLastValidMP string `json:",omitempty"`
+	SCCMLastValidMP      string   `json:",omitempty"`
+	SCCMAssignedSiteCode string   `json:",omitempty"` // Site this client belongs to
+	SCCMSiteServerCode   string   `json:",omitempty"` // Set if this is a site server
+	SCCMSiteAccounts     []string `json:",omitempty"` // Accounts stored in the site, if the SMS Provider is on this machine
 
 	WUServer       string `json:",omitempty"`
 	WUStatusServer string `json:",omitempty"`
 
-	UACConsentPromptBehaviorAdmin    uint64 `json:",omitempty"`
-	UACEnableLUA                     uint64 `json:",omitempty"`
-	UACLocalAccountTokenFilterPolicy uint64 `json:",omitempty"`
-	UACFilterAdministratorToken      uint64 `json:",omitempty"`
+	UACConsentPromptBehaviorAdmin    uint64  `json:",omitempty"`
+	UACEnableLUA                     *uint64 `json:",omitempty"` // Nil if the UAC settings couldn't be read
+	UACLocalAccountTokenFilterPolicy uint64  `json:",omitempty"`
+	UACFilterAdministratorToken      uint64  `json:",omitempty"`
+
+	LSARunAsPPL                 uint64 `json:",omitempty"` // 1 or 2 if LSASS runs as a protected process
+	LSACfgFlags                 uint64 `json:",omitempty"` // 1 or 2 if Credential Guard is enabled
+	WDigestUseLogonCredential   uint64 `json:",omitempty"` // 1 if cleartext passwords are kept in memory
+	CachedLogonsCount           string `json:",omitempty"` // Blank means the default of 10
+	RestrictedAdmin             bool   `json:",omitempty"`
+	SMBRequireSecuritySignature uint64 `json:",omitempty"`
+
+	PathDirectories []PathSecurity `json:",omitempty"` // Folders in the system PATH, used when searching for DLLs
+}
+
+// Owner and DACL of a file or folder
+type PathSecurity struct {
+	Path  string `json:",omitempty"`
+	Owner string `json:",omitempty"`
+	DACL  []byte `json:",omitempty"`
 }
 
 type Availability struct {
@@ -114,6 +133,9 @@ type Service struct {
 	ImageExecutableOwner string `json:",omitempty"`
 	ImageExecutableDACL  []byte `json:",omitempty"`
 
+	ImageDirectory     PathSecurity   `json:",omitempty"` // Folder with the executable, searched first for DLLs
+	UnquotedCandidates []PathSecurity `json:",omitempty"` // Executables tried before the real one because the image path is unquoted, with security of the folder they would be in
+
 	Start int `json:",omitempty"`
 	Type  int `json:",omitempty"`
 
@@ -1172,6 +1172,31 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 			} else {
 				out.ImageExecutableDACL = in.Bytes()
 			}
+		case "ImageDirectory":
+			(out.ImageDirectory).UnmarshalEasyJSON(in)
+		case "UnquotedCandidates":
+			if in.IsNull() {
+				in.Skip()
+				out.UnquotedCandidates = nil
+			} else {
+				in.Delim('[')
+				if out.UnquotedCandidates == nil {
+					if !in.IsDelim(']') {
+						out.UnquotedCandidates = make([]PathSecurity, 0, 1)
+					} else {
+						out.UnquotedCandidates = []PathSecurity{}
+					}
+				} else {
+					out.UnquotedCandidates = (out.UnquotedCandidates)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v18 PathSecurity
+					(v18).UnmarshalEasyJSON(in)
+					out.UnquotedCandidates = append(out.UnquotedCandidates, v18)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "Start":
 			out.Start = int(in.Int())
 		case "Type":
@@ -1196,9 +1221,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.RequiredPrivileges = (out.RequiredPrivileges)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v18 string
-					v18 = string(in.String())
-					out.RequiredPrivileges = append(out.RequiredPrivileges, v18)
+					var v19 string
+					v19 = string(in.String())
+					out.RequiredPrivileges = append(out.RequiredPrivileges, v19)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1303,6 +1328,35 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		out.Base64Bytes(in.ImageExecutableDACL)
 	}
+	if true {
+		const prefix string = ",\"ImageDirectory\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(in.ImageDirectory).MarshalEasyJSON(out)
+	}
+	if len(in.UnquotedCandidates) != 0 {
+		const prefix string = ",\"UnquotedCandidates\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v24, v25 := range in.UnquotedCandidates {
+				if v24 > 0 {
+					out.RawByte(',')
+				}
+				(v25).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	if in.Start != 0 {
 		const prefix string = ",\"Start\":"
 		if first {
@@ -1353,11 +1407,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v23, v24 := range in.RequiredPrivileges {
-				if v23 > 0 {
+			for v26, v27 := range in.RequiredPrivileges {
+				if v26 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v24))
+				out.String(string(v27))
 			}
 			out.RawByte(']')
 		}
@@ -1745,9 +1799,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.AssignedSIDs = (out.AssignedSIDs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v25 string
-					v25 = string(in.String())
-					out.AssignedSIDs = append(out.AssignedSIDs, v25)
+					var v28 string
+					v28 = string(in.String())
+					out.AssignedSIDs = append(out.AssignedSIDs, v28)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -1782,11 +1836,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v26, v27 := range in.AssignedSIDs {
-				if v26 > 0 {
+			for v29, v30 := range in.AssignedSIDs {
+				if v29 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v27))
+				out.String(string(v30))
 			}
 			out.RawByte(']')
 		}
@@ -1944,7 +1998,103 @@ func (v *Principal) UnmarshalJSON(data []byte) error {
 func (v *Principal) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine10(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(in *jlexer.Lexer, out *NetworkInterfaceInfo) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(in *jlexer.Lexer, out *PathSecurity) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Path":
+			out.Path = string(in.String())
+		case "Owner":
+			out.Owner = string(in.String())
+		case "DACL":
+			if in.IsNull() {
+				in.Skip()
+				out.DACL = nil
+			} else {
+				out.DACL = in.Bytes()
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(out *jwriter.Writer, in PathSecurity) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Path != "" {
+		const prefix string = ",\"Path\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Path))
+	}
+	if in.Owner != "" {
+		const prefix string = ",\"Owner\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Owner))
+	}
+	if len(in.DACL) != 0 {
+		const prefix string = ",\"DACL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Base64Bytes(in.DACL)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PathSecurity) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PathSecurity) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PathSecurity) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PathSecurity) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(l, v)
+}
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(in *jlexer.Lexer, out *NetworkInterfaceInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1985,9 +2135,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Addresses = (out.Addresses)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v28 string
-					v28 = string(in.String())
-					out.Addresses = append(out.Addresses, v28)
+					var v34 string
+					v34 = string(in.String())
+					out.Addresses = append(out.Addresses, v34)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2002,7 +2152,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(out *jwriter.Writer, in NetworkInterfaceInfo) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(out *jwriter.Writer, in NetworkInterfaceInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2042,11 +2192,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v29, v30 := range in.Addresses {
-				if v29 > 0 {
+			for v35, v36 := range in.Addresses {
+				if v35 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v30))
+				out.String(string(v36))
 			}
 			out.RawByte(']')
 		}
@@ -2057,27 +2207,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v NetworkInterfaceInfo) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v NetworkInterfaceInfo) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *NetworkInterfaceInfo) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *NetworkInterfaceInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine11(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(in *jlexer.Lexer, out *NetworkInformation) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(in *jlexer.Lexer, out *NetworkInformation) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2114,9 +2264,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.NetworkInterfaces = (out.NetworkInterfaces)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v31 NetworkInterfaceInfo
-					(v31).UnmarshalEasyJSON(in)
-					out.NetworkInterfaces = append(out.NetworkInterfaces, v31)
+					var v37 NetworkInterfaceInfo
+					(v37).UnmarshalEasyJSON(in)
+					out.NetworkInterfaces = append(out.NetworkInterfaces, v37)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2131,7 +2281,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(out *jwriter.Writer, in NetworkInformation) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(out *jwriter.Writer, in NetworkInformation) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2151,11 +2301,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v32, v33 := range in.NetworkInterfaces {
-				if v32 > 0 {
+			for v38, v39 := range in.NetworkInterfaces {
+				if v38 > 0 {
 					out.RawByte(',')
 				}
-				(v33).MarshalEasyJSON(out)
+				(v39).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2166,27 +2316,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v NetworkInformation) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v NetworkInformation) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *NetworkInformation) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *NetworkInformation) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine12(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(in *jlexer.Lexer, out *Member) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(in *jlexer.Lexer, out *Member) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2219,7 +2369,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(out *jwriter.Writer, in Member) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(out *jwriter.Writer, in Member) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2245,27 +2395,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v Member) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Member) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Member) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Member) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine13(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(in *jlexer.Lexer, out *Machine) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(in *jlexer.Lexer, out *Machine) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2340,20 +2490,47 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.AppCache = (out.AppCache)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v34 []uint8
+					var v40 []uint8
 					if in.IsNull() {
 						in.Skip()
-						v34 = nil
+						v40 = nil
 					} else {
-						v34 = in.Bytes()
+						v40 = in.Bytes()
 					}
-					out.AppCache = append(out.AppCache, v34)
+					out.AppCache = append(out.AppCache, v40)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
 		case "SCCMLastValidMP":
 			out.SCCMLastValidMP = string(in.String())
+		case "SCCMAssignedSiteCode":
+			out.SCCMAssignedSiteCode = string(in.String())
+		case "SCCMSiteServerCode":
+			out.SCCMSiteServerCode = string(in.String())
+		case "SCCMSiteAccounts":
+			if in.IsNull() {
+				in.Skip()
+				out.SCCMSiteAccounts = nil
+			} else {
+				in.Delim('[')
+				if out.SCCMSiteAccounts == nil {
+					if !in.IsDelim(']') {
+						out.SCCMSiteAccounts = make([]string, 0, 4)
+					} else {
+						out.SCCMSiteAccounts = []string{}
+					}
+				} else {
+					out.SCCMSiteAccounts = (out.SCCMSiteAccounts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v42 string
+					v42 = string(in.String())
+					out.SCCMSiteAccounts = append(out.SCCMSiteAccounts, v42)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "WUServer":
 			out.WUServer = string(in.String())
 		case "WUStatusServer":
@@ -2361,11 +2538,54 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		case "UACConsentPromptBehaviorAdmin":
 			out.UACConsentPromptBehaviorAdmin = uint64(in.Uint64())
 		case "UACEnableLUA":
-			out.UACEnableLUA = uint64(in.Uint64())
+			if in.IsNull() {
+				in.Skip()
+				out.UACEnableLUA = nil
+			} else {
+				if out.UACEnableLUA == nil {
+					out.UACEnableLUA = new(uint64)
+				}
+				*out.UACEnableLUA = uint64(in.Uint64())
+			}
 		case "UACLocalAccountTokenFilterPolicy":
 			out.UACLocalAccountTokenFilterPolicy = uint64(in.Uint64())
 		case "UACFilterAdministratorToken":
 			out.UACFilterAdministratorToken = uint64(in.Uint64())
+		case "LSARunAsPPL":
+			out.LSARunAsPPL = uint64(in.Uint64())
+		case "LSACfgFlags":
+			out.LSACfgFlags = uint64(in.Uint64())
+		case "WDigestUseLogonCredential":
+			out.WDigestUseLogonCredential = uint64(in.Uint64())
+		case "CachedLogonsCount":
+			out.CachedLogonsCount = string(in.String())
+		case "RestrictedAdmin":
+			out.RestrictedAdmin = bool(in.Bool())
+		case "SMBRequireSecuritySignature":
+			out.SMBRequireSecuritySignature = uint64(in.Uint64())
+		case "PathDirectories":
+			if in.IsNull() {
+				in.Skip()
+				out.PathDirectories = nil
+			} else {
+				in.Delim('[')
+				if out.PathDirectories == nil {
+					if !in.IsDelim(']') {
+						out.PathDirectories = make([]PathSecurity, 0, 1)
+					} else {
+						out.PathDirectories = []PathSecurity{}
+					}
+				} else {
+					out.PathDirectories = (out.PathDirectories)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 PathSecurity
+					(v43).UnmarshalEasyJSON(in)
+					out.PathDirectories = append(out.PathDirectories, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2376,7 +2596,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(out *jwriter.Writer, in Machine) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(out *jwriter.Writer, in Machine) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2586,11 +2806,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v36, v37 := range in.AppCache {
-				if v36 > 0 {
+			for v44, v45 := range in.AppCache {
+				if v44 > 0 {
 					out.RawByte(',')
 				}
-				out.Base64Bytes(v37)
+				out.Base64Bytes(v45)
 			}
 			out.RawByte(']')
 		}
@@ -2605,6 +2825,45 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		out.String(string(in.SCCMLastValidMP))
 	}
+	if in.SCCMAssignedSiteCode != "" {
+		const prefix string = ",\"SCCMAssignedSiteCode\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SCCMAssignedSiteCode))
+	}
+	if in.SCCMSiteServerCode != "" {
+		const prefix string = ",\"SCCMSiteServerCode\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.SCCMSiteServerCode))
+	}
+	if len(in.SCCMSiteAccounts) != 0 {
+		const prefix string = ",\"SCCMSiteAccounts\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v48, v49 := range in.SCCMSiteAccounts {
+				if v48 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v49))
+			}
+			out.RawByte(']')
+		}
+	}
 	if in.WUServer != "" {
 		const prefix string = ",\"WUServer\":"
 		if first {
@@ -2635,7 +2894,7 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		out.Uint64(uint64(in.UACConsentPromptBehaviorAdmin))
 	}
-	if in.UACEnableLUA != 0 {
+	if in.UACEnableLUA != nil {
 		const prefix string = ",\"UACEnableLUA\":"
 		if first {
 			first = false
@@ -2643,7 +2902,7 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		} else {
 			out.RawString(prefix)
 		}
-		out.Uint64(uint64(in.UACEnableLUA))
+		out.Uint64(uint64(*in.UACEnableLUA))
 	}
 	if in.UACLocalAccountTokenFilterPolicy != 0 {
 		const prefix string = ",\"UACLocalAccountTokenFilterPolicy\":"
@@ -2665,33 +2924,112 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		out.Uint64(uint64(in.UACFilterAdministratorToken))
 	}
+	if in.LSARunAsPPL != 0 {
+		const prefix string = ",\"LSARunAsPPL\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.LSARunAsPPL))
+	}
+	if in.LSACfgFlags != 0 {
+		const prefix string = ",\"LSACfgFlags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.LSACfgFlags))
+	}
+	if in.WDigestUseLogonCredential != 0 {
+		const prefix string = ",\"WDigestUseLogonCredential\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.WDigestUseLogonCredential))
+	}
+	if in.CachedLogonsCount != "" {
+		const prefix string = ",\"CachedLogonsCount\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.CachedLogonsCount))
+	}
+	if in.RestrictedAdmin {
+		const prefix string = ",\"RestrictedAdmin\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.RestrictedAdmin))
+	}
+	if in.SMBRequireSecuritySignature != 0 {
+		const prefix string = ",\"SMBRequireSecuritySignature\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Uint64(uint64(in.SMBRequireSecuritySignature))
+	}
+	if len(in.PathDirectories) != 0 {
+		const prefix string = ",\"PathDirectories\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v50, v51 := range in.PathDirectories {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				(v51).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v Machine) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Machine) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Machine) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Machine) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine14(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(in *jlexer.Lexer, out *LoginPopularity) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(in *jlexer.Lexer, out *LoginPopularity) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2726,9 +3064,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Day = (out.Day)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v40 LoginCount
-					(v40).UnmarshalEasyJSON(in)
-					out.Day = append(out.Day, v40)
+					var v52 LoginCount
+					(v52).UnmarshalEasyJSON(in)
+					out.Day = append(out.Day, v52)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2749,9 +3087,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Week = (out.Week)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v41 LoginCount
-					(v41).UnmarshalEasyJSON(in)
-					out.Week = append(out.Week, v41)
+					var v53 LoginCount
+					(v53).UnmarshalEasyJSON(in)
+					out.Week = append(out.Week, v53)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2772,9 +3110,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Month = (out.Month)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v42 LoginCount
-					(v42).UnmarshalEasyJSON(in)
-					out.Month = append(out.Month, v42)
+					var v54 LoginCount
+					(v54).UnmarshalEasyJSON(in)
+					out.Month = append(out.Month, v54)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2789,7 +3127,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(out *jwriter.Writer, in LoginPopularity) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(out *jwriter.Writer, in LoginPopularity) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2800,11 +3138,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v43, v44 := range in.Day {
-				if v43 > 0 {
+			for v55, v56 := range in.Day {
+				if v55 > 0 {
 					out.RawByte(',')
 				}
-				(v44).MarshalEasyJSON(out)
+				(v56).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2816,11 +3154,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v45, v46 := range in.Week {
-				if v45 > 0 {
+			for v57, v58 := range in.Week {
+				if v57 > 0 {
 					out.RawByte(',')
 				}
-				(v46).MarshalEasyJSON(out)
+				(v58).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2832,11 +3170,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v47, v48 := range in.Month {
-				if v47 > 0 {
+			for v59, v60 := range in.Month {
+				if v59 > 0 {
 					out.RawByte(',')
 				}
-				(v48).MarshalEasyJSON(out)
+				(v60).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -2847,27 +3185,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v LoginPopularity) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v LoginPopularity) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *LoginPopularity) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *LoginPopularity) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine15(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(in *jlexer.Lexer, out *LoginCount) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(in *jlexer.Lexer, out *LoginCount) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2902,7 +3240,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(out *jwriter.Writer, in LoginCount) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(out *jwriter.Writer, in LoginCount) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2938,27 +3276,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v LoginCount) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v LoginCount) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *LoginCount) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *LoginCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine16(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(in *jlexer.Lexer, out *Info) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(in *jlexer.Lexer, out *Info) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3003,9 +3341,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Users = (out.Users)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v49 User
-					(v49).UnmarshalEasyJSON(in)
-					out.Users = append(out.Users, v49)
+					var v61 User
+					(v61).UnmarshalEasyJSON(in)
+					out.Users = append(out.Users, v61)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3026,9 +3364,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Groups = (out.Groups)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v50 Group
-					(v50).UnmarshalEasyJSON(in)
-					out.Groups = append(out.Groups, v50)
+					var v62 Group
+					(v62).UnmarshalEasyJSON(in)
+					out.Groups = append(out.Groups, v62)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3049,9 +3387,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Shares = (out.Shares)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v51 Share
-					(v51).UnmarshalEasyJSON(in)
-					out.Shares = append(out.Shares, v51)
+					var v63 Share
+					(v63).UnmarshalEasyJSON(in)
+					out.Shares = append(out.Shares, v63)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3072,9 +3410,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Services = (out.Services)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v52 Service
-					(v52).UnmarshalEasyJSON(in)
-					out.Services = append(out.Services, v52)
+					var v64 Service
+					(v64).UnmarshalEasyJSON(in)
+					out.Services = append(out.Services, v64)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3095,9 +3433,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Software = (out.Software)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v53 Software
-					(v53).UnmarshalEasyJSON(in)
-					out.Software = append(out.Software, v53)
+					var v65 Software
+					(v65).UnmarshalEasyJSON(in)
+					out.Software = append(out.Software, v65)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3118,9 +3456,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Tasks = (out.Tasks)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v54 RegisteredTask
-					(v54).UnmarshalEasyJSON(in)
-					out.Tasks = append(out.Tasks, v54)
+					var v66 RegisteredTask
+					(v66).UnmarshalEasyJSON(in)
+					out.Tasks = append(out.Tasks, v66)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3141,9 +3479,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Privileges = (out.Privileges)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v55 Privilege
-					(v55).UnmarshalEasyJSON(in)
-					out.Privileges = append(out.Privileges, v55)
+					var v67 Privilege
+					(v67).UnmarshalEasyJSON(in)
+					out.Privileges = append(out.Privileges, v67)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3168,7 +3506,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(out *jwriter.Writer, in Info) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(out *jwriter.Writer, in Info) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3228,11 +3566,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v56, v57 := range in.Users {
-				if v56 > 0 {
+			for v68, v69 := range in.Users {
+				if v68 > 0 {
 					out.RawByte(',')
 				}
-				(v57).MarshalEasyJSON(out)
+				(v69).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3247,11 +3585,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v58, v59 := range in.Groups {
-				if v58 > 0 {
+			for v70, v71 := range in.Groups {
+				if v70 > 0 {
 					out.RawByte(',')
 				}
-				(v59).MarshalEasyJSON(out)
+				(v71).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3266,11 +3604,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v60, v61 := range in.Shares {
-				if v60 > 0 {
+			for v72, v73 := range in.Shares {
+				if v72 > 0 {
 					out.RawByte(',')
 				}
-				(v61).MarshalEasyJSON(out)
+				(v73).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3285,11 +3623,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v62, v63 := range in.Services {
-				if v62 > 0 {
+			for v74, v75 := range in.Services {
+				if v74 > 0 {
 					out.RawByte(',')
 				}
-				(v63).MarshalEasyJSON(out)
+				(v75).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3304,11 +3642,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v64, v65 := range in.Software {
-				if v64 > 0 {
+			for v76, v77 := range in.Software {
+				if v76 > 0 {
 					out.RawByte(',')
 				}
-				(v65).MarshalEasyJSON(out)
+				(v77).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3323,11 +3661,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v66, v67 := range in.Tasks {
-				if v66 > 0 {
+			for v78, v79 := range in.Tasks {
+				if v78 > 0 {
 					out.RawByte(',')
 				}
-				(v67).MarshalEasyJSON(out)
+				(v79).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3342,11 +3680,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v68, v69 := range in.Privileges {
-				if v68 > 0 {
+			for v80, v81 := range in.Privileges {
+				if v80 > 0 {
 					out.RawByte(',')
 				}
-				(v69).MarshalEasyJSON(out)
+				(v81).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3382,27 +3720,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v Info) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Info) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Info) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Info) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine17(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(in *jlexer.Lexer, out *Group) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(in *jlexer.Lexer, out *Group) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3443,9 +3781,9 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 					out.Members = (out.Members)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v70 Member
-					(v70).UnmarshalEasyJSON(in)
-					out.Members = append(out.Members, v70)
+					var v82 Member
+					(v82).UnmarshalEasyJSON(in)
+					out.Members = append(out.Members, v82)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -3460,7 +3798,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(out *jwriter.Writer, in Group) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(out *jwriter.Writer, in Group) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3500,11 +3838,11 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		}
 		{
 			out.RawByte('[')
-			for v71, v72 := range in.Members {
-				if v71 > 0 {
+			for v83, v84 := range in.Members {
+				if v83 > 0 {
 					out.RawByte(',')
 				}
-				(v72).MarshalEasyJSON(out)
+				(v84).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -3515,27 +3853,27 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v Group) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Group) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Group) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Group) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine18(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(l, v)
 }
-func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(in *jlexer.Lexer, out *Availability) {
+func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(in *jlexer.Lexer, out *Availability) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -3570,7 +3908,7 @@ func easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 		in.Consumed()
 	}
 }
-func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(out *jwriter.Writer, in Availability) {
+func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(out *jwriter.Writer, in Availability) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -3606,23 +3944,23 @@ func easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalm
 // MarshalJSON supports json.Marshaler interface
 func (v Availability) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(&w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Availability) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(w, v)
+	easyjson6a975c40EncodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Availability) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(&r, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Availability) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine19(l, v)
+	easyjson6a975c40DecodeGithubComLkarlslundAdalancheModulesIntegrationsLocalmachine20(l, v)
 }
@@ -2358,6 +2358,37 @@ func (z *Machine) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "SCCMLastValidMP")
 				return
 			}
+		case "SCCMAssignedSiteCode":
+			z.SCCMAssignedSiteCode, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMAssignedSiteCode")
+				return
+			}
+		case "SCCMSiteServerCode":
+			z.SCCMSiteServerCode, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMSiteServerCode")
+				return
+			}
+		case "SCCMSiteAccounts":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMSiteAccounts")
+				return
+			}
+			if cap(z.SCCMSiteAccounts) >= int(zb0003) {
+				z.SCCMSiteAccounts = (z.SCCMSiteAccounts)[:zb0003]
+			} else {
+				z.SCCMSiteAccounts = make([]string, zb0003)
+			}
+			for za0002 := range z.SCCMSiteAccounts {
+				z.SCCMSiteAccounts[za0002], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "SCCMSiteAccounts", za0002)
+					return
+				}
+			}
 		case "WUServer":
 			z.WUServer, err = dc.ReadString()
 			if err != nil {
@@ -2377,10 +2408,22 @@ func (z *Machine) DecodeMsg(dc *msgp.Reader) (err error) {
 				return
 			}
 		case "UACEnableLUA":
-			z.UACEnableLUA, err = dc.ReadUint64()
-			if err != nil {
-				err = msgp.WrapError(err, "UACEnableLUA")
-				return
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "UACEnableLUA")
+					return
+				}
+				z.UACEnableLUA = nil
+			} else {
+				if z.UACEnableLUA == nil {
+					z.UACEnableLUA = new(uint64)
+				}
+				*z.UACEnableLUA, err = dc.ReadUint64()
+				if err != nil {
+					err = msgp.WrapError(err, "UACEnableLUA")
+					return
+				}
 			}
 		case "UACLocalAccountTokenFilterPolicy":
 			z.UACLocalAccountTokenFilterPolicy, err = dc.ReadUint64()
@@ -2394,6 +2437,96 @@ func (z *Machine) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "UACFilterAdministratorToken")
 				return
 			}
+		case "LSARunAsPPL":
+			z.LSARunAsPPL, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "LSARunAsPPL")
+				return
+			}
+		case "LSACfgFlags":
+			z.LSACfgFlags, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "LSACfgFlags")
+				return
+			}
+		case "WDigestUseLogonCredential":
+			z.WDigestUseLogonCredential, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "WDigestUseLogonCredential")
+				return
+			}
+		case "CachedLogonsCount":
+			z.CachedLogonsCount, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "CachedLogonsCount")
+				return
+			}
+		case "RestrictedAdmin":
+			z.RestrictedAdmin, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "RestrictedAdmin")
+				return
+			}
+		case "SMBRequireSecuritySignature":
+			z.SMBRequireSecuritySignature, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "SMBRequireSecuritySignature")
+				return
+			}
+		case "PathDirectories":
+			var zb0004 uint32
+			zb0004, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "PathDirectories")
+				return
+			}
+			if cap(z.PathDirectories) >= int(zb0004) {
+				z.PathDirectories = (z.PathDirectories)[:zb0004]
+			} else {
+				z.PathDirectories = make([]PathSecurity, zb0004)
+			}
+			for za0003 := range z.PathDirectories {
+				var zb0005 uint32
+				zb0005, err = dc.ReadMapHeader()
+				if err != nil {
+					err = msgp.WrapError(err, "PathDirectories", za0003)
+					return
+				}
+				for zb0005 > 0 {
+					zb0005--
+					field, err = dc.ReadMapKeyPtr()
+					if err != nil {
+						err = msgp.WrapError(err, "PathDirectories", za0003)
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "Path":
+						z.PathDirectories[za0003].Path, err = dc.ReadString()
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "Path")
+							return
+						}
+					case "Owner":
+						z.PathDirectories[za0003].Owner, err = dc.ReadString()
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "Owner")
+							return
+						}
+					case "DACL":
+						z.PathDirectories[za0003].DACL, err = dc.ReadBytes(z.PathDirectories[za0003].DACL)
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "DACL")
+							return
+						}
+					default:
+						err = dc.Skip()
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003)
+							return
+						}
+					}
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -2407,9 +2540,9 @@ func (z *Machine) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Machine) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 28
+	// map header, size 38
 	// write "Name"
-	err = en.Append(0xde, 0x0, 0x1c, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
+	err = en.Append(0xde, 0x0, 0x26, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
 	if err != nil {
 		return
 	}
@@ -2635,6 +2768,43 @@ func (z *Machine) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "SCCMLastValidMP")
 		return
 	}
+	// write "SCCMAssignedSiteCode"
+	err = en.Append(0xb4, 0x53, 0x43, 0x43, 0x4d, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.SCCMAssignedSiteCode)
+	if err != nil {
+		err = msgp.WrapError(err, "SCCMAssignedSiteCode")
+		return
+	}
+	// write "SCCMSiteServerCode"
+	err = en.Append(0xb2, 0x53, 0x43, 0x43, 0x4d, 0x53, 0x69, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x43, 0x6f, 0x64, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.SCCMSiteServerCode)
+	if err != nil {
+		err = msgp.WrapError(err, "SCCMSiteServerCode")
+		return
+	}
+	// write "SCCMSiteAccounts"
+	err = en.Append(0xb0, 0x53, 0x43, 0x43, 0x4d, 0x53, 0x69, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.SCCMSiteAccounts)))
+	if err != nil {
+		err = msgp.WrapError(err, "SCCMSiteAccounts")
+		return
+	}
+	for za0002 := range z.SCCMSiteAccounts {
+		err = en.WriteString(z.SCCMSiteAccounts[za0002])
+		if err != nil {
+			err = msgp.WrapError(err, "SCCMSiteAccounts", za0002)
+			return
+		}
+	}
 	// write "WUServer"
 	err = en.Append(0xa8, 0x57, 0x55, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72)
 	if err != nil {
@@ -2670,10 +2840,17 @@ func (z *Machine) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
-	err = en.WriteUint64(z.UACEnableLUA)
-	if err != nil {
-		err = msgp.WrapError(err, "UACEnableLUA")
-		return
+	if z.UACEnableLUA == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = en.WriteUint64(*z.UACEnableLUA)
+		if err != nil {
+			err = msgp.WrapError(err, "UACEnableLUA")
+			return
+		}
 	}
 	// write "UACLocalAccountTokenFilterPolicy"
 	err = en.Append(0xd9, 0x20, 0x55, 0x41, 0x43, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
@@ -2695,15 +2872,118 @@ func (z *Machine) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "UACFilterAdministratorToken")
 		return
 	}
+	// write "LSARunAsPPL"
+	err = en.Append(0xab, 0x4c, 0x53, 0x41, 0x52, 0x75, 0x6e, 0x41, 0x73, 0x50, 0x50, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.LSARunAsPPL)
+	if err != nil {
+		err = msgp.WrapError(err, "LSARunAsPPL")
+		return
+	}
+	// write "LSACfgFlags"
+	err = en.Append(0xab, 0x4c, 0x53, 0x41, 0x43, 0x66, 0x67, 0x46, 0x6c, 0x61, 0x67, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.LSACfgFlags)
+	if err != nil {
+		err = msgp.WrapError(err, "LSACfgFlags")
+		return
+	}
+	// write "WDigestUseLogonCredential"
+	err = en.Append(0xb9, 0x57, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x55, 0x73, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x6e, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.WDigestUseLogonCredential)
+	if err != nil {
+		err = msgp.WrapError(err, "WDigestUseLogonCredential")
+		return
+	}
+	// write "CachedLogonsCount"
+	err = en.Append(0xb1, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.CachedLogonsCount)
+	if err != nil {
+		err = msgp.WrapError(err, "CachedLogonsCount")
+		return
+	}
+	// write "RestrictedAdmin"
+	err = en.Append(0xaf, 0x52, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x64, 0x41, 0x64, 0x6d, 0x69, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.RestrictedAdmin)
+	if err != nil {
+		err = msgp.WrapError(err, "RestrictedAdmin")
+		return
+	}
+	// write "SMBRequireSecuritySignature"
+	err = en.Append(0xbb, 0x53, 0x4d, 0x42, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.SMBRequireSecuritySignature)
+	if err != nil {
+		err = msgp.WrapError(err, "SMBRequireSecuritySignature")
+		return
+	}
+	// write "PathDirectories"
+	err = en.Append(0xaf, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.PathDirectories)))
+	if err != nil {
+		err = msgp.WrapError(err, "PathDirectories")
+		return
+	}
+	for za0003 := range z.PathDirectories {
+		// map header, size 3
+		// write "Path"
+		err = en.Append(0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.PathDirectories[za0003].Path)
+		if err != nil {
+			err = msgp.WrapError(err, "PathDirectories", za0003, "Path")
+			return
+		}
+		// write "Owner"
+		err = en.Append(0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.PathDirectories[za0003].Owner)
+		if err != nil {
+			err = msgp.WrapError(err, "PathDirectories", za0003, "Owner")
+			return
+		}
+		// write "DACL"
+		err = en.Append(0xa4, 0x44, 0x41, 0x43, 0x4c)
+		if err != nil {
+			return
+		}
+		err = en.WriteBytes(z.PathDirectories[za0003].DACL)
+		if err != nil {
+			err = msgp.WrapError(err, "PathDirectories", za0003, "DACL")
+			return
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Machine) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 28
+	// map header, size 38
 	// string "Name"
-	o = append(o, 0xde, 0x0, 0x1c, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
+	o = append(o, 0xde, 0x0, 0x26, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
 	o = msgp.AppendString(o, z.Name)
 	// string "LocalSID"
 	o = append(o, 0xa8, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x49, 0x44)
@@ -2771,6 +3051,18 @@ func (z *Machine) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "SCCMLastValidMP"
 	o = append(o, 0xaf, 0x53, 0x43, 0x43, 0x4d, 0x4c, 0x61, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x4d, 0x50)
 	o = msgp.AppendString(o, z.SCCMLastValidMP)
+	// string "SCCMAssignedSiteCode"
+	o = append(o, 0xb4, 0x53, 0x43, 0x43, 0x4d, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65)
+	o = msgp.AppendString(o, z.SCCMAssignedSiteCode)
+	// string "SCCMSiteServerCode"
+	o = append(o, 0xb2, 0x53, 0x43, 0x43, 0x4d, 0x53, 0x69, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x43, 0x6f, 0x64, 0x65)
+	o = msgp.AppendString(o, z.SCCMSiteServerCode)
+	// string "SCCMSiteAccounts"
+	o = append(o, 0xb0, 0x53, 0x43, 0x43, 0x4d, 0x53, 0x69, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.SCCMSiteAccounts)))
+	for za0002 := range z.SCCMSiteAccounts {
+		o = msgp.AppendString(o, z.SCCMSiteAccounts[za0002])
+	}
 	// string "WUServer"
 	o = append(o, 0xa8, 0x57, 0x55, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72)
 	o = msgp.AppendString(o, z.WUServer)
@@ -2782,13 +3074,50 @@ func (z *Machine) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.AppendUint64(o, z.UACConsentPromptBehaviorAdmin)
 	// string "UACEnableLUA"
 	o = append(o, 0xac, 0x55, 0x41, 0x43, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x4c, 0x55, 0x41)
-	o = msgp.AppendUint64(o, z.UACEnableLUA)
+	if z.UACEnableLUA == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o = msgp.AppendUint64(o, *z.UACEnableLUA)
+	}
 	// string "UACLocalAccountTokenFilterPolicy"
 	o = append(o, 0xd9, 0x20, 0x55, 0x41, 0x43, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
 	o = msgp.AppendUint64(o, z.UACLocalAccountTokenFilterPolicy)
 	// string "UACFilterAdministratorToken"
 	o = append(o, 0xbb, 0x55, 0x41, 0x43, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e)
 	o = msgp.AppendUint64(o, z.UACFilterAdministratorToken)
+	// string "LSARunAsPPL"
+	o = append(o, 0xab, 0x4c, 0x53, 0x41, 0x52, 0x75, 0x6e, 0x41, 0x73, 0x50, 0x50, 0x4c)
+	o = msgp.AppendUint64(o, z.LSARunAsPPL)
+	// string "LSACfgFlags"
+	o = append(o, 0xab, 0x4c, 0x53, 0x41, 0x43, 0x66, 0x67, 0x46, 0x6c, 0x61, 0x67, 0x73)
+	o = msgp.AppendUint64(o, z.LSACfgFlags)
+	// string "WDigestUseLogonCredential"
+	o = append(o, 0xb9, 0x57, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x55, 0x73, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x6e, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c)
+	o = msgp.AppendUint64(o, z.WDigestUseLogonCredential)
+	// string "CachedLogonsCount"
+	o = append(o, 0xb1, 0x43, 0x61, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+	o = msgp.AppendString(o, z.CachedLogonsCount)
+	// string "RestrictedAdmin"
+	o = append(o, 0xaf, 0x52, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x65, 0x64, 0x41, 0x64, 0x6d, 0x69, 0x6e)
+	o = msgp.AppendBool(o, z.RestrictedAdmin)
+	// string "SMBRequireSecuritySignature"
+	o = append(o, 0xbb, 0x53, 0x4d, 0x42, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65)
+	o = msgp.AppendUint64(o, z.SMBRequireSecuritySignature)
+	// string "PathDirectories"
+	o = append(o, 0xaf, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.PathDirectories)))
+	for za0003 := range z.PathDirectories {
+		// map header, size 3
+		// string "Path"
+		o = append(o, 0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+		o = msgp.AppendString(o, z.PathDirectories[za0003].Path)
+		// string "Owner"
+		o = append(o, 0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+		o = msgp.AppendString(o, z.PathDirectories[za0003].Owner)
+		// string "DACL"
+		o = append(o, 0xa4, 0x44, 0x41, 0x43, 0x4c)
+		o = msgp.AppendBytes(o, z.PathDirectories[za0003].DACL)
+	}
 	return
 }
 
@@ -2955,6 +3284,37 @@ func (z *Machine) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "SCCMLastValidMP")
 				return
 			}
+		case "SCCMAssignedSiteCode":
+			z.SCCMAssignedSiteCode, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMAssignedSiteCode")
+				return
+			}
+		case "SCCMSiteServerCode":
+			z.SCCMSiteServerCode, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMSiteServerCode")
+				return
+			}
+		case "SCCMSiteAccounts":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SCCMSiteAccounts")
+				return
+			}
+			if cap(z.SCCMSiteAccounts) >= int(zb0003) {
+				z.SCCMSiteAccounts = (z.SCCMSiteAccounts)[:zb0003]
+			} else {
+				z.SCCMSiteAccounts = make([]string, zb0003)
+			}
+			for za0002 := range z.SCCMSiteAccounts {
+				z.SCCMSiteAccounts[za0002], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "SCCMSiteAccounts", za0002)
+					return
+				}
+			}
 		case "WUServer":
 			z.WUServer, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
@@ -2974,10 +3334,21 @@ func (z *Machine) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		case "UACEnableLUA":
-			z.UACEnableLUA, bts, err = msgp.ReadUint64Bytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "UACEnableLUA")
-				return
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.UACEnableLUA = nil
+			} else {
+				if z.UACEnableLUA == nil {
+					z.UACEnableLUA = new(uint64)
+				}
+				*z.UACEnableLUA, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "UACEnableLUA")
+					return
+				}
 			}
 		case "UACLocalAccountTokenFilterPolicy":
 			z.UACLocalAccountTokenFilterPolicy, bts, err = msgp.ReadUint64Bytes(bts)
@@ -2991,6 +3362,96 @@ func (z *Machine) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "UACFilterAdministratorToken")
 				return
 			}
+		case "LSARunAsPPL":
+			z.LSARunAsPPL, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LSARunAsPPL")
+				return
+			}
+		case "LSACfgFlags":
+			z.LSACfgFlags, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LSACfgFlags")
+				return
+			}
+		case "WDigestUseLogonCredential":
+			z.WDigestUseLogonCredential, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "WDigestUseLogonCredential")
+				return
+			}
+		case "CachedLogonsCount":
+			z.CachedLogonsCount, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "CachedLogonsCount")
+				return
+			}
+		case "RestrictedAdmin":
+			z.RestrictedAdmin, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RestrictedAdmin")
+				return
+			}
+		case "SMBRequireSecuritySignature":
+			z.SMBRequireSecuritySignature, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SMBRequireSecuritySignature")
+				return
+			}
+		case "PathDirectories":
+			var zb0004 uint32
+			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "PathDirectories")
+				return
+			}
+			if cap(z.PathDirectories) >= int(zb0004) {
+				z.PathDirectories = (z.PathDirectories)[:zb0004]
+			} else {
+				z.PathDirectories = make([]PathSecurity, zb0004)
+			}
+			for za0003 := range z.PathDirectories {
+				var zb0005 uint32
+				zb0005, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "PathDirectories", za0003)
+					return
+				}
+				for zb0005 > 0 {
+					zb0005--
+					field, bts, err = msgp.ReadMapKeyZC(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "PathDirectories", za0003)
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "Path":
+						z.PathDirectories[za0003].Path, bts, err = msgp.ReadStringBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "Path")
+							return
+						}
+					case "Owner":
+						z.PathDirectories[za0003].Owner, bts, err = msgp.ReadStringBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "Owner")
+							return
+						}
+					case "DACL":
+						z.PathDirectories[za0003].DACL, bts, err = msgp.ReadBytesBytes(bts, z.PathDirectories[za0003].DACL)
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003, "DACL")
+							return
+						}
+					default:
+						bts, err = msgp.Skip(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "PathDirectories", za0003)
+							return
+						}
+					}
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -3009,7 +3470,20 @@ func (z *Machine) Msgsize() (s int) {
 	for za0001 := range z.AppCache {
 		s += msgp.BytesPrefixSize + len(z.AppCache[za0001])
 	}
-	s += 16 + msgp.StringPrefixSize + len(z.SCCMLastValidMP) + 9 + msgp.StringPrefixSize + len(z.WUServer) + 15 + msgp.StringPrefixSize + len(z.WUStatusServer) + 30 + msgp.Uint64Size + 13 + msgp.Uint64Size + 34 + msgp.Uint64Size + 28 + msgp.Uint64Size
+	s += 16 + msgp.StringPrefixSize + len(z.SCCMLastValidMP) + 21 + msgp.StringPrefixSize + len(z.SCCMAssignedSiteCode) + 19 + msgp.StringPrefixSize + len(z.SCCMSiteServerCode) + 17 + msgp.ArrayHeaderSize
+	for za0002 := range z.SCCMSiteAccounts {
+		s += msgp.StringPrefixSize + len(z.SCCMSiteAccounts[za0002])
+	}
+	s += 9 + msgp.StringPrefixSize + len(z.WUServer) + 15 + msgp.StringPrefixSize + len(z.WUStatusServer) + 30 + msgp.Uint64Size + 13
+	if z.UACEnableLUA == nil {
+		s += msgp.NilSize
+	} else {
+		s += msgp.Uint64Size
+	}
+	s += 34 + msgp.Uint64Size + 28 + msgp.Uint64Size + 12 + msgp.Uint64Size + 12 + msgp.Uint64Size + 26 + msgp.Uint64Size + 18 + msgp.StringPrefixSize + len(z.CachedLogonsCount) + 16 + msgp.BoolSize + 28 + msgp.Uint64Size + 16 + msgp.ArrayHeaderSize
+	for za0003 := range z.PathDirectories {
+		s += 1 + 5 + msgp.StringPrefixSize + len(z.PathDirectories[za0003].Path) + 6 + msgp.StringPrefixSize + len(z.PathDirectories[za0003].Owner) + 5 + msgp.BytesPrefixSize + len(z.PathDirectories[za0003].DACL)
+	}
 	return
 }
 
@@ -3421,40 +3895,215 @@ func (z *NetworkInterfaceInfo) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "Addresses")
 		return
 	}
-	for za0001 := range z.Addresses {
-		err = en.WriteString(z.Addresses[za0001])
-		if err != nil {
-			err = msgp.WrapError(err, "Addresses", za0001)
-			return
-		}
-	}
+	for za0001 := range z.Addresses {
+		err = en.WriteString(z.Addresses[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "Addresses", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *NetworkInterfaceInfo) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "Name"
+	o = append(o, 0x84, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
+	o = msgp.AppendString(o, z.Name)
+	// string "MACAddress"
+	o = append(o, 0xaa, 0x4d, 0x41, 0x43, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73)
+	o = msgp.AppendString(o, z.MACAddress)
+	// string "Flags"
+	o = append(o, 0xa5, 0x46, 0x6c, 0x61, 0x67, 0x73)
+	o = msgp.AppendUint(o, z.Flags)
+	// string "Addresses"
+	o = append(o, 0xa9, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Addresses)))
+	for za0001 := range z.Addresses {
+		o = msgp.AppendString(o, z.Addresses[za0001])
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *NetworkInterfaceInfo) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Name":
+			z.Name, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Name")
+				return
+			}
+		case "MACAddress":
+			z.MACAddress, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "MACAddress")
+				return
+			}
+		case "Flags":
+			z.Flags, bts, err = msgp.ReadUintBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Flags")
+				return
+			}
+		case "Addresses":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Addresses")
+				return
+			}
+			if cap(z.Addresses) >= int(zb0002) {
+				z.Addresses = (z.Addresses)[:zb0002]
+			} else {
+				z.Addresses = make([]string, zb0002)
+			}
+			for za0001 := range z.Addresses {
+				z.Addresses[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Addresses", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *NetworkInterfaceInfo) Msgsize() (s int) {
+	s = 1 + 5 + msgp.StringPrefixSize + len(z.Name) + 11 + msgp.StringPrefixSize + len(z.MACAddress) + 6 + msgp.UintSize + 10 + msgp.ArrayHeaderSize
+	for za0001 := range z.Addresses {
+		s += msgp.StringPrefixSize + len(z.Addresses[za0001])
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *PathSecurity) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Path":
+			z.Path, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Path")
+				return
+			}
+		case "Owner":
+			z.Owner, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Owner")
+				return
+			}
+		case "DACL":
+			z.DACL, err = dc.ReadBytes(z.DACL)
+			if err != nil {
+				err = msgp.WrapError(err, "DACL")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *PathSecurity) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "Path"
+	err = en.Append(0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Path)
+	if err != nil {
+		err = msgp.WrapError(err, "Path")
+		return
+	}
+	// write "Owner"
+	err = en.Append(0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Owner)
+	if err != nil {
+		err = msgp.WrapError(err, "Owner")
+		return
+	}
+	// write "DACL"
+	err = en.Append(0xa4, 0x44, 0x41, 0x43, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.DACL)
+	if err != nil {
+		err = msgp.WrapError(err, "DACL")
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *NetworkInterfaceInfo) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *PathSecurity) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 4
-	// string "Name"
-	o = append(o, 0x84, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
-	o = msgp.AppendString(o, z.Name)
-	// string "MACAddress"
-	o = append(o, 0xaa, 0x4d, 0x41, 0x43, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73)
-	o = msgp.AppendString(o, z.MACAddress)
-	// string "Flags"
-	o = append(o, 0xa5, 0x46, 0x6c, 0x61, 0x67, 0x73)
-	o = msgp.AppendUint(o, z.Flags)
-	// string "Addresses"
-	o = append(o, 0xa9, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.Addresses)))
-	for za0001 := range z.Addresses {
-		o = msgp.AppendString(o, z.Addresses[za0001])
-	}
+	// map header, size 3
+	// string "Path"
+	o = append(o, 0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+	o = msgp.AppendString(o, z.Path)
+	// string "Owner"
+	o = append(o, 0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	o = msgp.AppendString(o, z.Owner)
+	// string "DACL"
+	o = append(o, 0xa4, 0x44, 0x41, 0x43, 0x4c)
+	o = msgp.AppendBytes(o, z.DACL)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *NetworkInterfaceInfo) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *PathSecurity) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -3471,43 +4120,24 @@ func (z *NetworkInterfaceInfo) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "Name":
-			z.Name, bts, err = msgp.ReadStringBytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "Name")
-				return
-			}
-		case "MACAddress":
-			z.MACAddress, bts, err = msgp.ReadStringBytes(bts)
+		case "Path":
+			z.Path, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "MACAddress")
+				err = msgp.WrapError(err, "Path")
 				return
 			}
-		case "Flags":
-			z.Flags, bts, err = msgp.ReadUintBytes(bts)
+		case "Owner":
+			z.Owner, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "Flags")
+				err = msgp.WrapError(err, "Owner")
 				return
 			}
-		case "Addresses":
-			var zb0002 uint32
-			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		case "DACL":
+			z.DACL, bts, err = msgp.ReadBytesBytes(bts, z.DACL)
 			if err != nil {
-				err = msgp.WrapError(err, "Addresses")
+				err = msgp.WrapError(err, "DACL")
 				return
 			}
-			if cap(z.Addresses) >= int(zb0002) {
-				z.Addresses = (z.Addresses)[:zb0002]
-			} else {
-				z.Addresses = make([]string, zb0002)
-			}
-			for za0001 := range z.Addresses {
-				z.Addresses[za0001], bts, err = msgp.ReadStringBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "Addresses", za0001)
-					return
-				}
-			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -3521,11 +4151,8 @@ func (z *NetworkInterfaceInfo) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *NetworkInterfaceInfo) Msgsize() (s int) {
-	s = 1 + 5 + msgp.StringPrefixSize + len(z.Name) + 11 + msgp.StringPrefixSize + len(z.MACAddress) + 6 + msgp.UintSize + 10 + msgp.ArrayHeaderSize
-	for za0001 := range z.Addresses {
-		s += msgp.StringPrefixSize + len(z.Addresses[za0001])
-	}
+func (z *PathSecurity) Msgsize() (s int) {
+	s = 1 + 5 + msgp.StringPrefixSize + len(z.Path) + 6 + msgp.StringPrefixSize + len(z.Owner) + 5 + msgp.BytesPrefixSize + len(z.DACL)
 	return
 }
 
@@ -4787,6 +5414,101 @@ func (z *Service) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "ImageExecutableDACL")
 				return
 			}
+		case "ImageDirectory":
+			var zb0002 uint32
+			zb0002, err = dc.ReadMapHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "ImageDirectory")
+				return
+			}
+			for zb0002 > 0 {
+				zb0002--
+				field, err = dc.ReadMapKeyPtr()
+				if err != nil {
+					err = msgp.WrapError(err, "ImageDirectory")
+					return
+				}
+				switch msgp.UnsafeString(field) {
+				case "Path":
+					z.ImageDirectory.Path, err = dc.ReadString()
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "Path")
+						return
+					}
+				case "Owner":
+					z.ImageDirectory.Owner, err = dc.ReadString()
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "Owner")
+						return
+					}
+				case "DACL":
+					z.ImageDirectory.DACL, err = dc.ReadBytes(z.ImageDirectory.DACL)
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "DACL")
+						return
+					}
+				default:
+					err = dc.Skip()
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory")
+						return
+					}
+				}
+			}
+		case "UnquotedCandidates":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "UnquotedCandidates")
+				return
+			}
+			if cap(z.UnquotedCandidates) >= int(zb0003) {
+				z.UnquotedCandidates = (z.UnquotedCandidates)[:zb0003]
+			} else {
+				z.UnquotedCandidates = make([]PathSecurity, zb0003)
+			}
+			for za0001 := range z.UnquotedCandidates {
+				var zb0004 uint32
+				zb0004, err = dc.ReadMapHeader()
+				if err != nil {
+					err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+					return
+				}
+				for zb0004 > 0 {
+					zb0004--
+					field, err = dc.ReadMapKeyPtr()
+					if err != nil {
+						err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "Path":
+						z.UnquotedCandidates[za0001].Path, err = dc.ReadString()
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Path")
+							return
+						}
+					case "Owner":
+						z.UnquotedCandidates[za0001].Owner, err = dc.ReadString()
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Owner")
+							return
+						}
+					case "DACL":
+						z.UnquotedCandidates[za0001].DACL, err = dc.ReadBytes(z.UnquotedCandidates[za0001].DACL)
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "DACL")
+							return
+						}
+					default:
+						err = dc.Skip()
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+							return
+						}
+					}
+				}
+			}
 		case "Start":
 			z.Start, err = dc.ReadInt()
 			if err != nil {
@@ -4812,21 +5534,21 @@ func (z *Service) DecodeMsg(dc *msgp.Reader) (err error) {
 				return
 			}
 		case "RequiredPrivileges":
-			var zb0002 uint32
-			zb0002, err = dc.ReadArrayHeader()
+			var zb0005 uint32
+			zb0005, err = dc.ReadArrayHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "RequiredPrivileges")
 				return
 			}
-			if cap(z.RequiredPrivileges) >= int(zb0002) {
-				z.RequiredPrivileges = (z.RequiredPrivileges)[:zb0002]
+			if cap(z.RequiredPrivileges) >= int(zb0005) {
+				z.RequiredPrivileges = (z.RequiredPrivileges)[:zb0005]
 			} else {
-				z.RequiredPrivileges = make([]string, zb0002)
+				z.RequiredPrivileges = make([]string, zb0005)
 			}
-			for za0001 := range z.RequiredPrivileges {
-				z.RequiredPrivileges[za0001], err = dc.ReadString()
+			for za0002 := range z.RequiredPrivileges {
+				z.RequiredPrivileges[za0002], err = dc.ReadString()
 				if err != nil {
-					err = msgp.WrapError(err, "RequiredPrivileges", za0001)
+					err = msgp.WrapError(err, "RequiredPrivileges", za0002)
 					return
 				}
 			}
@@ -4843,9 +5565,9 @@ func (z *Service) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Service) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 14
+	// map header, size 16
 	// write "RegistryOwner"
-	err = en.Append(0x8e, 0xad, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	err = en.Append(0xde, 0x0, 0x10, 0xad, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72)
 	if err != nil {
 		return
 	}
@@ -4934,6 +5656,85 @@ func (z *Service) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "ImageExecutableDACL")
 		return
 	}
+	// write "ImageDirectory"
+	err = en.Append(0xae, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79)
+	if err != nil {
+		return
+	}
+	// map header, size 3
+	// write "Path"
+	err = en.Append(0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ImageDirectory.Path)
+	if err != nil {
+		err = msgp.WrapError(err, "ImageDirectory", "Path")
+		return
+	}
+	// write "Owner"
+	err = en.Append(0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ImageDirectory.Owner)
+	if err != nil {
+		err = msgp.WrapError(err, "ImageDirectory", "Owner")
+		return
+	}
+	// write "DACL"
+	err = en.Append(0xa4, 0x44, 0x41, 0x43, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.ImageDirectory.DACL)
+	if err != nil {
+		err = msgp.WrapError(err, "ImageDirectory", "DACL")
+		return
+	}
+	// write "UnquotedCandidates"
+	err = en.Append(0xb2, 0x55, 0x6e, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.UnquotedCandidates)))
+	if err != nil {
+		err = msgp.WrapError(err, "UnquotedCandidates")
+		return
+	}
+	for za0001 := range z.UnquotedCandidates {
+		// map header, size 3
+		// write "Path"
+		err = en.Append(0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.UnquotedCandidates[za0001].Path)
+		if err != nil {
+			err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Path")
+			return
+		}
+		// write "Owner"
+		err = en.Append(0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.UnquotedCandidates[za0001].Owner)
+		if err != nil {
+			err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Owner")
+			return
+		}
+		// write "DACL"
+		err = en.Append(0xa4, 0x44, 0x41, 0x43, 0x4c)
+		if err != nil {
+			return
+		}
+		err = en.WriteBytes(z.UnquotedCandidates[za0001].DACL)
+		if err != nil {
+			err = msgp.WrapError(err, "UnquotedCandidates", za0001, "DACL")
+			return
+		}
+	}
 	// write "Start"
 	err = en.Append(0xa5, 0x53, 0x74, 0x61, 0x72, 0x74)
 	if err != nil {
@@ -4984,10 +5785,10 @@ func (z *Service) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "RequiredPrivileges")
 		return
 	}
-	for za0001 := range z.RequiredPrivileges {
-		err = en.WriteString(z.RequiredPrivileges[za0001])
+	for za0002 := range z.RequiredPrivileges {
+		err = en.WriteString(z.RequiredPrivileges[za0002])
 		if err != nil {
-			err = msgp.WrapError(err, "RequiredPrivileges", za0001)
+			err = msgp.WrapError(err, "RequiredPrivileges", za0002)
 			return
 		}
 	}
@@ -4997,9 +5798,9 @@ func (z *Service) EncodeMsg(en *msgp.Writer) (err error) {
 // MarshalMsg implements msgp.Marshaler
 func (z *Service) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 14
+	// map header, size 16
 	// string "RegistryOwner"
-	o = append(o, 0x8e, 0xad, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	o = append(o, 0xde, 0x0, 0x10, 0xad, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72)
 	o = msgp.AppendString(o, z.RegistryOwner)
 	// string "RegistryDACL"
 	o = append(o, 0xac, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x44, 0x41, 0x43, 0x4c)
@@ -5025,6 +5826,33 @@ func (z *Service) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "ImageExecutableDACL"
 	o = append(o, 0xb3, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x44, 0x41, 0x43, 0x4c)
 	o = msgp.AppendBytes(o, z.ImageExecutableDACL)
+	// string "ImageDirectory"
+	o = append(o, 0xae, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79)
+	// map header, size 3
+	// string "Path"
+	o = append(o, 0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+	o = msgp.AppendString(o, z.ImageDirectory.Path)
+	// string "Owner"
+	o = append(o, 0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+	o = msgp.AppendString(o, z.ImageDirectory.Owner)
+	// string "DACL"
+	o = append(o, 0xa4, 0x44, 0x41, 0x43, 0x4c)
+	o = msgp.AppendBytes(o, z.ImageDirectory.DACL)
+	// string "UnquotedCandidates"
+	o = append(o, 0xb2, 0x55, 0x6e, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.UnquotedCandidates)))
+	for za0001 := range z.UnquotedCandidates {
+		// map header, size 3
+		// string "Path"
+		o = append(o, 0x83, 0xa4, 0x50, 0x61, 0x74, 0x68)
+		o = msgp.AppendString(o, z.UnquotedCandidates[za0001].Path)
+		// string "Owner"
+		o = append(o, 0xa5, 0x4f, 0x77, 0x6e, 0x65, 0x72)
+		o = msgp.AppendString(o, z.UnquotedCandidates[za0001].Owner)
+		// string "DACL"
+		o = append(o, 0xa4, 0x44, 0x41, 0x43, 0x4c)
+		o = msgp.AppendBytes(o, z.UnquotedCandidates[za0001].DACL)
+	}
 	// string "Start"
 	o = append(o, 0xa5, 0x53, 0x74, 0x61, 0x72, 0x74)
 	o = msgp.AppendInt(o, z.Start)
@@ -5040,8 +5868,8 @@ func (z *Service) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "RequiredPrivileges"
 	o = append(o, 0xb2, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x50, 0x72, 0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65, 0x73)
 	o = msgp.AppendArrayHeader(o, uint32(len(z.RequiredPrivileges)))
-	for za0001 := range z.RequiredPrivileges {
-		o = msgp.AppendString(o, z.RequiredPrivileges[za0001])
+	for za0002 := range z.RequiredPrivileges {
+		o = msgp.AppendString(o, z.RequiredPrivileges[za0002])
 	}
 	return
 }
@@ -5118,6 +5946,101 @@ func (z *Service) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "ImageExecutableDACL")
 				return
 			}
+		case "ImageDirectory":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ImageDirectory")
+				return
+			}
+			for zb0002 > 0 {
+				zb0002--
+				field, bts, err = msgp.ReadMapKeyZC(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "ImageDirectory")
+					return
+				}
+				switch msgp.UnsafeString(field) {
+				case "Path":
+					z.ImageDirectory.Path, bts, err = msgp.ReadStringBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "Path")
+						return
+					}
+				case "Owner":
+					z.ImageDirectory.Owner, bts, err = msgp.ReadStringBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "Owner")
+						return
+					}
+				case "DACL":
+					z.ImageDirectory.DACL, bts, err = msgp.ReadBytesBytes(bts, z.ImageDirectory.DACL)
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory", "DACL")
+						return
+					}
+				default:
+					bts, err = msgp.Skip(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "ImageDirectory")
+						return
+					}
+				}
+			}
+		case "UnquotedCandidates":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "UnquotedCandidates")
+				return
+			}
+			if cap(z.UnquotedCandidates) >= int(zb0003) {
+				z.UnquotedCandidates = (z.UnquotedCandidates)[:zb0003]
+			} else {
+				z.UnquotedCandidates = make([]PathSecurity, zb0003)
+			}
+			for za0001 := range z.UnquotedCandidates {
+				var zb0004 uint32
+				zb0004, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+					return
+				}
+				for zb0004 > 0 {
+					zb0004--
+					field, bts, err = msgp.ReadMapKeyZC(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "Path":
+						z.UnquotedCandidates[za0001].Path, bts, err = msgp.ReadStringBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Path")
+							return
+						}
+					case "Owner":
+						z.UnquotedCandidates[za0001].Owner, bts, err = msgp.ReadStringBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "Owner")
+							return
+						}
+					case "DACL":
+						z.UnquotedCandidates[za0001].DACL, bts, err = msgp.ReadBytesBytes(bts, z.UnquotedCandidates[za0001].DACL)
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001, "DACL")
+							return
+						}
+					default:
+						bts, err = msgp.Skip(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "UnquotedCandidates", za0001)
+							return
+						}
+					}
+				}
+			}
 		case "Start":
 			z.Start, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
@@ -5143,21 +6066,21 @@ func (z *Service) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		case "RequiredPrivileges":
-			var zb0002 uint32
-			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			var zb0005 uint32
+			zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "RequiredPrivileges")
 				return
 			}
-			if cap(z.RequiredPrivileges) >= int(zb0002) {
-				z.RequiredPrivileges = (z.RequiredPrivileges)[:zb0002]
+			if cap(z.RequiredPrivileges) >= int(zb0005) {
+				z.RequiredPrivileges = (z.RequiredPrivileges)[:zb0005]
 			} else {
-				z.RequiredPrivileges = make([]string, zb0002)
+				z.RequiredPrivileges = make([]string, zb0005)
 			}
-			for za0001 := range z.RequiredPrivileges {
-				z.RequiredPrivileges[za0001], bts, err = msgp.ReadStringBytes(bts)
+			for za0002 := range z.RequiredPrivileges {
+				z.RequiredPrivileges[za0002], bts, err = msgp.ReadStringBytes(bts)
 				if err != nil {
-					err = msgp.WrapError(err, "RequiredPrivileges", za0001)
+					err = msgp.WrapError(err, "RequiredPrivileges", za0002)
 					return
 				}
 			}
@@ -5175,9 +6098,13 @@ func (z *Service) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Service) Msgsize() (s int) {
-	s = 1 + 14 + msgp.StringPrefixSize + len(z.RegistryOwner) + 13 + msgp.BytesPrefixSize + len(z.RegistryDACL) + 5 + msgp.StringPrefixSize + len(z.Name) + 12 + msgp.StringPrefixSize + len(z.DisplayName) + 12 + msgp.StringPrefixSize + len(z.Description) + 10 + msgp.StringPrefixSize + len(z.ImagePath) + 16 + msgp.StringPrefixSize + len(z.ImageExecutable) + 21 + msgp.StringPrefixSize + len(z.ImageExecutableOwner) + 20 + msgp.BytesPrefixSize + len(z.ImageExecutableDACL) + 6 + msgp.IntSize + 5 + msgp.IntSize + 8 + msgp.StringPrefixSize + len(z.Account) + 11 + msgp.StringPrefixSize + len(z.AccountSID) + 19 + msgp.ArrayHeaderSize
-	for za0001 := range z.RequiredPrivileges {
-		s += msgp.StringPrefixSize + len(z.RequiredPrivileges[za0001])
+	s = 3 + 14 + msgp.StringPrefixSize + len(z.RegistryOwner) + 13 + msgp.BytesPrefixSize + len(z.RegistryDACL) + 5 + msgp.StringPrefixSize + len(z.Name) + 12 + msgp.StringPrefixSize + len(z.DisplayName) + 12 + msgp.StringPrefixSize + len(z.Description) + 10 + msgp.StringPrefixSize + len(z.ImagePath) + 16 + msgp.StringPrefixSize + len(z.ImageExecutable) + 21 + msgp.StringPrefixSize + len(z.ImageExecutableOwner) + 20 + msgp.BytesPrefixSize + len(z.ImageExecutableDACL) + 15 + 1 + 5 + msgp.StringPrefixSize + len(z.ImageDirectory.Path) + 6 + msgp.StringPrefixSize + len(z.ImageDirectory.Owner) + 5 + msgp.BytesPrefixSize + len(z.ImageDirectory.DACL) + 19 + msgp.ArrayHeaderSize
+	for za0001 := range z.UnquotedCandidates {
+		s += 1 + 5 + msgp.StringPrefixSize + len(z.UnquotedCandidates[za0001].Path) + 6 + msgp.StringPrefixSize + len(z.UnquotedCandidates[za0001].Owner) + 5 + msgp.BytesPrefixSize + len(z.UnquotedCandidates[za0001].DACL)
+	}
+	s += 6 + msgp.IntSize + 5 + msgp.IntSize + 8 + msgp.StringPrefixSize + len(z.Account) + 11 + msgp.StringPrefixSize + len(z.AccountSID) + 19 + msgp.ArrayHeaderSize
+	for za0002 := range z.RequiredPrivileges {
+		s += msgp.StringPrefixSize + len(z.RequiredPrivileges[za0002])
 	}
 	return
 }
@@ -0,0 +1,86 @@
+package analyze
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/lkarlslund/adalanche/modules/encryption"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+const loadername = "Nmap and masscan port scans"
+
+var (
+	loader = engine.AddLoader(func() engine.Loader { return &PortScanLoader{} })
+
+	// Scans are applied to machines after merge, as they're matched on addresses and names from other sources
+	pendingLock  sync.Mutex
+	pendingScans []portscan.Scan
+)
+
+// PortScanLoader doesn't create any objects, it only decorates the machines found by other loaders
+type PortScanLoader struct {
+	ao    *engine.Objects
+	lock  sync.Mutex
+	scans []portscan.Scan
+}
+
+func (ld *PortScanLoader) Name() string {
+	return loadername
+}
+
+func (ld *PortScanLoader) Init() error {
+	ld.ao = engine.NewLoaderObjects(ld)
+	return nil
+}
+
+func (ld *PortScanLoader) Close() ([]*engine.Objects, error) {
+	pendingLock.Lock()
+	pendingScans = append(pendingScans, ld.scans...)
+	pendingLock.Unlock()
+
+	result := []*engine.Objects{ld.ao}
+	ld.ao = nil
+	ld.scans = nil
+	return result, nil
+}
+
+func (ld *PortScanLoader) Load(path string, cb engine.ProgressCallbackFunc) error {
+	var suffix string
+	switch {
+	case strings.HasSuffix(strings.ToLower(path), portscan.MasscanSuffix):
+		suffix = portscan.MasscanSuffix
+	case strings.HasSuffix(strings.ToLower(path), portscan.NmapSuffix):
+		suffix = portscan.NmapSuffix
+	default:
+		return engine.ErrUninterested
+	}
+
+	raw, err := encryption.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var scan portscan.Scan
+	if suffix == portscan.MasscanSuffix {
+		scan, err = portscan.ParseMasscan(raw)
+	} else {
+		if !portscan.IsNmap(raw) {
+			// Some other XML file
+			return engine.ErrUninterested
+		}
+		scan, err = portscan.ParseNmap(raw)
+	}
+	if err != nil {
+		return err
+	}
+	scan.Segment = portscan.Segment(path, suffix)
+	ui.Debug().Msgf("Loaded port scan from segment %v with %v hosts", scan.Segment, len(scan.Hosts))
+
+	ld.lock.Lock()
+	ld.scans = append(ld.scans, scan)
+	ld.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+	"github.com/lkarlslund/adalanche/modules/ui"
+)
+
+// Addresses from collectors include the prefix length (10.0.0.5/24)
+func normalizeAddress(address string) string {
+	address, _, _ = strings.Cut(address, "/")
+	if addr, err := netip.ParseAddr(address); err == nil {
+		return addr.Unmap().String()
+	}
+	return ""
+}
+
+type machinePorts struct {
+	open, openfrom, closedfrom map[string]struct{}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func init() {
+	loader.AddProcessor(func(ao *engine.Objects) {
+		pendingLock.Lock()
+		scans := pendingScans
+		pendingScans = nil
+		pendingLock.Unlock()
+
+		if len(scans) == 0 {
+			return
+		}
+		applyScans(ao, scans)
+	},
+		"Network reachability from port scans",
+		engine.AfterMergeLow,
+	)
+}
+
+// Records the open ports and the ports edges require that were found closed on the machines matching the scanned hosts
+func applyScans(ao *engine.Objects, scans []portscan.Scan) {
+	byaddress := make(map[string][]*engine.Object)
+	byname := make(map[string][]*engine.Object)
+	ao.Iterate(func(o *engine.Object) bool {
+		if o.Type() != engine.ObjectTypeMachine {
+			return true
+		}
+		o.Attr(engine.IPAddress).Iterate(func(value engine.AttributeValue) bool {
+			if address := normalizeAddress(value.String()); address != "" {
+				byaddress[address] = append(byaddress[address], o)
+			}
+			return true
+		})
+		o.Attr(lmanalyze.DNSHostname).Iterate(func(value engine.AttributeValue) bool {
+			name := strings.ToLower(value.String())
+			byname[name] = append(byname[name], o)
+			return true
+		})
+		return true
+	})
+
+	requiredports := engine.RequiredPorts()
+	results := make(map[*engine.Object]*machinePorts)
+
+	var hosts, matched int
+	for _, scan := range scans {
+		for _, host := range scan.Hosts {
+			hosts++
+
+			machines := make(map[*engine.Object]struct{})
+			for _, address := range host.Addresses {
+				for _, machine := range byaddress[normalizeAddress(address)] {
+					machines[machine] = struct{}{}
+				}
+			}
+			for _, hostname := range host.Hostnames {
+				for _, machine := range byname[strings.ToLower(hostname)] {
+					machines[machine] = struct{}{}
+				}
+			}
+			if len(machines) == 0 {
+				ui.Debug().Msgf("Scanned host %v with names %v doesn't match any machine", host.Addresses, host.Hostnames)
+				continue
+			}
+			matched++
+
+			open := make(map[portscan.Port]struct{})
+			for _, port := range host.Open {
+				open[port] = struct{}{}
+			}
+
+			for machine := range machines {
+				mp := results[machine]
+				if mp == nil {
+					mp = &machinePorts{
+						open:       make(map[string]struct{}),
+						openfrom:   make(map[string]struct{}),
+						closedfrom: make(map[string]struct{}),
+					}
+					results[machine] = mp
+				}
+				for port := range open {
+					mp.open[port.String()] = struct{}{}
+					mp.openfrom[scan.Segment+" "+port.String()] = struct{}{}
+				}
+				for _, number := range requiredports {
+					port := portscan.Port{Number: number, Protocol: "tcp"}
+					if _, scanned := scan.Scanned[port]; !scanned {
+						continue
+					}
+					if _, isopen := open[port]; !isopen {
+						mp.closedfrom[scan.Segment+" "+port.String()] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	for machine, mp := range results {
+		machine.SetFlex(
+			engine.IgnoreBlanks,
+			engine.OpenPorts, sortedKeys(mp.open),
+			engine.OpenPortsFrom, sortedKeys(mp.openfrom),
+			engine.ClosedPortsFrom, sortedKeys(mp.closedfrom),
+		)
+	}
+
+	ui.Info().Msgf("Matched %v of %v scanned hosts to %v machines", matched, hosts, len(results))
+}
@@ -0,0 +1,79 @@
+package analyze
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	lmanalyze "github.com/lkarlslund/adalanche/modules/integrations/localmachine/analyze"
+	"github.com/lkarlslund/adalanche/modules/integrations/portscan"
+)
+
+func loadScan(t *testing.T, path, suffix string, parse func([]byte) (portscan.Scan, error)) portscan.Scan {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scan, err := parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scan.Segment = portscan.Segment(path, suffix)
+	return scan
+}
+
+func TestApplyScans(t *testing.T) {
+	scans := []portscan.Scan{
+		loadScan(t, "../testdata/office.nmap.xml", portscan.NmapSuffix, portscan.ParseNmap),
+		loadScan(t, "../testdata/dmz.masscan.json", portscan.MasscanSuffix, portscan.ParseMasscan),
+		{
+			// RDP was not part of this scan
+			Segment: "vpn",
+			Scanned: map[portscan.Port]struct{}{{Number: 445, Protocol: "tcp"}: {}},
+			Hosts:   []portscan.Host{{Addresses: []string{"10.0.0.77"}}},
+		},
+	}
+
+	machine := func(flexinit ...any) *engine.Object {
+		return engine.NewObject(append([]any{engine.Type, engine.ObjectTypeMachine.ValueString()}, flexinit...)...)
+	}
+	dc01 := machine(engine.IPAddress, "10.0.0.5/24", lmanalyze.DNSHostname, "DC01.contoso.local")
+	ws01 := machine(lmanalyze.DNSHostname, "ws01.contoso.local")
+	ws02 := machine(engine.IPAddress, "10.0.0.30/24")
+	ws03 := machine(engine.IPAddress, "10.0.0.77/24")
+	ws04 := machine(engine.IPAddress, "10.0.0.88/24")
+	user := engine.NewObject(engine.Type, engine.ObjectTypeUser.ValueString())
+
+	ao := engine.NewObjects()
+	ao.Add(dc01, ws01, ws02, ws03, ws04, user)
+	applyScans(ao, scans)
+
+	tests := []struct {
+		name       string
+		machine    *engine.Object
+		open       []string
+		closedfrom []string
+		rdp        bool
+	}{
+		{"open", dc01, []string{"3389/tcp", "445/tcp"}, []string{"office 22/tcp"}, true},
+		{"closed when scanned", ws01, []string{"22/tcp"}, []string{"office 3389/tcp", "office 445/tcp"}, false},
+		{"closed when masscan found it open elsewhere", ws02, []string{"80/tcp"}, []string{"dmz 3389/tcp", "dmz 445/tcp"}, false},
+		{"not scanned", ws03, nil, []string{"vpn 445/tcp"}, true},
+		{"not in any scan", ws04, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if open := tt.machine.AttrString(engine.OpenPorts); !slices.Equal(open, tt.open) {
+				t.Errorf("open ports %v, expected %v", open, tt.open)
+			}
+			if closed := tt.machine.AttrString(engine.ClosedPortsFrom); !slices.Equal(closed, tt.closedfrom) {
+				t.Errorf("closed ports %v, expected %v", closed, tt.closedfrom)
+			}
+			if probability := lmanalyze.EdgeLocalRDPRights.Probability(user, tt.machine); (probability != 0) != tt.rdp {
+				t.Errorf("RDP probability is %v", probability)
+			}
+		})
+	}
+}
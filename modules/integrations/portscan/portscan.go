@@ -0,0 +1,309 @@
+package portscan
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	NmapSuffix    = ".xml"
+	MasscanSuffix = ".masscan.json"
+)
+
+var ErrNotNmap = errors.New("not an Nmap XML file")
+
+type Port struct {
+	Number   int
+	Protocol string
+}
+
+func (p Port) String() string {
+	return strconv.Itoa(p.Number) + "/" + p.Protocol
+}
+
+type Host struct {
+	Addresses []string
+	Hostnames []string
+	Open      []Port
+}
+
+// Scan is the result of one scan run
+type Scan struct {
+	Segment string            // Where the scan was done from
+	Scanned map[Port]struct{} // Ports that were probed on all hosts
+	Hosts   []Host
+}
+
+// Segment returns the network segment a scan file was made from, which is the file name without the suffix
+func Segment(path, suffix string) string {
+	name := filepath.Base(path)
+	name = name[:len(name)-len(suffix)]
+	return strings.TrimSuffix(name, ".nmap")
+}
+
+// IsNmap checks the start of the file for the Nmap root element
+func IsNmap(data []byte) bool {
+	return bytes.Contains(data[:min(len(data), 4096)], []byte("<nmaprun"))
+}
+
+type nmapRun struct {
+	XMLName  xml.Name       `xml:"nmaprun"`
+	ScanInfo []nmapScanInfo `xml:"scaninfo"`
+	Hosts    []nmapHost     `xml:"host"`
+}
+
+type nmapScanInfo struct {
+	Protocol string `xml:"protocol,attr"`
+	Services string `xml:"services,attr"`
+}
+
+type nmapHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []nmapAddress  `xml:"address"`
+	Hostnames []nmapHostname `xml:"hostnames>hostname"`
+	Ports     []nmapPort     `xml:"ports>port"`
+}
+
+type nmapAddress struct {
+	Addr     string `xml:"addr,attr"`
+	AddrType string `xml:"addrtype,attr"`
+}
+
+type nmapHostname struct {
+	Name string `xml:"name,attr"`
+}
+
+type nmapPort struct {
+	Protocol string `xml:"protocol,attr"`
+	PortID   int    `xml:"portid,attr"`
+	State    struct {
+		State string `xml:"state,attr"`
+	} `xml:"state"`
+}
+
+// ParseNmap reads Nmap XML output (-oX)
+func ParseNmap(data []byte) (Scan, error) {
+	if !IsNmap(data) {
+		return Scan{}, ErrNotNmap
+	}
+
+	var run nmapRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return Scan{}, err
+	}
+
+	scan := Scan{
+		Scanned: make(map[Port]struct{}),
+	}
+	for _, info := range run.ScanInfo {
+		if err := addPortRanges(scan.Scanned, info.Services, info.Protocol); err != nil {
+			return scan, fmt.Errorf("invalid scanned services %v: %w", info.Services, err)
+		}
+	}
+
+	for _, nmaphost := range run.Hosts {
+		if nmaphost.Status.State == "down" {
+			continue
+		}
+		var host Host
+		for _, address := range nmaphost.Addresses {
+			if address.AddrType == "ipv4" || address.AddrType == "ipv6" {
+				host.Addresses = append(host.Addresses, address.Addr)
+			}
+		}
+		for _, hostname := range nmaphost.Hostnames {
+			host.Hostnames = append(host.Hostnames, hostname.Name)
+		}
+		for _, port := range nmaphost.Ports {
+			p := Port{Number: port.PortID, Protocol: port.Protocol}
+			if len(run.ScanInfo) == 0 {
+				// Old or hand edited output, everything listed was at least probed
+				scan.Scanned[p] = struct{}{}
+			}
+			if port.State.State == "open" {
+				host.Open = append(host.Open, p)
+			}
+		}
+		scan.Hosts = append(scan.Hosts, host)
+	}
+	return scan, nil
+}
+
+// MarshalNmap writes the scan as minimal Nmap XML, with the scanned ports and only the open ports of each host
+func (scan Scan) MarshalNmap() ([]byte, error) {
+	var run nmapRun
+	for _, protocol := range []string{"tcp", "udp", "sctp"} {
+		if services := portRanges(scan.Scanned, protocol); services != "" {
+			run.ScanInfo = append(run.ScanInfo, nmapScanInfo{Protocol: protocol, Services: services})
+		}
+	}
+	for _, host := range scan.Hosts {
+		var nmaphost nmapHost
+		nmaphost.Status.State = "up"
+		for _, address := range host.Addresses {
+			addrtype := "ipv4"
+			if strings.Contains(address, ":") {
+				addrtype = "ipv6"
+			}
+			nmaphost.Addresses = append(nmaphost.Addresses, nmapAddress{Addr: address, AddrType: addrtype})
+		}
+		for _, hostname := range host.Hostnames {
+			nmaphost.Hostnames = append(nmaphost.Hostnames, nmapHostname{Name: hostname})
+		}
+		for _, port := range host.Open {
+			nmapport := nmapPort{Protocol: port.Protocol, PortID: port.Number}
+			nmapport.State.State = "open"
+			nmaphost.Ports = append(nmaphost.Ports, nmapport)
+		}
+		run.Hosts = append(run.Hosts, nmaphost)
+	}
+	data, err := xml.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// Sorted ports of a protocol as 1,3-4,6
+func portRanges(ports map[Port]struct{}, protocol string) string {
+	var numbers []int
+	for port := range ports {
+		if port.Protocol == protocol {
+			numbers = append(numbers, port.Number)
+		}
+	}
+	sort.Ints(numbers)
+
+	var result []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			result = append(result, strconv.Itoa(numbers[i]))
+		} else {
+			result = append(result, strconv.Itoa(numbers[i])+"-"+strconv.Itoa(numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(result, ",")
+}
+
+// 1,3-4,6-7,9
+func addPortRanges(ports map[Port]struct{}, ranges, protocol string) error {
+	for _, item := range strings.Split(ranges, ",") {
+		if item == "" {
+			continue
+		}
+		first, last, isrange := strings.Cut(item, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return err
+		}
+		to := from
+		if isrange {
+			if to, err = strconv.Atoi(last); err != nil {
+				return err
+			}
+		}
+		for port := from; port <= to && port <= 65535; port++ {
+			ports[Port{Number: port, Protocol: protocol}] = struct{}{}
+		}
+	}
+	return nil
+}
+
+type masscanRecord struct {
+	IP    string        `json:"ip"`
+	Ports []masscanPort `json:"ports"`
+}
+
+type masscanPort struct {
+	Port   int    `json:"port"`
+	Proto  string `json:"proto"`
+	Status string `json:"status"`
+}
+
+// MarshalMasscan writes the scan as masscan NDJSON, one record per open port like masscan does
+func (scan Scan) MarshalMasscan() ([]byte, error) {
+	var result []byte
+	for _, host := range scan.Hosts {
+		for _, address := range host.Addresses {
+			for _, port := range host.Open {
+				line, err := json.Marshal(masscanRecord{
+					IP:    address,
+					Ports: []masscanPort{{Port: port.Number, Proto: port.Protocol, Status: "open"}},
+				})
+				if err != nil {
+					return nil, err
+				}
+				result = append(append(result, line...), '\n')
+			}
+		}
+	}
+	return result, nil
+}
+
+// ParseMasscan reads masscan JSON (-oJ) or NDJSON output. Masscan only reports open ports, so the ports found open
+// on any host count as scanned on all of them
+func ParseMasscan(data []byte) (Scan, error) {
+	var records []masscanRecord
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		// Older versions leave a comma after the last record
+		data = bytes.TrimSuffix(bytes.TrimSpace(bytes.TrimSuffix(data, []byte("]"))), []byte(","))
+		data = append(data, ']')
+		if err := json.Unmarshal(data, &records); err != nil {
+			return Scan{}, err
+		}
+	} else {
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			line = bytes.TrimSuffix(bytes.TrimSpace(line), []byte(","))
+			if len(line) == 0 {
+				continue
+			}
+			var record masscanRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				return Scan{}, err
+			}
+			records = append(records, record)
+		}
+	}
+
+	scan := Scan{
+		Scanned: make(map[Port]struct{}),
+	}
+	// Masscan writes one record per open port, so combine them per host
+	hosts := make(map[string]int)
+	for _, record := range records {
+		if record.IP == "" {
+			continue
+		}
+		index, found := hosts[record.IP]
+		if !found {
+			index = len(scan.Hosts)
+			hosts[record.IP] = index
+			scan.Hosts = append(scan.Hosts, Host{Addresses: []string{record.IP}})
+		}
+		for _, port := range record.Ports {
+			if port.Status != "open" {
+				continue
+			}
+			p := Port{Number: port.Port, Protocol: port.Proto}
+			scan.Scanned[p] = struct{}{}
+			scan.Hosts[index].Open = append(scan.Hosts[index].Open, p)
+		}
+	}
+	return scan, nil
+}
@@ -0,0 +1,125 @@
+package portscan
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func ports(protocol string, numbers ...int) map[Port]struct{} {
+	result := make(map[Port]struct{})
+	for _, number := range numbers {
+		result[Port{Number: number, Protocol: protocol}] = struct{}{}
+	}
+	return result
+}
+
+func TestParseNmap(t *testing.T) {
+	scan, err := ParseNmap(fixture(t, "office.nmap.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := ports("tcp", 22, 80, 443, 444, 445, 3389); !reflect.DeepEqual(scan.Scanned, expected) {
+		t.Errorf("scanned %v, expected %v", scan.Scanned, expected)
+	}
+
+	// The host that is down is left out, and only open ports count
+	expected := []Host{
+		{
+			Addresses: []string{"10.0.0.5"},
+			Hostnames: []string{"dc01.contoso.local"},
+			Open:      []Port{{445, "tcp"}, {3389, "tcp"}},
+		},
+		{
+			Addresses: []string{"10.0.0.20"},
+			Hostnames: []string{"ws01.contoso.local"},
+			Open:      []Port{{22, "tcp"}},
+		},
+	}
+	if !reflect.DeepEqual(scan.Hosts, expected) {
+		t.Errorf("hosts %+v, expected %+v", scan.Hosts, expected)
+	}
+
+	if _, err := ParseNmap([]byte(`<?xml version="1.0"?><Groups></Groups>`)); err != ErrNotNmap {
+		t.Errorf("other XML gave %v, expected %v", err, ErrNotNmap)
+	}
+}
+
+func TestParseNmapWithoutScanInfo(t *testing.T) {
+	scan, err := ParseNmap([]byte(`<nmaprun><host><status state="up"/><address addr="10.0.0.5" addrtype="ipv4"/>` +
+		`<ports><port protocol="tcp" portid="3389"><state state="closed"/></port></ports></host></nmaprun>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := ports("tcp", 3389); !reflect.DeepEqual(scan.Scanned, expected) {
+		t.Errorf("scanned %v, expected the listed ports %v", scan.Scanned, expected)
+	}
+	if len(scan.Hosts) != 1 || len(scan.Hosts[0].Open) != 0 {
+		t.Errorf("unexpected hosts %+v", scan.Hosts)
+	}
+}
+
+func TestParseMasscan(t *testing.T) {
+	expected := Scan{
+		Scanned: ports("tcp", 80, 445, 3389),
+		Hosts: []Host{
+			{Addresses: []string{"10.0.0.5"}, Open: []Port{{445, "tcp"}, {3389, "tcp"}}},
+			{Addresses: []string{"10.0.0.30"}, Open: []Port{{80, "tcp"}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"json", fixture(t, "dmz.masscan.json")},
+		{"ndjson", []byte(`{"ip": "10.0.0.5", "ports": [{"port": 445, "proto": "tcp", "status": "open"}]}
+{"ip": "10.0.0.30", "ports": [{"port": 80, "proto": "tcp", "status": "open"}]}
+{"ip": "10.0.0.5", "ports": [{"port": 3389, "proto": "tcp", "status": "open"}]}
+{"ip": "10.0.0.40", "ports": [{"port": 22, "proto": "tcp", "status": "closed"}]}
+`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan, err := ParseMasscan(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.name == "ndjson" {
+				// Hosts with nothing open are kept, they just have no open ports
+				if len(scan.Hosts) != 3 || len(scan.Hosts[2].Open) != 0 {
+					t.Fatalf("unexpected hosts %+v", scan.Hosts)
+				}
+				scan.Hosts = scan.Hosts[:2]
+			}
+			if !reflect.DeepEqual(scan, expected) {
+				t.Errorf("got %+v, expected %+v", scan, expected)
+			}
+		})
+	}
+}
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		path, suffix, segment string
+	}{
+		{"/data/scans/office.nmap.xml", NmapSuffix, "office"},
+		{"dmz.xml", NmapSuffix, "dmz"},
+		{"/data/vpn.masscan.json", MasscanSuffix, "vpn"},
+	}
+	for _, tt := range tests {
+		if segment := Segment(tt.path, tt.suffix); segment != tt.segment {
+			t.Errorf("segment of %v is %v, expected %v", tt.path, segment, tt.segment)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package ldapserver
+
+import (
+	"github.com/lkarlslund/adalanche/modules/cli"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "serve-ldap [-options]",
+		Short: "Serves the loaded objects over LDAP (read only), so other tools can query a snapshot as if it was a DC",
+	}
+
+	listen            = Command.Flags().String("listen", "127.0.0.1:10389", "Address and port to listen for LDAP connections on")
+	maxpagesize       = Command.Flags().Int("maxpagesize", 1000, "Maximum number of objects returned per page in paged searches")
+	acceptcredentials = Command.Flags().Bool("acceptcredentials", false, "Let binds with any credentials succeed instead of only anonymous binds (credentials are not checked)")
+)
+
+func init() {
+	cli.Root.AddCommand(Command)
+	Command.RunE = Execute
+}
+
+func Execute(cmd *cobra.Command, args []string) error {
+	datapath := cmd.InheritedFlags().Lookup("datapath").Value.String()
+
+	objs, err := engine.Run(datapath)
+	if err != nil {
+		return err
+	}
+
+	server := NewServer(objs)
+	server.AcceptCredentials = *acceptcredentials
+	return server.ListenAndServe(*listen)
+}
@@ -0,0 +1,336 @@
+package ldapserver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/integrations/activedirectory"
+	"github.com/lkarlslund/adalanche/modules/query"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/lkarlslund/adalanche/modules/util"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	ldap "github.com/lkarlslund/ldap/v3"
+)
+
+type pagedSearch struct {
+	remaining  []*engine.Object
+	attributes []string
+	typesonly  bool
+}
+
+type searchRequest struct {
+	base       string
+	scope      int
+	sizelimit  int
+	typesonly  bool
+	filter     string
+	attributes []string
+}
+
+func parseSearchRequest(packet *ber.Packet) (searchRequest, error) {
+	var req searchRequest
+	if len(packet.Children) < 8 {
+		return req, errors.New("search request is missing fields")
+	}
+	req.base = ber.DecodeString(packet.Children[0].Data.Bytes())
+	scope, _ := packet.Children[1].Value.(int64)
+	req.scope = int(scope)
+	sizelimit, _ := packet.Children[3].Value.(int64)
+	req.sizelimit = int(sizelimit)
+	req.typesonly, _ = packet.Children[5].Value.(bool)
+
+	var err error
+	req.filter, err = ldap.DecompileFilter(packet.Children[6])
+	if err != nil {
+		return req, err
+	}
+
+	for _, attribute := range packet.Children[7].Children {
+		req.attributes = append(req.attributes, ber.DecodeString(attribute.Data.Bytes()))
+	}
+	return req, nil
+}
+
+func (sess *session) search(messageid int64, packet *ber.Packet, controls []ldap.Control) error {
+	var paging *ldap.ControlPaging
+	if control := ldap.FindControl(controls, ldap.ControlTypePaging); control != nil {
+		paging, _ = control.(*ldap.ControlPaging)
+	}
+
+	var ps *pagedSearch
+	if paging != nil && len(paging.Cookie) > 0 {
+		// Continuation of a paged search
+		cookie := string(paging.Cookie)
+		ps = sess.pages[cookie]
+		delete(sess.pages, cookie)
+		if ps == nil {
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultUnwillingToPerform, "unknown paging cookie"))
+		}
+		if paging.PagingSize == 0 {
+			// Client gave up on the rest
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""), ldap.NewControlPaging(0))
+		}
+	} else {
+		req, err := parseSearchRequest(packet)
+		if err != nil {
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, err.Error()))
+		}
+
+		ui.Debug().Msgf("LDAP search from %v for %v in '%v' with scope %v", sess.conn.RemoteAddr(), req.filter, req.base, req.scope)
+
+		if req.base == "" && req.scope == ldap.ScopeBaseObject {
+			if err = sess.respond(messageid, sess.server.rootDSEEntry(req.attributes, req.typesonly)); err != nil {
+				return err
+			}
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+		}
+
+		results, code, message := sess.server.find(req)
+		if code != ldap.LDAPResultSuccess {
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, code, message))
+		}
+
+		if paging == nil && req.sizelimit > 0 && len(results) > req.sizelimit {
+			for _, o := range results[:req.sizelimit] {
+				if err = sess.respond(messageid, sess.server.entry(o, req.attributes, req.typesonly)); err != nil {
+					return err
+				}
+			}
+			return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, ""))
+		}
+
+		ps = &pagedSearch{
+			remaining:  results,
+			attributes: req.attributes,
+			typesonly:  req.typesonly,
+		}
+	}
+
+	page := ps.remaining
+	if paging != nil {
+		pagesize := int(paging.PagingSize)
+		if pagesize <= 0 || pagesize > *maxpagesize {
+			pagesize = *maxpagesize
+		}
+		if len(page) > pagesize {
+			page = page[:pagesize]
+		}
+	}
+	for _, o := range page {
+		if err := sess.respond(messageid, sess.server.entry(o, ps.attributes, ps.typesonly)); err != nil {
+			return err
+		}
+	}
+	ps.remaining = ps.remaining[len(page):]
+
+	if paging == nil {
+		return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+	}
+
+	response := ldap.NewControlPaging(0)
+	if len(ps.remaining) > 0 {
+		sess.nextcookie++
+		cookie := strconv.FormatUint(sess.nextcookie, 10)
+		sess.pages[cookie] = ps
+		response.SetCookie([]byte(cookie))
+	}
+	return sess.respond(messageid, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""), response)
+}
+
+// Finds the objects matching the scope and filter, using the parent/child tree from the analysis
+func (s *Server) find(req searchRequest) ([]*engine.Object, uint16, string) {
+	filter, err := query.ParseLDAPQueryStrict(req.filter, s.ao)
+	if err != nil {
+		return nil, ldap.LDAPResultUnwillingToPerform, "unsupported filter: " + err.Error()
+	}
+
+	var results []*engine.Object
+	check := func(o *engine.Object) {
+		if o.DN() != "" && filter.Evaluate(o) {
+			results = append(results, o)
+		}
+	}
+
+	if req.base == "" {
+		// Search everything we have, like a global catalog search would
+		if req.scope == ldap.ScopeWholeSubtree {
+			s.ao.Iterate(func(o *engine.Object) bool {
+				check(o)
+				return true
+			})
+		}
+		return results, ldap.LDAPResultSuccess, ""
+	}
+
+	base, found := s.ao.Find(engine.DistinguishedName, engine.AttributeValueString(req.base))
+	if !found {
+		return nil, ldap.LDAPResultNoSuchObject, ""
+	}
+
+	switch req.scope {
+	case ldap.ScopeBaseObject:
+		check(base)
+	case ldap.ScopeSingleLevel:
+		base.Children().Iterate(func(child *engine.Object) bool {
+			check(child)
+			return true
+		})
+	case ldap.ScopeWholeSubtree:
+		visited := make(map[*engine.Object]struct{})
+		queue := []*engine.Object{base}
+		for len(queue) > 0 {
+			o := queue[0]
+			queue = queue[1:]
+			if _, found := visited[o]; found {
+				continue
+			}
+			visited[o] = struct{}{}
+			check(o)
+			o.Children().Iterate(func(child *engine.Object) bool {
+				queue = append(queue, child)
+				return true
+			})
+		}
+	default:
+		return nil, ldap.LDAPResultProtocolError, "unknown scope"
+	}
+	return results, ldap.LDAPResultSuccess, ""
+}
+
+func (s *Server) entry(o *engine.Object, attributes []string, typesonly bool) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, o.DN(), "Object Name"))
+	attributespacket := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+
+	addattribute := func(attr engine.Attribute, avs engine.AttributeValues) {
+		var values []string
+		avs.Iterate(func(value engine.AttributeValue) bool {
+			if encoded, ok := encodeValue(attr, value); ok {
+				values = append(values, encoded)
+			}
+			return true
+		})
+		if len(values) == 0 {
+			return
+		}
+		attributespacket.AppendChild(attributePacket(attr.String(), values, typesonly))
+	}
+
+	if wantsAllAttributes(attributes) {
+		o.AttrIterator(func(attr engine.Attribute, avs engine.AttributeValues) bool {
+			addattribute(attr, avs)
+			return true
+		})
+	} else {
+		for _, name := range attributes {
+			attr := engine.LookupAttribute(name)
+			if attr == engine.NonExistingAttribute {
+				continue
+			}
+			addattribute(attr, o.Attr(attr))
+		}
+	}
+
+	entry.AppendChild(attributespacket)
+	return entry
+}
+
+func (s *Server) rootDSEEntry(attributes []string, typesonly bool) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Object Name"))
+	attributespacket := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	all := wantsAllAttributes(attributes)
+	for name, values := range s.rootdse {
+		wanted := all
+		for _, attribute := range attributes {
+			if strings.EqualFold(attribute, name) {
+				wanted = true
+			}
+		}
+		if wanted && len(values) > 0 {
+			attributespacket.AppendChild(attributePacket(name, values, typesonly))
+		}
+	}
+	entry.AppendChild(attributespacket)
+	return entry
+}
+
+func wantsAllAttributes(attributes []string) bool {
+	if len(attributes) == 0 {
+		return true
+	}
+	for _, attribute := range attributes {
+		if attribute == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func attributePacket(name string, values []string, typesonly bool) *ber.Packet {
+	attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+	attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+	valuespacket := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+	if !typesonly {
+		for _, value := range values {
+			valuespacket.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+	}
+	attribute.AppendChild(valuespacket)
+	return attribute
+}
+
+// Attributes that AD transfers as FILETIME integers rather than generalized time
+var filetimeAttributes = map[engine.Attribute]struct{}{
+	activedirectory.AccountExpires:            {},
+	activedirectory.CreationTime:              {},
+	activedirectory.PwdLastSet:                {},
+	activedirectory.LastLogon:                 {},
+	activedirectory.LastLogonTimestamp:        {},
+	activedirectory.MSmcsAdmPwdExpirationTime: {},
+	activedirectory.BadPasswordTime:           {},
+}
+
+// Converts values back into what a DC would have sent, so the wire format matches what was collected
+func encodeValue(attr engine.Attribute, value engine.AttributeValue) (string, bool) {
+	switch v := value.(type) {
+	case engine.AttributeValueString:
+		return string(v), true
+	case engine.AttributeValueBlob:
+		return string(v), true
+	case engine.AttributeValueBool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case engine.AttributeValueInt:
+		return strconv.FormatInt(int64(v), 10), true
+	case engine.AttributeValueTime:
+		if _, found := filetimeAttributes[attr]; found {
+			return strconv.FormatUint(util.TimeToFiletime(time.Time(v)), 10), true
+		}
+		return time.Time(v).UTC().Format("20060102150405.0Z"), true
+	case engine.AttributeValueSID:
+		return string(windowssecurity.SID(v).Bytes()), true
+	case engine.AttributeValueGUID:
+		guid := uuid.UUID(v)
+		switch attr {
+		case engine.ObjectGUID:
+			// Kept in wire order when imported
+		case activedirectory.RightsGUID:
+			return guid.String(), true
+		default:
+			guid = util.SwapUUIDEndianess(guid)
+		}
+		return string(guid.Bytes()), true
+	case engine.AttributeValueSecurityDescriptor:
+		return string(v.SD.Bytes()), true
+	}
+	// Object references and other things that only exist in analysis
+	return "", false
+}
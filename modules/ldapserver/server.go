@@ -0,0 +1,214 @@
+package ldapserver
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	ldap "github.com/lkarlslund/ldap/v3"
+)
+
+// Server answers LDAP searches against loaded objects. Only anonymous binds succeed unless AcceptCredentials is set,
+// and all writes are refused.
+type Server struct {
+	ao      *engine.Objects
+	rootdse map[string][]string
+
+	// Let binds with any name and password succeed, for tools that refuse to work on an anonymous session.
+	// Credentials are never checked, so they are only accepted when asked for.
+	AcceptCredentials bool
+}
+
+func NewServer(ao *engine.Objects) *Server {
+	s := &Server{
+		ao: ao,
+	}
+	s.rootdse = s.buildRootDSE()
+	return s
+}
+
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	ui.Info().Msgf("Serving LDAP on %v", listener.Addr())
+	return s.Serve(listener)
+}
+
+// Serve answers LDAP connections on the listener until accepting fails
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+type session struct {
+	server *Server
+	conn   net.Conn
+
+	// Paged searches in progress on this connection
+	pages      map[string]*pagedSearch
+	nextcookie uint64
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	ui.Debug().Msgf("LDAP connection from %v", conn.RemoteAddr())
+
+	sess := &session{
+		server: s,
+		conn:   conn,
+		pages:  make(map[string]*pagedSearch),
+	}
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				ui.Debug().Msgf("Problem reading LDAP request from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if len(packet.Children) < 2 {
+			ui.Debug().Msgf("Malformed LDAP message from %v", conn.RemoteAddr())
+			return
+		}
+
+		messageid, ok := packet.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		request := packet.Children[1]
+
+		var controls []ldap.Control
+		if len(packet.Children) > 2 {
+			for _, child := range packet.Children[2].Children {
+				if control, err := ldap.DecodeControl(child); err == nil {
+					controls = append(controls, control)
+				}
+			}
+		}
+
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			err = sess.bind(messageid, request)
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationAbandonRequest:
+			// Nothing is running in the background, so nothing to abandon
+		case ldap.ApplicationSearchRequest:
+			err = sess.search(messageid, request, controls)
+		case ldap.ApplicationModifyRequest, ldap.ApplicationAddRequest, ldap.ApplicationDelRequest, ldap.ApplicationModifyDNRequest, ldap.ApplicationCompareRequest:
+			err = sess.respond(messageid, ldapResult(request.Tag+1, ldap.LDAPResultUnwillingToPerform, "adalanche LDAP server is read only"))
+		case ldap.ApplicationExtendedRequest:
+			err = sess.respond(messageid, ldapResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, "extended operations are not supported"))
+		default:
+			ui.Debug().Msgf("Unsupported LDAP operation %v from %v", request.Tag, conn.RemoteAddr())
+			return
+		}
+		if err != nil {
+			ui.Debug().Msgf("Problem answering LDAP request from %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// Anonymous binds always succeed, simple binds with a password and SASL binds only if the server accepts credentials
+func (sess *session) bind(messageid int64, request *ber.Packet) error {
+	if len(request.Children) < 3 {
+		return sess.respond(messageid, ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "bind request is missing fields"))
+	}
+	authentication := request.Children[2]
+	anonymous := authentication.ClassType == ber.ClassContext && authentication.Tag == 0 && authentication.Data.Len() == 0
+	if !anonymous && !sess.server.AcceptCredentials {
+		ui.Debug().Msgf("Refused bind as '%v' from %v", ber.DecodeString(request.Children[1].Data.Bytes()), sess.conn.RemoteAddr())
+		return sess.respond(messageid, ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "adalanche LDAP server only accepts anonymous binds"))
+	}
+	return sess.respond(messageid, ldapResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, ""))
+}
+
+func (sess *session) respond(messageid int64, response *ber.Packet, controls ...ldap.Control) error {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageid, "MessageID"))
+	packet.AppendChild(response)
+	if len(controls) > 0 {
+		controlspacket := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		for _, control := range controls {
+			controlspacket.AppendChild(control.Encode())
+		}
+		packet.AppendChild(controlspacket)
+	}
+	_, err := sess.conn.Write(packet.Bytes())
+	return err
+}
+
+func ldapResult(tag ber.Tag, code uint16, message string) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return result
+}
+
+// The RootDSE is taken from what the collector saved, or made up from the domains we know if that is missing
+func (s *Server) buildRootDSE() map[string][]string {
+	rootdse := make(map[string][]string)
+	var namingcontexts []string
+	addcontext := func(context string) {
+		for _, existing := range namingcontexts {
+			if strings.EqualFold(existing, context) {
+				return
+			}
+		}
+		namingcontexts = append(namingcontexts, context)
+	}
+
+	s.ao.Iterate(func(o *engine.Object) bool {
+		if strings.HasPrefix(strings.ToLower(o.DN()), "cn=rootdse,") {
+			o.AttrIterator(func(attr engine.Attribute, avs engine.AttributeValues) bool {
+				name := attr.String()
+				if strings.EqualFold(name, "namingContexts") {
+					avs.Iterate(func(value engine.AttributeValue) bool {
+						addcontext(value.String())
+						return true
+					})
+					return true
+				}
+				if _, found := rootdse[name]; found || attr == engine.DistinguishedName {
+					return true
+				}
+				avs.Iterate(func(value engine.AttributeValue) bool {
+					if encoded, ok := encodeValue(attr, value); ok {
+						rootdse[name] = append(rootdse[name], encoded)
+					}
+					return true
+				})
+				return true
+			})
+		}
+		if o.Type() == engine.ObjectTypeDomainDNS && o.HasAttr(engine.ObjectSid) {
+			addcontext(o.DN())
+			if _, found := rootdse["defaultNamingContext"]; !found {
+				rootdse["defaultNamingContext"] = []string{o.DN()}
+			}
+		}
+		return true
+	})
+
+	rootdse["namingContexts"] = namingcontexts
+	rootdse["supportedLDAPVersion"] = []string{"3"}
+	rootdse["supportedControl"] = []string{ldap.ControlTypePaging}
+	rootdse["objectClass"] = []string{"top"}
+	return rootdse
+}
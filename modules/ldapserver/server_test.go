@@ -0,0 +1,192 @@
+package ldapserver
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	ldap "github.com/lkarlslund/ldap/v3"
+)
+
+func testObjects(t *testing.T) (*engine.Objects, []byte) {
+	domainsid, _ := windowssecurity.ParseStringSID("S-1-5-21-1-2-3")
+	usersid, _ := windowssecurity.ParseStringSID("S-1-5-21-1-2-3-1105")
+	adminssid, _ := windowssecurity.ParseStringSID("S-1-5-21-1-2-3-512")
+
+	sd := engine.SecurityDescriptor{
+		Owner:   adminssid,
+		Group:   adminssid,
+		DACL:    engine.ACL{Revision: 4},
+		Control: engine.CONTROLFLAG_DACL_PRESENT,
+	}
+	sd.DACL.Entries = append(sd.DACL.Entries,
+		engine.ACE{SID: adminssid, Type: engine.ACETYPE_ACCESS_ALLOWED, Mask: engine.RIGHT_GENERIC_ALL},
+		engine.ACE{SID: windowssecurity.EveryoneSID, Type: engine.ACETYPE_ACCESS_ALLOWED, Mask: engine.RIGHT_GENERIC_READ},
+	)
+	parsed, err := engine.ParseSecurityDescriptor(sd.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ao := engine.NewObjects()
+	domain := engine.NewObject(
+		engine.DistinguishedName, "DC=contoso,DC=local",
+		engine.Type, engine.ObjectTypeDomainDNS.ValueString(),
+		engine.ObjectClass, []string{"top", "domain", "domainDNS"},
+		engine.ObjectSid, engine.AttributeValueSID(domainsid),
+	)
+	users := engine.NewObject(
+		engine.DistinguishedName, "CN=Users,DC=contoso,DC=local",
+		engine.Type, engine.ObjectTypeContainer.ValueString(),
+		engine.ObjectClass, []string{"top", "container"},
+	)
+	alice := engine.NewObject(
+		engine.DistinguishedName, "CN=Alice,CN=Users,DC=contoso,DC=local",
+		engine.Type, engine.ObjectTypeUser.ValueString(),
+		engine.ObjectClass, []string{"top", "person", "organizationalPerson", "user"},
+		engine.SAMAccountName, "alice",
+		engine.ObjectSid, engine.AttributeValueSID(usersid),
+		engine.NTSecurityDescriptor, engine.AttributeValueSecurityDescriptor{SD: &parsed},
+	)
+	ao.Add(domain, users, alice)
+	users.ChildOf(domain)
+	alice.ChildOf(users)
+	return ao, parsed.Bytes()
+}
+
+func startServer(t *testing.T, server *Server) *ldap.Conn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go server.Serve(listener)
+
+	conn, err := ldap.DialURL("ldap://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServerRoundTrip(t *testing.T) {
+	ao, sdbytes := testObjects(t)
+	conn := startServer(t, NewServer(ao))
+
+	if err := conn.UnauthenticatedBind(""); err != nil {
+		t.Fatalf("anonymous bind failed: %v", err)
+	}
+
+	rootdse, err := conn.Search(ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"defaultNamingContext"}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rootdse.Entries) != 1 || rootdse.Entries[0].GetAttributeValue("defaultNamingContext") != "DC=contoso,DC=local" {
+		t.Errorf("unexpected RootDSE %+v", rootdse.Entries)
+	}
+
+	tests := []struct {
+		name   string
+		base   string
+		scope  int
+		filter string
+		dns    []string
+	}{
+		{"base", "DC=contoso,DC=local", ldap.ScopeBaseObject, "(objectClass=*)", []string{"DC=contoso,DC=local"}},
+		{"one level", "DC=contoso,DC=local", ldap.ScopeSingleLevel, "(objectClass=*)", []string{"CN=Users,DC=contoso,DC=local"}},
+		{"subtree", "DC=contoso,DC=local", ldap.ScopeWholeSubtree, "(objectClass=*)", []string{"DC=contoso,DC=local", "CN=Users,DC=contoso,DC=local", "CN=Alice,CN=Users,DC=contoso,DC=local"}},
+		{"filter", "DC=contoso,DC=local", ldap.ScopeWholeSubtree, "(&(objectClass=user)(sAMAccountName=alice))", []string{"CN=Alice,CN=Users,DC=contoso,DC=local"}},
+		{"no match", "DC=contoso,DC=local", ldap.ScopeWholeSubtree, "(sAMAccountName=bob)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := conn.Search(ldap.NewSearchRequest(tt.base, tt.scope, ldap.NeverDerefAliases, 0, 0, false, tt.filter, nil, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Entries) != len(tt.dns) {
+				t.Fatalf("got %v entries, expected %v", len(result.Entries), len(tt.dns))
+			}
+			for i, entry := range result.Entries {
+				if entry.DN != tt.dns[i] {
+					t.Errorf("entry %v is %v, expected %v", i, entry.DN, tt.dns[i])
+				}
+			}
+		})
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest("CN=Alice,CN=Users,DC=contoso,DC=local", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"sAMAccountName", "objectClass", "objectSid", "nTSecurityDescriptor"}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 1 {
+		t.Fatalf("got %v entries for Alice", len(result.Entries))
+	}
+	alice := result.Entries[0]
+	if got := alice.GetAttributeValue("sAMAccountName"); got != "alice" {
+		t.Errorf("sAMAccountName is %v", got)
+	}
+	if got := alice.GetAttributeValues("objectClass"); len(got) != 4 || got[3] != "user" {
+		t.Errorf("objectClass is %v", got)
+	}
+	if sid, _, err := windowssecurity.BytesToSID(alice.GetRawAttributeValue("objectSid")); err != nil || sid.String() != "S-1-5-21-1-2-3-1105" {
+		t.Errorf("objectSid is %v (%v)", sid, err)
+	}
+	if got := alice.GetRawAttributeValue("nTSecurityDescriptor"); !bytes.Equal(got, sdbytes) {
+		t.Errorf("nTSecurityDescriptor is %x, expected %x", got, sdbytes)
+	}
+
+	paged, err := conn.SearchWithPaging(ldap.NewSearchRequest("DC=contoso,DC=local", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"objectClass"}, nil), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paged.Entries) != 3 {
+		t.Errorf("paged search returned %v entries, expected 3", len(paged.Entries))
+	}
+
+	err = conn.Add(ldap.NewAddRequest("CN=Bob,CN=Users,DC=contoso,DC=local", nil))
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform) {
+		t.Errorf("add was not refused: %v", err)
+	}
+}
+
+func TestServerBind(t *testing.T) {
+	ao, _ := testObjects(t)
+
+	tests := []struct {
+		name              string
+		acceptcredentials bool
+		anonymous         bool
+		success           bool
+	}{
+		{"anonymous", false, true, true},
+		{"credentials refused", false, false, false},
+		{"credentials accepted", true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(ao)
+			server.AcceptCredentials = tt.acceptcredentials
+			conn := startServer(t, server)
+
+			var err error
+			if tt.anonymous {
+				err = conn.UnauthenticatedBind("")
+			} else {
+				err = conn.Bind("CN=Alice,CN=Users,DC=contoso,DC=local", "Summer2024!")
+			}
+			if tt.success && err != nil {
+				t.Errorf("bind failed: %v", err)
+			}
+			if !tt.success && !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+				t.Errorf("bind was not refused with invalid credentials: %v", err)
+			}
+		})
+	}
+}
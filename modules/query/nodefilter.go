@@ -449,15 +449,15 @@ type TypedComparison[t cmp.Ordered] struct {
 }
 
 func (tc TypedComparison[t]) Evaluate(a engine.Attribute, o *engine.Object) bool {
-	val := o.Attr(a)
-	if val == nil {
-		return false
-	}
-	realval, ok := val.(t)
-	if !ok {
-		return false
-	}
-	return Comparator[t](tc.Comparator).Compare(realval, tc.Value)
+	var result bool
+	o.Attr(a).Iterate(func(value engine.AttributeValue) bool {
+		if realval, ok := value.Raw().(t); ok && Comparator[t](tc.Comparator).Compare(realval, tc.Value) {
+			result = true
+			return false // break
+		}
+		return true
+	})
+	return result
 }
 
 func (tc TypedComparison[t]) ToLDAPFilter(a string) string {
@@ -0,0 +1,159 @@
+package scripting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lkarlslund/adalanche/modules/cli"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/ui"
+	"github.com/spf13/cobra"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// Rules are Starlark scripts (*.star) that register processors with register(fn, description, priority).
+// The function is called with the objects to analyze, and can look at attributes and security descriptors
+// and add edges and tags, just like the built in analyzers.
+func init() {
+	cobra.OnInitialize(func() {
+		if *cli.RulesPath == "" {
+			return
+		}
+		if err := LoadDirectory(*cli.RulesPath); err != nil {
+			ui.Fatal().Msgf("Problem loading rules from %v: %v", *cli.RulesPath, err)
+		}
+	})
+}
+
+// LoadDirectory runs all *.star files in a directory (not recursively) in alphabetical order
+func LoadDirectory(path string) error {
+	files, err := filepath.Glob(filepath.Join(path, "*.star"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	var processors int
+	for _, file := range files {
+		count, err := LoadFile(file)
+		if err != nil {
+			return err
+		}
+		processors += count
+	}
+	ui.Info().Msgf("Loaded %v analyzers from %v rule files in %v", processors, len(files), path)
+	return nil
+}
+
+// LoadFile runs a rule file and registers the processors it defines, returning how many there were
+func LoadFile(path string) (int, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var registered []registration
+	register := starlark.NewBuiltin("register", func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var fn starlark.Callable
+		var description string
+		priority := engine.AfterMerge.String()
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "fn", &fn, "description", &description, "priority?", &priority); err != nil {
+			return nil, err
+		}
+		pp, err := engine.ProcessPriorityString(priority)
+		if err != nil {
+			return nil, fmt.Errorf("%v: unknown priority %v, use one of %v", b.Name(), priority, strings.Join(engine.ProcessPriorityStrings(), ", "))
+		}
+		registered = append(registered, registration{fn: fn, description: description, priority: pp})
+		return starlark.None, nil
+	})
+
+	predeclared := starlark.StringDict{
+		"register": register,
+		"edge":     starlark.NewBuiltin("edge", defineEdge),
+		"rights":   rightsModule,
+	}
+
+	thread := newThread(path)
+	globals, err := starlark.ExecFile(thread, path, src, predeclared)
+	if err != nil {
+		if evalErr, ok := err.(*starlark.EvalError); ok {
+			return 0, fmt.Errorf("%v", evalErr.Backtrace())
+		}
+		return 0, err
+	}
+	globals.Freeze() // Processors run in parallel, so they must not share mutable state
+
+	for _, r := range registered {
+		engine.AnyLoader.AddProcessor(r.processor(path), r.description, r.priority)
+	}
+	return len(registered), nil
+}
+
+type registration struct {
+	fn          starlark.Callable
+	description string
+	priority    engine.ProcessPriority
+}
+
+func (r registration) processor(path string) engine.ProcessorFunc {
+	return func(ao *engine.Objects) {
+		_, err := starlark.Call(newThread(path), r.fn, starlark.Tuple{&objectsValue{ao: ao}}, nil)
+		if err != nil {
+			if evalErr, ok := err.(*starlark.EvalError); ok {
+				ui.Error().Msgf("Rule %v (%v) failed: %v", r.description, path, evalErr.Backtrace())
+				return
+			}
+			ui.Error().Msgf("Rule %v (%v) failed: %v", r.description, path, err)
+		}
+	}
+}
+
+func newThread(path string) *starlark.Thread {
+	return &starlark.Thread{
+		Name: path,
+		Print: func(thread *starlark.Thread, msg string) {
+			ui.Info().Msgf("%v: %v", filepath.Base(path), msg)
+		},
+	}
+}
+
+// edge(name, description="") makes sure an edge exists and returns its name
+func defineEdge(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, description string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "description?", &description); err != nil {
+		return nil, err
+	}
+	edge := engine.NewEdge(name)
+	if description != "" {
+		edge.Describe(description)
+	}
+	return starlark.String(edge.String()), nil
+}
+
+var rightsModule = &starlarkstruct.Module{
+	Name: "rights",
+	Members: starlark.StringDict{
+		"GENERIC_READ":               starlark.MakeUint64(uint64(engine.RIGHT_GENERIC_READ)),
+		"GENERIC_WRITE":              starlark.MakeUint64(uint64(engine.RIGHT_GENERIC_WRITE)),
+		"GENERIC_EXECUTE":            starlark.MakeUint64(uint64(engine.RIGHT_GENERIC_EXECUTE)),
+		"GENERIC_ALL":                starlark.MakeUint64(uint64(engine.RIGHT_GENERIC_ALL)),
+		"WRITE_OWNER":                starlark.MakeUint64(uint64(engine.RIGHT_WRITE_OWNER)),
+		"WRITE_DACL":                 starlark.MakeUint64(uint64(engine.RIGHT_WRITE_DACL)),
+		"READ_CONTROL":               starlark.MakeUint64(uint64(engine.RIGHT_READ_CONTROL)),
+		"DELETE":                     starlark.MakeUint64(uint64(engine.RIGHT_DELETE)),
+		"DS_CONTROL_ACCESS":          starlark.MakeUint64(uint64(engine.RIGHT_DS_CONTROL_ACCESS)),
+		"DS_LIST_OBJECT":             starlark.MakeUint64(uint64(engine.RIGHT_DS_LIST_OBJECT)),
+		"DS_DELETE_TREE":             starlark.MakeUint64(uint64(engine.RIGHT_DS_DELETE_TREE)),
+		"DS_WRITE_PROPERTY":          starlark.MakeUint64(uint64(engine.RIGHT_DS_WRITE_PROPERTY)),
+		"DS_READ_PROPERTY":           starlark.MakeUint64(uint64(engine.RIGHT_DS_READ_PROPERTY)),
+		"DS_WRITE_PROPERTY_EXTENDED": starlark.MakeUint64(uint64(engine.RIGHT_DS_WRITE_PROPERTY_EXTENDED)),
+		"DS_LIST_CONTENTS":           starlark.MakeUint64(uint64(engine.RIGHT_DS_LIST_CONTENTS)),
+		"DS_DELETE_CHILD":            starlark.MakeUint64(uint64(engine.RIGHT_DS_DELETE_CHILD)),
+		"DS_CREATE_CHILD":            starlark.MakeUint64(uint64(engine.RIGHT_DS_CREATE_CHILD)),
+	},
+}
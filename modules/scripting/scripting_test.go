@@ -0,0 +1,80 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lkarlslund/adalanche/modules/engine"
+)
+
+const testRule = `
+managedby = edge("ScriptManagedBy", "Manages the account according to the description")
+
+def analyze(objects):
+    for o in objects.query("(&(type=Person)(description=*helpdesk*))"):
+        o.tag("script_helpdesk_managed")
+        for helpdesk in objects.find("name", "Helpdesk"):
+            helpdesk.edge_to(o, managedby)
+
+register(analyze, "Accounts managed by helpdesk")
+`
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "helpdesk.star")
+	if err := os.WriteFile(path, []byte(testRule), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	count, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("registered %v processors, expected 1", count)
+	}
+	managedby := engine.LookupEdge("ScriptManagedBy")
+	if managedby == engine.NonExistingEdge {
+		t.Fatal("edge was not defined")
+	}
+
+	// Rules are registered for any loader, so they must run both for a single loader and on the merged objects
+	tests := []struct {
+		name   string
+		loader engine.LoaderID
+	}{
+		{"single loader", engine.LoaderID(0)},
+		{"all loaders", engine.AllLoaders},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := func(name string, objecttype engine.ObjectType, description string) *engine.Object {
+				return engine.NewObject(engine.IgnoreBlanks,
+					engine.Name, name,
+					engine.Type, objecttype.ValueString(),
+					engine.Description, description,
+				)
+			}
+			helpdesk := object("Helpdesk", engine.ObjectTypeGroup, "")
+			alice := object("Alice", engine.ObjectTypeUser, "Reset by helpdesk")
+			bob := object("Bob", engine.ObjectTypeUser, "Service account")
+			kiosk := object("KIOSK01", engine.ObjectTypeComputer, "Reset by helpdesk")
+
+			ao := engine.NewObjects()
+			ao.Add(helpdesk, alice, bob, kiosk)
+			if err := engine.Process(ao, "Testing rule", tt.loader, engine.AfterMerge); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, o := range []*engine.Object{helpdesk, alice, bob, kiosk} {
+				expected := o == alice
+				if tagged := o.HasTag(engine.AttributeValueString("script_helpdesk_managed")); tagged != expected {
+					t.Errorf("%v tagged is %v, expected %v", o.Label(), tagged, expected)
+				}
+				edges, _ := helpdesk.Edges(engine.Out).Get(o)
+				if edges.IsSet(managedby) != expected {
+					t.Errorf("edge to %v is %v, expected %v", o.Label(), edges.IsSet(managedby), expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package scripting
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gofrs/uuid"
+	"github.com/lkarlslund/adalanche/modules/engine"
+	"github.com/lkarlslund/adalanche/modules/query"
+	"github.com/lkarlslund/adalanche/modules/windowssecurity"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// objectsValue is the collection a processor works on, it can be iterated and searched
+type objectsValue struct {
+	ao *engine.Objects
+}
+
+var (
+	_ starlark.Iterable = (*objectsValue)(nil)
+	_ starlark.HasAttrs = (*objectsValue)(nil)
+)
+
+func (ov *objectsValue) String() string        { return fmt.Sprintf("<objects %v>", ov.ao.Len()) }
+func (ov *objectsValue) Type() string          { return "objects" }
+func (ov *objectsValue) Freeze()               {}
+func (ov *objectsValue) Truth() starlark.Bool  { return starlark.True }
+func (ov *objectsValue) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: objects") }
+
+func (ov *objectsValue) Iterate() starlark.Iterator {
+	var objects []*engine.Object
+	ov.ao.Iterate(func(o *engine.Object) bool {
+		objects = append(objects, o)
+		return true
+	})
+	return &objectIterator{ao: ov.ao, objects: objects}
+}
+
+var objectsMethods = map[string]*starlark.Builtin{
+	"query":    starlark.NewBuiltin("query", objectsQuery),
+	"find":     starlark.NewBuiltin("find", objectsFind),
+	"find_sid": starlark.NewBuiltin("find_sid", objectsFindSID),
+	"find_dn":  starlark.NewBuiltin("find_dn", objectsFindDN),
+	"len":      starlark.NewBuiltin("len", objectsLen),
+}
+
+func (ov *objectsValue) Attr(name string) (starlark.Value, error) {
+	if method, found := objectsMethods[name]; found {
+		return method.BindReceiver(ov), nil
+	}
+	return nil, nil
+}
+
+func (ov *objectsValue) AttrNames() []string {
+	return builtinNames(objectsMethods)
+}
+
+// objects.query(ldapfilter) returns the objects matching an LDAP style filter
+func objectsQuery(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectsValue)
+	var filter string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &filter); err != nil {
+		return nil, err
+	}
+	nodefilter, err := query.ParseLDAPQueryStrict(filter, ov.ao)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", b.Name(), err)
+	}
+	var result []starlark.Value
+	query.Execute(nodefilter, ov.ao).Iterate(func(o *engine.Object) bool {
+		result = append(result, &objectValue{o: o, ao: ov.ao})
+		return true
+	})
+	return starlark.NewList(result), nil
+}
+
+// objects.find(attribute, value) returns the objects having that exact value
+func objectsFind(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectsValue)
+	var name string
+	var value starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &name, &value); err != nil {
+		return nil, err
+	}
+	attribute := engine.LookupAttribute(name)
+	if attribute == engine.NonExistingAttribute {
+		return starlark.NewList(nil), nil
+	}
+	av, err := toAttributeValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", b.Name(), err)
+	}
+	objects, _ := ov.ao.FindMulti(attribute, av)
+	return objectList(objects, ov.ao), nil
+}
+
+// objects.find_sid(sid) returns the first object with that SID, or None
+func objectsFindSID(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectsValue)
+	var s string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &s); err != nil {
+		return nil, err
+	}
+	sid, err := windowssecurity.ParseStringSID(s)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", b.Name(), err)
+	}
+	if o, found := ov.ao.Find(engine.ObjectSid, engine.AttributeValueSID(sid)); found {
+		return &objectValue{o: o, ao: ov.ao}, nil
+	}
+	return starlark.None, nil
+}
+
+// objects.find_dn(dn) returns the object with that distinguishedName, or None
+func objectsFindDN(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectsValue)
+	var dn string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &dn); err != nil {
+		return nil, err
+	}
+	if o, found := ov.ao.Find(engine.DistinguishedName, engine.AttributeValueString(dn)); found {
+		return &objectValue{o: o, ao: ov.ao}, nil
+	}
+	return starlark.None, nil
+}
+
+func objectsLen(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	return starlark.MakeInt(b.Receiver().(*objectsValue).ao.Len()), nil
+}
+
+type objectIterator struct {
+	ao      *engine.Objects
+	objects []*engine.Object
+}
+
+func (oi *objectIterator) Next(p *starlark.Value) bool {
+	if len(oi.objects) == 0 {
+		return false
+	}
+	*p = &objectValue{o: oi.objects[0], ao: oi.ao}
+	oi.objects = oi.objects[1:]
+	return true
+}
+
+func (oi *objectIterator) Done() {}
+
+// objectValue wraps a single object, along with the objects it belongs to for SID lookups
+type objectValue struct {
+	o  *engine.Object
+	ao *engine.Objects
+}
+
+var (
+	_ starlark.HasAttrs   = (*objectValue)(nil)
+	_ starlark.Comparable = (*objectValue)(nil)
+)
+
+func (ov *objectValue) String() string        { return ov.o.Label() }
+func (ov *objectValue) Type() string          { return "object" }
+func (ov *objectValue) Freeze()               {}
+func (ov *objectValue) Truth() starlark.Bool  { return starlark.True }
+func (ov *objectValue) Hash() (uint32, error) { return uint32(ov.o.ID()), nil }
+
+func (ov *objectValue) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	other := y.(*objectValue)
+	switch op {
+	case syntax.EQL:
+		return ov.o == other.o, nil
+	case syntax.NEQ:
+		return ov.o != other.o, nil
+	}
+	return false, fmt.Errorf("%v not supported for objects", op)
+}
+
+var objectMethods = map[string]*starlark.Builtin{
+	"attr":                starlark.NewBuiltin("attr", objectAttr),
+	"one":                 starlark.NewBuiltin("one", objectOne),
+	"has":                 starlark.NewBuiltin("has", objectHas),
+	"set":                 starlark.NewBuiltin("set", objectSet),
+	"tag":                 starlark.NewBuiltin("tag", objectTag),
+	"has_tag":             starlark.NewBuiltin("has_tag", objectHasTag),
+	"edge_to":             starlark.NewBuiltin("edge_to", objectEdgeTo),
+	"edges":               starlark.NewBuiltin("edges", objectEdges),
+	"children":            starlark.NewBuiltin("children", objectChildren),
+	"security_descriptor": starlark.NewBuiltin("security_descriptor", objectSecurityDescriptor),
+	"allowed":             starlark.NewBuiltin("allowed", objectAllowed),
+}
+
+func (ov *objectValue) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "dn":
+		return starlark.String(ov.o.DN()), nil
+	case "label":
+		return starlark.String(ov.o.Label()), nil
+	case "type":
+		return starlark.String(ov.o.Type().String()), nil
+	case "sid":
+		if sid := ov.o.SID(); !sid.IsBlank() {
+			return starlark.String(sid.String()), nil
+		}
+		return starlark.None, nil
+	case "parent":
+		if parent := ov.o.Parent(); parent != nil {
+			return &objectValue{o: parent, ao: ov.ao}, nil
+		}
+		return starlark.None, nil
+	}
+	if method, found := objectMethods[name]; found {
+		return method.BindReceiver(ov), nil
+	}
+	return nil, nil
+}
+
+func (ov *objectValue) AttrNames() []string {
+	return append(builtinNames(objectMethods), "dn", "label", "parent", "sid", "type")
+}
+
+func unpackAttribute(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (engine.Attribute, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+		return engine.NonExistingAttribute, err
+	}
+	return engine.LookupAttribute(name), nil
+}
+
+// object.attr(name) returns all values of an attribute as a list
+func objectAttr(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	attribute, err := unpackAttribute(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	var result []starlark.Value
+	if attribute != engine.NonExistingAttribute {
+		b.Receiver().(*objectValue).o.Attr(attribute).Iterate(func(av engine.AttributeValue) bool {
+			result = append(result, fromAttributeValue(av))
+			return true
+		})
+	}
+	return starlark.NewList(result), nil
+}
+
+// object.one(name) returns the first value of an attribute, or None
+func objectOne(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	attribute, err := unpackAttribute(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	if attribute == engine.NonExistingAttribute {
+		return starlark.None, nil
+	}
+	if av := b.Receiver().(*objectValue).o.OneAttr(attribute); av != nil {
+		return fromAttributeValue(av), nil
+	}
+	return starlark.None, nil
+}
+
+func objectHas(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	attribute, err := unpackAttribute(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.Bool(attribute != engine.NonExistingAttribute && b.Receiver().(*objectValue).o.HasAttr(attribute)), nil
+}
+
+// object.set(name, value, ...) replaces the values of an attribute
+func objectSet(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%v: unexpected keyword arguments", b.Name())
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%v: needs an attribute name and at least one value", b.Name())
+	}
+	name, ok := starlark.AsString(args[0])
+	if !ok {
+		return nil, fmt.Errorf("%v: attribute name must be a string", b.Name())
+	}
+	values := make([]engine.AttributeValue, len(args)-1)
+	for i, arg := range args[1:] {
+		av, err := toAttributeValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", b.Name(), err)
+		}
+		values[i] = av
+	}
+	b.Receiver().(*objectValue).o.SetValues(engine.NewAttribute(name), values...)
+	return starlark.None, nil
+}
+
+func objectTag(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var tag string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &tag); err != nil {
+		return nil, err
+	}
+	b.Receiver().(*objectValue).o.Tag(engine.AttributeValueString(tag))
+	return starlark.None, nil
+}
+
+func objectHasTag(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var tag string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &tag); err != nil {
+		return nil, err
+	}
+	return starlark.Bool(b.Receiver().(*objectValue).o.HasTag(engine.AttributeValueString(tag))), nil
+}
+
+// object.edge_to(target, edge) adds an edge from this object to target, creating the edge type if needed
+func objectEdgeTo(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var target *objectValue
+	var edge string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &target, &edge); err != nil {
+		return nil, err
+	}
+	b.Receiver().(*objectValue).o.EdgeTo(target.o, engine.NewEdge(edge))
+	return starlark.None, nil
+}
+
+// object.edges(direction="out") returns a list of (object, [edge names]) tuples
+func objectEdges(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectValue)
+	direction := "out"
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "direction?", &direction); err != nil {
+		return nil, err
+	}
+	var ed engine.EdgeDirection
+	switch direction {
+	case "out":
+		ed = engine.Out
+	case "in":
+		ed = engine.In
+	default:
+		return nil, fmt.Errorf("%v: direction must be \"in\" or \"out\"", b.Name())
+	}
+	var result []starlark.Value
+	ov.o.Edges(ed).Range(func(target *engine.Object, eb engine.EdgeBitmap) bool {
+		names := eb.StringSlice()
+		edges := make([]starlark.Value, len(names))
+		for i, name := range names {
+			edges[i] = starlark.String(name)
+		}
+		result = append(result, starlark.Tuple{&objectValue{o: target, ao: ov.ao}, starlark.NewList(edges)})
+		return true
+	})
+	return starlark.NewList(result), nil
+}
+
+func objectChildren(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	ov := b.Receiver().(*objectValue)
+	return objectList(ov.o.Children(), ov.ao), nil
+}
+
+// object.security_descriptor() returns the parsed security descriptor, or None
+func objectSecurityDescriptor(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	ov := b.Receiver().(*objectValue)
+	sd, err := ov.o.SecurityDescriptor()
+	if err != nil {
+		return starlark.None, nil
+	}
+	return securityDescriptorValue(ov, sd), nil
+}
+
+// object.allowed(mask, guid="") returns the principals that are granted the access on this object by its DACL,
+// with the same object class and GUID matching the built in analyzers use
+func objectAllowed(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ov := b.Receiver().(*objectValue)
+	var mask uint32
+	var guid string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "mask", &mask, "guid?", &guid); err != nil {
+		return nil, err
+	}
+	var g uuid.UUID
+	if guid != "" {
+		var err error
+		if g, err = uuid.FromString(guid); err != nil {
+			return nil, fmt.Errorf("%v: %v", b.Name(), err)
+		}
+	}
+
+	sd, err := ov.o.SecurityDescriptor()
+	if err != nil {
+		return starlark.NewList(nil), nil
+	}
+	var result []starlark.Value
+	seen := make(map[*engine.Object]struct{})
+	for index, acl := range sd.DACL.Entries {
+		if !sd.DACL.IsObjectClassAccessAllowed(index, ov.o, engine.Mask(mask), g, ov.ao) {
+			continue
+		}
+		principal := ov.ao.FindOrAddAdjacentSID(acl.SID, ov.o)
+		if _, found := seen[principal]; found {
+			continue
+		}
+		seen[principal] = struct{}{}
+		result = append(result, &objectValue{o: principal, ao: ov.ao})
+	}
+	return starlark.NewList(result), nil
+}
+
+func securityDescriptorValue(ov *objectValue, sd *engine.SecurityDescriptor) starlark.Value {
+	entries := make([]starlark.Value, len(sd.DACL.Entries))
+	for i, ace := range sd.DACL.Entries {
+		entries[i] = aceValue(ace)
+	}
+	owner, group := starlark.Value(starlark.None), starlark.Value(starlark.None)
+	if !sd.Owner.IsBlank() {
+		owner = starlark.String(sd.Owner.String())
+	}
+	if !sd.Group.IsBlank() {
+		group = starlark.String(sd.Group.String())
+	}
+	return newStruct(starlark.StringDict{
+		"owner":   owner,
+		"group":   group,
+		"dacl":    starlark.NewList(entries),
+		"control": starlark.MakeInt(int(sd.Control)),
+	})
+}
+
+func aceValue(ace engine.ACE) starlark.Value {
+	guidString := func(g uuid.UUID) starlark.String {
+		if g.IsNil() {
+			return ""
+		}
+		return starlark.String(g.String())
+	}
+	return newStruct(starlark.StringDict{
+		"sid":                   starlark.String(ace.SID.String()),
+		"allow":                 starlark.Bool(ace.Type == engine.ACETYPE_ACCESS_ALLOWED || ace.Type == engine.ACETYPE_ACCESS_ALLOWED_OBJECT),
+		"type":                  starlark.MakeInt(int(ace.Type)),
+		"flags":                 starlark.MakeInt(int(ace.ACEFlags)),
+		"inherited":             starlark.Bool(ace.ACEFlags&engine.ACEFLAG_INHERITED_ACE != 0),
+		"inherit_only":          starlark.Bool(ace.ACEFlags&engine.ACEFLAG_INHERIT_ONLY_ACE != 0),
+		"mask":                  starlark.MakeUint64(uint64(ace.Mask)),
+		"object_type":           guidString(ace.ObjectType),
+		"inherited_object_type": guidString(ace.InheritedObjectType),
+	})
+}
+
+func objectList(objects engine.ObjectSlice, ao *engine.Objects) *starlark.List {
+	result := make([]starlark.Value, 0, objects.Len())
+	objects.Iterate(func(o *engine.Object) bool {
+		result = append(result, &objectValue{o: o, ao: ao})
+		return true
+	})
+	return starlark.NewList(result)
+}
+
+func fromAttributeValue(av engine.AttributeValue) starlark.Value {
+	switch v := av.(type) {
+	case engine.AttributeValueInt:
+		return starlark.MakeInt64(int64(v))
+	case engine.AttributeValueBool:
+		return starlark.Bool(v)
+	}
+	return starlark.String(av.String())
+}
+
+func toAttributeValue(v starlark.Value) (engine.AttributeValue, error) {
+	switch value := v.(type) {
+	case starlark.String:
+		return engine.AttributeValueString(value), nil
+	case starlark.Bool:
+		return engine.AttributeValueBool(value), nil
+	case starlark.Int:
+		i, ok := value.Int64()
+		if !ok {
+			return nil, fmt.Errorf("integer %v too large", value)
+		}
+		return engine.AttributeValueInt(i), nil
+	}
+	return nil, fmt.Errorf("can't use %v as attribute value", v.Type())
+}
+
+func builtinNames(methods map[string]*starlark.Builtin) []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newStruct(members starlark.StringDict) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, members)
+}
@@ -81,6 +81,14 @@ func FiletimeToTime(filetime uint64) time.Time {
 	return t
 }
 
+// TimeToFiletime is the reverse of FiletimeToTime
+func TimeToFiletime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.Unix()+11644473600)*10000000 + uint64(t.Nanosecond()/100)
+}
+
 func IsASCII(s string) bool {
 	for i := 0; i < len(s); i++ {
 		if s[i] > unicode.MaxASCII {
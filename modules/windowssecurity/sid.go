@@ -39,11 +39,17 @@ func BytesToSID(data []byte) (SID, []byte, error) {
 		}
 		return "", data, fmt.Errorf("SID revision must be 1 (dump %x ...)", data)
 	}
+	if len(data) < 8 {
+		return "", data, errors.New("SID is truncated")
+	}
 	subauthoritycount := int(data[1])
 	if subauthoritycount > 15 {
 		return "", data, errors.New("SID subauthority count is more than 15")
 	}
 	sidend := 8 + 4*subauthoritycount
+	if len(data) < sidend {
+		return "", data, errors.New("SID is truncated")
+	}
 	return SID(dedup.D.BS(data[2:sidend])), data[sidend:], nil
 }
 
@@ -118,6 +124,18 @@ func (sid SID) String() string {
 	return s
 }
 
+// Bytes returns the Windows binary representation of the SID
+func (sid SID) Bytes() []byte {
+	if sid == "" {
+		return nil
+	}
+	result := make([]byte, 2+len(sid))
+	result[0] = 0x01
+	result[1] = byte((len(sid) - 6) / 4)
+	copy(result[2:], sid)
+	return result
+}
+
 func (sid SID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(sid.String())
 }
@@ -187,7 +205,6 @@ func (sid SID) AddComponent(component uint32) SID {
 	newsid := make([]byte, len(sid)+4)
 	copy(newsid, sid)
 	binary.LittleEndian.PutUint32(newsid[len(sid):], component)
-	newsid[1] = byte(len(newsid)/4) - 2 // Adjust internal length
 	return SID(newsid)
 }
 
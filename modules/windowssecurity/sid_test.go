@@ -27,3 +27,10 @@ func TestServiceNameToServiceSID(t *testing.T) {
 		})
 	}
 }
+
+func TestAddComponent(t *testing.T) {
+	domain := MustParseStringSID("S-1-5-21-1004336348-1177238915-682003330")
+	if got := domain.AddComponent(512).String(); got != "S-1-5-21-1004336348-1177238915-682003330-512" {
+		t.Errorf("AddComponent() = %v", got)
+	}
+}